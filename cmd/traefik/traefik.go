@@ -2,12 +2,14 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	stdlog "log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -20,10 +22,12 @@ import (
 	"github.com/containous/traefik/pkg/config"
 	"github.com/containous/traefik/pkg/config/static"
 	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/provider/acme"
 	"github.com/containous/traefik/pkg/provider/aggregator"
 	"github.com/containous/traefik/pkg/safe"
 	"github.com/containous/traefik/pkg/server"
 	"github.com/containous/traefik/pkg/server/router"
+	"github.com/containous/traefik/pkg/statistics"
 	traefiktls "github.com/containous/traefik/pkg/tls"
 	"github.com/containous/traefik/pkg/version"
 	"github.com/coreos/go-systemd/daemon"
@@ -112,14 +116,21 @@ func runCmd(staticConfiguration *static.Configuration, configFile string) error
 
 	providerAggregator := aggregator.NewProviderAggregator(*staticConfiguration.Providers)
 
-	acmeProvider, err := staticConfiguration.InitACMEProvider()
-	if err != nil {
-		log.WithoutContext().Errorf("Unable to initialize ACME provider: %v", err)
-	} else if acmeProvider != nil {
-		if err := providerAggregator.AddProvider(acmeProvider); err != nil {
-			log.WithoutContext().Errorf("Unable to add ACME provider to the providers list: %v", err)
-			acmeProvider = nil
+	tlsManager := traefiktls.NewManager(staticConfiguration.OCSP)
+
+	if staticConfiguration.InternalCA != nil {
+		internalCA, err := traefiktls.NewInternalCA(staticConfiguration.InternalCA)
+		if err != nil {
+			return fmt.Errorf("error while building the internal CA: %v", err)
 		}
+		tlsManager.SetInternalCA(internalCA)
+	}
+
+	acmeProviders := initACMEProvider(staticConfiguration, &providerAggregator, tlsManager)
+
+	var statsRecorder *statistics.Recorder
+	if staticConfiguration.API != nil && staticConfiguration.API.Statistics != nil {
+		statsRecorder = statistics.NewRecorder(staticConfiguration.API.Statistics.RecentErrors)
 	}
 
 	serverEntryPointsTCP := make(server.TCPEntryPoints)
@@ -129,27 +140,20 @@ func runCmd(staticConfiguration *static.Configuration, configFile string) error
 		if err != nil {
 			return fmt.Errorf("error while building entryPoint %s: %v", entryPointName, err)
 		}
-		serverEntryPointsTCP[entryPointName].RouteAppenderFactory = router.NewRouteAppenderFactory(*staticConfiguration, entryPointName, acmeProvider)
+		serverEntryPointsTCP[entryPointName].RouteAppenderFactory = router.NewRouteAppenderFactory(*staticConfiguration, entryPointName, acmeProviders, tlsManager, statsRecorder)
 
 	}
 
-	tlsManager := traefiktls.NewManager()
+	svr := server.NewServer(*staticConfiguration, providerAggregator, serverEntryPointsTCP, tlsManager, statsRecorder)
 
-	if acmeProvider != nil {
-		acmeProvider.SetTLSManager(tlsManager)
-		if acmeProvider.TLSChallenge != nil &&
-			acmeProvider.HTTPChallenge == nil &&
-			acmeProvider.DNSChallenge == nil {
-			tlsManager.TLSAlpnGetter = acmeProvider.GetTLSALPNCertificate
-		}
+	for _, acmeProvider := range acmeProviders {
+		svr.AddListener(acmeProvider.ListenConfiguration)
 	}
 
-	svr := server.NewServer(*staticConfiguration, providerAggregator, serverEntryPointsTCP, tlsManager)
-
-	if acmeProvider != nil && acmeProvider.OnHostRule {
-		acmeProvider.SetConfigListenerChan(make(chan config.Configuration))
-		svr.AddListener(acmeProvider.ListenConfiguration)
+	if crdProvider := staticConfiguration.Providers.KubernetesCRD; crdProvider != nil && crdProvider.UpdateStatus {
+		svr.AddRuntimeListener(crdProvider.ListenRuntimeConfiguration)
 	}
+
 	ctx := cmd.ContextWithSignal(context.Background())
 
 	if staticConfiguration.Ping != nil {
@@ -196,6 +200,119 @@ func runCmd(staticConfiguration *static.Configuration, configFile string) error
 	return nil
 }
 
+// initACMEProvider creates an ACME provider for each ACME certificates resolver, and adds it to the providers.
+// The resolvers using the same storage share the same store, and all of them share the challenges.
+func initACMEProvider(c *static.Configuration, providerAggregator *aggregator.ProviderAggregator, tlsManager *traefiktls.Manager) []*acme.Provider {
+	var resolverNames []string
+	for name := range c.CertificatesResolvers {
+		resolverNames = append(resolverNames, name)
+	}
+	sort.Strings(resolverNames)
+
+	stores := map[string]acme.Store{}
+	resolverStores := map[string]acme.Store{}
+
+	// The challenges must be served by every instance, so they are kept in the first store shared by several instances, if any.
+	var challengeStore acme.ChallengeStore
+
+	for _, name := range resolverNames {
+		resolver := c.CertificatesResolvers[name]
+		if resolver.ACME == nil {
+			continue
+		}
+
+		store, err := getACMEStore(resolver.ACME, stores)
+		if err != nil {
+			log.WithoutContext().Errorf("Unable to create the store of the ACME provider %q: %v", name, err)
+			continue
+		}
+		resolverStores[name] = store
+
+		if sharedStore, ok := store.(acme.ChallengeStore); ok && challengeStore == nil {
+			challengeStore = sharedStore
+		}
+	}
+
+	if challengeStore == nil {
+		challengeStore = acme.NewLocalChallengeStore()
+	}
+
+	var resolvers []*acme.Provider
+	var tlsALPNGetters []func(string) (*tls.Certificate, error)
+	for _, name := range resolverNames {
+		store, ok := resolverStores[name]
+		if !ok {
+			continue
+		}
+
+		p := &acme.Provider{
+			Configuration:  c.CertificatesResolvers[name].ACME,
+			Store:          store,
+			ChallengeStore: challengeStore,
+			ResolverName:   name,
+		}
+
+		if err := providerAggregator.AddProvider(p); err != nil {
+			log.WithoutContext().Errorf("Unable to add ACME provider %q to the providers list: %v", name, err)
+			continue
+		}
+
+		p.SetTLSManager(tlsManager)
+		if p.TLSChallenge != nil {
+			tlsALPNGetters = append(tlsALPNGetters, p.GetTLSALPNCertificate)
+		}
+
+		p.SetConfigListenerChan(make(chan config.Configuration))
+
+		resolvers = append(resolvers, p)
+	}
+
+	if len(tlsALPNGetters) > 0 {
+		tlsManager.TLSAlpnGetter = chainTLSALPNGetters(tlsALPNGetters)
+	}
+
+	return resolvers
+}
+
+// chainTLSALPNGetters returns a getter answering the TLS-ALPN-01 challenges of all the resolvers:
+// the first certificate found by a getter is returned.
+func chainTLSALPNGetters(getters []func(string) (*tls.Certificate, error)) func(string) (*tls.Certificate, error) {
+	return func(domain string) (*tls.Certificate, error) {
+		for _, getter := range getters {
+			cert, err := getter(domain)
+			if err != nil {
+				return nil, err
+			}
+
+			if cert != nil {
+				return cert, nil
+			}
+		}
+		return nil, nil
+	}
+}
+
+// getACMEStore returns the store of the ACME configuration, created once per storage location.
+func getACMEStore(configuration *acme.Configuration, stores map[string]acme.Store) (acme.Store, error) {
+	if storage := configuration.KubernetesStorage; storage != nil {
+		key := "kubernetes:" + storage.Namespace + "/" + storage.SecretName
+		if stores[key] == nil {
+			store, err := acme.NewKubernetesStore(storage)
+			if err != nil {
+				return nil, err
+			}
+			stores[key] = store
+		}
+		return stores[key], nil
+	}
+
+	key := "file:" + configuration.Storage
+	if stores[key] == nil {
+		stores[key] = acme.NewLocalStore(configuration.Storage)
+	}
+	return stores[key], nil
+}
+
 func configureLogging(staticConfiguration *static.Configuration) {
 	// configure default log flags
 	stdlog.SetFlags(stdlog.Lshortfile | stdlog.LstdFlags)
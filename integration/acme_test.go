@@ -165,7 +165,6 @@ func (s *AcmeSuite) TestHTTP01OnHostRule(c *check.C) {
 		template: templateModel{
 			Acme: acme.Configuration{
 				HTTPChallenge: &acme.HTTPChallenge{EntryPoint: "web"},
-				OnHostRule:    true,
 			},
 		},
 		expectedCommonName: acmeDomain,
@@ -181,7 +180,6 @@ func (s *AcmeSuite) TestHTTP01OnHostRuleECDSA(c *check.C) {
 		template: templateModel{
 			Acme: acme.Configuration{
 				HTTPChallenge: &acme.HTTPChallenge{EntryPoint: "web"},
-				OnHostRule:    true,
 				KeyType:       "EC384",
 			},
 		},
@@ -198,7 +196,6 @@ func (s *AcmeSuite) TestHTTP01OnHostRuleInvalidAlgo(c *check.C) {
 		template: templateModel{
 			Acme: acme.Configuration{
 				HTTPChallenge: &acme.HTTPChallenge{EntryPoint: "web"},
-				OnHostRule:    true,
 				KeyType:       "INVALID",
 			},
 		},
@@ -215,7 +212,6 @@ func (s *AcmeSuite) TestHTTP01OnHostRuleStaticCertificatesWithWildcard(c *check.
 		template: templateModel{
 			Acme: acme.Configuration{
 				HTTPChallenge: &acme.HTTPChallenge{EntryPoint: "web"},
-				OnHostRule:    true,
 			},
 		},
 		expectedCommonName: wildcardDomain,
@@ -231,7 +227,6 @@ func (s *AcmeSuite) TestHTTP01OnHostRuleDynamicCertificatesWithWildcard(c *check
 		template: templateModel{
 			Acme: acme.Configuration{
 				HTTPChallenge: &acme.HTTPChallenge{EntryPoint: "web"},
-				OnHostRule:    true,
 			},
 		},
 		expectedCommonName: wildcardDomain,
@@ -247,7 +242,6 @@ func (s *AcmeSuite) TestTLSALPN01OnHostRule(c *check.C) {
 		template: templateModel{
 			Acme: acme.Configuration{
 				TLSChallenge: &acme.TLSChallenge{},
-				OnHostRule:   true,
 			},
 		},
 		expectedCommonName: acmeDomain,
@@ -321,7 +315,6 @@ func (s *AcmeSuite) TestNoValidLetsEncryptServer(c *check.C) {
 		Acme: acme.Configuration{
 			CAServer:      "http://wrongurl:4001/directory",
 			HTTPChallenge: &acme.HTTPChallenge{EntryPoint: "web"},
-			OnHostRule:    true,
 		},
 	})
 	defer os.Remove(file)
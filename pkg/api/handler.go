@@ -12,6 +12,9 @@ import (
 	"github.com/containous/traefik/pkg/config"
 	"github.com/containous/traefik/pkg/config/static"
 	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/rules"
+	"github.com/containous/traefik/pkg/statistics"
+	"github.com/containous/traefik/pkg/tls"
 	"github.com/containous/traefik/pkg/types"
 	"github.com/containous/traefik/pkg/version"
 	assetfs "github.com/elazarl/go-bindata-assetfs"
@@ -69,6 +72,16 @@ type tcpServiceRepresentation struct {
 	Provider string `json:"provider,omitempty"`
 }
 
+type tlsCertificateRepresentation struct {
+	tls.CertificateInfo
+	Routers []string `json:"routers,omitempty"`
+}
+
+type tlsOptionsRepresentation struct {
+	tls.OptionsInfo
+	Routers []string `json:"routers,omitempty"`
+}
+
 type pageInfo struct {
 	startIndex int
 	endIndex   int
@@ -81,15 +94,16 @@ type Handler struct {
 	debug     bool
 	// runtimeConfiguration is the data set used to create all the data representations exposed by the API.
 	runtimeConfiguration *config.RuntimeConfiguration
+	tlsManager           *tls.Manager
 	statistics           *types.Statistics
-	// stats                *thoasstats.Stats // FIXME stats
-	// StatsRecorder         *middlewares.StatsRecorder // FIXME stats
+	// statsRecorder holds the recent errors and the live statistics of the services, when the statistics are enabled.
+	statsRecorder   *statistics.Recorder
 	dashboardAssets *assetfs.AssetFS
 }
 
-// New returns a Handler defined by staticConfig, and if provided, by runtimeConfig.
+// New returns a Handler defined by staticConfig, and if provided, by runtimeConfig, tlsManager and statsRecorder.
 // It finishes populating the information provided in the runtimeConfig.
-func New(staticConfig static.Configuration, runtimeConfig *config.RuntimeConfiguration) *Handler {
+func New(staticConfig static.Configuration, runtimeConfig *config.RuntimeConfiguration, tlsManager *tls.Manager, statsRecorder *statistics.Recorder) *Handler {
 	rConfig := runtimeConfig
 	if rConfig == nil {
 		rConfig = &config.RuntimeConfiguration{}
@@ -100,6 +114,8 @@ func New(staticConfig static.Configuration, runtimeConfig *config.RuntimeConfigu
 		statistics:           staticConfig.API.Statistics,
 		dashboardAssets:      staticConfig.API.DashboardAssets,
 		runtimeConfiguration: rConfig,
+		tlsManager:           tlsManager,
+		statsRecorder:        statsRecorder,
 		debug:                staticConfig.API.Debug,
 	}
 }
@@ -111,11 +127,13 @@ func (h Handler) Append(router *mux.Router) {
 	}
 
 	router.Methods(http.MethodGet).Path("/api/rawdata").HandlerFunc(h.getRuntimeConfiguration)
+	router.Methods(http.MethodGet).Path("/api/overview").HandlerFunc(h.getOverview)
 
 	router.Methods(http.MethodGet).Path("/api/http/routers").HandlerFunc(h.getRouters)
 	router.Methods(http.MethodGet).Path("/api/http/routers/{routerID}").HandlerFunc(h.getRouter)
 	router.Methods(http.MethodGet).Path("/api/http/services").HandlerFunc(h.getServices)
 	router.Methods(http.MethodGet).Path("/api/http/services/{serviceID}").HandlerFunc(h.getService)
+	router.Methods(http.MethodGet).Path("/api/http/services/{serviceID}/stats").HandlerFunc(h.getServiceStats)
 	router.Methods(http.MethodGet).Path("/api/http/middlewares").HandlerFunc(h.getMiddlewares)
 	router.Methods(http.MethodGet).Path("/api/http/middlewares/{middlewareID}").HandlerFunc(h.getMiddleware)
 
@@ -124,9 +142,9 @@ func (h Handler) Append(router *mux.Router) {
 	router.Methods(http.MethodGet).Path("/api/tcp/services").HandlerFunc(h.getTCPServices)
 	router.Methods(http.MethodGet).Path("/api/tcp/services/{serviceID}").HandlerFunc(h.getTCPService)
 
-	// FIXME stats
-	// health route
-	// router.Methods(http.MethodGet).Path("/health").HandlerFunc(p.getHealthHandler)
+	router.Methods(http.MethodGet).Path("/api/tls/ocsp").HandlerFunc(h.getOCSPStaples)
+	router.Methods(http.MethodGet).Path("/api/tls/certificates").HandlerFunc(h.getTLSCertificates)
+	router.Methods(http.MethodGet).Path("/api/tls/options").HandlerFunc(h.getTLSOptions)
 
 	version.Handler{}.Append(router)
 
@@ -397,6 +415,193 @@ func (h Handler) getTCPService(rw http.ResponseWriter, request *http.Request) {
 	}
 }
 
+func (h Handler) getOCSPStaples(rw http.ResponseWriter, request *http.Request) {
+	results := make([]tls.OCSPStapleInfo, 0)
+	if h.tlsManager != nil {
+		results = h.tlsManager.GetOCSPStaples()
+	}
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) getTLSCertificates(rw http.ResponseWriter, request *http.Request) {
+	var certificates []tls.CertificateInfo
+	if h.tlsManager != nil {
+		certificates = h.tlsManager.GetCertificates()
+	}
+
+	routersDomains := h.getTLSRoutersDomains()
+
+	results := make([]tlsCertificateRepresentation, 0, len(certificates))
+	for _, certificate := range certificates {
+		results = append(results, tlsCertificateRepresentation{
+			CertificateInfo: certificate,
+			Routers:         getCertificateRouters(certificate, routersDomains),
+		})
+	}
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) getTLSOptions(rw http.ResponseWriter, request *http.Request) {
+	var options []tls.OptionsInfo
+	if h.tlsManager != nil {
+		options = h.tlsManager.GetOptions()
+	}
+
+	routersOptions := h.getTLSRoutersOptions()
+
+	results := make([]tlsOptionsRepresentation, 0, len(options))
+	for _, option := range options {
+		results = append(results, tlsOptionsRepresentation{
+			OptionsInfo: option,
+			Routers:     routersOptions[option.Name],
+		})
+	}
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// getTLSRoutersDomains returns the domains of the HTTP and TCP routers terminating TLS, indexed by router name.
+func (h Handler) getTLSRoutersDomains() map[string][]string {
+	routersDomains := make(map[string][]string)
+
+	for name, rt := range h.runtimeConfiguration.Routers {
+		if rt.TLS == nil {
+			continue
+		}
+
+		domains, _ := rules.ParseDomains(rt.Rule)
+		routersDomains[name] = append(domains, getTLSDomains(rt.TLS.Domains)...)
+	}
+
+	for name, rt := range h.runtimeConfiguration.TCPRouters {
+		if rt.TLS == nil || rt.TLS.Passthrough {
+			continue
+		}
+
+		domains, _ := rules.ParseHostSNI(rt.Rule)
+		routersDomains[name] = append(domains, getTLSDomains(rt.TLS.Domains)...)
+	}
+
+	return routersDomains
+}
+
+// getTLSRoutersOptions returns the names of the HTTP and TCP routers terminating TLS, indexed by TLS options name.
+func (h Handler) getTLSRoutersOptions() map[string][]string {
+	routersOptions := make(map[string][]string)
+
+	addRouter := func(options, name string) {
+		if len(options) == 0 {
+			options = "default"
+		}
+		routersOptions[options] = append(routersOptions[options], name)
+	}
+
+	for name, rt := range h.runtimeConfiguration.Routers {
+		if rt.TLS != nil {
+			addRouter(rt.TLS.Options, name)
+		}
+	}
+
+	for name, rt := range h.runtimeConfiguration.TCPRouters {
+		if rt.TLS != nil && !rt.TLS.Passthrough {
+			addRouter(rt.TLS.Options, name)
+		}
+	}
+
+	for _, routers := range routersOptions {
+		sort.Strings(routers)
+	}
+
+	return routersOptions
+}
+
+// getCertificateRouters returns the names of the routers with a domain matching the certificate.
+// The routers use the certificates of the default store.
+func getCertificateRouters(certificate tls.CertificateInfo, routersDomains map[string][]string) []string {
+	if !containsString(certificate.Stores, "default") {
+		return nil
+	}
+
+	var routers []string
+	for name, domains := range routersDomains {
+		if matchCertificate(certificate, domains) {
+			routers = append(routers, name)
+		}
+	}
+
+	sort.Strings(routers)
+	return routers
+}
+
+func matchCertificate(certificate tls.CertificateInfo, domains []string) bool {
+	for _, domain := range domains {
+		for _, certDomain := range certificate.Domains {
+			if tls.MatchDomain(strings.ToLower(domain), strings.ToLower(certDomain)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func getTLSDomains(tlsDomains []types.Domain) []string {
+	var domains []string
+	for _, domain := range tlsDomains {
+		if len(domain.Main) > 0 {
+			domains = append(domains, domain.Main)
+		}
+		domains = append(domains, domain.SANs...)
+	}
+	return domains
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 func (h Handler) getRuntimeConfiguration(rw http.ResponseWriter, request *http.Request) {
 	siRepr := make(map[string]*serviceInfoRepresentation, len(h.runtimeConfiguration.Services))
 	for k, v := range h.runtimeConfiguration.Services {
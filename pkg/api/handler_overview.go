@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/containous/mux"
+	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/statistics"
+)
+
+type section struct {
+	Total  int `json:"total"`
+	Errors int `json:"errors"`
+}
+
+type schemeOverview struct {
+	Routers     *section `json:"routers,omitempty"`
+	Services    *section `json:"services,omitempty"`
+	Middlewares *section `json:"middlewares,omitempty"`
+}
+
+type statisticsOverview struct {
+	statistics.ServiceStats
+	RecentErrors []statistics.ErrorEntry `json:"recentErrors"`
+}
+
+type overview struct {
+	HTTP       schemeOverview      `json:"http"`
+	TCP        schemeOverview      `json:"tcp"`
+	Statistics *statisticsOverview `json:"statistics,omitempty"`
+}
+
+type serviceStatsRepresentation struct {
+	statistics.ServiceStats
+	Name     string `json:"name,omitempty"`
+	Provider string `json:"provider,omitempty"`
+}
+
+func (h Handler) getOverview(rw http.ResponseWriter, request *http.Request) {
+	result := overview{
+		HTTP: schemeOverview{
+			Routers:     &section{},
+			Services:    &section{},
+			Middlewares: &section{},
+		},
+		TCP: schemeOverview{
+			Routers:  &section{},
+			Services: &section{},
+		},
+	}
+
+	for _, rt := range h.runtimeConfiguration.Routers {
+		result.HTTP.Routers.add(rt.Err != "")
+	}
+	for _, si := range h.runtimeConfiguration.Services {
+		result.HTTP.Services.add(si.Err != nil)
+	}
+	for _, mi := range h.runtimeConfiguration.Middlewares {
+		result.HTTP.Middlewares.add(mi.Err != nil)
+	}
+	for _, rt := range h.runtimeConfiguration.TCPRouters {
+		result.TCP.Routers.add(rt.Err != "")
+	}
+	for _, si := range h.runtimeConfiguration.TCPServices {
+		result.TCP.Services.add(si.Err != nil)
+	}
+
+	if h.statsRecorder != nil {
+		result.Statistics = &statisticsOverview{
+			ServiceStats: h.statsRecorder.TotalStats(),
+			RecentErrors: h.statsRecorder.RecentErrors(),
+		}
+	}
+
+	err := json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) getServiceStats(rw http.ResponseWriter, request *http.Request) {
+	serviceID := mux.Vars(request)["serviceID"]
+
+	if h.statsRecorder == nil {
+		http.Error(rw, "statistics are not enabled", http.StatusNotFound)
+		return
+	}
+
+	if _, ok := h.runtimeConfiguration.Services[serviceID]; !ok {
+		http.NotFound(rw, request)
+		return
+	}
+
+	result := serviceStatsRepresentation{
+		ServiceStats: h.statsRecorder.ServiceStats(serviceID),
+		Name:         serviceID,
+		Provider:     getProviderName(serviceID),
+	}
+
+	err := json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *section) add(inError bool) {
+	s.Total++
+	if inError {
+		s.Errors++
+	}
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/containous/mux"
+	"github.com/containous/traefik/pkg/config"
+	"github.com/containous/traefik/pkg/config/static"
+	"github.com/containous/traefik/pkg/statistics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_Overview(t *testing.T) {
+	rtConf := &config.RuntimeConfiguration{
+		Routers: map[string]*config.RouterInfo{
+			"myprovider@foo": {Router: &config.Router{Service: "myprovider@bar"}},
+			"myprovider@bar": {Router: &config.Router{Service: "myprovider@baz"}, Err: "service not found"},
+		},
+		Services: map[string]*config.ServiceInfo{
+			"myprovider@bar": {Service: &config.Service{}},
+		},
+		Middlewares: map[string]*config.MiddlewareInfo{
+			"myprovider@auth": {Middleware: &config.Middleware{}},
+		},
+		TCPServices: map[string]*config.TCPServiceInfo{
+			"myprovider@tcpbar": {TCPService: &config.TCPService{}},
+		},
+	}
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf, nil, nil)
+	router := mux.NewRouter()
+	handler.Append(router)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.DefaultClient.Get(server.URL + "/api/overview")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	contents, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	if *updateExpected {
+		var results interface{}
+		require.NoError(t, json.Unmarshal(contents, &results))
+
+		newJSON, err := json.MarshalIndent(results, "", "\t")
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile("testdata/overview.json", newJSON, 0644))
+	}
+
+	data, err := ioutil.ReadFile("testdata/overview.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(contents))
+}
+
+func TestHandler_Overview_statistics(t *testing.T) {
+	recorder := statistics.NewRecorder(10)
+	recorder.Record(statistics.Entry{Router: "myprovider@foo", Service: "myprovider@bar", Status: http.StatusOK, Duration: 10 * time.Millisecond})
+	recorder.Record(statistics.Entry{Router: "myprovider@foo", Service: "myprovider@bar", Status: http.StatusBadGateway, Error: "connection refused"})
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, &config.RuntimeConfiguration{}, nil, recorder)
+	router := mux.NewRouter()
+	handler.Append(router)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.DefaultClient.Get(server.URL + "/api/overview")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result overview
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.NoError(t, resp.Body.Close())
+
+	require.NotNil(t, result.Statistics)
+	assert.Equal(t, uint64(2), result.Statistics.Requests)
+	assert.Equal(t, uint64(1), result.Statistics.Errors)
+	assert.Equal(t, 0.5, result.Statistics.ErrorRate)
+
+	require.Len(t, result.Statistics.RecentErrors, 1)
+	assert.Equal(t, "myprovider@bar", result.Statistics.RecentErrors[0].Service)
+	assert.Equal(t, http.StatusBadGateway, result.Statistics.RecentErrors[0].Status)
+	assert.Equal(t, "connection refused", result.Statistics.RecentErrors[0].Message)
+}
+
+func TestHandler_ServiceStats(t *testing.T) {
+	rtConf := &config.RuntimeConfiguration{
+		Services: map[string]*config.ServiceInfo{
+			"myprovider@bar": {Service: &config.Service{}},
+		},
+	}
+
+	recorder := statistics.NewRecorder(10)
+	recorder.Record(statistics.Entry{Service: "myprovider@bar", Status: http.StatusOK, Duration: 10 * time.Millisecond})
+	recorder.Record(statistics.Entry{Service: "myprovider@bar", Status: http.StatusInternalServerError, Duration: 20 * time.Millisecond})
+	recorder.Record(statistics.Entry{Service: "myprovider@baz", Status: http.StatusOK})
+
+	testCases := []struct {
+		desc               string
+		path               string
+		recorder           *statistics.Recorder
+		expectedStatusCode int
+		expected           *serviceStatsRepresentation
+	}{
+		{
+			desc:               "service statistics",
+			path:               "/api/http/services/myprovider@bar/stats",
+			recorder:           recorder,
+			expectedStatusCode: http.StatusOK,
+			expected: &serviceStatsRepresentation{
+				ServiceStats: statistics.ServiceStats{
+					Requests:  2,
+					Errors:    1,
+					ErrorRate: 0.5,
+					Latency:   statistics.Latency{P50: 0.01, P90: 0.02, P99: 0.02},
+				},
+				Name:     "myprovider@bar",
+				Provider: "myprovider@bar",
+			},
+		},
+		{
+			desc:               "unknown service",
+			path:               "/api/http/services/myprovider@baz/stats",
+			recorder:           recorder,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			desc:               "statistics not enabled",
+			path:               "/api/http/services/myprovider@bar/stats",
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf, nil, test.recorder)
+			router := mux.NewRouter()
+			handler.Append(router)
+
+			server := httptest.NewServer(router)
+			defer server.Close()
+
+			resp, err := http.DefaultClient.Get(server.URL + test.path)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+
+			require.Equal(t, test.expectedStatusCode, resp.StatusCode)
+
+			if test.expected == nil {
+				return
+			}
+
+			var result serviceStatsRepresentation
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+
+			// The rates depend on the time elapsed since the creation of the recorder.
+			result.RequestsPerSecond = 0
+			result.ErrorsPerSecond = 0
+			assert.Equal(t, *test.expected, result)
+		})
+	}
+}
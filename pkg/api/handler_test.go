@@ -13,6 +13,7 @@ import (
 	"github.com/containous/mux"
 	"github.com/containous/traefik/pkg/config"
 	"github.com/containous/traefik/pkg/config/static"
+	"github.com/containous/traefik/pkg/tls"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -309,7 +310,7 @@ func TestHandlerTCP_API(t *testing.T) {
 			t.Parallel()
 
 			rtConf := &test.conf
-			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf)
+			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf, nil, nil)
 			router := mux.NewRouter()
 			handler.Append(router)
 
@@ -852,7 +853,7 @@ func TestHandlerHTTP_API(t *testing.T) {
 			t.Parallel()
 
 			rtConf := &test.conf
-			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf)
+			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf, nil, nil)
 			router := mux.NewRouter()
 			handler.Append(router)
 
@@ -1012,7 +1013,7 @@ func TestHandler_Configuration(t *testing.T) {
 
 			rtConf := &test.conf
 			rtConf.PopulateUsedBy()
-			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf)
+			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf, nil, nil)
 			router := mux.NewRouter()
 			handler.Append(router)
 
@@ -1051,6 +1052,49 @@ func TestHandler_Configuration(t *testing.T) {
 	}
 }
 
+func TestHandler_OCSP(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		tlsManager *tls.Manager
+	}{
+		{
+			desc: "without TLS manager",
+		},
+		{
+			desc:       "without certificates to staple",
+			tlsManager: tls.NewManager(nil),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, nil, test.tlsManager, nil)
+			router := mux.NewRouter()
+			handler.Append(router)
+
+			server := httptest.NewServer(router)
+			defer server.Close()
+
+			resp, err := http.DefaultClient.Get(server.URL + "/api/tls/ocsp")
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "1", resp.Header.Get(nextPageHeader))
+
+			contents, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			err = resp.Body.Close()
+			require.NoError(t, err)
+
+			assert.JSONEq(t, "[]", string(contents))
+		})
+	}
+}
+
 func generateHTTPRouters(nbRouters int) map[string]*config.RouterInfo {
 	routers := make(map[string]*config.RouterInfo, nbRouters)
 	for i := 0; i < nbRouters; i++ {
@@ -1064,3 +1108,110 @@ func generateHTTPRouters(nbRouters int) map[string]*config.RouterInfo {
 	}
 	return routers
 }
+
+func TestHandler_TLS(t *testing.T) {
+	tlsManager := tls.NewManager(nil)
+	tlsManager.UpdateConfigs(nil, map[string]tls.TLS{
+		"foo": {MinVersion: "VersionTLS12"},
+		"bar": {MinVersion: "VersionTLS42"},
+	}, []*tls.Configuration{
+		{
+			Certificate: &tls.Certificate{
+				CertFile: tls.FileOrContent("../../integration/fixtures/https/snitest.com.cert"),
+				KeyFile:  tls.FileOrContent("../../integration/fixtures/https/snitest.com.key"),
+			},
+			Provider: "file",
+		},
+	})
+
+	rtConf := &config.RuntimeConfiguration{
+		Routers: map[string]*config.RouterInfo{
+			"file@secured": {
+				Router: &config.Router{
+					Rule: "Host(`snitest.com`)",
+					TLS:  &config.RouterTLSConfig{Options: "foo"},
+				},
+			},
+			"file@default": {
+				Router: &config.Router{
+					Rule: "Host(`snitest.com`)",
+					TLS:  &config.RouterTLSConfig{},
+				},
+			},
+			"file@unsecured": {
+				Router: &config.Router{
+					Rule: "Host(`snitest.com`)",
+				},
+			},
+		},
+		TCPRouters: map[string]*config.TCPRouterInfo{
+			"file@tcp": {
+				TCPRouter: &config.TCPRouter{
+					Rule: "HostSNI(`snitest.com`)",
+					TLS:  &config.RouterTCPTLSConfig{},
+				},
+			},
+			"file@passthrough": {
+				TCPRouter: &config.TCPRouter{
+					Rule: "HostSNI(`snitest.com`)",
+					TLS:  &config.RouterTCPTLSConfig{Passthrough: true},
+				},
+			},
+		},
+	}
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf, tlsManager, nil)
+	router := mux.NewRouter()
+	handler.Append(router)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	var certificates []tlsCertificateRepresentation
+	getJSON(t, server.URL+"/api/tls/certificates", &certificates)
+
+	// The generated default certificate of the default store is listed after the expiring one.
+	require.Len(t, certificates, 2)
+	assert.Equal(t, "snitest.com", certificates[0].CommonName)
+	assert.Equal(t, []string{"snitest.com"}, certificates[0].Domains)
+	assert.Equal(t, "file", certificates[0].Provider)
+	assert.Equal(t, []string{"default"}, certificates[0].Stores)
+	assert.Equal(t, []string{"file@default", "file@secured", "file@tcp"}, certificates[0].Routers)
+	assert.NotEmpty(t, certificates[0].Fingerprint)
+
+	assert.Equal(t, []string{"default"}, certificates[1].DefaultStores)
+	assert.Empty(t, certificates[1].Routers)
+
+	var options []tlsOptionsRepresentation
+	getJSON(t, server.URL+"/api/tls/options", &options)
+
+	require.Len(t, options, 3)
+
+	assert.Equal(t, "bar", options[0].Name)
+	assert.NotEmpty(t, options[0].Error)
+	assert.Empty(t, options[0].Routers)
+
+	assert.Equal(t, "default", options[1].Name)
+	assert.Empty(t, options[1].Error)
+	assert.Equal(t, []string{"file@default", "file@tcp"}, options[1].Routers)
+
+	assert.Equal(t, "foo", options[2].Name)
+	assert.Equal(t, "VersionTLS12", options[2].MinVersion)
+	assert.Empty(t, options[2].Error)
+	assert.Equal(t, []string{"file@secured"}, options[2].Routers)
+}
+
+func getJSON(t *testing.T, url string, value interface{}) {
+	t.Helper()
+
+	resp, err := http.DefaultClient.Get(url)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	err = json.NewDecoder(resp.Body).Decode(value)
+	require.NoError(t, err)
+
+	err = resp.Body.Close()
+	require.NoError(t, err)
+}
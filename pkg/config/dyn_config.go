@@ -7,8 +7,10 @@ import (
 	"io/ioutil"
 	"os"
 	"reflect"
+	"time"
 
 	traefiktls "github.com/containous/traefik/pkg/tls"
+	"github.com/containous/traefik/pkg/types"
 )
 
 // Router holds the router configuration.
@@ -19,11 +21,24 @@ type Router struct {
 	Rule        string           `json:"rule,omitempty" toml:",omitempty"`
 	Priority    int              `json:"priority,omitempty" toml:"priority,omitzero"`
 	TLS         *RouterTLSConfig `json:"tls,omitempty" toml:"tls,omitzero" label:"allowEmpty"`
+	AccessLog   *RouterAccessLog `json:"accessLog,omitempty" toml:"accessLog,omitzero"`
 }
 
 // RouterTLSConfig holds the TLS configuration for a router
 type RouterTLSConfig struct {
-	Options string `json:"options,omitempty" toml:"options,omitzero"`
+	Options      string         `json:"options,omitempty" toml:"options,omitzero"`
+	CertResolver string         `json:"certResolver,omitempty" toml:"certResolver,omitzero"`
+	Domains      []types.Domain `json:"domains,omitempty" toml:"domains,omitzero"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RouterAccessLog holds the access log options of a router, overriding the access log filters.
+// Enabled opts the router in or out of the access logs,
+// and SampleRate overrides the fraction of its successful requests which are logged.
+type RouterAccessLog struct {
+	Enabled    *bool   `json:"enabled,omitempty" toml:"enabled,omitzero"`
+	SampleRate float64 `json:"sampleRate,omitempty" toml:"sampleRate,omitzero"`
 }
 
 // TCPRouter holds the router configuration.
@@ -36,8 +51,10 @@ type TCPRouter struct {
 
 // RouterTCPTLSConfig holds the TLS configuration for a router
 type RouterTCPTLSConfig struct {
-	Passthrough bool   `json:"passthrough" toml:"passthrough,omitzero"`
-	Options     string `json:"options,omitempty" toml:"options,omitzero"`
+	Passthrough  bool           `json:"passthrough" toml:"passthrough,omitzero"`
+	Options      string         `json:"options,omitempty" toml:"options,omitzero"`
+	CertResolver string         `json:"certResolver,omitempty" toml:"certResolver,omitzero"`
+	Domains      []types.Domain `json:"domains,omitempty" toml:"domains,omitzero"`
 }
 
 // LoadBalancerService holds the LoadBalancerService configuration.
@@ -47,11 +64,23 @@ type LoadBalancerService struct {
 	HealthCheck        *HealthCheck        `json:"healthCheck,omitempty" toml:",omitempty"`
 	PassHostHeader     bool                `json:"passHostHeader" toml:",omitempty"`
 	ResponseForwarding *ResponseForwarding `json:"forwardingResponse,omitempty" toml:",omitempty"`
+	ServersTransport   string              `json:"serversTransport,omitempty" toml:",omitempty"`
 }
 
 // TCPLoadBalancerService holds the LoadBalancerService configuration.
 type TCPLoadBalancerService struct {
-	Servers []TCPServer `json:"servers,omitempty" toml:",omitempty" label-slice-as-struct:"server"`
+	Servers     []TCPServer    `json:"servers,omitempty" toml:",omitempty" label-slice-as-struct:"server"`
+	TLS         *TCPServersTLS `json:"tls,omitempty" toml:",omitempty"`
+	DialTimeout types.Duration `json:"dialTimeout,omitempty" toml:",omitempty"`
+	KeepAlive   types.Duration `json:"keepAlive,omitempty" toml:",omitempty"`
+}
+
+// TCPServersTLS holds the TLS configuration used to connect to the servers of a TCP service.
+type TCPServersTLS struct {
+	ServerName         string                     `json:"serverName,omitempty" toml:",omitempty"`
+	InsecureSkipVerify bool                       `json:"insecureSkipVerify,omitempty" toml:",omitempty"`
+	RootCAs            []traefiktls.FileOrContent `json:"rootCAs,omitempty" toml:",omitempty"`
+	Certificates       traefiktls.Certificates    `json:"certificates,omitempty" toml:",omitempty"`
 }
 
 // Mergeable tells if the given service is mergeable.
@@ -136,6 +165,31 @@ type HealthCheck struct {
 	Headers  map[string]string `json:"headers,omitempty" toml:",omitempty"`
 }
 
+// ServersTransport holds the options to configure the communication between Traefik and the servers of a service.
+type ServersTransport struct {
+	ServerName          string                     `json:"serverName,omitempty" toml:",omitempty"`
+	InsecureSkipVerify  bool                       `json:"insecureSkipVerify,omitempty" toml:",omitempty"`
+	RootCAs             []traefiktls.FileOrContent `json:"rootCAs,omitempty" toml:",omitempty"`
+	Certificates        traefiktls.Certificates    `json:"certificates,omitempty" toml:",omitempty"`
+	PeerSANs            []string                   `json:"peerSANs,omitempty" toml:",omitempty"`
+	MaxIdleConnsPerHost int                        `json:"maxIdleConnsPerHost,omitempty" toml:",omitempty"`
+	ForwardingTimeouts  *ForwardingTimeouts        `json:"forwardingTimeouts,omitempty" toml:",omitempty"`
+	DisableHTTP2        bool                       `json:"disableHTTP2,omitempty" toml:",omitempty"`
+}
+
+// ForwardingTimeouts holds the timeouts of the requests forwarded to the servers.
+type ForwardingTimeouts struct {
+	DialTimeout           types.Duration `json:"dialTimeout,omitempty" toml:",omitempty"`
+	ResponseHeaderTimeout types.Duration `json:"responseHeaderTimeout,omitempty" toml:",omitempty"`
+	IdleConnTimeout       types.Duration `json:"idleConnTimeout,omitempty" toml:",omitempty"`
+}
+
+// SetDefaults sets the default values.
+func (f *ForwardingTimeouts) SetDefaults() {
+	f.DialTimeout = types.Duration(30 * time.Second)
+	f.IdleConnTimeout = types.Duration(90 * time.Second)
+}
+
 // CreateTLSConfig creates a TLS config from ClientTLS structures.
 func (clientTLS *ClientTLS) CreateTLSConfig() (*tls.Config, error) {
 	if clientTLS == nil {
@@ -205,6 +259,53 @@ func (clientTLS *ClientTLS) CreateTLSConfig() (*tls.Config, error) {
 	}, nil
 }
 
+// CreateTLSConfig creates a TLS config from TCPServersTLS structures.
+func (serversTLS *TCPServersTLS) CreateTLSConfig() (*tls.Config, error) {
+	if serversTLS == nil {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         serversTLS.ServerName,
+		InsecureSkipVerify: serversTLS.InsecureSkipVerify,
+	}
+
+	if len(serversTLS.RootCAs) > 0 {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		for _, rootCA := range serversTLS.RootCAs {
+			ca, err := rootCA.Read()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read root CA: %v", err)
+			}
+
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+				return nil, fmt.Errorf("failed to parse root CA")
+			}
+		}
+	}
+
+	for _, certificate := range serversTLS.Certificates {
+		cert, err := certificate.CertFile.Read()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS certificate: %v", err)
+		}
+
+		key, err := certificate.KeyFile.Read()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS key: %v", err)
+		}
+
+		keyPair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS keypair: %v", err)
+		}
+
+		tlsConfig.Certificates = append(tlsConfig.Certificates, keyPair)
+	}
+
+	return tlsConfig, nil
+}
+
 // Message holds configuration information exchanged between parts of traefik.
 type Message struct {
 	ProviderName  string
@@ -225,9 +326,10 @@ type Configurations map[string]*Configuration
 
 // HTTPConfiguration FIXME better name?
 type HTTPConfiguration struct {
-	Routers     map[string]*Router     `json:"routers,omitempty" toml:",omitempty"`
-	Middlewares map[string]*Middleware `json:"middlewares,omitempty" toml:",omitempty"`
-	Services    map[string]*Service    `json:"services,omitempty" toml:",omitempty"`
+	Routers           map[string]*Router           `json:"routers,omitempty" toml:",omitempty"`
+	Middlewares       map[string]*Middleware       `json:"middlewares,omitempty" toml:",omitempty"`
+	Services          map[string]*Service          `json:"services,omitempty" toml:",omitempty"`
+	ServersTransports map[string]*ServersTransport `json:"serversTransports,omitempty" toml:",omitempty"`
 }
 
 // TCPConfiguration FIXME better name?
@@ -244,4 +346,23 @@ type Service struct {
 // TCPService holds a tcp service configuration (can only be of one type at the same time).
 type TCPService struct {
 	LoadBalancer *TCPLoadBalancerService `json:"loadbalancer,omitempty" toml:",omitempty,omitzero"`
+	// Weighted cannot be expressed with labels, as they cannot hold a list of services.
+	Weighted *TCPWeightedRoundRobin `json:"weighted,omitempty" toml:",omitempty,omitzero" label:"-"`
+}
+
+// TCPWeightedRoundRobin is a weighted round robin tcp load-balancer of services.
+type TCPWeightedRoundRobin struct {
+	Services []TCPWRRService `json:"services,omitempty" toml:",omitempty"`
+}
+
+// TCPWRRService is a reference to a tcp service load-balanced with weighted round robin.
+type TCPWRRService struct {
+	Name   string `json:"name,omitempty" toml:",omitempty"`
+	Weight *int   `json:"weight,omitempty" toml:",omitempty"`
+}
+
+// SetDefaults Default values for a TCPWRRService.
+func (w *TCPWRRService) SetDefaults() {
+	defaultWeight := 1
+	w.Weight = &defaultWeight
 }
@@ -44,7 +44,7 @@ func Test_getRootFieldNames(t *testing.T) {
 
 func Test_decodeFileToNode_compare(t *testing.T) {
 	nodeToml, err := decodeFileToNode("./fixtures/sample.toml",
-		"Global", "ServersTransport", "EntryPoints", "Providers", "API", "Metrics", "Ping", "Log", "AccessLog", "Tracing", "HostResolver", "ACME")
+		"Global", "ServersTransport", "EntryPoints", "Providers", "API", "Metrics", "Ping", "Log", "AccessLog", "Tracing", "HostResolver", "CertificatesResolvers")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -59,7 +59,7 @@ func Test_decodeFileToNode_compare(t *testing.T) {
 
 func Test_decodeFileToNode_Toml(t *testing.T) {
 	node, err := decodeFileToNode("./fixtures/sample.toml",
-		"Global", "ServersTransport", "EntryPoints", "Providers", "API", "Metrics", "Ping", "Log", "AccessLog", "Tracing", "HostResolver", "ACME")
+		"Global", "ServersTransport", "EntryPoints", "Providers", "API", "Metrics", "Ping", "Log", "AccessLog", "Tracing", "HostResolver", "CertificatesResolvers")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -67,36 +67,6 @@ func Test_decodeFileToNode_Toml(t *testing.T) {
 	expected := &parser.Node{
 		Name: "traefik",
 		Children: []*parser.Node{
-			{Name: "ACME",
-				Children: []*parser.Node{
-					{Name: "ACMELogging", Value: "true"},
-					{Name: "CAServer", Value: "foobar"},
-					{Name: "DNSChallenge", Children: []*parser.Node{
-						{Name: "DelayBeforeCheck", Value: "42"},
-						{Name: "DisablePropagationCheck", Value: "true"},
-						{Name: "Provider", Value: "foobar"},
-						{Name: "Resolvers", Value: "foobar,foobar"},
-					}},
-					{Name: "Domains", Children: []*parser.Node{
-						{Name: "[0]", Children: []*parser.Node{
-							{Name: "Main", Value: "foobar"},
-							{Name: "SANs", Value: "foobar,foobar"},
-						}},
-						{Name: "[1]", Children: []*parser.Node{
-							{Name: "Main", Value: "foobar"},
-							{Name: "SANs", Value: "foobar,foobar"},
-						}},
-					}},
-					{Name: "Email", Value: "foobar"},
-					{Name: "EntryPoint", Value: "foobar"},
-					{Name: "HTTPChallenge", Children: []*parser.Node{
-						{Name: "EntryPoint", Value: "foobar"}}},
-					{Name: "KeyType", Value: "foobar"},
-					{Name: "OnHostRule", Value: "true"},
-					{Name: "Storage", Value: "foobar"},
-					{Name: "TLSChallenge"},
-				},
-			},
 			{Name: "API", Children: []*parser.Node{
 				{Name: "Dashboard", Value: "true"},
 				{Name: "EntryPoint", Value: "foobar"},
@@ -121,6 +91,39 @@ func Test_decodeFileToNode_Toml(t *testing.T) {
 					{Name: "RetryAttempts", Value: "true"},
 					{Name: "StatusCodes", Value: "foobar,foobar"}}},
 				{Name: "Format", Value: "foobar"}}},
+			{Name: "CertificatesResolvers", Children: []*parser.Node{
+				{Name: "CertificateResolver0", Children: []*parser.Node{
+					{Name: "ACME",
+						Children: []*parser.Node{
+							{Name: "ACMELogging", Value: "true"},
+							{Name: "CAServer", Value: "foobar"},
+							{Name: "DNSChallenge", Children: []*parser.Node{
+								{Name: "DelayBeforeCheck", Value: "42"},
+								{Name: "DisablePropagationCheck", Value: "true"},
+								{Name: "Provider", Value: "foobar"},
+								{Name: "Resolvers", Value: "foobar,foobar"},
+							}},
+							{Name: "Domains", Children: []*parser.Node{
+								{Name: "[0]", Children: []*parser.Node{
+									{Name: "Main", Value: "foobar"},
+									{Name: "SANs", Value: "foobar,foobar"},
+								}},
+								{Name: "[1]", Children: []*parser.Node{
+									{Name: "Main", Value: "foobar"},
+									{Name: "SANs", Value: "foobar,foobar"},
+								}},
+							}},
+							{Name: "Email", Value: "foobar"},
+							{Name: "EntryPoint", Value: "foobar"},
+							{Name: "HTTPChallenge", Children: []*parser.Node{
+								{Name: "EntryPoint", Value: "foobar"}}},
+							{Name: "KeyType", Value: "foobar"},
+							{Name: "Storage", Value: "foobar"},
+							{Name: "TLSChallenge"},
+						},
+					},
+				}},
+			}},
 			{Name: "EntryPoints", Children: []*parser.Node{
 				{Name: "EntryPoint0", Children: []*parser.Node{
 					{Name: "Address", Value: "foobar"},
@@ -337,36 +340,6 @@ func Test_decodeFileToNode_Yaml(t *testing.T) {
 	expected := &parser.Node{
 		Name: "traefik",
 		Children: []*parser.Node{
-			{Name: "ACME",
-				Children: []*parser.Node{
-					{Name: "ACMELogging", Value: "true"},
-					{Name: "CAServer", Value: "foobar"},
-					{Name: "DNSChallenge", Children: []*parser.Node{
-						{Name: "DelayBeforeCheck", Value: "42"},
-						{Name: "DisablePropagationCheck", Value: "true"},
-						{Name: "Provider", Value: "foobar"},
-						{Name: "Resolvers", Value: "foobar,foobar"},
-					}},
-					{Name: "Domains", Children: []*parser.Node{
-						{Name: "[0]", Children: []*parser.Node{
-							{Name: "Main", Value: "foobar"},
-							{Name: "SANs", Value: "foobar,foobar"},
-						}},
-						{Name: "[1]", Children: []*parser.Node{
-							{Name: "Main", Value: "foobar"},
-							{Name: "SANs", Value: "foobar,foobar"},
-						}},
-					}},
-					{Name: "Email", Value: "foobar"},
-					{Name: "EntryPoint", Value: "foobar"},
-					{Name: "HTTPChallenge", Children: []*parser.Node{
-						{Name: "EntryPoint", Value: "foobar"}}},
-					{Name: "KeyType", Value: "foobar"},
-					{Name: "OnHostRule", Value: "true"},
-					{Name: "Storage", Value: "foobar"},
-					{Name: "TLSChallenge"},
-				},
-			},
 			{Name: "API", Children: []*parser.Node{
 				{Name: "Dashboard", Value: "true"},
 				{Name: "EntryPoint", Value: "foobar"},
@@ -391,6 +364,39 @@ func Test_decodeFileToNode_Yaml(t *testing.T) {
 					{Name: "RetryAttempts", Value: "true"},
 					{Name: "StatusCodes", Value: "foobar,foobar"}}},
 				{Name: "Format", Value: "foobar"}}},
+			{Name: "CertificatesResolvers", Children: []*parser.Node{
+				{Name: "CertificateResolver0", Children: []*parser.Node{
+					{Name: "ACME",
+						Children: []*parser.Node{
+							{Name: "ACMELogging", Value: "true"},
+							{Name: "CAServer", Value: "foobar"},
+							{Name: "DNSChallenge", Children: []*parser.Node{
+								{Name: "DelayBeforeCheck", Value: "42"},
+								{Name: "DisablePropagationCheck", Value: "true"},
+								{Name: "Provider", Value: "foobar"},
+								{Name: "Resolvers", Value: "foobar,foobar"},
+							}},
+							{Name: "Domains", Children: []*parser.Node{
+								{Name: "[0]", Children: []*parser.Node{
+									{Name: "Main", Value: "foobar"},
+									{Name: "SANs", Value: "foobar,foobar"},
+								}},
+								{Name: "[1]", Children: []*parser.Node{
+									{Name: "Main", Value: "foobar"},
+									{Name: "SANs", Value: "foobar,foobar"},
+								}},
+							}},
+							{Name: "Email", Value: "foobar"},
+							{Name: "EntryPoint", Value: "foobar"},
+							{Name: "HTTPChallenge", Children: []*parser.Node{
+								{Name: "EntryPoint", Value: "foobar"}}},
+							{Name: "KeyType", Value: "foobar"},
+							{Name: "Storage", Value: "foobar"},
+							{Name: "TLSChallenge"},
+						},
+					},
+				}},
+			}},
 			{Name: "EntryPoints", Children: []*parser.Node{
 				{Name: "EntryPoint0", Children: []*parser.Node{
 					{Name: "Address", Value: "foobar"},
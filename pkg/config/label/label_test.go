@@ -79,6 +79,7 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware9.ipwhitelist.sourcerange":                         "foobar, fiibar",
 		"traefik.http.middlewares.Middleware10.maxconn.amount":                                 "42",
 		"traefik.http.middlewares.Middleware10.maxconn.extractorfunc":                          "foobar",
+		"traefik.http.middlewares.Middleware11.passtlsclientcert.chain":                        "true",
 		"traefik.http.middlewares.Middleware11.passtlsclientcert.info.notafter":                "true",
 		"traefik.http.middlewares.Middleware11.passtlsclientcert.info.notbefore":               "true",
 		"traefik.http.middlewares.Middleware11.passtlsclientcert.info.sans":                    "true",
@@ -118,17 +119,19 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware18.stripprefixregex.regex":                         "foobar, fiibar",
 		"traefik.http.middlewares.Middleware19.compress":                                       "true",
 
-		"traefik.http.routers.Router0.entrypoints": "foobar, fiibar",
-		"traefik.http.routers.Router0.middlewares": "foobar, fiibar",
-		"traefik.http.routers.Router0.priority":    "42",
-		"traefik.http.routers.Router0.rule":        "foobar",
-		"traefik.http.routers.Router0.tls":         "true",
-		"traefik.http.routers.Router0.service":     "foobar",
-		"traefik.http.routers.Router1.entrypoints": "foobar, fiibar",
-		"traefik.http.routers.Router1.middlewares": "foobar, fiibar",
-		"traefik.http.routers.Router1.priority":    "42",
-		"traefik.http.routers.Router1.rule":        "foobar",
-		"traefik.http.routers.Router1.service":     "foobar",
+		"traefik.http.routers.Router0.accesslog.enabled":    "false",
+		"traefik.http.routers.Router0.accesslog.samplerate": "0.5",
+		"traefik.http.routers.Router0.entrypoints":          "foobar, fiibar",
+		"traefik.http.routers.Router0.middlewares":          "foobar, fiibar",
+		"traefik.http.routers.Router0.priority":             "42",
+		"traefik.http.routers.Router0.rule":                 "foobar",
+		"traefik.http.routers.Router0.tls":                  "true",
+		"traefik.http.routers.Router0.service":              "foobar",
+		"traefik.http.routers.Router1.entrypoints":          "foobar, fiibar",
+		"traefik.http.routers.Router1.middlewares":          "foobar, fiibar",
+		"traefik.http.routers.Router1.priority":             "42",
+		"traefik.http.routers.Router1.rule":                 "foobar",
+		"traefik.http.routers.Router1.service":              "foobar",
 
 		"traefik.http.services.Service0.loadbalancer.healthcheck.headers.name0":        "foobar",
 		"traefik.http.services.Service0.loadbalancer.healthcheck.headers.name1":        "foobar",
@@ -168,7 +171,11 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.tcp.routers.Router1.service":                                          "foobar",
 		"traefik.tcp.routers.Router1.tls.options":                                      "foo",
 		"traefik.tcp.routers.Router1.tls.passthrough":                                  "false",
+		"traefik.tcp.services.Service0.loadbalancer.dialtimeout":                       "42",
+		"traefik.tcp.services.Service0.loadbalancer.keepalive":                         "42",
 		"traefik.tcp.services.Service0.loadbalancer.server.Port":                       "42",
+		"traefik.tcp.services.Service0.loadbalancer.tls.insecureskipverify":            "true",
+		"traefik.tcp.services.Service0.loadbalancer.tls.servername":                    "foobar",
 		"traefik.tcp.services.Service1.loadbalancer.server.Port":                       "42",
 	}
 
@@ -211,6 +218,12 @@ func TestDecodeConfiguration(t *testing.T) {
 								Port: "42",
 							},
 						},
+						TLS: &config.TCPServersTLS{
+							ServerName:         "foobar",
+							InsecureSkipVerify: true,
+						},
+						DialTimeout: types.Duration(42 * time.Second),
+						KeepAlive:   types.Duration(42 * time.Second),
 					},
 				},
 				"Service1": {
@@ -239,6 +252,10 @@ func TestDecodeConfiguration(t *testing.T) {
 					Rule:     "foobar",
 					Priority: 42,
 					TLS:      &config.RouterTLSConfig{},
+					AccessLog: &config.RouterAccessLog{
+						Enabled:    boolPtr(false),
+						SampleRate: 0.5,
+					},
 				},
 				"Router1": {
 					EntryPoints: []string{
@@ -280,7 +297,8 @@ func TestDecodeConfiguration(t *testing.T) {
 				},
 				"Middleware11": {
 					PassTLSClientCert: &config.PassTLSClientCert{
-						PEM: true,
+						PEM:   true,
+						Chain: true,
 						Info: &config.TLSClientCertificateInfo{
 							NotAfter:  true,
 							NotBefore: true,
@@ -608,6 +626,12 @@ func TestEncodeConfiguration(t *testing.T) {
 								Port: "42",
 							},
 						},
+						TLS: &config.TCPServersTLS{
+							ServerName:         "foobar",
+							InsecureSkipVerify: true,
+						},
+						DialTimeout: types.Duration(42 * time.Nanosecond),
+						KeepAlive:   types.Duration(42 * time.Nanosecond),
 					},
 				},
 				"Service1": {
@@ -636,6 +660,10 @@ func TestEncodeConfiguration(t *testing.T) {
 					Rule:     "foobar",
 					Priority: 42,
 					TLS:      &config.RouterTLSConfig{},
+					AccessLog: &config.RouterAccessLog{
+						Enabled:    boolPtr(false),
+						SampleRate: 0.5,
+					},
 				},
 				"Router1": {
 					EntryPoints: []string{
@@ -677,7 +705,8 @@ func TestEncodeConfiguration(t *testing.T) {
 				},
 				"Middleware11": {
 					PassTLSClientCert: &config.PassTLSClientCert{
-						PEM: true,
+						PEM:   true,
+						Chain: true,
 						Info: &config.TLSClientCertificateInfo{
 							NotAfter:  true,
 							NotBefore: true,
@@ -1033,6 +1062,7 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware9.IPWhiteList.SourceRange":                         "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware10.MaxConn.Amount":                                 "42",
 		"traefik.HTTP.Middlewares.Middleware10.MaxConn.ExtractorFunc":                          "foobar",
+		"traefik.HTTP.Middlewares.Middleware11.PassTLSClientCert.Chain":                        "true",
 		"traefik.HTTP.Middlewares.Middleware11.PassTLSClientCert.Info.NotAfter":                "true",
 		"traefik.HTTP.Middlewares.Middleware11.PassTLSClientCert.Info.NotBefore":               "true",
 		"traefik.HTTP.Middlewares.Middleware11.PassTLSClientCert.Info.Sans":                    "true",
@@ -1072,17 +1102,19 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware18.StripPrefixRegex.Regex":                         "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware19.Compress":                                       "true",
 
-		"traefik.HTTP.Routers.Router0.EntryPoints": "foobar, fiibar",
-		"traefik.HTTP.Routers.Router0.Middlewares": "foobar, fiibar",
-		"traefik.HTTP.Routers.Router0.Priority":    "42",
-		"traefik.HTTP.Routers.Router0.Rule":        "foobar",
-		"traefik.HTTP.Routers.Router0.Service":     "foobar",
-		"traefik.HTTP.Routers.Router0.TLS":         "true",
-		"traefik.HTTP.Routers.Router1.EntryPoints": "foobar, fiibar",
-		"traefik.HTTP.Routers.Router1.Middlewares": "foobar, fiibar",
-		"traefik.HTTP.Routers.Router1.Priority":    "42",
-		"traefik.HTTP.Routers.Router1.Rule":        "foobar",
-		"traefik.HTTP.Routers.Router1.Service":     "foobar",
+		"traefik.HTTP.Routers.Router0.AccessLog.Enabled":    "false",
+		"traefik.HTTP.Routers.Router0.AccessLog.SampleRate": "0.500000",
+		"traefik.HTTP.Routers.Router0.EntryPoints":          "foobar, fiibar",
+		"traefik.HTTP.Routers.Router0.Middlewares":          "foobar, fiibar",
+		"traefik.HTTP.Routers.Router0.Priority":             "42",
+		"traefik.HTTP.Routers.Router0.Rule":                 "foobar",
+		"traefik.HTTP.Routers.Router0.Service":              "foobar",
+		"traefik.HTTP.Routers.Router0.TLS":                  "true",
+		"traefik.HTTP.Routers.Router1.EntryPoints":          "foobar, fiibar",
+		"traefik.HTTP.Routers.Router1.Middlewares":          "foobar, fiibar",
+		"traefik.HTTP.Routers.Router1.Priority":             "42",
+		"traefik.HTTP.Routers.Router1.Rule":                 "foobar",
+		"traefik.HTTP.Routers.Router1.Service":              "foobar",
 
 		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Headers.name1":        "foobar",
 		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Hostname":             "foobar",
@@ -1112,18 +1144,24 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Services.Service1.LoadBalancer.server.Scheme":                    "foobar",
 		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Headers.name0":        "foobar",
 
-		"traefik.TCP.Routers.Router0.Rule":                       "foobar",
-		"traefik.TCP.Routers.Router0.EntryPoints":                "foobar, fiibar",
-		"traefik.TCP.Routers.Router0.Service":                    "foobar",
-		"traefik.TCP.Routers.Router0.TLS.Passthrough":            "false",
-		"traefik.TCP.Routers.Router0.TLS.Options":                "foo",
-		"traefik.TCP.Routers.Router1.Rule":                       "foobar",
-		"traefik.TCP.Routers.Router1.EntryPoints":                "foobar, fiibar",
-		"traefik.TCP.Routers.Router1.Service":                    "foobar",
-		"traefik.TCP.Routers.Router1.TLS.Passthrough":            "false",
-		"traefik.TCP.Routers.Router1.TLS.Options":                "foo",
-		"traefik.TCP.Services.Service0.LoadBalancer.server.Port": "42",
-		"traefik.TCP.Services.Service1.LoadBalancer.server.Port": "42",
+		"traefik.TCP.Routers.Router0.Rule":                                  "foobar",
+		"traefik.TCP.Routers.Router0.EntryPoints":                           "foobar, fiibar",
+		"traefik.TCP.Routers.Router0.Service":                               "foobar",
+		"traefik.TCP.Routers.Router0.TLS.Passthrough":                       "false",
+		"traefik.TCP.Routers.Router0.TLS.Options":                           "foo",
+		"traefik.TCP.Routers.Router1.Rule":                                  "foobar",
+		"traefik.TCP.Routers.Router1.EntryPoints":                           "foobar, fiibar",
+		"traefik.TCP.Routers.Router1.Service":                               "foobar",
+		"traefik.TCP.Routers.Router1.TLS.Passthrough":                       "false",
+		"traefik.TCP.Routers.Router1.TLS.Options":                           "foo",
+		"traefik.TCP.Services.Service0.LoadBalancer.DialTimeout":            "42",
+		"traefik.TCP.Services.Service0.LoadBalancer.KeepAlive":              "42",
+		"traefik.TCP.Services.Service0.LoadBalancer.server.Port":            "42",
+		"traefik.TCP.Services.Service0.LoadBalancer.TLS.InsecureSkipVerify": "true",
+		"traefik.TCP.Services.Service0.LoadBalancer.TLS.ServerName":         "foobar",
+		"traefik.TCP.Services.Service1.LoadBalancer.DialTimeout":            "0",
+		"traefik.TCP.Services.Service1.LoadBalancer.KeepAlive":              "0",
+		"traefik.TCP.Services.Service1.LoadBalancer.server.Port":            "42",
 	}
 
 	for key, val := range expected {
@@ -1139,3 +1177,7 @@ func TestEncodeConfiguration(t *testing.T) {
 	}
 	assert.Equal(t, expected, labels)
 }
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -265,8 +265,9 @@ func (m *MaxConn) SetDefaults() {
 
 // PassTLSClientCert holds the TLS client cert headers configuration.
 type PassTLSClientCert struct {
-	PEM  bool                      `description:"Enable header with escaped client pem" json:"pem"`
-	Info *TLSClientCertificateInfo `description:"Enable header with configured client cert info" json:"info,omitempty"`
+	PEM   bool                      `description:"Enable header with escaped client pem" json:"pem"`
+	Info  *TLSClientCertificateInfo `description:"Enable header with configured client cert info" json:"info,omitempty"`
+	Chain bool                      `description:"Pass the verified chain up to the trusted root instead of the certificates sent by the client" json:"chain,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -1,7 +1,6 @@
 package static
 
 import (
-	"errors"
 	"strings"
 	"time"
 
@@ -16,10 +15,12 @@ import (
 	"github.com/containous/traefik/pkg/provider/rancher"
 	"github.com/containous/traefik/pkg/provider/rest"
 	"github.com/containous/traefik/pkg/tls"
+	"github.com/containous/traefik/pkg/tracing"
 	"github.com/containous/traefik/pkg/tracing/datadog"
 	"github.com/containous/traefik/pkg/tracing/haystack"
 	"github.com/containous/traefik/pkg/tracing/instana"
 	"github.com/containous/traefik/pkg/tracing/jaeger"
+	"github.com/containous/traefik/pkg/tracing/opentelemetry"
 	"github.com/containous/traefik/pkg/tracing/zipkin"
 	"github.com/containous/traefik/pkg/types"
 	assetfs "github.com/elazarl/go-bindata-assetfs"
@@ -61,6 +62,15 @@ type Configuration struct {
 
 	HostResolver *types.HostResolverConfig `description:"Enable CNAME Flattening." export:"true" label:"allowEmpty"`
 
+	CertificatesResolvers map[string]CertificateResolver `description:"Certificates resolvers configuration." export:"true"`
+
+	OCSP *tls.OCSPConfig `description:"OCSP stapling configuration." export:"true" label:"allowEmpty"`
+
+	InternalCA *tls.InternalCAConfig `description:"Certificates issued on demand by an internal certificate authority." export:"true"`
+}
+
+// CertificateResolver contains the configuration for the different types of certificates resolver.
+type CertificateResolver struct {
 	ACME *acmeprovider.Configuration `description:"Enable ACME (Let's Encrypt): automatic SSL." export:"true"`
 }
 
@@ -130,14 +140,19 @@ func (a *LifeCycle) SetDefaults() {
 
 // Tracing holds the tracing configuration.
 type Tracing struct {
-	Backend       string           `description:"Selects the tracking backend ('jaeger','zipkin','datadog','instana')." export:"true"`
-	ServiceName   string           `description:"Set the name for this service." export:"true"`
-	SpanNameLimit int              `description:"Set the maximum character limit for Span names (default 0 = no limit)." export:"true"`
-	Jaeger        *jaeger.Config   `description:"Settings for jaeger." label:"allowEmpty"`
-	Zipkin        *zipkin.Config   `description:"Settings for zipkin." label:"allowEmpty"`
-	DataDog       *datadog.Config  `description:"Settings for DataDog." label:"allowEmpty"`
-	Instana       *instana.Config  `description:"Settings for Instana." label:"allowEmpty"`
-	Haystack      *haystack.Config `description:"Settings for Haystack." label:"allowEmpty"`
+	Backend       string                `description:"Selects the tracking backend ('jaeger','zipkin','datadog','instana','opentelemetry')." export:"true"`
+	ServiceName   string                `description:"Set the name for this service." export:"true"`
+	SpanNameLimit int                   `description:"Set the maximum character limit for Span names (default 0 = no limit)." export:"true"`
+	Jaeger        *jaeger.Config        `description:"Settings for jaeger." label:"allowEmpty"`
+	Zipkin        *zipkin.Config        `description:"Settings for zipkin." label:"allowEmpty"`
+	DataDog       *datadog.Config       `description:"Settings for DataDog." label:"allowEmpty"`
+	Instana       *instana.Config       `description:"Settings for Instana." label:"allowEmpty"`
+	Haystack      *haystack.Config      `description:"Settings for Haystack." label:"allowEmpty"`
+	OpenTelemetry *opentelemetry.Config `description:"Settings for OpenTelemetry." label:"allowEmpty"`
+	Propagation   []string              `description:"Formats used to propagate the trace context instead of the native one of the backend ('tracecontext','b3','b3multi','jaeger')." export:"true"`
+	HeaderTags    map[string]string     `description:"Span tags set from the request headers: the keys are the tag names and the values the header names." export:"true"`
+	Sampling      *tracing.Sampling     `description:"Sampling of the requests starting a trace." export:"true" label:"allowEmpty"`
+	ExcludedPaths []string              `description:"Paths of the requests that are not traced (e.g. /ping)." export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -228,6 +243,10 @@ func (c *Configuration) initTracing() {
 				log.Warn("Instana configuration will be ignored")
 				c.Tracing.Instana = nil
 			}
+			if c.Tracing.OpenTelemetry != nil {
+				log.Warn("OpenTelemetry configuration will be ignored")
+				c.Tracing.OpenTelemetry = nil
+			}
 		case zipkin.Name:
 			if c.Tracing.Zipkin == nil {
 				c.Tracing.Zipkin = &zipkin.Config{
@@ -250,6 +269,10 @@ func (c *Configuration) initTracing() {
 				log.Warn("Instana configuration will be ignored")
 				c.Tracing.Instana = nil
 			}
+			if c.Tracing.OpenTelemetry != nil {
+				log.Warn("OpenTelemetry configuration will be ignored")
+				c.Tracing.OpenTelemetry = nil
+			}
 		case datadog.Name:
 			if c.Tracing.DataDog == nil {
 				c.Tracing.DataDog = &datadog.Config{
@@ -270,6 +293,10 @@ func (c *Configuration) initTracing() {
 				log.Warn("Instana configuration will be ignored")
 				c.Tracing.Instana = nil
 			}
+			if c.Tracing.OpenTelemetry != nil {
+				log.Warn("OpenTelemetry configuration will be ignored")
+				c.Tracing.OpenTelemetry = nil
+			}
 		case instana.Name:
 			if c.Tracing.Instana == nil {
 				c.Tracing.Instana = &instana.Config{
@@ -290,6 +317,32 @@ func (c *Configuration) initTracing() {
 				log.Warn("DataDog configuration will be ignored")
 				c.Tracing.DataDog = nil
 			}
+			if c.Tracing.OpenTelemetry != nil {
+				log.Warn("OpenTelemetry configuration will be ignored")
+				c.Tracing.OpenTelemetry = nil
+			}
+		case opentelemetry.Name:
+			if c.Tracing.OpenTelemetry == nil {
+				c.Tracing.OpenTelemetry = &opentelemetry.Config{
+					Address: "localhost:4318",
+				}
+			}
+			if c.Tracing.Jaeger != nil {
+				log.Warn("Jaeger configuration will be ignored")
+				c.Tracing.Jaeger = nil
+			}
+			if c.Tracing.Zipkin != nil {
+				log.Warn("Zipkin configuration will be ignored")
+				c.Tracing.Zipkin = nil
+			}
+			if c.Tracing.DataDog != nil {
+				log.Warn("DataDog configuration will be ignored")
+				c.Tracing.DataDog = nil
+			}
+			if c.Tracing.Instana != nil {
+				log.Warn("Instana configuration will be ignored")
+				c.Tracing.Instana = nil
+			}
 		default:
 			log.Warnf("Unknown tracer %q", c.Tracing.Backend)
 			return
@@ -299,43 +352,38 @@ func (c *Configuration) initTracing() {
 
 // FIXME handle on new configuration ACME struct
 func (c *Configuration) initACMEProvider() {
-	if c.ACME != nil {
-		c.ACME.CAServer = getSafeACMECAServer(c.ACME.CAServer)
+	for _, resolver := range c.CertificatesResolvers {
+		if resolver.ACME == nil {
+			continue
+		}
+
+		resolver.ACME.CAServer = getSafeACMECAServer(resolver.ACME.CAServer)
 
-		if c.ACME.DNSChallenge != nil && c.ACME.HTTPChallenge != nil {
+		if resolver.ACME.DNSChallenge != nil && resolver.ACME.HTTPChallenge != nil {
 			log.Warn("Unable to use DNS challenge and HTTP challenge at the same time. Fallback to DNS challenge.")
-			c.ACME.HTTPChallenge = nil
+			resolver.ACME.HTTPChallenge = nil
 		}
 
-		if c.ACME.DNSChallenge != nil && c.ACME.TLSChallenge != nil {
+		if resolver.ACME.DNSChallenge != nil && resolver.ACME.TLSChallenge != nil {
 			log.Warn("Unable to use DNS challenge and TLS challenge at the same time. Fallback to DNS challenge.")
-			c.ACME.TLSChallenge = nil
+			resolver.ACME.TLSChallenge = nil
 		}
 
-		if c.ACME.HTTPChallenge != nil && c.ACME.TLSChallenge != nil {
+		if resolver.ACME.HTTPChallenge != nil && resolver.ACME.TLSChallenge != nil {
 			log.Warn("Unable to use HTTP challenge and TLS challenge at the same time. Fallback to TLS challenge.")
-			c.ACME.HTTPChallenge = nil
-		}
-	}
-}
-
-// InitACMEProvider create an acme provider from the ACME part of globalConfiguration
-func (c *Configuration) InitACMEProvider() (*acmeprovider.Provider, error) {
-	if c.ACME != nil {
-		if len(c.ACME.Storage) == 0 {
-			return nil, errors.New("unable to initialize ACME provider with no storage location for the certificates")
+			resolver.ACME.HTTPChallenge = nil
 		}
-		return &acmeprovider.Provider{
-			Configuration: c.ACME,
-		}, nil
 	}
-	return nil, nil
 }
 
 // ValidateConfiguration validate that configuration is coherent
 func (c *Configuration) ValidateConfiguration() {
-	if c.ACME != nil {
-		for _, domain := range c.ACME.Domains {
+	for _, resolver := range c.CertificatesResolvers {
+		if resolver.ACME == nil {
+			continue
+		}
+
+		for _, domain := range resolver.ACME.Domains {
 			if domain.Main != dns01.UnFqdn(domain.Main) {
 				log.Warnf("FQDN detected, please remove the trailing dot: %s", domain.Main)
 			}
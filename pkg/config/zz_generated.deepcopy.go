@@ -643,6 +643,27 @@ func (in *Retry) DeepCopy() *Retry {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouterAccessLog) DeepCopyInto(out *RouterAccessLog) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouterAccessLog.
+func (in *RouterAccessLog) DeepCopy() *RouterAccessLog {
+	if in == nil {
+		return nil
+	}
+	out := new(RouterAccessLog)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StripPrefix) DeepCopyInto(out *StripPrefix) {
 	*out = *in
@@ -20,18 +20,32 @@ var datadogTicker *time.Ticker
 
 // Metric names consistent with https://github.com/DataDog/integrations-extras/pull/64
 const (
-	ddMetricsBackendReqsName      = "backend.request.total"
-	ddMetricsBackendLatencyName   = "backend.request.duration"
-	ddRetriesTotalName            = "backend.retries.total"
-	ddConfigReloadsName           = "config.reload.total"
-	ddConfigReloadsFailureTagName = "failure"
-	ddLastConfigReloadSuccessName = "config.reload.lastSuccessTimestamp"
-	ddLastConfigReloadFailureName = "config.reload.lastFailureTimestamp"
-	ddEntrypointReqsName          = "entrypoint.request.total"
-	ddEntrypointReqDurationName   = "entrypoint.request.duration"
-	ddEntrypointOpenConnsName     = "entrypoint.connections.open"
-	ddOpenConnsName               = "backend.connections.open"
-	ddServerUpName                = "backend.server.up"
+	ddMetricsBackendReqsName        = "backend.request.total"
+	ddMetricsBackendLatencyName     = "backend.request.duration"
+	ddRetriesTotalName              = "backend.retries.total"
+	ddConfigReloadsName             = "config.reload.total"
+	ddConfigReloadsFailureTagName   = "failure"
+	ddLastConfigReloadSuccessName   = "config.reload.lastSuccessTimestamp"
+	ddLastConfigReloadFailureName   = "config.reload.lastFailureTimestamp"
+	ddTLSCertsNotAfterTimestamp     = "tls.certs.notAfterTimestamp"
+	ddEntrypointReqsName            = "entrypoint.request.total"
+	ddEntrypointReqDurationName     = "entrypoint.request.duration"
+	ddEntrypointOpenConnsName       = "entrypoint.connections.open"
+	ddRouterReqsName                = "router.request.total"
+	ddRouterReqDurationName         = "router.request.duration"
+	ddOpenConnsName                 = "backend.connections.open"
+	ddServerUpName                  = "backend.server.up"
+	ddTCPEntrypointConnsOpenedName  = "tcp.entrypoint.connections.opened.total"
+	ddTCPEntrypointConnsClosedName  = "tcp.entrypoint.connections.closed.total"
+	ddTCPEntrypointConnDurationName = "tcp.entrypoint.connection.duration"
+	ddTCPEntrypointBytesName        = "tcp.entrypoint.bytes.total"
+	ddTCPServiceConnsOpenedName     = "tcp.service.connections.opened.total"
+	ddTCPServiceConnsClosedName     = "tcp.service.connections.closed.total"
+	ddTCPServiceConnDurationName    = "tcp.service.connection.duration"
+	ddTCPServiceBytesName           = "tcp.service.bytes.total"
+	ddTCPServiceDialFailuresName    = "tcp.service.dial.failures.total"
+	ddAccessLogDroppedName          = "accesslog.dropped.total"
+	ddAccessLogSampledName          = "accesslog.sampled.total"
 )
 
 // RegisterDatadog registers the metrics pusher if this didn't happen yet and creates a datadog Registry instance.
@@ -41,19 +55,33 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 	}
 
 	registry := &standardRegistry{
-		enabled:                        true,
-		configReloadsCounter:           datadogClient.NewCounter(ddConfigReloadsName, 1.0),
-		configReloadsFailureCounter:    datadogClient.NewCounter(ddConfigReloadsName, 1.0).With(ddConfigReloadsFailureTagName, "true"),
-		lastConfigReloadSuccessGauge:   datadogClient.NewGauge(ddLastConfigReloadSuccessName),
-		lastConfigReloadFailureGauge:   datadogClient.NewGauge(ddLastConfigReloadFailureName),
-		entrypointReqsCounter:          datadogClient.NewCounter(ddEntrypointReqsName, 1.0),
-		entrypointReqDurationHistogram: datadogClient.NewHistogram(ddEntrypointReqDurationName, 1.0),
-		entrypointOpenConnsGauge:       datadogClient.NewGauge(ddEntrypointOpenConnsName),
-		backendReqsCounter:             datadogClient.NewCounter(ddMetricsBackendReqsName, 1.0),
-		backendReqDurationHistogram:    datadogClient.NewHistogram(ddMetricsBackendLatencyName, 1.0),
-		backendRetriesCounter:          datadogClient.NewCounter(ddRetriesTotalName, 1.0),
-		backendOpenConnsGauge:          datadogClient.NewGauge(ddOpenConnsName),
-		backendServerUpGauge:           datadogClient.NewGauge(ddServerUpName),
+		enabled:                            true,
+		configReloadsCounter:               datadogClient.NewCounter(ddConfigReloadsName, 1.0),
+		configReloadsFailureCounter:        datadogClient.NewCounter(ddConfigReloadsName, 1.0).With(ddConfigReloadsFailureTagName, "true"),
+		lastConfigReloadSuccessGauge:       datadogClient.NewGauge(ddLastConfigReloadSuccessName),
+		lastConfigReloadFailureGauge:       datadogClient.NewGauge(ddLastConfigReloadFailureName),
+		tlsCertsNotAfterTimestampGauge:     datadogClient.NewGauge(ddTLSCertsNotAfterTimestamp),
+		entrypointReqsCounter:              datadogClient.NewCounter(ddEntrypointReqsName, 1.0),
+		entrypointReqDurationHistogram:     datadogClient.NewHistogram(ddEntrypointReqDurationName, 1.0),
+		entrypointOpenConnsGauge:           datadogClient.NewGauge(ddEntrypointOpenConnsName),
+		routerReqsCounter:                  datadogClient.NewCounter(ddRouterReqsName, 1.0),
+		routerReqDurationHistogram:         datadogClient.NewHistogram(ddRouterReqDurationName, 1.0),
+		backendReqsCounter:                 datadogClient.NewCounter(ddMetricsBackendReqsName, 1.0),
+		backendReqDurationHistogram:        datadogClient.NewHistogram(ddMetricsBackendLatencyName, 1.0),
+		backendRetriesCounter:              datadogClient.NewCounter(ddRetriesTotalName, 1.0),
+		backendOpenConnsGauge:              datadogClient.NewGauge(ddOpenConnsName),
+		backendServerUpGauge:               datadogClient.NewGauge(ddServerUpName),
+		tcpEntrypointConnsOpenedCounter:    datadogClient.NewCounter(ddTCPEntrypointConnsOpenedName, 1.0),
+		tcpEntrypointConnsClosedCounter:    datadogClient.NewCounter(ddTCPEntrypointConnsClosedName, 1.0),
+		tcpEntrypointConnDurationHistogram: datadogClient.NewHistogram(ddTCPEntrypointConnDurationName, 1.0),
+		tcpEntrypointBytesCounter:          datadogClient.NewCounter(ddTCPEntrypointBytesName, 1.0),
+		tcpServiceConnsOpenedCounter:       datadogClient.NewCounter(ddTCPServiceConnsOpenedName, 1.0),
+		tcpServiceConnsClosedCounter:       datadogClient.NewCounter(ddTCPServiceConnsClosedName, 1.0),
+		tcpServiceConnDurationHistogram:    datadogClient.NewHistogram(ddTCPServiceConnDurationName, 1.0),
+		tcpServiceBytesCounter:             datadogClient.NewCounter(ddTCPServiceBytesName, 1.0),
+		tcpServiceDialFailuresCounter:      datadogClient.NewCounter(ddTCPServiceDialFailuresName, 1.0),
+		accessLogDroppedCounter:            datadogClient.NewCounter(ddAccessLogDroppedName, 1.0),
+		accessLogSampledCounter:            datadogClient.NewCounter(ddAccessLogSampledName, 1.0),
 	}
 
 	return registry
@@ -26,18 +26,32 @@ type influxDBWriter struct {
 var influxDBTicker *time.Ticker
 
 const (
-	influxDBMetricsBackendReqsName      = "traefik.backend.requests.total"
-	influxDBMetricsBackendLatencyName   = "traefik.backend.request.duration"
-	influxDBRetriesTotalName            = "traefik.backend.retries.total"
-	influxDBConfigReloadsName           = "traefik.config.reload.total"
-	influxDBConfigReloadsFailureName    = influxDBConfigReloadsName + ".failure"
-	influxDBLastConfigReloadSuccessName = "traefik.config.reload.lastSuccessTimestamp"
-	influxDBLastConfigReloadFailureName = "traefik.config.reload.lastFailureTimestamp"
-	influxDBEntrypointReqsName          = "traefik.entrypoint.requests.total"
-	influxDBEntrypointReqDurationName   = "traefik.entrypoint.request.duration"
-	influxDBEntrypointOpenConnsName     = "traefik.entrypoint.connections.open"
-	influxDBOpenConnsName               = "traefik.backend.connections.open"
-	influxDBServerUpName                = "traefik.backend.server.up"
+	influxDBMetricsBackendReqsName        = "traefik.backend.requests.total"
+	influxDBMetricsBackendLatencyName     = "traefik.backend.request.duration"
+	influxDBRetriesTotalName              = "traefik.backend.retries.total"
+	influxDBConfigReloadsName             = "traefik.config.reload.total"
+	influxDBConfigReloadsFailureName      = influxDBConfigReloadsName + ".failure"
+	influxDBLastConfigReloadSuccessName   = "traefik.config.reload.lastSuccessTimestamp"
+	influxDBLastConfigReloadFailureName   = "traefik.config.reload.lastFailureTimestamp"
+	influxDBTLSCertsNotAfterTimestamp     = "traefik.tls.certs.notAfterTimestamp"
+	influxDBEntrypointReqsName            = "traefik.entrypoint.requests.total"
+	influxDBEntrypointReqDurationName     = "traefik.entrypoint.request.duration"
+	influxDBEntrypointOpenConnsName       = "traefik.entrypoint.connections.open"
+	influxDBRouterReqsName                = "traefik.router.requests.total"
+	influxDBRouterReqDurationName         = "traefik.router.request.duration"
+	influxDBOpenConnsName                 = "traefik.backend.connections.open"
+	influxDBServerUpName                  = "traefik.backend.server.up"
+	influxDBTCPEntrypointConnsOpenedName  = "traefik.tcp.entrypoint.connections.opened.total"
+	influxDBTCPEntrypointConnsClosedName  = "traefik.tcp.entrypoint.connections.closed.total"
+	influxDBTCPEntrypointConnDurationName = "traefik.tcp.entrypoint.connection.duration"
+	influxDBTCPEntrypointBytesName        = "traefik.tcp.entrypoint.bytes.total"
+	influxDBTCPServiceConnsOpenedName     = "traefik.tcp.service.connections.opened.total"
+	influxDBTCPServiceConnsClosedName     = "traefik.tcp.service.connections.closed.total"
+	influxDBTCPServiceConnDurationName    = "traefik.tcp.service.connection.duration"
+	influxDBTCPServiceBytesName           = "traefik.tcp.service.bytes.total"
+	influxDBTCPServiceDialFailuresName    = "traefik.tcp.service.dial.failures.total"
+	influxDBAccessLogDroppedName          = "traefik.accesslog.dropped.total"
+	influxDBAccessLogSampledName          = "traefik.accesslog.sampled.total"
 )
 
 const (
@@ -55,19 +69,33 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 	}
 
 	return &standardRegistry{
-		enabled:                        true,
-		configReloadsCounter:           influxDBClient.NewCounter(influxDBConfigReloadsName),
-		configReloadsFailureCounter:    influxDBClient.NewCounter(influxDBConfigReloadsFailureName),
-		lastConfigReloadSuccessGauge:   influxDBClient.NewGauge(influxDBLastConfigReloadSuccessName),
-		lastConfigReloadFailureGauge:   influxDBClient.NewGauge(influxDBLastConfigReloadFailureName),
-		entrypointReqsCounter:          influxDBClient.NewCounter(influxDBEntrypointReqsName),
-		entrypointReqDurationHistogram: influxDBClient.NewHistogram(influxDBEntrypointReqDurationName),
-		entrypointOpenConnsGauge:       influxDBClient.NewGauge(influxDBEntrypointOpenConnsName),
-		backendReqsCounter:             influxDBClient.NewCounter(influxDBMetricsBackendReqsName),
-		backendReqDurationHistogram:    influxDBClient.NewHistogram(influxDBMetricsBackendLatencyName),
-		backendRetriesCounter:          influxDBClient.NewCounter(influxDBRetriesTotalName),
-		backendOpenConnsGauge:          influxDBClient.NewGauge(influxDBOpenConnsName),
-		backendServerUpGauge:           influxDBClient.NewGauge(influxDBServerUpName),
+		enabled:                            true,
+		configReloadsCounter:               influxDBClient.NewCounter(influxDBConfigReloadsName),
+		configReloadsFailureCounter:        influxDBClient.NewCounter(influxDBConfigReloadsFailureName),
+		lastConfigReloadSuccessGauge:       influxDBClient.NewGauge(influxDBLastConfigReloadSuccessName),
+		lastConfigReloadFailureGauge:       influxDBClient.NewGauge(influxDBLastConfigReloadFailureName),
+		tlsCertsNotAfterTimestampGauge:     influxDBClient.NewGauge(influxDBTLSCertsNotAfterTimestamp),
+		entrypointReqsCounter:              influxDBClient.NewCounter(influxDBEntrypointReqsName),
+		entrypointReqDurationHistogram:     influxDBClient.NewHistogram(influxDBEntrypointReqDurationName),
+		entrypointOpenConnsGauge:           influxDBClient.NewGauge(influxDBEntrypointOpenConnsName),
+		routerReqsCounter:                  influxDBClient.NewCounter(influxDBRouterReqsName),
+		routerReqDurationHistogram:         influxDBClient.NewHistogram(influxDBRouterReqDurationName),
+		backendReqsCounter:                 influxDBClient.NewCounter(influxDBMetricsBackendReqsName),
+		backendReqDurationHistogram:        influxDBClient.NewHistogram(influxDBMetricsBackendLatencyName),
+		backendRetriesCounter:              influxDBClient.NewCounter(influxDBRetriesTotalName),
+		backendOpenConnsGauge:              influxDBClient.NewGauge(influxDBOpenConnsName),
+		backendServerUpGauge:               influxDBClient.NewGauge(influxDBServerUpName),
+		tcpEntrypointConnsOpenedCounter:    influxDBClient.NewCounter(influxDBTCPEntrypointConnsOpenedName),
+		tcpEntrypointConnsClosedCounter:    influxDBClient.NewCounter(influxDBTCPEntrypointConnsClosedName),
+		tcpEntrypointConnDurationHistogram: influxDBClient.NewHistogram(influxDBTCPEntrypointConnDurationName),
+		tcpEntrypointBytesCounter:          influxDBClient.NewCounter(influxDBTCPEntrypointBytesName),
+		tcpServiceConnsOpenedCounter:       influxDBClient.NewCounter(influxDBTCPServiceConnsOpenedName),
+		tcpServiceConnsClosedCounter:       influxDBClient.NewCounter(influxDBTCPServiceConnsClosedName),
+		tcpServiceConnDurationHistogram:    influxDBClient.NewHistogram(influxDBTCPServiceConnDurationName),
+		tcpServiceBytesCounter:             influxDBClient.NewCounter(influxDBTCPServiceBytesName),
+		tcpServiceDialFailuresCounter:      influxDBClient.NewCounter(influxDBTCPServiceDialFailuresName),
+		accessLogDroppedCounter:            influxDBClient.NewCounter(influxDBAccessLogDroppedName),
+		accessLogSampledCounter:            influxDBClient.NewCounter(influxDBAccessLogSampledName),
 	}
 }
 
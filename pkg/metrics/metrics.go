@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"net/http"
+
 	"github.com/go-kit/kit/metrics"
 	"github.com/go-kit/kit/metrics/multi"
 )
@@ -16,17 +18,44 @@ type Registry interface {
 	LastConfigReloadSuccessGauge() metrics.Gauge
 	LastConfigReloadFailureGauge() metrics.Gauge
 
+	// TLS
+	TLSCertsNotAfterTimestampGauge() metrics.Gauge
+
 	// entry point metrics
 	EntrypointReqsCounter() metrics.Counter
 	EntrypointReqDurationHistogram() metrics.Histogram
 	EntrypointOpenConnsGauge() metrics.Gauge
 
+	// router metrics
+	RouterReqsCounter() metrics.Counter
+	RouterReqDurationHistogram() metrics.Histogram
+
+	// HeaderLabels returns the names and values of the labels added to the router metrics from the request headers.
+	HeaderLabels(header http.Header) []string
+
 	// backend metrics
 	BackendReqsCounter() metrics.Counter
 	BackendReqDurationHistogram() metrics.Histogram
 	BackendOpenConnsGauge() metrics.Gauge
 	BackendRetriesCounter() metrics.Counter
 	BackendServerUpGauge() metrics.Gauge
+
+	// TCP entry point metrics
+	TCPEntrypointConnsOpenedCounter() metrics.Counter
+	TCPEntrypointConnsClosedCounter() metrics.Counter
+	TCPEntrypointConnDurationHistogram() metrics.Histogram
+	TCPEntrypointBytesCounter() metrics.Counter
+
+	// TCP service metrics
+	TCPServiceConnsOpenedCounter() metrics.Counter
+	TCPServiceConnsClosedCounter() metrics.Counter
+	TCPServiceConnDurationHistogram() metrics.Histogram
+	TCPServiceBytesCounter() metrics.Counter
+	TCPServiceDialFailuresCounter() metrics.Counter
+
+	// access log metrics
+	AccessLogDroppedCounter() metrics.Counter
+	AccessLogSampledCounter() metrics.Counter
 }
 
 // NewVoidRegistry is a noop implementation of metrics.Registry.
@@ -43,14 +72,28 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var configReloadsFailureCounter []metrics.Counter
 	var lastConfigReloadSuccessGauge []metrics.Gauge
 	var lastConfigReloadFailureGauge []metrics.Gauge
+	var tlsCertsNotAfterTimestampGauge []metrics.Gauge
 	var entrypointReqsCounter []metrics.Counter
 	var entrypointReqDurationHistogram []metrics.Histogram
 	var entrypointOpenConnsGauge []metrics.Gauge
+	var routerReqsCounter []metrics.Counter
+	var routerReqDurationHistogram []metrics.Histogram
 	var backendReqsCounter []metrics.Counter
 	var backendReqDurationHistogram []metrics.Histogram
 	var backendOpenConnsGauge []metrics.Gauge
 	var backendRetriesCounter []metrics.Counter
 	var backendServerUpGauge []metrics.Gauge
+	var tcpEntrypointConnsOpenedCounter []metrics.Counter
+	var tcpEntrypointConnsClosedCounter []metrics.Counter
+	var tcpEntrypointConnDurationHistogram []metrics.Histogram
+	var tcpEntrypointBytesCounter []metrics.Counter
+	var tcpServiceConnsOpenedCounter []metrics.Counter
+	var tcpServiceConnsClosedCounter []metrics.Counter
+	var tcpServiceConnDurationHistogram []metrics.Histogram
+	var tcpServiceBytesCounter []metrics.Counter
+	var tcpServiceDialFailuresCounter []metrics.Counter
+	var accessLogDroppedCounter []metrics.Counter
+	var accessLogSampledCounter []metrics.Counter
 
 	for _, r := range registries {
 		if r.ConfigReloadsCounter() != nil {
@@ -65,6 +108,9 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.LastConfigReloadFailureGauge() != nil {
 			lastConfigReloadFailureGauge = append(lastConfigReloadFailureGauge, r.LastConfigReloadFailureGauge())
 		}
+		if r.TLSCertsNotAfterTimestampGauge() != nil {
+			tlsCertsNotAfterTimestampGauge = append(tlsCertsNotAfterTimestampGauge, r.TLSCertsNotAfterTimestampGauge())
+		}
 		if r.EntrypointReqsCounter() != nil {
 			entrypointReqsCounter = append(entrypointReqsCounter, r.EntrypointReqsCounter())
 		}
@@ -74,6 +120,12 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.EntrypointOpenConnsGauge() != nil {
 			entrypointOpenConnsGauge = append(entrypointOpenConnsGauge, r.EntrypointOpenConnsGauge())
 		}
+		if r.RouterReqsCounter() != nil {
+			routerReqsCounter = append(routerReqsCounter, r.RouterReqsCounter())
+		}
+		if r.RouterReqDurationHistogram() != nil {
+			routerReqDurationHistogram = append(routerReqDurationHistogram, r.RouterReqDurationHistogram())
+		}
 		if r.BackendReqsCounter() != nil {
 			backendReqsCounter = append(backendReqsCounter, r.BackendReqsCounter())
 		}
@@ -89,39 +141,113 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.BackendServerUpGauge() != nil {
 			backendServerUpGauge = append(backendServerUpGauge, r.BackendServerUpGauge())
 		}
+		if r.TCPEntrypointConnsOpenedCounter() != nil {
+			tcpEntrypointConnsOpenedCounter = append(tcpEntrypointConnsOpenedCounter, r.TCPEntrypointConnsOpenedCounter())
+		}
+		if r.TCPEntrypointConnsClosedCounter() != nil {
+			tcpEntrypointConnsClosedCounter = append(tcpEntrypointConnsClosedCounter, r.TCPEntrypointConnsClosedCounter())
+		}
+		if r.TCPEntrypointConnDurationHistogram() != nil {
+			tcpEntrypointConnDurationHistogram = append(tcpEntrypointConnDurationHistogram, r.TCPEntrypointConnDurationHistogram())
+		}
+		if r.TCPEntrypointBytesCounter() != nil {
+			tcpEntrypointBytesCounter = append(tcpEntrypointBytesCounter, r.TCPEntrypointBytesCounter())
+		}
+		if r.TCPServiceConnsOpenedCounter() != nil {
+			tcpServiceConnsOpenedCounter = append(tcpServiceConnsOpenedCounter, r.TCPServiceConnsOpenedCounter())
+		}
+		if r.TCPServiceConnsClosedCounter() != nil {
+			tcpServiceConnsClosedCounter = append(tcpServiceConnsClosedCounter, r.TCPServiceConnsClosedCounter())
+		}
+		if r.TCPServiceConnDurationHistogram() != nil {
+			tcpServiceConnDurationHistogram = append(tcpServiceConnDurationHistogram, r.TCPServiceConnDurationHistogram())
+		}
+		if r.TCPServiceBytesCounter() != nil {
+			tcpServiceBytesCounter = append(tcpServiceBytesCounter, r.TCPServiceBytesCounter())
+		}
+		if r.TCPServiceDialFailuresCounter() != nil {
+			tcpServiceDialFailuresCounter = append(tcpServiceDialFailuresCounter, r.TCPServiceDialFailuresCounter())
+		}
+		if r.AccessLogDroppedCounter() != nil {
+			accessLogDroppedCounter = append(accessLogDroppedCounter, r.AccessLogDroppedCounter())
+		}
+		if r.AccessLogSampledCounter() != nil {
+			accessLogSampledCounter = append(accessLogSampledCounter, r.AccessLogSampledCounter())
+		}
 	}
 
 	return &standardRegistry{
-		enabled:                        len(registries) > 0,
-		configReloadsCounter:           multi.NewCounter(configReloadsCounter...),
-		configReloadsFailureCounter:    multi.NewCounter(configReloadsFailureCounter...),
-		lastConfigReloadSuccessGauge:   multi.NewGauge(lastConfigReloadSuccessGauge...),
-		lastConfigReloadFailureGauge:   multi.NewGauge(lastConfigReloadFailureGauge...),
-		entrypointReqsCounter:          multi.NewCounter(entrypointReqsCounter...),
-		entrypointReqDurationHistogram: multi.NewHistogram(entrypointReqDurationHistogram...),
-		entrypointOpenConnsGauge:       multi.NewGauge(entrypointOpenConnsGauge...),
-		backendReqsCounter:             multi.NewCounter(backendReqsCounter...),
-		backendReqDurationHistogram:    multi.NewHistogram(backendReqDurationHistogram...),
-		backendOpenConnsGauge:          multi.NewGauge(backendOpenConnsGauge...),
-		backendRetriesCounter:          multi.NewCounter(backendRetriesCounter...),
-		backendServerUpGauge:           multi.NewGauge(backendServerUpGauge...),
+		enabled:                            len(registries) > 0,
+		configReloadsCounter:               multi.NewCounter(configReloadsCounter...),
+		configReloadsFailureCounter:        multi.NewCounter(configReloadsFailureCounter...),
+		lastConfigReloadSuccessGauge:       multi.NewGauge(lastConfigReloadSuccessGauge...),
+		lastConfigReloadFailureGauge:       multi.NewGauge(lastConfigReloadFailureGauge...),
+		tlsCertsNotAfterTimestampGauge:     multi.NewGauge(tlsCertsNotAfterTimestampGauge...),
+		entrypointReqsCounter:              multi.NewCounter(entrypointReqsCounter...),
+		entrypointReqDurationHistogram:     multi.NewHistogram(entrypointReqDurationHistogram...),
+		entrypointOpenConnsGauge:           multi.NewGauge(entrypointOpenConnsGauge...),
+		routerReqsCounter:                  multi.NewCounter(routerReqsCounter...),
+		routerReqDurationHistogram:         multi.NewHistogram(routerReqDurationHistogram...),
+		headerLabels:                       multiHeaderLabels(registries),
+		backendReqsCounter:                 multi.NewCounter(backendReqsCounter...),
+		backendReqDurationHistogram:        multi.NewHistogram(backendReqDurationHistogram...),
+		backendOpenConnsGauge:              multi.NewGauge(backendOpenConnsGauge...),
+		backendRetriesCounter:              multi.NewCounter(backendRetriesCounter...),
+		backendServerUpGauge:               multi.NewGauge(backendServerUpGauge...),
+		tcpEntrypointConnsOpenedCounter:    multi.NewCounter(tcpEntrypointConnsOpenedCounter...),
+		tcpEntrypointConnsClosedCounter:    multi.NewCounter(tcpEntrypointConnsClosedCounter...),
+		tcpEntrypointConnDurationHistogram: multi.NewHistogram(tcpEntrypointConnDurationHistogram...),
+		tcpEntrypointBytesCounter:          multi.NewCounter(tcpEntrypointBytesCounter...),
+		tcpServiceConnsOpenedCounter:       multi.NewCounter(tcpServiceConnsOpenedCounter...),
+		tcpServiceConnsClosedCounter:       multi.NewCounter(tcpServiceConnsClosedCounter...),
+		tcpServiceConnDurationHistogram:    multi.NewHistogram(tcpServiceConnDurationHistogram...),
+		tcpServiceBytesCounter:             multi.NewCounter(tcpServiceBytesCounter...),
+		tcpServiceDialFailuresCounter:      multi.NewCounter(tcpServiceDialFailuresCounter...),
+		accessLogDroppedCounter:            multi.NewCounter(accessLogDroppedCounter...),
+		accessLogSampledCounter:            multi.NewCounter(accessLogSampledCounter...),
+	}
+}
+
+// multiHeaderLabels returns the header labels of all the registries.
+func multiHeaderLabels(registries []Registry) func(header http.Header) []string {
+	return func(header http.Header) []string {
+		var labels []string
+		for _, r := range registries {
+			labels = append(labels, r.HeaderLabels(header)...)
+		}
+		return labels
 	}
 }
 
 type standardRegistry struct {
-	enabled                        bool
-	configReloadsCounter           metrics.Counter
-	configReloadsFailureCounter    metrics.Counter
-	lastConfigReloadSuccessGauge   metrics.Gauge
-	lastConfigReloadFailureGauge   metrics.Gauge
-	entrypointReqsCounter          metrics.Counter
-	entrypointReqDurationHistogram metrics.Histogram
-	entrypointOpenConnsGauge       metrics.Gauge
-	backendReqsCounter             metrics.Counter
-	backendReqDurationHistogram    metrics.Histogram
-	backendOpenConnsGauge          metrics.Gauge
-	backendRetriesCounter          metrics.Counter
-	backendServerUpGauge           metrics.Gauge
+	enabled                            bool
+	configReloadsCounter               metrics.Counter
+	configReloadsFailureCounter        metrics.Counter
+	lastConfigReloadSuccessGauge       metrics.Gauge
+	lastConfigReloadFailureGauge       metrics.Gauge
+	tlsCertsNotAfterTimestampGauge     metrics.Gauge
+	entrypointReqsCounter              metrics.Counter
+	entrypointReqDurationHistogram     metrics.Histogram
+	entrypointOpenConnsGauge           metrics.Gauge
+	routerReqsCounter                  metrics.Counter
+	routerReqDurationHistogram         metrics.Histogram
+	headerLabels                       func(header http.Header) []string
+	backendReqsCounter                 metrics.Counter
+	backendReqDurationHistogram        metrics.Histogram
+	backendOpenConnsGauge              metrics.Gauge
+	backendRetriesCounter              metrics.Counter
+	backendServerUpGauge               metrics.Gauge
+	tcpEntrypointConnsOpenedCounter    metrics.Counter
+	tcpEntrypointConnsClosedCounter    metrics.Counter
+	tcpEntrypointConnDurationHistogram metrics.Histogram
+	tcpEntrypointBytesCounter          metrics.Counter
+	tcpServiceConnsOpenedCounter       metrics.Counter
+	tcpServiceConnsClosedCounter       metrics.Counter
+	tcpServiceConnDurationHistogram    metrics.Histogram
+	tcpServiceBytesCounter             metrics.Counter
+	tcpServiceDialFailuresCounter      metrics.Counter
+	accessLogDroppedCounter            metrics.Counter
+	accessLogSampledCounter            metrics.Counter
 }
 
 func (r *standardRegistry) IsEnabled() bool {
@@ -144,6 +270,10 @@ func (r *standardRegistry) LastConfigReloadFailureGauge() metrics.Gauge {
 	return r.lastConfigReloadFailureGauge
 }
 
+func (r *standardRegistry) TLSCertsNotAfterTimestampGauge() metrics.Gauge {
+	return r.tlsCertsNotAfterTimestampGauge
+}
+
 func (r *standardRegistry) EntrypointReqsCounter() metrics.Counter {
 	return r.entrypointReqsCounter
 }
@@ -156,6 +286,21 @@ func (r *standardRegistry) EntrypointOpenConnsGauge() metrics.Gauge {
 	return r.entrypointOpenConnsGauge
 }
 
+func (r *standardRegistry) RouterReqsCounter() metrics.Counter {
+	return r.routerReqsCounter
+}
+
+func (r *standardRegistry) RouterReqDurationHistogram() metrics.Histogram {
+	return r.routerReqDurationHistogram
+}
+
+func (r *standardRegistry) HeaderLabels(header http.Header) []string {
+	if r.headerLabels == nil {
+		return nil
+	}
+	return r.headerLabels(header)
+}
+
 func (r *standardRegistry) BackendReqsCounter() metrics.Counter {
 	return r.backendReqsCounter
 }
@@ -175,3 +320,47 @@ func (r *standardRegistry) BackendRetriesCounter() metrics.Counter {
 func (r *standardRegistry) BackendServerUpGauge() metrics.Gauge {
 	return r.backendServerUpGauge
 }
+
+func (r *standardRegistry) TCPEntrypointConnsOpenedCounter() metrics.Counter {
+	return r.tcpEntrypointConnsOpenedCounter
+}
+
+func (r *standardRegistry) TCPEntrypointConnsClosedCounter() metrics.Counter {
+	return r.tcpEntrypointConnsClosedCounter
+}
+
+func (r *standardRegistry) TCPEntrypointConnDurationHistogram() metrics.Histogram {
+	return r.tcpEntrypointConnDurationHistogram
+}
+
+func (r *standardRegistry) TCPEntrypointBytesCounter() metrics.Counter {
+	return r.tcpEntrypointBytesCounter
+}
+
+func (r *standardRegistry) TCPServiceConnsOpenedCounter() metrics.Counter {
+	return r.tcpServiceConnsOpenedCounter
+}
+
+func (r *standardRegistry) TCPServiceConnsClosedCounter() metrics.Counter {
+	return r.tcpServiceConnsClosedCounter
+}
+
+func (r *standardRegistry) TCPServiceConnDurationHistogram() metrics.Histogram {
+	return r.tcpServiceConnDurationHistogram
+}
+
+func (r *standardRegistry) TCPServiceBytesCounter() metrics.Counter {
+	return r.tcpServiceBytesCounter
+}
+
+func (r *standardRegistry) TCPServiceDialFailuresCounter() metrics.Counter {
+	return r.tcpServiceDialFailuresCounter
+}
+
+func (r *standardRegistry) AccessLogDroppedCounter() metrics.Counter {
+	return r.accessLogDroppedCounter
+}
+
+func (r *standardRegistry) AccessLogSampledCounter() metrics.Counter {
+	return r.accessLogSampledCounter
+}
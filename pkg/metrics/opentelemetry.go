@@ -0,0 +1,374 @@
+package metrics
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/otlp"
+	"github.com/containous/traefik/pkg/safe"
+	"github.com/containous/traefik/pkg/types"
+	"github.com/go-kit/kit/metrics"
+)
+
+var (
+	openTelemetryTicker *time.Ticker
+	openTelemetryClient *otlp.Client
+	openTelemetryState  *otelState
+)
+
+const (
+	otelConfigReloadsName             = "traefik.config.reload.total"
+	otelConfigReloadsFailureTagName   = "failure"
+	otelLastConfigReloadSuccessName   = "traefik.config.reload.lastSuccessTimestamp"
+	otelLastConfigReloadFailureName   = "traefik.config.reload.lastFailureTimestamp"
+	otelTLSCertsNotAfterTimestamp     = "traefik.tls.certs.notAfterTimestamp"
+	otelEntrypointReqsName            = "traefik.entrypoint.requests.total"
+	otelEntrypointReqDurationName     = "traefik.entrypoint.request.duration"
+	otelEntrypointOpenConnsName       = "traefik.entrypoint.connections.open"
+	otelRouterReqsName                = "traefik.router.requests.total"
+	otelRouterReqDurationName         = "traefik.router.request.duration"
+	otelBackendReqsName               = "traefik.backend.requests.total"
+	otelBackendReqDurationName        = "traefik.backend.request.duration"
+	otelBackendRetriesName            = "traefik.backend.retries.total"
+	otelBackendOpenConnsName          = "traefik.backend.connections.open"
+	otelBackendServerUpName           = "traefik.backend.server.up"
+	otelTCPEntrypointConnsOpenedName  = "traefik.tcp.entrypoint.connections.opened.total"
+	otelTCPEntrypointConnsClosedName  = "traefik.tcp.entrypoint.connections.closed.total"
+	otelTCPEntrypointConnDurationName = "traefik.tcp.entrypoint.connection.duration"
+	otelTCPEntrypointBytesName        = "traefik.tcp.entrypoint.bytes.total"
+	otelTCPServiceConnsOpenedName     = "traefik.tcp.service.connections.opened.total"
+	otelTCPServiceConnsClosedName     = "traefik.tcp.service.connections.closed.total"
+	otelTCPServiceConnDurationName    = "traefik.tcp.service.connection.duration"
+	otelTCPServiceBytesName           = "traefik.tcp.service.bytes.total"
+	otelTCPServiceDialFailuresName    = "traefik.tcp.service.dial.failures.total"
+	otelAccessLogDroppedName          = "traefik.accesslog.dropped.total"
+	otelAccessLogSampledName          = "traefik.accesslog.sampled.total"
+)
+
+// RegisterOpenTelemetry registers the metrics pusher if this didn't happen yet and creates an OpenTelemetry Registry instance.
+func RegisterOpenTelemetry(ctx context.Context, config *types.OpenTelemetry) Registry {
+	if openTelemetryTicker == nil {
+		ticker, err := initOpenTelemetryTicker(ctx, config)
+		if err != nil {
+			log.FromContext(ctx).Errorf("Unable to push the metrics to OpenTelemetry: %v", err)
+			return nil
+		}
+		openTelemetryTicker = ticker
+	}
+
+	buckets := []float64{0.1, 0.3, 1.2, 5.0}
+	if config.Buckets != nil {
+		buckets = config.Buckets
+	}
+
+	state := openTelemetryState
+
+	return &standardRegistry{
+		enabled:                            true,
+		configReloadsCounter:               state.newCounter(otelConfigReloadsName),
+		configReloadsFailureCounter:        state.newCounter(otelConfigReloadsName).With(otelConfigReloadsFailureTagName, "true"),
+		lastConfigReloadSuccessGauge:       state.newGauge(otelLastConfigReloadSuccessName),
+		lastConfigReloadFailureGauge:       state.newGauge(otelLastConfigReloadFailureName),
+		tlsCertsNotAfterTimestampGauge:     state.newGauge(otelTLSCertsNotAfterTimestamp),
+		entrypointReqsCounter:              state.newCounter(otelEntrypointReqsName),
+		entrypointReqDurationHistogram:     state.newHistogram(otelEntrypointReqDurationName, buckets),
+		entrypointOpenConnsGauge:           state.newGauge(otelEntrypointOpenConnsName),
+		routerReqsCounter:                  state.newCounter(otelRouterReqsName),
+		routerReqDurationHistogram:         state.newHistogram(otelRouterReqDurationName, buckets),
+		backendReqsCounter:                 state.newCounter(otelBackendReqsName),
+		backendReqDurationHistogram:        state.newHistogram(otelBackendReqDurationName, buckets),
+		backendRetriesCounter:              state.newCounter(otelBackendRetriesName),
+		backendOpenConnsGauge:              state.newGauge(otelBackendOpenConnsName),
+		backendServerUpGauge:               state.newGauge(otelBackendServerUpName),
+		tcpEntrypointConnsOpenedCounter:    state.newCounter(otelTCPEntrypointConnsOpenedName),
+		tcpEntrypointConnsClosedCounter:    state.newCounter(otelTCPEntrypointConnsClosedName),
+		tcpEntrypointConnDurationHistogram: state.newHistogram(otelTCPEntrypointConnDurationName, buckets),
+		tcpEntrypointBytesCounter:          state.newCounter(otelTCPEntrypointBytesName),
+		tcpServiceConnsOpenedCounter:       state.newCounter(otelTCPServiceConnsOpenedName),
+		tcpServiceConnsClosedCounter:       state.newCounter(otelTCPServiceConnsClosedName),
+		tcpServiceConnDurationHistogram:    state.newHistogram(otelTCPServiceConnDurationName, buckets),
+		tcpServiceBytesCounter:             state.newCounter(otelTCPServiceBytesName),
+		tcpServiceDialFailuresCounter:      state.newCounter(otelTCPServiceDialFailuresName),
+		accessLogDroppedCounter:            state.newCounter(otelAccessLogDroppedName),
+		accessLogSampledCounter:            state.newCounter(otelAccessLogSampledName),
+	}
+}
+
+// initOpenTelemetryTicker creates the openTelemetryClient and initializes the metrics pusher.
+func initOpenTelemetryTicker(ctx context.Context, config *types.OpenTelemetry) (*time.Ticker, error) {
+	client, err := otlp.NewClient(otlp.Config{
+		Address:  config.Address,
+		GRPC:     config.GRPC,
+		Insecure: config.Insecure,
+		Headers:  config.Headers,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	state := newOTelState()
+
+	openTelemetryClient = client
+	openTelemetryState = state
+
+	report := time.NewTicker(time.Duration(config.PushInterval))
+
+	safe.Go(func() {
+		for range report.C {
+			pushOpenTelemetry(ctx, client, state)
+		}
+	})
+
+	return report, nil
+}
+
+func pushOpenTelemetry(ctx context.Context, client *otlp.Client, state *otelState) {
+	collected := state.collect(time.Now())
+	if len(collected) == 0 {
+		return
+	}
+
+	if err := client.ExportMetrics(ctx, otlp.EncodeMetrics(otlp.Resource("traefik"), collected)); err != nil {
+		log.FromContext(ctx).Errorf("Error while pushing the metrics to OpenTelemetry: %v", err)
+	}
+}
+
+// StopOpenTelemetry stops internal openTelemetryTicker which controls the pushing of metrics to the OpenTelemetry collector and resets it to `nil`
+func StopOpenTelemetry() {
+	if openTelemetryTicker != nil {
+		openTelemetryTicker.Stop()
+	}
+	openTelemetryTicker = nil
+
+	if openTelemetryClient != nil {
+		if err := openTelemetryClient.Close(); err != nil {
+			log.WithoutContext().Errorf("Unable to close the OpenTelemetry client: %v", err)
+		}
+	}
+	openTelemetryClient = nil
+}
+
+// otelState holds the cumulative values of the metrics, which are all pushed to the collector on each tick.
+// As with Prometheus, the series of the removed entry points, routers, services and certificates are removed once pushed.
+type otelState struct {
+	lock   sync.Mutex
+	start  time.Time
+	series map[string]*otelSeries
+
+	dynamicConfig   *dynamicConfig
+	tlsCertificates map[string]bool
+}
+
+type otelSeries struct {
+	name             string
+	kind             otlp.MetricKind
+	labelNamesValues labelNamesValues
+	value            float64
+	count            uint64
+	sum              float64
+	bounds           []float64
+	bucketCounts     []uint64
+}
+
+func newOTelState() *otelState {
+	return &otelState{
+		start:  time.Now(),
+		series: make(map[string]*otelSeries),
+	}
+}
+
+func (s *otelState) setDynamicConfig(dynamicConfig *dynamicConfig) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.dynamicConfig = dynamicConfig
+}
+
+func (s *otelState) setTLSCertificates(tlsCertificates map[string]bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.tlsCertificates = tlsCertificates
+}
+
+func (s *otelState) newCounter(name string) metrics.Counter {
+	return &otelCounter{name: name, state: s}
+}
+
+func (s *otelState) newGauge(name string) metrics.Gauge {
+	return &otelGauge{name: name, state: s}
+}
+
+func (s *otelState) newHistogram(name string, bounds []float64) metrics.Histogram {
+	return &otelHistogram{name: name, bounds: bounds, state: s}
+}
+
+// update applies fn to the series identified by the name and labels of the metric, creating it if needed.
+func (s *otelState) update(name string, kind otlp.MetricKind, lvs labelNamesValues, bounds []float64, fn func(series *otelSeries)) {
+	key := name + "\x00" + strings.Join(lvs, "\x00")
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	series, ok := s.series[key]
+	if !ok {
+		series = &otelSeries{
+			name:             name,
+			kind:             kind,
+			labelNamesValues: append(labelNamesValues(nil), lvs...),
+			bounds:           bounds,
+		}
+		if kind == otlp.MetricHistogram {
+			series.bucketCounts = make([]uint64, len(bounds)+1)
+		}
+		s.series[key] = series
+	}
+
+	fn(series)
+}
+
+// collect returns the data points of all the series, grouped by metric,
+// and removes the series which belong to an outdated configuration.
+func (s *otelState) collect(now time.Time) []otlp.Metric {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	keys := make([]string, 0, len(s.series))
+	for key := range s.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var collected []otlp.Metric
+	for _, key := range keys {
+		series := s.series[key]
+
+		if len(collected) == 0 || collected[len(collected)-1].Name != series.name {
+			collected = append(collected, otlp.Metric{Name: series.name, Kind: series.kind})
+		}
+		metric := &collected[len(collected)-1]
+
+		attributes := make([]otlp.KeyValue, 0, len(series.labelNamesValues)/2)
+		for i := 0; i+1 < len(series.labelNamesValues); i += 2 {
+			attributes = append(attributes, otlp.KeyValue{Key: series.labelNamesValues[i], Value: series.labelNamesValues[i+1]})
+		}
+
+		if series.kind == otlp.MetricHistogram {
+			metric.HistogramPoints = append(metric.HistogramPoints, otlp.HistogramDataPoint{
+				Attributes:     attributes,
+				Start:          s.start,
+				Time:           now,
+				Count:          series.count,
+				Sum:            series.sum,
+				BucketCounts:   append([]uint64(nil), series.bucketCounts...),
+				ExplicitBounds: series.bounds,
+			})
+			continue
+		}
+
+		point := otlp.NumberDataPoint{Attributes: attributes, Time: now, Value: series.value}
+		if series.kind == otlp.MetricCounter {
+			point.Start = s.start
+		}
+		metric.NumberPoints = append(metric.NumberPoints, point)
+	}
+
+	s.removeOutdated()
+
+	return collected
+}
+
+// removeOutdated removes the series which belong to an outdated configuration,
+// it does nothing until the configuration is known.
+func (s *otelState) removeOutdated() {
+	if s.dynamicConfig == nil {
+		return
+	}
+
+	for key, series := range s.series {
+		labels := make(map[string]string, len(series.labelNamesValues)/2)
+		for i := 0; i+1 < len(series.labelNamesValues); i += 2 {
+			labels[series.labelNamesValues[i]] = series.labelNamesValues[i+1]
+		}
+
+		if isOutdated(labels, s.dynamicConfig, s.tlsCertificates) {
+			delete(s.series, key)
+		}
+	}
+}
+
+type otelCounter struct {
+	name             string
+	labelNamesValues labelNamesValues
+	state            *otelState
+}
+
+func (c *otelCounter) With(labelValues ...string) metrics.Counter {
+	return &otelCounter{
+		name:             c.name,
+		labelNamesValues: c.labelNamesValues.With(labelValues...),
+		state:            c.state,
+	}
+}
+
+func (c *otelCounter) Add(delta float64) {
+	c.state.update(c.name, otlp.MetricCounter, c.labelNamesValues, nil, func(series *otelSeries) {
+		series.value += delta
+	})
+}
+
+type otelGauge struct {
+	name             string
+	labelNamesValues labelNamesValues
+	state            *otelState
+}
+
+func (g *otelGauge) With(labelValues ...string) metrics.Gauge {
+	return &otelGauge{
+		name:             g.name,
+		labelNamesValues: g.labelNamesValues.With(labelValues...),
+		state:            g.state,
+	}
+}
+
+func (g *otelGauge) Set(value float64) {
+	g.state.update(g.name, otlp.MetricGauge, g.labelNamesValues, nil, func(series *otelSeries) {
+		series.value = value
+	})
+}
+
+func (g *otelGauge) Add(delta float64) {
+	g.state.update(g.name, otlp.MetricGauge, g.labelNamesValues, nil, func(series *otelSeries) {
+		series.value += delta
+	})
+}
+
+type otelHistogram struct {
+	name             string
+	labelNamesValues labelNamesValues
+	bounds           []float64
+	state            *otelState
+}
+
+func (h *otelHistogram) With(labelValues ...string) metrics.Histogram {
+	return &otelHistogram{
+		name:             h.name,
+		labelNamesValues: h.labelNamesValues.With(labelValues...),
+		bounds:           h.bounds,
+		state:            h.state,
+	}
+}
+
+func (h *otelHistogram) Observe(value float64) {
+	h.state.update(h.name, otlp.MetricHistogram, h.labelNamesValues, h.bounds, func(series *otelSeries) {
+		series.count++
+		series.sum += value
+
+		// The bucket i counts the values in (bounds[i-1], bounds[i]], the last one the values greater than all the bounds.
+		series.bucketCounts[sort.SearchFloat64s(series.bounds, value)]++
+	})
+}
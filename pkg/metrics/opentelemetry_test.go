@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/pkg/otlp"
+	"github.com/containous/traefik/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenTelemetry(t *testing.T) {
+	payloads := make(chan []byte, 10)
+
+	collector := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/v1/metrics", req.URL.Path)
+
+		payload, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		payloads <- payload
+	}))
+	defer collector.Close()
+
+	openTelemetryRegistry := RegisterOpenTelemetry(context.Background(), &types.OpenTelemetry{
+		Address:      strings.TrimPrefix(collector.URL, "http://"),
+		Insecure:     true,
+		PushInterval: types.Duration(100 * time.Millisecond),
+	})
+	defer StopOpenTelemetry()
+
+	require.NotNil(t, openTelemetryRegistry)
+	if !openTelemetryRegistry.IsEnabled() {
+		t.Errorf("OpenTelemetryRegistry should return true for IsEnabled()")
+	}
+
+	openTelemetryRegistry.ConfigReloadsCounter().Add(1)
+	openTelemetryRegistry.EntrypointReqsCounter().With("entrypoint", "test", "code", strconv.Itoa(http.StatusOK)).Add(1)
+	openTelemetryRegistry.RouterReqDurationHistogram().With("router", "foo@file", "service", "bar@file").Observe(0.2)
+	openTelemetryRegistry.BackendServerUpGauge().With("backend", "test", "url", "http://127.0.0.1").Set(1)
+	openTelemetryRegistry.TCPServiceDialFailuresCounter().With("service", "test").Add(1)
+
+	expected := []string{
+		otelConfigReloadsName,
+		otelEntrypointReqsName,
+		otelRouterReqDurationName,
+		otelBackendServerUpName,
+		otelTCPServiceDialFailuresName,
+		"foo@file",
+		"http://127.0.0.1",
+	}
+
+	select {
+	case payload := <-payloads:
+		for _, value := range expected {
+			assert.True(t, bytes.Contains(payload, []byte(value)), "pushed metrics should contain %q", value)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("metrics have not been pushed")
+	}
+}
+
+func TestOTelState_collect(t *testing.T) {
+	state := newOTelState()
+
+	counter := state.newCounter("counter")
+	counter.With("code", "200").Add(1)
+	counter.With("code", "200").Add(2)
+	counter.With("code", "500").Add(1)
+
+	gauge := state.newGauge("gauge")
+	gauge.Set(3)
+	gauge.Add(-1)
+
+	histogram := state.newHistogram("histogram", []float64{0.1, 1})
+	histogram.Observe(0.1)
+	histogram.Observe(0.5)
+	histogram.Observe(2)
+
+	now := time.Now()
+	collected := state.collect(now)
+	require.Len(t, collected, 3)
+
+	assert.Equal(t, otlp.Metric{
+		Name: "counter",
+		Kind: otlp.MetricCounter,
+		NumberPoints: []otlp.NumberDataPoint{
+			{Attributes: []otlp.KeyValue{{Key: "code", Value: "200"}}, Start: state.start, Time: now, Value: 3},
+			{Attributes: []otlp.KeyValue{{Key: "code", Value: "500"}}, Start: state.start, Time: now, Value: 1},
+		},
+	}, collected[0])
+
+	assert.Equal(t, otlp.Metric{
+		Name:         "gauge",
+		Kind:         otlp.MetricGauge,
+		NumberPoints: []otlp.NumberDataPoint{{Attributes: []otlp.KeyValue{}, Time: now, Value: 2}},
+	}, collected[1])
+
+	assert.Equal(t, otlp.Metric{
+		Name: "histogram",
+		Kind: otlp.MetricHistogram,
+		HistogramPoints: []otlp.HistogramDataPoint{{
+			Attributes:     []otlp.KeyValue{},
+			Start:          state.start,
+			Time:           now,
+			Count:          3,
+			Sum:            2.6,
+			BucketCounts:   []uint64{1, 1, 1},
+			ExplicitBounds: []float64{0.1, 1},
+		}},
+	}, collected[2])
+}
+
+func TestOTelState_removeOutdated(t *testing.T) {
+	state := newOTelState()
+
+	counter := state.newCounter("counter")
+	counter.With("router", "foo@file").Add(1)
+	counter.With("router", "bar@file").Add(1)
+	counter.With("service", "foo@file").Add(1)
+	counter.Add(1)
+
+	// The series are kept until the configuration is known.
+	collected := state.collect(time.Now())
+	require.Len(t, collected, 1)
+	assert.Len(t, collected[0].NumberPoints, 4)
+
+	dynamicConfig := newDynamicConfig()
+	dynamicConfig.routers["foo@file"] = true
+	state.setDynamicConfig(dynamicConfig)
+
+	// The outdated series are pushed one last time.
+	collected = state.collect(time.Now())
+	require.Len(t, collected, 1)
+	assert.Len(t, collected[0].NumberPoints, 4)
+
+	collected = state.collect(time.Now())
+	require.Len(t, collected, 1)
+	assert.Len(t, collected[0].NumberPoints, 2)
+}
@@ -3,6 +3,7 @@ package metrics
 import (
 	"context"
 	"net/http"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
@@ -28,12 +29,21 @@ const (
 	configLastReloadSuccessName    = metricConfigPrefix + "last_reload_success"
 	configLastReloadFailureName    = metricConfigPrefix + "last_reload_failure"
 
+	// TLS
+	metricsTLSPrefix              = MetricNamePrefix + "tls_"
+	tlsCertsNotAfterTimestampName = metricsTLSPrefix + "certs_not_after"
+
 	// entrypoint
 	metricEntryPointPrefix    = MetricNamePrefix + "entrypoint_"
 	entrypointReqsTotalName   = metricEntryPointPrefix + "requests_total"
 	entrypointReqDurationName = metricEntryPointPrefix + "request_duration_seconds"
 	entrypointOpenConnsName   = metricEntryPointPrefix + "open_connections"
 
+	// router level
+	metricRouterPrefix    = MetricNamePrefix + "router_"
+	routerReqsTotalName   = metricRouterPrefix + "requests_total"
+	routerReqDurationName = metricRouterPrefix + "request_duration_seconds"
+
 	// backend level.
 
 	// MetricBackendPrefix prefix of all backend metric names
@@ -43,6 +53,26 @@ const (
 	backendOpenConnsName    = MetricBackendPrefix + "open_connections"
 	backendRetriesTotalName = MetricBackendPrefix + "retries_total"
 	backendServerUpName     = MetricBackendPrefix + "server_up"
+
+	// TCP entrypoint
+	metricTCPEntryPointPrefix     = MetricNamePrefix + "tcp_entrypoint_"
+	tcpEntrypointConnsOpenedName  = metricTCPEntryPointPrefix + "connections_opened_total"
+	tcpEntrypointConnsClosedName  = metricTCPEntryPointPrefix + "connections_closed_total"
+	tcpEntrypointConnDurationName = metricTCPEntryPointPrefix + "connection_duration_seconds"
+	tcpEntrypointBytesTotalName   = metricTCPEntryPointPrefix + "bytes_total"
+
+	// TCP service
+	metricTCPServicePrefix          = MetricNamePrefix + "tcp_service_"
+	tcpServiceConnsOpenedName       = metricTCPServicePrefix + "connections_opened_total"
+	tcpServiceConnsClosedName       = metricTCPServicePrefix + "connections_closed_total"
+	tcpServiceConnDurationName      = metricTCPServicePrefix + "connection_duration_seconds"
+	tcpServiceBytesTotalName        = metricTCPServicePrefix + "bytes_total"
+	tcpServiceDialFailuresTotalName = metricTCPServicePrefix + "dial_failures_total"
+
+	// access log
+	metricAccessLogPrefix     = MetricNamePrefix + "accesslog_"
+	accessLogDroppedTotalName = metricAccessLogPrefix + "dropped_total"
+	accessLogSampledTotalName = metricAccessLogPrefix + "sampled_total"
 )
 
 // promState holds all metric state internally and acts as the only Collector we register for Prometheus.
@@ -106,33 +136,50 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		Help: "Last config reload failure",
 	}, []string{})
 
+	tlsCertsNotAfterTimestamp := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+		Name: tlsCertsNotAfterTimestampName,
+		Help: "Certificate expiration timestamp",
+	}, []string{"cn", "serial", "sans"})
+
+	headerLabels := newHeaderLabels(config.HeaderLabels)
+
 	entrypointReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
 		Name: entrypointReqsTotalName,
 		Help: "How many HTTP requests processed on an entrypoint, partitioned by status code, protocol, and method.",
-	}, []string{"code", "method", "protocol", "entrypoint"})
+	}, requestLabelNames(config, "code", "method", "protocol", "entrypoint"))
 	entrypointReqDurations := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
 		Name:    entrypointReqDurationName,
 		Help:    "How long it took to process the request on an entrypoint, partitioned by status code, protocol, and method.",
 		Buckets: buckets,
-	}, []string{"code", "method", "protocol", "entrypoint"})
+	}, requestLabelNames(config, "code", "method", "protocol", "entrypoint"))
 	entrypointOpenConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
 		Name: entrypointOpenConnsName,
 		Help: "How many open connections exist on an entrypoint, partitioned by method and protocol.",
-	}, []string{"method", "protocol", "entrypoint"})
+	}, requestLabelNames(config, "method", "protocol", "entrypoint"))
+
+	routerReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: routerReqsTotalName,
+		Help: "How many HTTP requests are processed on a router, partitioned by service, status code, protocol, and method.",
+	}, append(requestLabelNames(config, "code", "method", "protocol", "router", "service"), headerLabels.names...))
+	routerReqDurations := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
+		Name:    routerReqDurationName,
+		Help:    "How long it took to process the request on a router, partitioned by service, status code, protocol, and method.",
+		Buckets: buckets,
+	}, append(requestLabelNames(config, "code", "method", "protocol", "router", "service"), headerLabels.names...))
 
 	backendReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
 		Name: backendReqsTotalName,
 		Help: "How many HTTP requests processed on a backend, partitioned by status code, protocol, and method.",
-	}, []string{"code", "method", "protocol", "backend"})
+	}, requestLabelNames(config, "code", "method", "protocol", "backend"))
 	backendReqDurations := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
 		Name:    backendReqDurationName,
 		Help:    "How long it took to process the request on a backend, partitioned by status code, protocol, and method.",
 		Buckets: buckets,
-	}, []string{"code", "method", "protocol", "backend"})
+	}, requestLabelNames(config, "code", "method", "protocol", "backend"))
 	backendOpenConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
 		Name: backendOpenConnsName,
 		Help: "How many open connections exist on a backend, partitioned by method and protocol.",
-	}, []string{"method", "protocol", "backend"})
+	}, requestLabelNames(config, "method", "protocol", "backend"))
 	backendRetries := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
 		Name: backendRetriesTotalName,
 		Help: "How many request retries happened on a backend.",
@@ -142,35 +189,113 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		Help: "Backend server is up, described by gauge value of 0 or 1.",
 	}, []string{"backend", "url"})
 
+	tcpEntrypointConnsOpened := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: tcpEntrypointConnsOpenedName,
+		Help: "How many TCP connections were opened on an entrypoint.",
+	}, []string{"entrypoint"})
+	tcpEntrypointConnsClosed := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: tcpEntrypointConnsClosedName,
+		Help: "How many TCP connections were closed on an entrypoint.",
+	}, []string{"entrypoint"})
+	tcpEntrypointConnDurations := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
+		Name:    tcpEntrypointConnDurationName,
+		Help:    "How long the TCP connections of an entrypoint lasted.",
+		Buckets: buckets,
+	}, []string{"entrypoint"})
+	tcpEntrypointBytes := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: tcpEntrypointBytesTotalName,
+		Help: "How many bytes were received from (in) and sent to (out) the clients of an entrypoint.",
+	}, []string{"direction", "entrypoint"})
+
+	tcpServiceConnsOpened := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: tcpServiceConnsOpenedName,
+		Help: "How many TCP connections were forwarded to a service.",
+	}, []string{"service"})
+	tcpServiceConnsClosed := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: tcpServiceConnsClosedName,
+		Help: "How many TCP connections forwarded to a service were closed.",
+	}, []string{"service"})
+	tcpServiceConnDurations := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
+		Name:    tcpServiceConnDurationName,
+		Help:    "How long the TCP connections forwarded to a service lasted.",
+		Buckets: buckets,
+	}, []string{"service"})
+	tcpServiceBytes := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: tcpServiceBytesTotalName,
+		Help: "How many bytes of the TCP connections forwarded to a service were received from (in) and sent to (out) the clients.",
+	}, []string{"direction", "service"})
+	tcpServiceDialFailures := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: tcpServiceDialFailuresTotalName,
+		Help: "How many times dialing a server of a service failed.",
+	}, []string{"service"})
+
+	accessLogDropped := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: accessLogDroppedTotalName,
+		Help: "How many access log lines were dropped, partitioned by reason.",
+	}, []string{"reason"})
+	accessLogSampled := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: accessLogSampledTotalName,
+		Help: "How many access log lines were subject to sampling, partitioned by decision.",
+	}, []string{"decision"})
+
 	promState.describers = []func(chan<- *stdprometheus.Desc){
 		configReloads.cv.Describe,
 		configReloadsFailures.cv.Describe,
 		lastConfigReloadSuccess.gv.Describe,
 		lastConfigReloadFailure.gv.Describe,
+		tlsCertsNotAfterTimestamp.gv.Describe,
 		entrypointReqs.cv.Describe,
 		entrypointReqDurations.hv.Describe,
 		entrypointOpenConns.gv.Describe,
+		routerReqs.cv.Describe,
+		routerReqDurations.hv.Describe,
 		backendReqs.cv.Describe,
 		backendReqDurations.hv.Describe,
 		backendOpenConns.gv.Describe,
 		backendRetries.cv.Describe,
 		backendServerUp.gv.Describe,
+		tcpEntrypointConnsOpened.cv.Describe,
+		tcpEntrypointConnsClosed.cv.Describe,
+		tcpEntrypointConnDurations.hv.Describe,
+		tcpEntrypointBytes.cv.Describe,
+		tcpServiceConnsOpened.cv.Describe,
+		tcpServiceConnsClosed.cv.Describe,
+		tcpServiceConnDurations.hv.Describe,
+		tcpServiceBytes.cv.Describe,
+		tcpServiceDialFailures.cv.Describe,
+		accessLogDropped.cv.Describe,
+		accessLogSampled.cv.Describe,
 	}
 
 	return &standardRegistry{
-		enabled:                        true,
-		configReloadsCounter:           configReloads,
-		configReloadsFailureCounter:    configReloadsFailures,
-		lastConfigReloadSuccessGauge:   lastConfigReloadSuccess,
-		lastConfigReloadFailureGauge:   lastConfigReloadFailure,
-		entrypointReqsCounter:          entrypointReqs,
-		entrypointReqDurationHistogram: entrypointReqDurations,
-		entrypointOpenConnsGauge:       entrypointOpenConns,
-		backendReqsCounter:             backendReqs,
-		backendReqDurationHistogram:    backendReqDurations,
-		backendOpenConnsGauge:          backendOpenConns,
-		backendRetriesCounter:          backendRetries,
-		backendServerUpGauge:           backendServerUp,
+		enabled:                            true,
+		configReloadsCounter:               configReloads,
+		configReloadsFailureCounter:        configReloadsFailures,
+		lastConfigReloadSuccessGauge:       lastConfigReloadSuccess,
+		lastConfigReloadFailureGauge:       lastConfigReloadFailure,
+		tlsCertsNotAfterTimestampGauge:     tlsCertsNotAfterTimestamp,
+		entrypointReqsCounter:              entrypointReqs,
+		entrypointReqDurationHistogram:     entrypointReqDurations,
+		entrypointOpenConnsGauge:           entrypointOpenConns,
+		routerReqsCounter:                  routerReqs,
+		routerReqDurationHistogram:         routerReqDurations,
+		headerLabels:                       headerLabels.values,
+		backendReqsCounter:                 backendReqs,
+		backendReqDurationHistogram:        backendReqDurations,
+		backendOpenConnsGauge:              backendOpenConns,
+		backendRetriesCounter:              backendRetries,
+		backendServerUpGauge:               backendServerUp,
+		tcpEntrypointConnsOpenedCounter:    tcpEntrypointConnsOpened,
+		tcpEntrypointConnsClosedCounter:    tcpEntrypointConnsClosed,
+		tcpEntrypointConnDurationHistogram: tcpEntrypointConnDurations,
+		tcpEntrypointBytesCounter:          tcpEntrypointBytes,
+		tcpServiceConnsOpenedCounter:       tcpServiceConnsOpened,
+		tcpServiceConnsClosedCounter:       tcpServiceConnsClosed,
+		tcpServiceConnDurationHistogram:    tcpServiceConnDurations,
+		tcpServiceBytesCounter:             tcpServiceBytes,
+		tcpServiceDialFailuresCounter:      tcpServiceDialFailures,
+		accessLogDroppedCounter:            accessLogDropped,
+		accessLogSampledCounter:            accessLogSampled,
 	}
 }
 
@@ -186,29 +311,154 @@ func registerPromState(ctx context.Context) bool {
 	return true
 }
 
-// OnConfigurationUpdate receives the current configuration from Traefik.
+// requestLabelNames returns the label names of a request metric,
+// without the method and protocol labels if they are disabled to reduce the cardinality of the metrics.
+func requestLabelNames(config *types.Prometheus, labelNames ...string) []string {
+	var names []string
+	for _, name := range labelNames {
+		if (name == "method" && config.DisableMethodLabel) || (name == "protocol" && config.DisableProtocolLabel) {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// otherHeaderLabelValue is the value of the header labels when the header value is not allowed.
+const otherHeaderLabelValue = "other"
+
+// labelNameRegexp matches the valid Prometheus label names.
+var labelNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// headerLabels are the labels added to the router metrics from the request headers.
+type headerLabels struct {
+	names   []string
+	headers map[string]string
+	allowed map[string]map[string]bool
+}
+
+func newHeaderLabels(config map[string]*types.PrometheusHeaderLabel) *headerLabels {
+	labels := &headerLabels{
+		headers: make(map[string]string),
+		allowed: make(map[string]map[string]bool),
+	}
+
+	for name, label := range config {
+		if label == nil || len(label.Header) == 0 {
+			log.WithoutContext().Errorf("Ignoring the Prometheus header label %q without header", name)
+			continue
+		}
+
+		if !labelNameRegexp.MatchString(name) || strings.HasPrefix(name, "__") || isRouterLabelName(name) {
+			log.WithoutContext().Errorf("Ignoring the Prometheus header label %q: invalid or reserved label name", name)
+			continue
+		}
+
+		labels.names = append(labels.names, name)
+		labels.headers[name] = label.Header
+		labels.allowed[name] = make(map[string]bool)
+		for _, value := range label.Values {
+			labels.allowed[name][value] = true
+		}
+	}
+
+	sort.Strings(labels.names)
+
+	return labels
+}
+
+func isRouterLabelName(name string) bool {
+	switch name {
+	case "code", "method", "protocol", "router", "service":
+		return true
+	default:
+		return false
+	}
+}
+
+// values returns the names and values of the header labels of a request,
+// the values which are not allowed being replaced to bound the cardinality of the metrics.
+func (h *headerLabels) values(header http.Header) []string {
+	values := make([]string, 0, 2*len(h.names))
+	for _, name := range h.names {
+		value := header.Get(h.headers[name])
+		if len(value) > 0 && !h.allowed[name][value] {
+			value = otherHeaderLabelValue
+		}
+		values = append(values, name, value)
+	}
+	return values
+}
+
+// OnConfigurationUpdate receives the current configuration from Traefik, and the entry points names.
 // It then converts the configuration to the optimized package internal format
 // and sets it to the promState.
-func OnConfigurationUpdate(configurations config.Configurations) {
+func OnConfigurationUpdate(configurations config.Configurations, entryPoints []string) {
 	dynamicConfig := newDynamicConfig()
 
-	// FIXME metrics
-	// for _, config := range configurations {
-	// 	for _, frontend := range config.Frontends {
-	// 		for _, entrypointName := range frontend.EntryPoints {
-	// 			dynamicConfig.entrypoints[entrypointName] = true
-	// 		}
-	// 	}
-	//
-	// 	for backendName, backend := range config.Backends {
-	// 		dynamicConfig.backends[backendName] = make(map[string]bool)
-	// 		for _, server := range backend.Servers {
-	// 			dynamicConfig.backends[backendName][server.URL] = true
-	// 		}
-	// 	}
-	// }
+	for _, entryPointName := range entryPoints {
+		dynamicConfig.entrypoints[entryPointName] = true
+	}
+
+	// The routers and services are identified by their qualified name in the metrics.
+	for providerName, conf := range configurations {
+		if conf == nil {
+			continue
+		}
+
+		if conf.TCP != nil {
+			for routerName := range conf.TCP.Routers {
+				dynamicConfig.routers[providerName+"@"+routerName] = true
+			}
+
+			for serviceName := range conf.TCP.Services {
+				dynamicConfig.services[providerName+"@"+serviceName] = true
+			}
+		}
+
+		if conf.HTTP == nil {
+			continue
+		}
+
+		for routerName := range conf.HTTP.Routers {
+			dynamicConfig.routers[providerName+"@"+routerName] = true
+		}
+
+		for serviceName, service := range conf.HTTP.Services {
+			backendName := providerName + "@" + serviceName
+			dynamicConfig.services[backendName] = true
+
+			dynamicConfig.backends[backendName] = make(map[string]bool)
+			if service.LoadBalancer == nil {
+				continue
+			}
+
+			for _, server := range service.LoadBalancer.Servers {
+				dynamicConfig.backends[backendName][server.URL] = true
+			}
+		}
+	}
 
 	promState.SetDynamicConfig(dynamicConfig)
+
+	if openTelemetryState != nil {
+		openTelemetryState.setDynamicConfig(dynamicConfig)
+	}
+}
+
+// OnTLSCertificatesUpdate receives the serial numbers of the certificates currently served by Traefik,
+// so that the expiration metrics of the removed certificates are removed too.
+func OnTLSCertificatesUpdate(serials []string) {
+	tlsCertificates := make(map[string]bool, len(serials))
+	for _, serial := range serials {
+		tlsCertificates[serial] = true
+	}
+
+	promState.SetTLSCertificates(tlsCertificates)
+
+	if openTelemetryState != nil {
+		openTelemetryState.setTLSCertificates(tlsCertificates)
+	}
 }
 
 func newPrometheusState() *prometheusState {
@@ -223,9 +473,10 @@ type prometheusState struct {
 	collectors chan *collector
 	describers []func(ch chan<- *stdprometheus.Desc)
 
-	mtx           sync.Mutex
-	dynamicConfig *dynamicConfig
-	state         map[string]*collector
+	mtx             sync.Mutex
+	dynamicConfig   *dynamicConfig
+	tlsCertificates map[string]bool
+	state           map[string]*collector
 }
 
 func (ps *prometheusState) SetDynamicConfig(dynamicConfig *dynamicConfig) {
@@ -234,6 +485,12 @@ func (ps *prometheusState) SetDynamicConfig(dynamicConfig *dynamicConfig) {
 	ps.dynamicConfig = dynamicConfig
 }
 
+func (ps *prometheusState) SetTLSCertificates(tlsCertificates map[string]bool) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+	ps.tlsCertificates = tlsCertificates
+}
+
 func (ps *prometheusState) ListenValueUpdates() {
 	for collector := range ps.collectors {
 		ps.mtx.Lock()
@@ -277,17 +534,32 @@ func (ps *prometheusState) Collect(ch chan<- stdprometheus.Metric) {
 // isOutdated checks whether the passed collector has labels that mark
 // it as belonging to an outdated configuration of Traefik.
 func (ps *prometheusState) isOutdated(collector *collector) bool {
-	labels := collector.labels
+	return isOutdated(collector.labels, ps.dynamicConfig, ps.tlsCertificates)
+}
+
+// isOutdated checks whether the labels of a metric mark it as belonging to an outdated configuration of Traefik.
+func isOutdated(labels map[string]string, dynamicConfig *dynamicConfig, tlsCertificates map[string]bool) bool {
+	if entrypointName, ok := labels["entrypoint"]; ok && !dynamicConfig.hasEntrypoint(entrypointName) {
+		return true
+	}
+
+	if serial, ok := labels["serial"]; ok && tlsCertificates != nil && !tlsCertificates[serial] {
+		return true
+	}
+
+	if routerName, ok := labels["router"]; ok && !dynamicConfig.hasRouter(routerName) {
+		return true
+	}
 
-	if entrypointName, ok := labels["entrypoint"]; ok && !ps.dynamicConfig.hasEntrypoint(entrypointName) {
+	if serviceName, ok := labels["service"]; ok && !dynamicConfig.hasService(serviceName) {
 		return true
 	}
 
 	if backendName, ok := labels["backend"]; ok {
-		if !ps.dynamicConfig.hasBackend(backendName) {
+		if !dynamicConfig.hasBackend(backendName) {
 			return true
 		}
-		if url, ok := labels["url"]; ok && !ps.dynamicConfig.hasServerURL(backendName, url) {
+		if url, ok := labels["url"]; ok && !dynamicConfig.hasServerURL(backendName, url) {
 			return true
 		}
 	}
@@ -299,16 +571,20 @@ func newDynamicConfig() *dynamicConfig {
 	return &dynamicConfig{
 		entrypoints: make(map[string]bool),
 		backends:    make(map[string]map[string]bool),
+		routers:     make(map[string]bool),
+		services:    make(map[string]bool),
 	}
 }
 
 // dynamicConfig holds the current configuration for entrypoints, backends,
-// and server URLs in an optimized way to check for existence. This provides
+// server URLs, routers and services in an optimized way to check for existence. This provides
 // a performant way to check whether the collected metrics belong to the
 // current configuration or to an outdated one.
 type dynamicConfig struct {
 	entrypoints map[string]bool
 	backends    map[string]map[string]bool
+	routers     map[string]bool
+	services    map[string]bool
 }
 
 func (d *dynamicConfig) hasEntrypoint(entrypointName string) bool {
@@ -321,6 +597,16 @@ func (d *dynamicConfig) hasBackend(backendName string) bool {
 	return ok
 }
 
+func (d *dynamicConfig) hasRouter(routerName string) bool {
+	_, ok := d.routers[routerName]
+	return ok
+}
+
+func (d *dynamicConfig) hasService(serviceName string) bool {
+	_, ok := d.services[serviceName]
+	return ok
+}
+
 func (d *dynamicConfig) hasServerURL(backendName, serverURL string) bool {
 	if backend, hasBackend := d.backends[backendName]; hasBackend {
 		_, ok := backend[serverURL]
@@ -362,6 +648,7 @@ func newCounterFrom(collectors chan<- *collector, opts stdprometheus.CounterOpts
 	c := &counter{
 		name:       opts.Name,
 		cv:         cv,
+		labelNames: newLabelNames(labelNames),
 		collectors: collectors,
 	}
 	if len(labelNames) == 0 {
@@ -373,6 +660,7 @@ func newCounterFrom(collectors chan<- *collector, opts stdprometheus.CounterOpts
 type counter struct {
 	name             string
 	cv               *stdprometheus.CounterVec
+	labelNames       map[string]bool
 	labelNamesValues labelNamesValues
 	collectors       chan<- *collector
 }
@@ -381,13 +669,14 @@ func (c *counter) With(labelValues ...string) metrics.Counter {
 	return &counter{
 		name:             c.name,
 		cv:               c.cv,
+		labelNames:       c.labelNames,
 		labelNamesValues: c.labelNamesValues.With(labelValues...),
 		collectors:       c.collectors,
 	}
 }
 
 func (c *counter) Add(delta float64) {
-	labels := c.labelNamesValues.ToLabels()
+	labels := c.labelNamesValues.ToLabels(c.labelNames)
 	collector := c.cv.With(labels)
 	collector.Add(delta)
 	c.collectors <- newCollector(c.name, labels, collector, func() {
@@ -404,6 +693,7 @@ func newGaugeFrom(collectors chan<- *collector, opts stdprometheus.GaugeOpts, la
 	g := &gauge{
 		name:       opts.Name,
 		gv:         gv,
+		labelNames: newLabelNames(labelNames),
 		collectors: collectors,
 	}
 	if len(labelNames) == 0 {
@@ -415,6 +705,7 @@ func newGaugeFrom(collectors chan<- *collector, opts stdprometheus.GaugeOpts, la
 type gauge struct {
 	name             string
 	gv               *stdprometheus.GaugeVec
+	labelNames       map[string]bool
 	labelNamesValues labelNamesValues
 	collectors       chan<- *collector
 }
@@ -423,13 +714,14 @@ func (g *gauge) With(labelValues ...string) metrics.Gauge {
 	return &gauge{
 		name:             g.name,
 		gv:               g.gv,
+		labelNames:       g.labelNames,
 		labelNamesValues: g.labelNamesValues.With(labelValues...),
 		collectors:       g.collectors,
 	}
 }
 
 func (g *gauge) Add(delta float64) {
-	labels := g.labelNamesValues.ToLabels()
+	labels := g.labelNamesValues.ToLabels(g.labelNames)
 	collector := g.gv.With(labels)
 	collector.Add(delta)
 	g.collectors <- newCollector(g.name, labels, collector, func() {
@@ -438,7 +730,7 @@ func (g *gauge) Add(delta float64) {
 }
 
 func (g *gauge) Set(value float64) {
-	labels := g.labelNamesValues.ToLabels()
+	labels := g.labelNamesValues.ToLabels(g.labelNames)
 	collector := g.gv.With(labels)
 	collector.Set(value)
 	g.collectors <- newCollector(g.name, labels, collector, func() {
@@ -455,6 +747,7 @@ func newHistogramFrom(collectors chan<- *collector, opts stdprometheus.Histogram
 	return &histogram{
 		name:       opts.Name,
 		hv:         hv,
+		labelNames: newLabelNames(labelNames),
 		collectors: collectors,
 	}
 }
@@ -462,6 +755,7 @@ func newHistogramFrom(collectors chan<- *collector, opts stdprometheus.Histogram
 type histogram struct {
 	name             string
 	hv               *stdprometheus.HistogramVec
+	labelNames       map[string]bool
 	labelNamesValues labelNamesValues
 	collectors       chan<- *collector
 }
@@ -470,13 +764,14 @@ func (h *histogram) With(labelValues ...string) metrics.Histogram {
 	return &histogram{
 		name:             h.name,
 		hv:               h.hv,
+		labelNames:       h.labelNames,
 		labelNamesValues: h.labelNamesValues.With(labelValues...),
 		collectors:       h.collectors,
 	}
 }
 
 func (h *histogram) Observe(value float64) {
-	labels := h.labelNamesValues.ToLabels()
+	labels := h.labelNamesValues.ToLabels(h.labelNames)
 	collector := h.hv.With(labels)
 	collector.Observe(value)
 	h.collectors <- newCollector(h.name, labels, collector, func() {
@@ -503,10 +798,21 @@ func (lvs labelNamesValues) With(labelValues ...string) labelNamesValues {
 
 // ToLabels is a convenience method to convert a labelNamesValues
 // to the native prometheus.Labels.
-func (lvs labelNamesValues) ToLabels() stdprometheus.Labels {
+// Only the labels in labelNames are kept, as some labels can be removed from the metrics by the configuration.
+func (lvs labelNamesValues) ToLabels(labelNames map[string]bool) stdprometheus.Labels {
 	labels := stdprometheus.Labels{}
 	for i := 0; i < len(lvs); i += 2 {
-		labels[lvs[i]] = lvs[i+1]
+		if labelNames[lvs[i]] {
+			labels[lvs[i]] = lvs[i+1]
+		}
 	}
 	return labels
 }
+
+func newLabelNames(labelNames []string) map[string]bool {
+	names := make(map[string]bool, len(labelNames))
+	for _, name := range labelNames {
+		names[name] = true
+	}
+	return names
+}
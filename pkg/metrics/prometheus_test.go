@@ -14,6 +14,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRegisterPromState(t *testing.T) {
@@ -95,6 +96,7 @@ func (ps *prometheusState) reset() {
 	ps.collectors = make(chan *collector)
 	ps.describers = []func(ch chan<- *prometheus.Desc){}
 	ps.dynamicConfig = newDynamicConfig()
+	ps.tlsCertificates = nil
 	ps.state = make(map[string]*collector)
 }
 
@@ -114,6 +116,11 @@ func TestPrometheus(t *testing.T) {
 	prometheusRegistry.LastConfigReloadSuccessGauge().Set(float64(time.Now().Unix()))
 	prometheusRegistry.LastConfigReloadFailureGauge().Set(float64(time.Now().Unix()))
 
+	prometheusRegistry.
+		TLSCertsNotAfterTimestampGauge().
+		With("cn", "value", "serial", "value", "sans", "value").
+		Set(float64(time.Now().Unix()))
+
 	prometheusRegistry.
 		EntrypointReqsCounter().
 		With("code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http", "entrypoint", "http").
@@ -127,6 +134,15 @@ func TestPrometheus(t *testing.T) {
 		With("method", http.MethodGet, "protocol", "http", "entrypoint", "http").
 		Set(1)
 
+	prometheusRegistry.
+		RouterReqsCounter().
+		With("code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http", "router", "router1", "service", "service1").
+		Add(1)
+	prometheusRegistry.
+		RouterReqDurationHistogram().
+		With("code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http", "router", "router1", "service", "service1").
+		Observe(1)
+
 	prometheusRegistry.
 		BackendReqsCounter().
 		With("backend", "backend1", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http").
@@ -148,6 +164,31 @@ func TestPrometheus(t *testing.T) {
 		With("backend", "backend1", "url", "http://127.0.0.10:80").
 		Set(1)
 
+	prometheusRegistry.
+		TCPEntrypointConnsOpenedCounter().
+		With("entrypoint", "tcp").
+		Add(1)
+	prometheusRegistry.
+		TCPEntrypointConnDurationHistogram().
+		With("entrypoint", "tcp").
+		Observe(1)
+	prometheusRegistry.
+		TCPServiceBytesCounter().
+		With("direction", "in", "service", "service1").
+		Add(42)
+	prometheusRegistry.
+		TCPServiceDialFailuresCounter().
+		With("service", "service1").
+		Add(1)
+	prometheusRegistry.
+		AccessLogDroppedCounter().
+		With("reason", "buffer").
+		Add(1)
+	prometheusRegistry.
+		AccessLogSampledCounter().
+		With("decision", "kept").
+		Add(1)
+
 	delayForTrackingCompletion()
 
 	metricsFamilies := mustScrape()
@@ -173,6 +214,15 @@ func TestPrometheus(t *testing.T) {
 			name:   configLastReloadFailureName,
 			assert: buildTimestampAssert(t, configLastReloadFailureName),
 		},
+		{
+			name: tlsCertsNotAfterTimestampName,
+			labels: map[string]string{
+				"cn":     "value",
+				"serial": "value",
+				"sans":   "value",
+			},
+			assert: buildTimestampAssert(t, tlsCertsNotAfterTimestampName),
+		},
 		{
 			name: entrypointReqsTotalName,
 			labels: map[string]string{
@@ -246,6 +296,71 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, backendServerUpName, 1),
 		},
+		{
+			name: tcpEntrypointConnsOpenedName,
+			labels: map[string]string{
+				"entrypoint": "tcp",
+			},
+			assert: buildCounterAssert(t, tcpEntrypointConnsOpenedName, 1),
+		},
+		{
+			name: tcpEntrypointConnDurationName,
+			labels: map[string]string{
+				"entrypoint": "tcp",
+			},
+			assert: buildHistogramAssert(t, tcpEntrypointConnDurationName, 1),
+		},
+		{
+			name: tcpServiceBytesTotalName,
+			labels: map[string]string{
+				"direction": "in",
+				"service":   "service1",
+			},
+			assert: buildCounterAssert(t, tcpServiceBytesTotalName, 42),
+		},
+		{
+			name: tcpServiceDialFailuresTotalName,
+			labels: map[string]string{
+				"service": "service1",
+			},
+			assert: buildCounterAssert(t, tcpServiceDialFailuresTotalName, 1),
+		},
+		{
+			name: accessLogDroppedTotalName,
+			labels: map[string]string{
+				"reason": "buffer",
+			},
+			assert: buildCounterAssert(t, accessLogDroppedTotalName, 1),
+		},
+		{
+			name: accessLogSampledTotalName,
+			labels: map[string]string{
+				"decision": "kept",
+			},
+			assert: buildCounterAssert(t, accessLogSampledTotalName, 1),
+		},
+		{
+			name: routerReqsTotalName,
+			labels: map[string]string{
+				"code":     "200",
+				"method":   http.MethodGet,
+				"protocol": "http",
+				"router":   "router1",
+				"service":  "service1",
+			},
+			assert: buildCounterAssert(t, routerReqsTotalName, 1),
+		},
+		{
+			name: routerReqDurationName,
+			labels: map[string]string{
+				"code":     "200",
+				"method":   http.MethodGet,
+				"protocol": "http",
+				"router":   "router1",
+				"service":  "service1",
+			},
+			assert: buildHistogramAssert(t, routerReqDurationName, 1),
+		},
 	}
 
 	for _, test := range tests {
@@ -267,9 +382,6 @@ func TestPrometheus(t *testing.T) {
 }
 
 func TestPrometheusMetricRemoval(t *testing.T) {
-	// FIXME metrics
-	t.Skip("waiting for metrics")
-
 	// Reset state of global promState.
 	defer promState.reset()
 
@@ -287,9 +399,16 @@ func TestPrometheusMetricRemoval(t *testing.T) {
 				th.WithServers(th.WithServer("http://localhost:9000"))),
 			),
 		),
+		TCP: &config.TCPConfiguration{
+			Services: map[string]*config.TCPService{
+				"tcpbar": {},
+			},
+		},
 	}
 
-	OnConfigurationUpdate(configurations)
+	OnConfigurationUpdate(configurations, []string{"entrypoint1"})
+
+	routerLabels := []string{"code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http"}
 
 	// Register some metrics manually that are not part of the active configuration.
 	// Those metrics should be part of the /metrics output on the first scrape but
@@ -306,11 +425,23 @@ func TestPrometheusMetricRemoval(t *testing.T) {
 		BackendServerUpGauge().
 		With("backend", "backend1", "url", "http://localhost:9999").
 		Set(1)
+	prometheusRegistry.
+		TCPServiceConnsOpenedCounter().
+		With("service", "providerName@tcpfoo").
+		Add(1)
+	prometheusRegistry.
+		RouterReqsCounter().
+		With(append(routerLabels, "router", "providerName@baz", "service", "providerName@bar")...).
+		Add(1)
+	prometheusRegistry.
+		RouterReqDurationHistogram().
+		With(append(routerLabels, "router", "providerName@foo", "service", "providerName@qux")...).
+		Observe(1)
 
 	delayForTrackingCompletion()
 
-	assertMetricsExist(t, mustScrape(), entrypointReqsTotalName, backendReqsTotalName, backendServerUpName)
-	assertMetricsAbsent(t, mustScrape(), entrypointReqsTotalName, backendReqsTotalName, backendServerUpName)
+	assertMetricsExist(t, mustScrape(), entrypointReqsTotalName, backendReqsTotalName, backendServerUpName, tcpServiceConnsOpenedName, routerReqsTotalName, routerReqDurationName)
+	assertMetricsAbsent(t, mustScrape(), entrypointReqsTotalName, backendReqsTotalName, backendServerUpName, tcpServiceConnsOpenedName, routerReqsTotalName, routerReqDurationName)
 
 	// To verify that metrics belonging to active configurations are not removed
 	// here the counter examples.
@@ -318,11 +449,83 @@ func TestPrometheusMetricRemoval(t *testing.T) {
 		EntrypointReqsCounter().
 		With("entrypoint", "entrypoint1", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http").
 		Add(1)
+	prometheusRegistry.
+		TCPServiceConnsOpenedCounter().
+		With("service", "providerName@tcpbar").
+		Add(1)
+
+	delayForTrackingCompletion()
+
+	prometheusRegistry.
+		RouterReqsCounter().
+		With(append(routerLabels, "router", "providerName@foo", "service", "providerName@bar")...).
+		Add(1)
+
+	delayForTrackingCompletion()
+
+	assertMetricsExist(t, mustScrape(), entrypointReqsTotalName, tcpServiceConnsOpenedName, routerReqsTotalName)
+	assertMetricsExist(t, mustScrape(), entrypointReqsTotalName, tcpServiceConnsOpenedName, routerReqsTotalName)
+}
+
+func TestPrometheusLabelCardinality(t *testing.T) {
+	// Reset state of global promState.
+	defer promState.reset()
+
+	// The label names of the metrics differ from the other tests, so they are registered in a dedicated registry.
+	prometheusRegistry := initStandardRegistry(&types.Prometheus{
+		DisableMethodLabel:   true,
+		DisableProtocolLabel: true,
+		HeaderLabels: map[string]*types.PrometheusHeaderLabel{
+			"tenant":       {Header: "X-Tenant", Values: []string{"acme"}},
+			"code":         {Header: "X-Code"},
+			"invalid-name": {Header: "X-Invalid"},
+		},
+	})
+
+	gatherer := prometheus.NewRegistry()
+	require.NoError(t, gatherer.Register(promState))
+
+	header := http.Header{}
+	header.Set("X-Tenant", "unknown")
+	assert.Equal(t, []string{"tenant", "other"}, prometheusRegistry.HeaderLabels(header))
+
+	header.Set("X-Tenant", "acme")
+	assert.Equal(t, []string{"tenant", "acme"}, prometheusRegistry.HeaderLabels(header))
+
+	assert.Equal(t, []string{"tenant", ""}, prometheusRegistry.HeaderLabels(http.Header{}))
+
+	labels := []string{"method", http.MethodGet, "protocol", "http", "router", "router1", "service", "service1"}
+	labels = append(labels, prometheusRegistry.HeaderLabels(header)...)
+	labels = append(labels, "code", strconv.Itoa(http.StatusOK))
+
+	prometheusRegistry.RouterReqsCounter().With(labels...).Add(1)
+	prometheusRegistry.
+		EntrypointReqsCounter().
+		With("code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http", "entrypoint", "http").
+		Add(1)
 
 	delayForTrackingCompletion()
 
-	assertMetricsExist(t, mustScrape(), entrypointReqsTotalName)
-	assertMetricsExist(t, mustScrape(), entrypointReqsTotalName)
+	metricsFamilies, err := gatherer.Gather()
+	require.NoError(t, err)
+
+	routerFamily := findMetricFamily(routerReqsTotalName, metricsFamilies)
+	require.NotNil(t, routerFamily)
+	require.Len(t, routerFamily.Metric, 1)
+	assert.Equal(t, map[string]string{
+		"code":    "200",
+		"router":  "router1",
+		"service": "service1",
+		"tenant":  "acme",
+	}, labelPairs(routerFamily.Metric[0]))
+
+	entrypointFamily := findMetricFamily(entrypointReqsTotalName, metricsFamilies)
+	require.NotNil(t, entrypointFamily)
+	require.Len(t, entrypointFamily.Metric, 1)
+	assert.Equal(t, map[string]string{
+		"code":       "200",
+		"entrypoint": "http",
+	}, labelPairs(entrypointFamily.Metric[0]))
 }
 
 func TestPrometheusRemovedMetricsReset(t *testing.T) {
@@ -363,6 +566,40 @@ func TestPrometheusRemovedMetricsReset(t *testing.T) {
 	assertCounterValue(t, 1, findMetricFamily(backendReqsTotalName, metricsFamilies), labelNamesValues...)
 }
 
+func TestPrometheusTLSCertificatesRemoval(t *testing.T) {
+	// Reset state of global promState.
+	defer promState.reset()
+
+	prometheusRegistry := RegisterPrometheus(context.Background(), &types.Prometheus{})
+	defer prometheus.Unregister(promState)
+
+	OnTLSCertificatesUpdate([]string{"1"})
+
+	prometheusRegistry.
+		TLSCertsNotAfterTimestampGauge().
+		With("cn", "foo.com", "serial", "1", "sans", "foo.com").
+		Set(float64(time.Now().Unix()))
+	prometheusRegistry.
+		TLSCertsNotAfterTimestampGauge().
+		With("cn", "bar.com", "serial", "2", "sans", "bar.com").
+		Set(float64(time.Now().Unix()))
+
+	delayForTrackingCompletion()
+
+	metricsFamilies := mustScrape()
+	family := findMetricFamily(tlsCertsNotAfterTimestampName, metricsFamilies)
+	if family == nil || len(family.Metric) != 2 {
+		t.Fatalf("gathered metrics should contain the expiration of 2 certificates")
+	}
+
+	// The certificate 2 is not served anymore, so its metric is removed after the first scrape.
+	metricsFamilies = mustScrape()
+	family = findMetricFamily(tlsCertsNotAfterTimestampName, metricsFamilies)
+	if family == nil || len(family.Metric) != 1 {
+		t.Fatalf("gathered metrics should contain the expiration of 1 certificate")
+	}
+}
+
 // Tracking and gathering the metrics happens concurrently.
 // In practice this is no problem, because in case a tracked metric would miss
 // the current scrape, it would just be there in the next one.
@@ -424,6 +661,14 @@ func findMetricByLabelNamesValues(family *dto.MetricFamily, labelNamesValues ...
 	return nil
 }
 
+func labelPairs(metric *dto.Metric) map[string]string {
+	pairs := make(map[string]string)
+	for _, labelPair := range metric.Label {
+		pairs[labelPair.GetName()] = labelPair.GetValue()
+	}
+	return pairs
+}
+
 func hasMetricAllLabelPairs(metric *dto.Metric, labelNamesValues ...string) bool {
 	for i := 0; i < len(labelNamesValues); i += 2 {
 		name, val := labelNamesValues[i], labelNamesValues[i+1]
@@ -19,18 +19,32 @@ var statsdClient = statsd.New("traefik.", kitlog.LoggerFunc(func(keyvals ...inte
 var statsdTicker *time.Ticker
 
 const (
-	statsdMetricsBackendReqsName      = "backend.request.total"
-	statsdMetricsBackendLatencyName   = "backend.request.duration"
-	statsdRetriesTotalName            = "backend.retries.total"
-	statsdConfigReloadsName           = "config.reload.total"
-	statsdConfigReloadsFailureName    = statsdConfigReloadsName + ".failure"
-	statsdLastConfigReloadSuccessName = "config.reload.lastSuccessTimestamp"
-	statsdLastConfigReloadFailureName = "config.reload.lastFailureTimestamp"
-	statsdEntrypointReqsName          = "entrypoint.request.total"
-	statsdEntrypointReqDurationName   = "entrypoint.request.duration"
-	statsdEntrypointOpenConnsName     = "entrypoint.connections.open"
-	statsdOpenConnsName               = "backend.connections.open"
-	statsdServerUpName                = "backend.server.up"
+	statsdMetricsBackendReqsName        = "backend.request.total"
+	statsdMetricsBackendLatencyName     = "backend.request.duration"
+	statsdRetriesTotalName              = "backend.retries.total"
+	statsdConfigReloadsName             = "config.reload.total"
+	statsdConfigReloadsFailureName      = statsdConfigReloadsName + ".failure"
+	statsdLastConfigReloadSuccessName   = "config.reload.lastSuccessTimestamp"
+	statsdLastConfigReloadFailureName   = "config.reload.lastFailureTimestamp"
+	statsdTLSCertsNotAfterTimestamp     = "tls.certs.notAfterTimestamp"
+	statsdEntrypointReqsName            = "entrypoint.request.total"
+	statsdEntrypointReqDurationName     = "entrypoint.request.duration"
+	statsdEntrypointOpenConnsName       = "entrypoint.connections.open"
+	statsdRouterReqsName                = "router.request.total"
+	statsdRouterReqDurationName         = "router.request.duration"
+	statsdOpenConnsName                 = "backend.connections.open"
+	statsdServerUpName                  = "backend.server.up"
+	statsdTCPEntrypointConnsOpenedName  = "tcp.entrypoint.connections.opened.total"
+	statsdTCPEntrypointConnsClosedName  = "tcp.entrypoint.connections.closed.total"
+	statsdTCPEntrypointConnDurationName = "tcp.entrypoint.connection.duration"
+	statsdTCPEntrypointBytesName        = "tcp.entrypoint.bytes.total"
+	statsdTCPServiceConnsOpenedName     = "tcp.service.connections.opened.total"
+	statsdTCPServiceConnsClosedName     = "tcp.service.connections.closed.total"
+	statsdTCPServiceConnDurationName    = "tcp.service.connection.duration"
+	statsdTCPServiceBytesName           = "tcp.service.bytes.total"
+	statsdTCPServiceDialFailuresName    = "tcp.service.dial.failures.total"
+	statsdAccessLogDroppedName          = "accesslog.dropped.total"
+	statsdAccessLogSampledName          = "accesslog.sampled.total"
 )
 
 // RegisterStatsd registers the metrics pusher if this didn't happen yet and creates a statsd Registry instance.
@@ -40,19 +54,33 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 	}
 
 	return &standardRegistry{
-		enabled:                        true,
-		configReloadsCounter:           statsdClient.NewCounter(statsdConfigReloadsName, 1.0),
-		configReloadsFailureCounter:    statsdClient.NewCounter(statsdConfigReloadsFailureName, 1.0),
-		lastConfigReloadSuccessGauge:   statsdClient.NewGauge(statsdLastConfigReloadSuccessName),
-		lastConfigReloadFailureGauge:   statsdClient.NewGauge(statsdLastConfigReloadFailureName),
-		entrypointReqsCounter:          statsdClient.NewCounter(statsdEntrypointReqsName, 1.0),
-		entrypointReqDurationHistogram: statsdClient.NewTiming(statsdEntrypointReqDurationName, 1.0),
-		entrypointOpenConnsGauge:       statsdClient.NewGauge(statsdEntrypointOpenConnsName),
-		backendReqsCounter:             statsdClient.NewCounter(statsdMetricsBackendReqsName, 1.0),
-		backendReqDurationHistogram:    statsdClient.NewTiming(statsdMetricsBackendLatencyName, 1.0),
-		backendRetriesCounter:          statsdClient.NewCounter(statsdRetriesTotalName, 1.0),
-		backendOpenConnsGauge:          statsdClient.NewGauge(statsdOpenConnsName),
-		backendServerUpGauge:           statsdClient.NewGauge(statsdServerUpName),
+		enabled:                            true,
+		configReloadsCounter:               statsdClient.NewCounter(statsdConfigReloadsName, 1.0),
+		configReloadsFailureCounter:        statsdClient.NewCounter(statsdConfigReloadsFailureName, 1.0),
+		lastConfigReloadSuccessGauge:       statsdClient.NewGauge(statsdLastConfigReloadSuccessName),
+		lastConfigReloadFailureGauge:       statsdClient.NewGauge(statsdLastConfigReloadFailureName),
+		tlsCertsNotAfterTimestampGauge:     statsdClient.NewGauge(statsdTLSCertsNotAfterTimestamp),
+		entrypointReqsCounter:              statsdClient.NewCounter(statsdEntrypointReqsName, 1.0),
+		entrypointReqDurationHistogram:     statsdClient.NewTiming(statsdEntrypointReqDurationName, 1.0),
+		entrypointOpenConnsGauge:           statsdClient.NewGauge(statsdEntrypointOpenConnsName),
+		routerReqsCounter:                  statsdClient.NewCounter(statsdRouterReqsName, 1.0),
+		routerReqDurationHistogram:         statsdClient.NewTiming(statsdRouterReqDurationName, 1.0),
+		backendReqsCounter:                 statsdClient.NewCounter(statsdMetricsBackendReqsName, 1.0),
+		backendReqDurationHistogram:        statsdClient.NewTiming(statsdMetricsBackendLatencyName, 1.0),
+		backendRetriesCounter:              statsdClient.NewCounter(statsdRetriesTotalName, 1.0),
+		backendOpenConnsGauge:              statsdClient.NewGauge(statsdOpenConnsName),
+		backendServerUpGauge:               statsdClient.NewGauge(statsdServerUpName),
+		tcpEntrypointConnsOpenedCounter:    statsdClient.NewCounter(statsdTCPEntrypointConnsOpenedName, 1.0),
+		tcpEntrypointConnsClosedCounter:    statsdClient.NewCounter(statsdTCPEntrypointConnsClosedName, 1.0),
+		tcpEntrypointConnDurationHistogram: statsdClient.NewTiming(statsdTCPEntrypointConnDurationName, 1.0),
+		tcpEntrypointBytesCounter:          statsdClient.NewCounter(statsdTCPEntrypointBytesName, 1.0),
+		tcpServiceConnsOpenedCounter:       statsdClient.NewCounter(statsdTCPServiceConnsOpenedName, 1.0),
+		tcpServiceConnsClosedCounter:       statsdClient.NewCounter(statsdTCPServiceConnsClosedName, 1.0),
+		tcpServiceConnDurationHistogram:    statsdClient.NewTiming(statsdTCPServiceConnDurationName, 1.0),
+		tcpServiceBytesCounter:             statsdClient.NewCounter(statsdTCPServiceBytesName, 1.0),
+		tcpServiceDialFailuresCounter:      statsdClient.NewCounter(statsdTCPServiceDialFailuresName, 1.0),
+		accessLogDroppedCounter:            statsdClient.NewCounter(statsdAccessLogDroppedName, 1.0),
+		accessLogSampledCounter:            statsdClient.NewCounter(statsdAccessLogSampledName, 1.0),
 	}
 }
 
@@ -2,8 +2,10 @@ package accesslog
 
 import (
 	"net/http"
+	"net/http/httptrace"
 	"time"
 
+	"github.com/containous/traefik/pkg/config"
 	"github.com/vulcand/oxy/utils"
 )
 
@@ -44,8 +46,25 @@ func AddServiceFields(rw http.ResponseWriter, req *http.Request, next http.Handl
 	data.Core[ServiceURL] = req.URL // note that this is *not* the original incoming URL
 	data.Core[ServiceAddr] = req.URL.Host
 
-	next.ServeHTTP(rw, req)
+	// The targets are only recorded once the request is retried.
+	if targets, ok := data.Core[RetryTargets].(string); ok {
+		data.Core[RetryTargets] = targets + "," + req.URL.Host
+	}
+
+	trace := newUpstreamTrace()
 
+	next.ServeHTTP(rw, req.WithContext(httptrace.WithClientTrace(req.Context(), trace.clientTrace())))
+
+	trace.addFields(data.Core)
+}
+
+// AddRouterOptions returns a hook adding the access log options of the router to the log data.
+func AddRouterOptions(options *config.RouterAccessLog) FieldApply {
+	return func(rw http.ResponseWriter, req *http.Request, next http.Handler, data *LogData) {
+		data.RouterOptions = options
+
+		next.ServeHTTP(rw, req)
+	}
 }
 
 // AddOriginFields add origin fields
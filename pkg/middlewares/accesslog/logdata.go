@@ -2,6 +2,8 @@ package accesslog
 
 import (
 	"net/http"
+
+	"github.com/containous/traefik/pkg/config"
 )
 
 const (
@@ -13,6 +15,9 @@ const (
 	// not the log writing time.
 	Duration = "Duration"
 
+	// EntryPointName is the map key used for the name of the entry point which received the request.
+	// Unlike the other keys, it is not capitalized, as it was logged before being documented.
+	EntryPointName = "entryPointName"
 	// RouterName is the map key used for the name of the Traefik router.
 	RouterName = "RouterName"
 	// ServiceName is the map key used for the name of the Traefik backend.
@@ -55,6 +60,18 @@ const (
 	// OriginStatus is the map key used for the HTTP status code returned by the origin server.
 	// If the request was handled by this Traefik instance (e.g. with a redirect), then this value will be absent.
 	OriginStatus = "OriginStatus"
+	// OriginTimeToFirstByte is the map key used for the time taken by the origin server to send the first byte of its response,
+	// from the start of the attempt which succeeded.
+	OriginTimeToFirstByte = "OriginTimeToFirstByte"
+	// OriginDNSDuration is the map key used for the time taken to resolve the address of the origin server.
+	// It is absent when the connection to the origin server was reused.
+	OriginDNSDuration = "OriginDNSDuration"
+	// OriginConnectDuration is the map key used for the time taken to connect to the origin server.
+	// It is absent when the connection to the origin server was reused.
+	OriginConnectDuration = "OriginConnectDuration"
+	// OriginTLSDuration is the map key used for the time taken by the TLS handshake with the origin server.
+	// It is absent when the connection to the origin server was reused, or is not encrypted.
+	OriginTLSDuration = "OriginTLSDuration"
 	// DownstreamStatus is the map key used for the HTTP status code returned to the client.
 	DownstreamStatus = "DownstreamStatus"
 	// DownstreamContentSize is the map key used for the number of bytes in the response entity returned to the client.
@@ -68,6 +85,21 @@ const (
 	Overhead = "Overhead"
 	// RetryAttempts is the map key used for the amount of attempts the request was retried.
 	RetryAttempts = "RetryAttempts"
+	// RetryTargets is the map key used for the addresses of the servers the request was forwarded to, one per attempt,
+	// when it was retried.
+	RetryTargets = "RetryTargets"
+	// TLSServerName is the map key used for the server name sent by the client in the TLS handshake.
+	TLSServerName = "TLSServerName"
+	// TLSVersion is the map key used for the TLS version negotiated with the client.
+	TLSVersion = "TLSVersion"
+	// TLSCipher is the map key used for the TLS cipher suite negotiated with the client.
+	TLSCipher = "TLSCipher"
+	// TLSClientSubject is the map key used for the subject of the certificate sent by the client.
+	TLSClientSubject = "TLSClientSubject"
+	// TLSALPN is the map key used for the application protocol negotiated with the client (ALPN).
+	TLSALPN = "TLSALPN"
+	// CloseReason is the map key used for the reason for which a TCP connection was closed.
+	CloseReason = "CloseReason"
 )
 
 // These are written out in the default case when no config is provided to specify keys of interest.
@@ -108,6 +140,18 @@ func init() {
 	allCoreKeys[StartLocal] = struct{}{}
 	allCoreKeys[Overhead] = struct{}{}
 	allCoreKeys[RetryAttempts] = struct{}{}
+	allCoreKeys[TLSServerName] = struct{}{}
+	allCoreKeys[CloseReason] = struct{}{}
+	allCoreKeys[EntryPointName] = struct{}{}
+	allCoreKeys[OriginTimeToFirstByte] = struct{}{}
+	allCoreKeys[OriginDNSDuration] = struct{}{}
+	allCoreKeys[OriginConnectDuration] = struct{}{}
+	allCoreKeys[OriginTLSDuration] = struct{}{}
+	allCoreKeys[RetryTargets] = struct{}{}
+	allCoreKeys[TLSVersion] = struct{}{}
+	allCoreKeys[TLSCipher] = struct{}{}
+	allCoreKeys[TLSClientSubject] = struct{}{}
+	allCoreKeys[TLSALPN] = struct{}{}
 }
 
 // CoreLogData holds the fields computed from the request/response.
@@ -119,4 +163,6 @@ type LogData struct {
 	Request            http.Header
 	OriginResponse     http.Header
 	DownstreamResponse http.Header
+	// RouterOptions are the access log options of the router which handled the request, if any.
+	RouterOptions *config.RouterAccessLog
 }
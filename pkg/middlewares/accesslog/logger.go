@@ -3,6 +3,7 @@ package accesslog
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"net"
 	"net/http"
 	"net/url"
@@ -14,7 +15,9 @@ import (
 
 	"github.com/containous/alice"
 	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/metrics"
 	"github.com/containous/traefik/pkg/types"
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/sirupsen/logrus"
 )
 
@@ -29,6 +32,15 @@ const (
 
 	// JSONFormat is the JSON logging format.
 	JSONFormat string = "json"
+
+	// CombinedFormat is the combined logging format, as written by the Apache HTTP server.
+	CombinedFormat string = "combined"
+
+	// LogfmtFormat is the logfmt logging format.
+	LogfmtFormat string = "logfmt"
+
+	// TemplateFormat is the logging format described by a template.
+	TemplateFormat string = "template"
 )
 
 type handlerParams struct {
@@ -41,11 +53,18 @@ type handlerParams struct {
 type Handler struct {
 	config         *types.AccessLog
 	logger         *logrus.Logger
-	file           *os.File
+	out            sink
 	mu             sync.Mutex
 	httpCodeRanges types.HTTPCodeRanges
 	logHandlerChan chan handlerParams
-	wg             sync.WaitGroup
+	// dropOnFullBuffer is set for the outputs which already drop the lines they cannot send,
+	// the file and the standard output are lossless.
+	dropOnFullBuffer bool
+	wg               sync.WaitGroup
+	droppedCounter   gokitmetrics.Counter
+	sampledCounter   gokitmetrics.Counter
+	// random returns the pseudo-random numbers, in [0,1), of the sampling.
+	random func() float64
 }
 
 // WrapHandler Wraps access log handler into an Alice Constructor.
@@ -57,18 +76,8 @@ func WrapHandler(handler *Handler) alice.Constructor {
 	}
 }
 
-// NewHandler creates a new Handler.
-func NewHandler(config *types.AccessLog) (*Handler, error) {
-	file := os.Stdout
-	if len(config.FilePath) > 0 {
-		f, err := openAccessLogFile(config.FilePath)
-		if err != nil {
-			return nil, fmt.Errorf("error opening access log file: %s", err)
-		}
-		file = f
-	}
-	logHandlerChan := make(chan handlerParams, config.BufferingSize)
-
+// NewHandler creates a new Handler, counting the dropped access log lines with the metrics registry.
+func NewHandler(config *types.AccessLog, metricsRegistry metrics.Registry) (*Handler, error) {
 	var formatter logrus.Formatter
 
 	switch config.Format {
@@ -76,22 +85,53 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 		formatter = new(CommonLogFormatter)
 	case JSONFormat:
 		formatter = new(logrus.JSONFormatter)
+	case CombinedFormat:
+		formatter = new(CombinedLogFormatter)
+	case LogfmtFormat:
+		formatter = new(LogfmtFormatter)
+	case TemplateFormat:
+		templateFormatter, err := NewTemplateFormatter(config.Template)
+		if err != nil {
+			return nil, err
+		}
+		formatter = templateFormatter
 	default:
 		return nil, fmt.Errorf("unsupported access log format: %s", config.Format)
 	}
 
+	if config.Filters != nil {
+		if err := ValidateSampleRate(config.Filters.SampleRate); err != nil {
+			return nil, err
+		}
+	}
+
+	droppedCounter := metricsRegistry.AccessLogDroppedCounter()
+
+	out, err := newSink(config, func() {
+		droppedCounter.With("reason", droppedSink).Add(1)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logHandlerChan := make(chan handlerParams, config.BufferingSize)
+
 	logger := &logrus.Logger{
-		Out:       file,
+		Out:       out,
 		Formatter: formatter,
 		Hooks:     make(logrus.LevelHooks),
 		Level:     logrus.InfoLevel,
 	}
 
 	logHandler := &Handler{
-		config:         config,
-		logger:         logger,
-		file:           file,
-		logHandlerChan: logHandlerChan,
+		config:           config,
+		logger:           logger,
+		out:              out,
+		logHandlerChan:   logHandlerChan,
+		dropOnFullBuffer: config.Syslog != nil || config.Remote != nil,
+		droppedCounter:   droppedCounter,
+		sampledCounter:   metricsRegistry.AccessLogSampledCounter(),
+		random:           rand.Float64,
 	}
 
 	if config.Filters != nil {
@@ -107,6 +147,11 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 		go func() {
 			defer logHandler.wg.Done()
 			for handlerParams := range logHandler.logHandlerChan {
+				// Only the HTTP requests have a captured response.
+				if handlerParams.crw == nil {
+					logHandler.logTheTCPConnection(handlerParams.logDataTable)
+					continue
+				}
 				logHandler.logTheRoundTrip(handlerParams.logDataTable, handlerParams.crr, handlerParams.crw)
 			}
 		}()
@@ -182,6 +227,10 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http
 		core[ClientHost] = forwardedFor
 	}
 
+	if req.TLS != nil {
+		addTLSFields(core, req.TLS)
+	}
+
 	crw := &captureResponseWriter{rw: rw}
 
 	next.ServeHTTP(crw, reqWithDataTable)
@@ -193,41 +242,41 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http
 	logDataTable.DownstreamResponse = crw.Header()
 
 	if h.config.BufferingSize > 0 {
-		h.logHandlerChan <- handlerParams{
+		h.enqueue(handlerParams{
 			logDataTable: logDataTable,
 			crr:          crr,
 			crw:          crw,
-		}
+		})
 	} else {
 		h.logTheRoundTrip(logDataTable, crr, crw)
 	}
 }
 
-// Close closes the Logger (i.e. the file, drain logHandlerChan, etc).
+// enqueue queues the access log line to be written in the background.
+// If the buffer is full, it waits for the line to be queued, or drops it for the syslog and remote outputs.
+func (h *Handler) enqueue(params handlerParams) {
+	if !h.dropOnFullBuffer {
+		h.logHandlerChan <- params
+		return
+	}
+
+	select {
+	case h.logHandlerChan <- params:
+	default:
+		h.droppedCounter.With("reason", droppedBufferFull).Add(1)
+	}
+}
+
+// Close closes the Logger (i.e. the output, drain logHandlerChan, etc).
 func (h *Handler) Close() error {
 	close(h.logHandlerChan)
 	h.wg.Wait()
-	return h.file.Close()
+	return h.out.Close()
 }
 
 // Rotate closes and reopens the log file to allow for rotation by an external source.
 func (h *Handler) Rotate() error {
-	var err error
-
-	if h.file != nil {
-		defer func(f *os.File) {
-			f.Close()
-		}(h.file)
-	}
-
-	h.file, err = os.OpenFile(h.config.FilePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0664)
-	if err != nil {
-		return err
-	}
-	h.mu.Lock()
-	defer h.mu.Unlock()
-	h.logger.Out = h.file
-	return nil
+	return h.out.Rotate()
 }
 
 func silentSplitHostPort(value string) (host string, port string) {
@@ -267,7 +316,9 @@ func (h *Handler) logTheRoundTrip(logDataTable *LogData, crr *captureRequestRead
 	totalDuration := time.Now().UTC().Sub(core[StartUTC].(time.Time))
 	core[Duration] = totalDuration
 
-	if h.keepAccessLog(crw.Status(), retryAttempts, totalDuration) {
+	if h.routerEnabled(logDataTable.RouterOptions) &&
+		h.keepAccessLog(crw.Status(), retryAttempts, totalDuration) &&
+		h.sampled(logDataTable.RouterOptions, crw.Status()) {
 		core[DownstreamContentSize] = crw.Size()
 		if original, ok := core[OriginContentSize]; ok {
 			o64 := original.(int64)
@@ -3,7 +3,11 @@ package accesslog
 import (
 	"bytes"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/sirupsen/logrus"
 )
@@ -50,6 +54,87 @@ func (f *CommonLogFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	return b.Bytes(), err
 }
 
+// CombinedLogFormatter provides formatting in the Combined Log Format, as written by the Apache HTTP server.
+type CombinedLogFormatter struct{}
+
+// Format formats the log entry in the Combined Log Format.
+func (f *CombinedLogFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	b := &bytes.Buffer{}
+
+	var timestamp = defaultValue
+	if v, ok := entry.Data[StartUTC]; ok {
+		timestamp = v.(time.Time).Format(commonLogTimeFormat)
+	}
+
+	// The size of the response is - when no bytes were sent.
+	size := toLog(entry.Data, DownstreamContentSize, defaultValue, false)
+	if v, ok := size.(int64); ok && v == 0 {
+		size = defaultValue
+	}
+
+	requestLine := fmt.Sprintf("%s %s %s",
+		toLog(entry.Data, RequestMethod, defaultValue, false),
+		toLog(entry.Data, RequestPath, defaultValue, false),
+		toLog(entry.Data, RequestProtocol, defaultValue, false))
+
+	_, err := fmt.Fprintf(b, "%s - %s [%s] %s %v %v %s %s\n",
+		escapeControlCharacters(fmt.Sprint(toLog(entry.Data, ClientHost, defaultValue, false))),
+		escapeControlCharacters(fmt.Sprint(toLog(entry.Data, ClientUsername, defaultValue, false))),
+		timestamp,
+		strconv.Quote(requestLine),
+		toLog(entry.Data, DownstreamStatus, defaultValue, false),
+		size,
+		strconv.Quote(fmt.Sprint(toLog(entry.Data, RequestRefererHeader, defaultValue, false))),
+		strconv.Quote(fmt.Sprint(toLog(entry.Data, RequestUserAgentHeader, defaultValue, false))))
+
+	return b.Bytes(), err
+}
+
+// LogfmtFormatter provides formatting in the logfmt format: a key=value pair per field, sorted by key.
+// The durations are written in nanoseconds and the times in RFC 3339, as in the JSON format.
+type LogfmtFormatter struct{}
+
+// Format formats the log entry in the logfmt format.
+func (f *LogfmtFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	b := &bytes.Buffer{}
+	for i, key := range keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(logfmtValue(entry.Data[key]))
+	}
+	b.WriteByte('\n')
+
+	return b.Bytes(), nil
+}
+
+func logfmtValue(value interface{}) string {
+	var s string
+	switch v := value.(type) {
+	case time.Duration:
+		s = strconv.FormatInt(int64(v), 10)
+	default:
+		s, _ = fieldString(value)
+	}
+
+	if len(s) == 0 || strings.IndexFunc(s, needsLogfmtQuoting) >= 0 {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func needsLogfmtQuoting(r rune) bool {
+	return r <= ' ' || r == '=' || r == '"' || r == 0x7f || r == utf8.RuneError
+}
+
 func toLog(fields logrus.Fields, key string, defaultValue string, quoted bool) interface{} {
 	if v, ok := fields[key]; ok {
 		if v == nil {
@@ -77,6 +77,79 @@ func TestCommonLogFormatter_Format(t *testing.T) {
 
 }
 
+func TestCombinedLogFormatter_Format(t *testing.T) {
+	clf := CombinedLogFormatter{}
+
+	testCases := []struct {
+		name        string
+		data        map[string]interface{}
+		expectedLog string
+	}{
+		{
+			name: "missing data",
+			data: map[string]interface{}{
+				StartUTC:              time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC),
+				ClientHost:            "10.0.0.1",
+				RequestMethod:         http.MethodGet,
+				RequestPath:           "/foo",
+				RequestProtocol:       "HTTP/1.1",
+				DownstreamContentSize: int64(0),
+			},
+			expectedLog: `10.0.0.1 - - [10/Nov/2009:23:00:00 +0000] "GET /foo HTTP/1.1" - - "-" "-"
+`,
+		},
+		{
+			name: "all data",
+			data: map[string]interface{}{
+				StartUTC:               time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC),
+				ClientHost:             "10.0.0.1",
+				ClientUsername:         "Client",
+				RequestMethod:          http.MethodGet,
+				RequestPath:            `/foo?bar="baz"`,
+				RequestProtocol:        "HTTP/1.1",
+				DownstreamStatus:       200,
+				DownstreamContentSize:  int64(132),
+				RequestRefererHeader:   "referer",
+				RequestUserAgentHeader: `agent "007"`,
+			},
+			expectedLog: `10.0.0.1 - Client [10/Nov/2009:23:00:00 +0000] "GET /foo?bar=\"baz\" HTTP/1.1" 200 132 "referer" "agent \"007\""
+`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			raw, err := clf.Format(&logrus.Entry{Data: test.data})
+			assert.NoError(t, err)
+
+			assert.Equal(t, test.expectedLog, string(raw))
+		})
+	}
+}
+
+func TestLogfmtFormatter_Format(t *testing.T) {
+	formatter := LogfmtFormatter{}
+
+	data := map[string]interface{}{
+		StartUTC:             time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC),
+		Duration:             123 * time.Millisecond,
+		RouterName:           "foo@file",
+		ServiceURL:           "",
+		OriginStatus:         200,
+		RequestRefererHeader: `foo "bar"=baz`,
+		ClientUsername:       nil,
+	}
+
+	raw, err := formatter.Format(&logrus.Entry{Data: data})
+	assert.NoError(t, err)
+
+	expected := `ClientUsername="" Duration=123000000 OriginStatus=200 RouterName=foo@file ServiceURL="" StartUTC=2009-11-10T23:00:00Z request_Referer="foo \"bar\"=baz"` + "\n"
+	assert.Equal(t, expected, string(raw))
+}
+
 func Test_toLog(t *testing.T) {
 
 	testCases := []struct {
@@ -0,0 +1,215 @@
+package accesslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Escaping modes of the template fields.
+const (
+	// escapeDefault escapes the control characters.
+	escapeDefault = ""
+	// escapeRaw writes the value as is.
+	escapeRaw = "raw"
+	// escapeQuote writes the value as a double quoted string, with Go escape sequences.
+	escapeQuote = "quote"
+	// escapeJSON writes the value as a JSON value.
+	escapeJSON = "json"
+)
+
+// headerPrefixes are the prefixes of the fields holding the headers.
+var headerPrefixes = []string{"request_", "origin_", "downstream_"}
+
+// templateSegment is either a literal text, or a field when name is set.
+type templateSegment struct {
+	literal string
+	name    string
+	escape  string
+}
+
+// TemplateFormatter formats the log entries with a template,
+// where the %{Field} placeholders are replaced by the values of the fields, and %% by %.
+// The escaping of a field can be chosen with %{Field:raw}, %{Field:quote} or %{Field:json}.
+type TemplateFormatter struct {
+	segments []templateSegment
+}
+
+// NewTemplateFormatter parses and validates the template.
+func NewTemplateFormatter(template string) (*TemplateFormatter, error) {
+	if len(template) == 0 {
+		return nil, fmt.Errorf("empty access log template")
+	}
+
+	var segments []templateSegment
+	var literal strings.Builder
+
+	for i := 0; i < len(template); i++ {
+		if template[i] != '%' {
+			literal.WriteByte(template[i])
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(template[i:], "%%"):
+			literal.WriteByte('%')
+			i++
+		case strings.HasPrefix(template[i:], "%{"):
+			end := strings.IndexByte(template[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated placeholder at position %d of the access log template", i)
+			}
+
+			segment, err := parsePlaceholder(template[i+2 : i+end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid placeholder at position %d of the access log template: %v", i, err)
+			}
+
+			if literal.Len() > 0 {
+				segments = append(segments, templateSegment{literal: literal.String()})
+				literal.Reset()
+			}
+			segments = append(segments, segment)
+			i += end
+		default:
+			return nil, fmt.Errorf("invalid %% at position %d of the access log template, a literal %% is written %%%%", i)
+		}
+	}
+
+	if literal.Len() > 0 {
+		segments = append(segments, templateSegment{literal: literal.String()})
+	}
+
+	return &TemplateFormatter{segments: segments}, nil
+}
+
+func parsePlaceholder(placeholder string) (templateSegment, error) {
+	parts := strings.SplitN(placeholder, ":", 2)
+
+	segment := templateSegment{name: parts[0]}
+	if len(parts) == 2 {
+		segment.escape = parts[1]
+	}
+
+	if !isValidField(segment.name) {
+		return templateSegment{}, fmt.Errorf("unknown field %q", segment.name)
+	}
+
+	switch segment.escape {
+	case escapeRaw, escapeQuote, escapeJSON:
+	case escapeDefault:
+		if len(parts) == 2 {
+			return templateSegment{}, fmt.Errorf("empty escaping of the field %q", segment.name)
+		}
+	default:
+		return templateSegment{}, fmt.Errorf("unknown escaping %q of the field %q", segment.escape, segment.name)
+	}
+
+	return segment, nil
+}
+
+func isValidField(name string) bool {
+	if _, ok := allCoreKeys[name]; ok {
+		return true
+	}
+
+	for _, prefix := range headerPrefixes {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Format formats the log entry with the template.
+func (f *TemplateFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	b := &bytes.Buffer{}
+
+	for _, segment := range f.segments {
+		if len(segment.name) == 0 {
+			b.WriteString(segment.literal)
+			continue
+		}
+
+		if err := writeField(b, entry.Data[segment.name], segment.escape); err != nil {
+			return nil, err
+		}
+	}
+
+	b.WriteByte('\n')
+	return b.Bytes(), nil
+}
+
+func writeField(b *bytes.Buffer, value interface{}, escape string) error {
+	if escape == escapeJSON {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		b.Write(raw)
+		return nil
+	}
+
+	s, ok := fieldString(value)
+	if !ok {
+		s = defaultValue
+	}
+
+	switch escape {
+	case escapeRaw:
+		b.WriteString(s)
+	case escapeQuote:
+		b.WriteString(strconv.Quote(s))
+	default:
+		b.WriteString(escapeControlCharacters(s))
+	}
+	return nil
+}
+
+// fieldString returns the string representation of the value of a field, and whether the value is set.
+func fieldString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, len(v) > 0
+	case time.Time:
+		return v.Format(time.RFC3339Nano), true
+	case fmt.Stringer:
+		return v.String(), true
+	default:
+		return fmt.Sprint(v), true
+	}
+}
+
+// escapeControlCharacters replaces the control characters, and the invalid UTF-8 bytes, by their \x escape sequence,
+// so that a value cannot inject a line in the access log.
+func escapeControlCharacters(s string) string {
+	if strings.IndexFunc(s, isUnsafe) < 0 {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if isUnsafe(r) {
+			for _, c := range []byte(s[i : i+size]) {
+				fmt.Fprintf(&b, `\x%02x`, c)
+			}
+		} else {
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+func isUnsafe(r rune) bool {
+	return r < 0x20 || r == 0x7f || r == utf8.RuneError
+}
@@ -0,0 +1,145 @@
+package accesslog
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTemplateFormatter(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		template string
+		expected []templateSegment
+	}{
+		{
+			desc:     "literal only",
+			template: "foo 100%% bar",
+			expected: []templateSegment{{literal: "foo 100% bar"}},
+		},
+		{
+			desc:     "fields",
+			template: `%{ClientHost} "%{request_User-Agent:quote}" %{Duration:json}%{RouterName:raw}`,
+			expected: []templateSegment{
+				{name: ClientHost},
+				{literal: ` "`},
+				{name: "request_User-Agent", escape: escapeQuote},
+				{literal: `" `},
+				{name: Duration, escape: escapeJSON},
+				{name: RouterName, escape: escapeRaw},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			formatter, err := NewTemplateFormatter(test.template)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, formatter.segments)
+		})
+	}
+}
+
+func TestNewTemplateFormatter_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		template string
+	}{
+		{desc: "empty template", template: ""},
+		{desc: "unknown field", template: "%{Foo}"},
+		{desc: "header without name", template: "%{request_}"},
+		{desc: "unknown escaping", template: "%{RouterName:foo}"},
+		{desc: "empty escaping", template: "%{RouterName:}"},
+		{desc: "unterminated placeholder", template: "%{RouterName"},
+		{desc: "lone percent", template: "100% %{RouterName}"},
+		{desc: "trailing percent", template: "%{RouterName} %"},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewTemplateFormatter(test.template)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTemplateFormatter_Format(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		template    string
+		data        map[string]interface{}
+		expectedLog string
+	}{
+		{
+			desc:     "default escaping",
+			template: "%{ClientHost} %{RouterName} %{OriginStatus} %{Duration} %{StartUTC}",
+			data: map[string]interface{}{
+				ClientHost:   "10.0.0.1\nfake line",
+				RouterName:   "",
+				OriginStatus: 200,
+				Duration:     1500 * time.Millisecond,
+				StartUTC:     time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC),
+			},
+			expectedLog: "10.0.0.1\\x0afake line - 200 1.5s 2009-11-10T23:00:00Z\n",
+		},
+		{
+			desc:     "quote escaping",
+			template: "%{request_User-Agent:quote} %{request_Referer:quote}",
+			data: map[string]interface{}{
+				"request_User-Agent": `foo "bar"`,
+			},
+			expectedLog: `"foo \"bar\"" "-"` + "\n",
+		},
+		{
+			desc:     "json escaping",
+			template: `{"router":%{RouterName:json},"status":%{OriginStatus:json},"duration":%{Duration:json},"service":%{ServiceName:json}}`,
+			data: map[string]interface{}{
+				RouterName:   `foo"bar`,
+				OriginStatus: http.StatusOK,
+				Duration:     time.Second,
+			},
+			expectedLog: `{"router":"foo\"bar","status":200,"duration":1000000000,"service":null}` + "\n",
+		},
+		{
+			desc:     "raw",
+			template: "%{RouterName:raw} %{ServiceName:raw}",
+			data: map[string]interface{}{
+				RouterName: "foo\tbar",
+			},
+			expectedLog: "foo\tbar -\n",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			formatter, err := NewTemplateFormatter(test.template)
+			require.NoError(t, err)
+
+			raw, err := formatter.Format(&logrus.Entry{Data: test.data})
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedLog, string(raw))
+		})
+	}
+}
+
+func TestEscapeControlCharacters(t *testing.T) {
+	assert.Equal(t, "foo bar", escapeControlCharacters("foo bar"))
+	assert.Equal(t, "héllo", escapeControlCharacters("héllo"))
+	assert.Equal(t, `foo\x0d\x0abar\x7f`, escapeControlCharacters("foo\r\nbar\x7f"))
+	assert.Equal(t, `foo\xff`, escapeControlCharacters("foo\xff"))
+}
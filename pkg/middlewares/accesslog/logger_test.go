@@ -14,7 +14,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/containous/traefik/pkg/metrics"
 	"github.com/containous/traefik/pkg/types"
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -49,7 +51,7 @@ func TestLogRotation(t *testing.T) {
 	rotatedFileName := fileName + ".rotated"
 
 	config := &types.AccessLog{FilePath: fileName, Format: CommonFormat}
-	logHandler, err := NewHandler(config)
+	logHandler, err := NewHandler(config, metrics.NewVoidRegistry())
 	if err != nil {
 		t.Fatalf("Error creating new log handler: %s", err)
 	}
@@ -132,6 +134,37 @@ func TestLoggerCLF(t *testing.T) {
 	assertValidLogData(t, expectedLog, logData)
 }
 
+func TestLoggerTemplate(t *testing.T) {
+	tmpDir := createTempDir(t, TemplateFormat)
+	defer os.RemoveAll(tmpDir)
+
+	logFilePath := filepath.Join(tmpDir, logFileNameSuffix)
+	config := &types.AccessLog{
+		FilePath: logFilePath,
+		Format:   TemplateFormat,
+		Template: `%{ClientHost} %{RouterName} %{request_User-Agent:quote} %{request_Referer:quote} %{OriginStatus:json}`,
+		Fields: &types.AccessLogFields{
+			DefaultMode: types.AccessLogKeep,
+			Names:       map[string]string{RouterName: types.AccessLogDrop},
+			Headers: &types.FieldHeaders{
+				DefaultMode: types.AccessLogKeep,
+				Names:       map[string]string{"User-Agent": types.AccessLogRedact, "Referer": types.AccessLogDrop},
+			},
+		},
+	}
+	doLogging(t, config)
+
+	logData, err := ioutil.ReadFile(logFilePath)
+	require.NoError(t, err)
+
+	assert.Equal(t, "TestHost - \"REDACTED\" \"-\" 123\n", string(logData))
+}
+
+func TestNewHandler_invalidTemplate(t *testing.T) {
+	_, err := NewHandler(&types.AccessLog{Format: TemplateFormat, Template: "%{Foo}"}, metrics.NewVoidRegistry())
+	assert.Error(t, err)
+}
+
 func TestAsyncLoggerCLF(t *testing.T) {
 	tmpDir := createTempDir(t, CommonFormat)
 	defer os.RemoveAll(tmpDir)
@@ -147,6 +180,38 @@ func TestAsyncLoggerCLF(t *testing.T) {
 	assertValidLogData(t, expectedLog, logData)
 }
 
+func TestAsyncLogger_dropped(t *testing.T) {
+	counter := &testCounter{}
+	handler := &Handler{
+		logHandlerChan:   make(chan handlerParams, 1),
+		dropOnFullBuffer: true,
+		droppedCounter:   counter,
+	}
+
+	for i := 0; i < 3; i++ {
+		handler.enqueue(handlerParams{logDataTable: &LogData{}})
+	}
+
+	assert.Len(t, handler.logHandlerChan, 1)
+	assert.Equal(t, float64(2), counter.value)
+	assert.Equal(t, []string{"reason", droppedBufferFull}, counter.labels)
+}
+
+// testCounter is a counter keeping its value and the labels it was last used with.
+type testCounter struct {
+	labels []string
+	value  float64
+}
+
+func (c *testCounter) With(labelValues ...string) gokitmetrics.Counter {
+	c.labels = labelValues
+	return c
+}
+
+func (c *testCounter) Add(delta float64) {
+	c.value += delta
+}
+
 func assertString(exp string) func(t *testing.T, actual interface{}) {
 	return func(t *testing.T, actual interface{}) {
 		t.Helper()
@@ -599,7 +664,7 @@ func createTempDir(t *testing.T, prefix string) string {
 }
 
 func doLogging(t *testing.T, config *types.AccessLog) {
-	logger, err := NewHandler(config)
+	logger, err := NewHandler(config, metrics.NewVoidRegistry())
 	require.NoError(t, err)
 	defer logger.Close()
 
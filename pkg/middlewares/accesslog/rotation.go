@@ -0,0 +1,185 @@
+package accesslog
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/types"
+)
+
+const (
+	// backupTimeFormat is the format of the time added to the name of the rotated files.
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+	compressSuffix   = ".gz"
+	megabyte         = 1024 * 1024
+)
+
+// rotation renames the access log file when it gets too big or too old,
+// then removes the oldest rotated files and compresses the others in the background.
+type rotation struct {
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+
+	// lock serializes the clean-ups of the rotated files.
+	lock sync.Mutex
+	wg   sync.WaitGroup
+}
+
+func newRotation(path string, config *types.AccessLogRotation) *rotation {
+	return &rotation{
+		path:       path,
+		maxSize:    int64(config.MaxSize) * megabyte,
+		maxAge:     time.Duration(config.MaxAge),
+		maxBackups: config.MaxBackups,
+		compress:   config.Compress,
+	}
+}
+
+// isDue checks whether the file, of the given size and age, has to be rotated before writing the next bytes to it.
+// An empty file is never rotated.
+func (r *rotation) isDue(size, next int64, age time.Duration) bool {
+	if size == 0 {
+		return false
+	}
+	return (r.maxSize > 0 && size+next > r.maxSize) || (r.maxAge > 0 && age >= r.maxAge)
+}
+
+// archive renames the closed access log file with the given time,
+// then removes the oldest rotated files and compresses the others in the background.
+func (r *rotation) archive(now time.Time) error {
+	if err := os.Rename(r.path, r.backupName(now)); err != nil {
+		return err
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.cleanUp()
+	}()
+
+	return nil
+}
+
+// wait waits for the rotated files to be compressed and removed.
+func (r *rotation) wait() {
+	r.wg.Wait()
+}
+
+func (r *rotation) backupName(t time.Time) string {
+	dir, prefix, ext := r.nameParts()
+	return filepath.Join(dir, prefix+t.Format(backupTimeFormat)+ext)
+}
+
+func (r *rotation) nameParts() (dir, prefix, ext string) {
+	dir, name := filepath.Split(r.path)
+	ext = filepath.Ext(name)
+	return dir, strings.TrimSuffix(name, ext) + "-", ext
+}
+
+// backups returns the rotated files, the most recent first.
+func (r *rotation) backups() ([]string, error) {
+	dir, prefix, ext := r.nameParts()
+	if dir == "" {
+		dir = "."
+	}
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []string
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+
+		timestamp := strings.TrimSuffix(strings.TrimSuffix(info.Name(), compressSuffix), ext)
+		if !strings.HasPrefix(timestamp, prefix) {
+			continue
+		}
+		if _, err := time.Parse(backupTimeFormat, strings.TrimPrefix(timestamp, prefix)); err != nil {
+			continue
+		}
+
+		backups = append(backups, filepath.Join(dir, info.Name()))
+	}
+
+	// The time format sorts lexically.
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+	return backups, nil
+}
+
+func (r *rotation) cleanUp() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	backups, err := r.backups()
+	if err != nil {
+		log.WithoutContext().Errorf("Error listing the rotated access log files: %v", err)
+		return
+	}
+
+	if r.maxBackups > 0 && len(backups) > r.maxBackups {
+		for _, backup := range backups[r.maxBackups:] {
+			if err := os.Remove(backup); err != nil {
+				log.WithoutContext().Errorf("Error removing the rotated access log file %s: %v", backup, err)
+			}
+		}
+		backups = backups[:r.maxBackups]
+	}
+
+	if !r.compress {
+		return
+	}
+
+	for _, backup := range backups {
+		if strings.HasSuffix(backup, compressSuffix) {
+			continue
+		}
+		if err := compressFile(backup); err != nil {
+			log.WithoutContext().Errorf("Error compressing the rotated access log file %s: %v", backup, err)
+		}
+	}
+}
+
+// compressFile compresses the file with gzip, and removes it once compressed.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+
+	dst, err := os.OpenFile(path+compressSuffix, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0664)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if err == nil {
+		err = gz.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path + compressSuffix)
+		return err
+	}
+
+	_ = src.Close()
+	return os.Remove(path)
+}
@@ -0,0 +1,57 @@
+package accesslog
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/containous/traefik/pkg/config"
+)
+
+// Decisions of the sampling, recorded by the sampled metric.
+const (
+	sampledKept    = "kept"
+	sampledDropped = "dropped"
+)
+
+// ValidateSampleRate checks that the sample rate is between 0 and 1.
+func ValidateSampleRate(rate float64) error {
+	if rate < 0 || rate > 1 {
+		return fmt.Errorf("invalid access log sample rate %v, it must be between 0 and 1", rate)
+	}
+	return nil
+}
+
+// routerEnabled checks whether the access logs are enabled for the router which handled the request,
+// the routers having to opt in when the OptIn filter is set.
+func (h *Handler) routerEnabled(options *config.RouterAccessLog) bool {
+	if options != nil && options.Enabled != nil {
+		return *options.Enabled
+	}
+
+	return h.config.Filters == nil || !h.config.Filters.OptIn
+}
+
+// sampled decides whether the access log line of a request is written, according to the sample rate of its router,
+// or to the sample rate of the filters.
+// Only the successful requests are sampled, the errors being always logged.
+func (h *Handler) sampled(options *config.RouterAccessLog, statusCode int) bool {
+	var rate float64
+	if h.config.Filters != nil {
+		rate = h.config.Filters.SampleRate
+	}
+	if options != nil && options.SampleRate > 0 {
+		rate = options.SampleRate
+	}
+
+	if rate <= 0 || rate >= 1 || statusCode >= http.StatusBadRequest {
+		return true
+	}
+
+	if h.random() < rate {
+		h.sampledCounter.With("decision", sampledKept).Add(1)
+		return true
+	}
+
+	h.sampledCounter.With("decision", sampledDropped).Add(1)
+	return false
+}
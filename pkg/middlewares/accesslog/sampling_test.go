@@ -0,0 +1,204 @@
+package accesslog
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containous/traefik/pkg/config"
+	"github.com/containous/traefik/pkg/metrics"
+	"github.com/containous/traefik/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_routerEnabled(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		filters  *types.AccessLogFilters
+		options  *config.RouterAccessLog
+		expected bool
+	}{
+		{
+			desc:     "no filters",
+			expected: true,
+		},
+		{
+			desc:     "router without options",
+			filters:  &types.AccessLogFilters{},
+			expected: true,
+		},
+		{
+			desc:     "router opting out",
+			filters:  &types.AccessLogFilters{},
+			options:  &config.RouterAccessLog{Enabled: boolPtr(false)},
+			expected: false,
+		},
+		{
+			desc:     "opt-in, router without options",
+			filters:  &types.AccessLogFilters{OptIn: true},
+			expected: false,
+		},
+		{
+			desc:     "opt-in, router with a sample rate only",
+			filters:  &types.AccessLogFilters{OptIn: true},
+			options:  &config.RouterAccessLog{SampleRate: 0.5},
+			expected: false,
+		},
+		{
+			desc:     "opt-in, router opting in",
+			filters:  &types.AccessLogFilters{OptIn: true},
+			options:  &config.RouterAccessLog{Enabled: boolPtr(true)},
+			expected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			h := &Handler{config: &types.AccessLog{Filters: test.filters}}
+
+			assert.Equal(t, test.expected, h.routerEnabled(test.options))
+		})
+	}
+}
+
+func TestHandler_sampled(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		filters          *types.AccessLogFilters
+		options          *config.RouterAccessLog
+		statusCode       int
+		random           float64
+		expected         bool
+		expectedDecision string
+	}{
+		{
+			desc:       "no sample rate",
+			filters:    &types.AccessLogFilters{},
+			statusCode: http.StatusOK,
+			random:     0.9,
+			expected:   true,
+		},
+		{
+			desc:             "sampled in",
+			filters:          &types.AccessLogFilters{SampleRate: 0.1},
+			statusCode:       http.StatusOK,
+			random:           0.05,
+			expected:         true,
+			expectedDecision: sampledKept,
+		},
+		{
+			desc:             "sampled out",
+			filters:          &types.AccessLogFilters{SampleRate: 0.1},
+			statusCode:       http.StatusOK,
+			random:           0.5,
+			expected:         false,
+			expectedDecision: sampledDropped,
+		},
+		{
+			desc:       "client error never sampled",
+			filters:    &types.AccessLogFilters{SampleRate: 0.1},
+			statusCode: http.StatusNotFound,
+			random:     0.5,
+			expected:   true,
+		},
+		{
+			desc:       "server error never sampled",
+			filters:    &types.AccessLogFilters{SampleRate: 0.1},
+			statusCode: http.StatusBadGateway,
+			random:     0.5,
+			expected:   true,
+		},
+		{
+			desc:             "router sample rate",
+			options:          &config.RouterAccessLog{SampleRate: 0.1},
+			statusCode:       http.StatusOK,
+			random:           0.5,
+			expected:         false,
+			expectedDecision: sampledDropped,
+		},
+		{
+			desc:       "router sample rate overriding the filters",
+			filters:    &types.AccessLogFilters{SampleRate: 0.1},
+			options:    &config.RouterAccessLog{SampleRate: 1},
+			statusCode: http.StatusOK,
+			random:     0.5,
+			expected:   true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			counter := &testCounter{}
+			h := &Handler{
+				config:         &types.AccessLog{Filters: test.filters},
+				sampledCounter: counter,
+				random:         func() float64 { return test.random },
+			}
+
+			assert.Equal(t, test.expected, h.sampled(test.options, test.statusCode))
+
+			if len(test.expectedDecision) == 0 {
+				assert.Zero(t, counter.value)
+				return
+			}
+
+			assert.Equal(t, float64(1), counter.value)
+			assert.Equal(t, []string{"decision", test.expectedDecision}, counter.labels)
+		})
+	}
+}
+
+func TestNewHandler_invalidSampleRate(t *testing.T) {
+	_, err := NewHandler(&types.AccessLog{Format: CommonFormat, Filters: &types.AccessLogFilters{SampleRate: 1.5}}, metrics.NewVoidRegistry())
+	assert.Error(t, err)
+}
+
+func TestLoggerRouterOptions(t *testing.T) {
+	tmpDir := createTempDir(t, CommonFormat)
+	defer os.RemoveAll(tmpDir)
+
+	logFilePath := filepath.Join(tmpDir, logFileNameSuffix)
+	logger, err := NewHandler(&types.AccessLog{
+		FilePath: logFilePath,
+		Format:   TemplateFormat,
+		Template: "%{RouterName}",
+		Filters:  &types.AccessLogFilters{OptIn: true},
+	}, metrics.NewVoidRegistry())
+	require.NoError(t, err)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	routers := map[string]http.Handler{
+		"foo": NewFieldHandler(next, RouterName, "foo", AddRouterOptions(&config.RouterAccessLog{Enabled: boolPtr(true)})),
+		"bar": NewFieldHandler(next, RouterName, "bar", nil),
+		"baz": NewFieldHandler(next, RouterName, "baz", AddRouterOptions(&config.RouterAccessLog{Enabled: boolPtr(false)})),
+	}
+
+	for _, name := range []string{"foo", "bar", "baz"} {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+		logger.ServeHTTP(httptest.NewRecorder(), req, routers[name])
+	}
+
+	require.NoError(t, logger.Close())
+
+	logData, err := ioutil.ReadFile(logFilePath)
+	require.NoError(t, err)
+
+	assert.Equal(t, "foo\n", string(logData))
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -17,5 +17,14 @@ func (s *SaveRetries) Retried(req *http.Request, attempt int) {
 	table := GetLogData(req)
 	if table != nil {
 		table.Core[RetryAttempts] = attempt
+
+		// The next targets are added by AddServiceFields.
+		if _, ok := table.Core[RetryTargets]; !ok {
+			target, ok := table.Core[ServiceAddr].(string)
+			if !ok {
+				target = defaultValue
+			}
+			table.Core[RetryTargets] = target
+		}
 	}
 }
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestSaveRetries(t *testing.T) {
@@ -46,3 +48,27 @@ func TestSaveRetries(t *testing.T) {
 		})
 	}
 }
+
+func TestSaveRetries_targets(t *testing.T) {
+	logDataTable := &LogData{Core: make(CoreLogData)}
+	req := httptest.NewRequest(http.MethodGet, "/some/path", nil)
+	req = req.WithContext(context.WithValue(req.Context(), DataTableKey, logDataTable))
+
+	attempt := 1
+	servers := []string{"10.0.0.1:80", "10.0.0.2:80", "10.0.0.3:80"}
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	for _, server := range servers {
+		req.URL.Host = server
+		AddServiceFields(httptest.NewRecorder(), req, next, logDataTable)
+
+		if attempt < len(servers) {
+			attempt++
+			(&SaveRetries{}).Retried(req, attempt)
+		}
+	}
+
+	assert.Equal(t, 2, logDataTable.Core[RetryAttempts])
+	assert.Equal(t, "10.0.0.1:80,10.0.0.2:80,10.0.0.3:80", logDataTable.Core[RetryTargets])
+	assert.Equal(t, "10.0.0.3:80", logDataTable.Core[ServiceAddr])
+}
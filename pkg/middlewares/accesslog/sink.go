@@ -0,0 +1,165 @@
+package accesslog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/pkg/types"
+)
+
+// Reasons of the access log lines being dropped, used as the value of the reason label of the dropped counter.
+const (
+	droppedBufferFull = "buffer"
+	droppedSink       = "sink"
+)
+
+// sink is the output the access log lines are written to, each call to Write being a line.
+type sink interface {
+	io.WriteCloser
+	// Rotate closes and reopens the output, to allow for its rotation by an external source.
+	Rotate() error
+}
+
+// newSink creates the sink of the access logs described by the configuration,
+// dropped being called for each line which could not be written.
+func newSink(config *types.AccessLog, dropped func()) (sink, error) {
+	outputs := 0
+	for _, enabled := range []bool{len(config.FilePath) > 0, config.Syslog != nil, config.Remote != nil} {
+		if enabled {
+			outputs++
+		}
+	}
+	if outputs > 1 {
+		return nil, errors.New("only one of the file, syslog and remote outputs can be configured")
+	}
+
+	switch {
+	case config.Syslog != nil:
+		return newSyslogSink(config.Syslog, dropped)
+	case config.Remote != nil:
+		return newRemoteSink(config.Remote, dropped)
+	case len(config.FilePath) > 0:
+		return newFileSink(config.FilePath, config.Rotation)
+	default:
+		return stdoutSink{}, nil
+	}
+}
+
+// stdoutSink writes the access logs to the standard output.
+type stdoutSink struct{}
+
+func (stdoutSink) Write(p []byte) (int, error) {
+	return os.Stdout.Write(p)
+}
+
+func (stdoutSink) Rotate() error {
+	return nil
+}
+
+func (stdoutSink) Close() error {
+	return nil
+}
+
+// fileSink writes the access logs to a file, rotating it when it gets too big or too old if the rotation is enabled.
+type fileSink struct {
+	path     string
+	rotation *rotation
+
+	lock     sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	// now is overridden in the tests.
+	now func() time.Time
+}
+
+func newFileSink(path string, config *types.AccessLogRotation) (*fileSink, error) {
+	s := &fileSink{
+		path: path,
+		now:  time.Now,
+	}
+
+	if config != nil {
+		s.rotation = newRotation(path, config)
+	}
+
+	if err := s.open(); err != nil {
+		return nil, fmt.Errorf("error opening access log file: %s", err)
+	}
+
+	return s, nil
+}
+
+func (s *fileSink) open() error {
+	file, err := openAccessLogFile(s.path)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	s.file = file
+	s.size = info.Size()
+	s.openedAt = s.now()
+	return nil
+}
+
+func (s *fileSink) Write(p []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.rotation != nil && s.rotation.isDue(s.size, int64(len(p)), s.now().Sub(s.openedAt)) {
+		if err := s.rotate(); err != nil {
+			return 0, fmt.Errorf("error rotating access log file: %s", err)
+		}
+	}
+
+	n, err := s.file.Write(p)
+	s.size += int64(n)
+	return n, err
+}
+
+func (s *fileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+
+	if err := s.rotation.archive(s.now()); err != nil {
+		// The file is reopened anyway, for the access logs to be written.
+		_ = s.open()
+		return err
+	}
+
+	return s.open()
+}
+
+// Rotate closes and reopens the file to allow for its rotation by an external source.
+func (s *fileSink) Rotate() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	return s.open()
+}
+
+// Close closes the file, and waits for the rotated files to be compressed.
+func (s *fileSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.rotation != nil {
+		s.rotation.wait()
+	}
+	return s.file.Close()
+}
@@ -0,0 +1,200 @@
+package accesslog
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/types"
+)
+
+// remoteSink sends the access log lines to a remote log collector.
+// The lines are queued, and sent in the background by a single connection which is reopened when it fails.
+// The lines written while the queue is full are dropped, so that a slow or unavailable collector does not block the requests.
+type remoteSink struct {
+	network string
+	address string
+	timeout time.Duration
+	// frame returns the message sent for a line.
+	frame func(line []byte) []byte
+
+	dropped func()
+	queue   chan []byte
+	stop    chan struct{}
+	done    chan struct{}
+
+	lock   sync.RWMutex
+	closed bool
+
+	// conn, backOff and failing are only used by the sending goroutine.
+	conn    net.Conn
+	backOff backoff.BackOff
+	failing bool
+}
+
+func newRemoteSink(config *types.AccessLogRemote, dropped func()) (*remoteSink, error) {
+	switch config.Network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6":
+	default:
+		return nil, fmt.Errorf("unsupported access log remote network: %q", config.Network)
+	}
+
+	if len(config.Address) == 0 {
+		return nil, fmt.Errorf("missing access log remote address")
+	}
+
+	return startRemoteSink(config.Network, config.Address, config.QueueSize, time.Duration(config.Timeout), copyLine, dropped), nil
+}
+
+func startRemoteSink(network, address string, queueSize int, timeout time.Duration, frame func([]byte) []byte, dropped func()) *remoteSink {
+	ebo := backoff.NewExponentialBackOff()
+	ebo.MaxElapsedTime = 0
+
+	s := &remoteSink{
+		network: network,
+		address: address,
+		timeout: timeout,
+		frame:   frame,
+		dropped: dropped,
+		queue:   make(chan []byte, queueSize),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+		backOff: ebo,
+	}
+
+	go s.run()
+
+	return s
+}
+
+func copyLine(line []byte) []byte {
+	return append([]byte(nil), line...)
+}
+
+// Write queues the line to be sent, or drops it if the queue is full.
+func (s *remoteSink) Write(p []byte) (int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		s.dropped()
+		return len(p), nil
+	}
+
+	select {
+	case s.queue <- s.frame(p):
+	default:
+		s.dropped()
+	}
+	return len(p), nil
+}
+
+func (s *remoteSink) run() {
+	defer close(s.done)
+
+	for msg := range s.queue {
+		// Once the sink is stopped, the remaining lines are given up without trying to send them.
+		if s.isStopped() {
+			s.dropped()
+			continue
+		}
+		s.send(msg)
+	}
+
+	if s.conn != nil {
+		_ = s.conn.Close()
+	}
+}
+
+func (s *remoteSink) isStopped() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// send sends the message, reconnecting to the log collector until it succeeds or the sink is stopped.
+func (s *remoteSink) send(msg []byte) {
+	for {
+		if s.isStopped() {
+			s.dropped()
+			return
+		}
+
+		err := s.write(msg)
+		if err == nil {
+			if s.failing {
+				log.WithoutContext().Infof("Sending the access logs to %s://%s again", s.network, s.address)
+				s.failing = false
+			}
+			s.backOff.Reset()
+			return
+		}
+
+		if !s.failing {
+			log.WithoutContext().Errorf("Unable to send the access logs to %s://%s: %v", s.network, s.address, err)
+			s.failing = true
+		}
+
+		if s.conn != nil {
+			_ = s.conn.Close()
+			s.conn = nil
+		}
+
+		select {
+		case <-time.After(s.backOff.NextBackOff()):
+		case <-s.stop:
+			s.dropped()
+			return
+		}
+	}
+}
+
+func (s *remoteSink) write(msg []byte) error {
+	if s.conn == nil {
+		conn, err := net.DialTimeout(s.network, s.address, s.timeout)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	if s.timeout > 0 {
+		if err := s.conn.SetWriteDeadline(time.Now().Add(s.timeout)); err != nil {
+			return err
+		}
+	}
+
+	_, err := s.conn.Write(msg)
+	return err
+}
+
+// Rotate does nothing, as there is no file to rotate.
+func (s *remoteSink) Rotate() error {
+	return nil
+}
+
+// Close sends the queued lines, and gives up on the ones which could not be sent within the timeout.
+func (s *remoteSink) Close() error {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	s.lock.Unlock()
+
+	select {
+	case <-s.done:
+	case <-time.After(s.timeout):
+		close(s.stop)
+		<-s.done
+	}
+	return nil
+}
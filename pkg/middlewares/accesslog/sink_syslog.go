@@ -0,0 +1,121 @@
+package accesslog
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/containous/traefik/pkg/types"
+)
+
+const (
+	// syslogQueueSize is the number of messages waiting to be sent to the syslog server before the next ones are dropped.
+	syslogQueueSize = 1000
+	// syslogTimeout is the timeout to connect and write to the syslog server.
+	syslogTimeout = 5 * time.Second
+	// syslogSeverityInfo is the severity of the messages.
+	syslogSeverityInfo = 6
+	// syslogTimeFormat is the RFC 5424 timestamp format, with a microsecond precision.
+	syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+// syslogFacilities are the codes of the syslog facilities.
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// localSyslogPaths are the usual paths of the socket of the local syslog daemon.
+var localSyslogPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+func newSyslogSink(config *types.AccessLogSyslog, dropped func()) (*remoteSink, error) {
+	facility, ok := syslogFacilities[config.Facility]
+	if !ok {
+		return nil, fmt.Errorf("unsupported access log syslog facility: %q", config.Facility)
+	}
+
+	network, address := config.Network, config.Address
+	switch network {
+	case "":
+		network = "unixgram"
+		address = findLocalSyslog()
+		if len(address) == 0 {
+			return nil, fmt.Errorf("unable to find the local syslog daemon")
+		}
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix", "unixgram":
+		if len(address) == 0 {
+			return nil, fmt.Errorf("missing access log syslog address")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported access log syslog network: %q", network)
+	}
+
+	formatter := &syslogFormatter{
+		priority: facility*8 + syslogSeverityInfo,
+		hostname: "-",
+		appName:  "-",
+		procID:   strconv.Itoa(os.Getpid()),
+		// On a stream, the messages are framed with their length, as described by RFC 6587.
+		octetCounting: network != "udp" && network != "udp4" && network != "udp6" && network != "unixgram",
+		now:           time.Now,
+	}
+	if hostname, err := os.Hostname(); err == nil && len(hostname) > 0 {
+		formatter.hostname = hostname
+	}
+	if len(config.Tag) > 0 {
+		formatter.appName = config.Tag
+	}
+
+	return startRemoteSink(network, address, syslogQueueSize, syslogTimeout, formatter.format, dropped), nil
+}
+
+func findLocalSyslog() string {
+	for _, path := range localSyslogPaths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// syslogFormatter formats the access log lines as RFC 5424 messages.
+type syslogFormatter struct {
+	priority      int
+	hostname      string
+	appName       string
+	procID        string
+	octetCounting bool
+
+	now func() time.Time
+}
+
+func (f *syslogFormatter) format(line []byte) []byte {
+	// No message ID nor structured data.
+	msg := fmt.Sprintf("<%d>1 %s %s %s %s - - %s",
+		f.priority, f.now().Format(syslogTimeFormat), f.hostname, f.appName, f.procID, bytes.TrimRight(line, "\n"))
+
+	if f.octetCounting {
+		msg = strconv.Itoa(len(msg)) + " " + msg
+	}
+	return []byte(msg)
+}
@@ -0,0 +1,359 @@
+package accesslog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/containous/traefik/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSink(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      *types.AccessLog
+		expectedErr bool
+	}{
+		{
+			desc:   "stdout",
+			config: &types.AccessLog{},
+		},
+		{
+			desc:        "file and remote",
+			config:      &types.AccessLog{FilePath: "access.log", Remote: &types.AccessLogRemote{Network: "tcp", Address: "127.0.0.1:514"}},
+			expectedErr: true,
+		},
+		{
+			desc:        "remote without address",
+			config:      &types.AccessLog{Remote: &types.AccessLogRemote{Network: "tcp"}},
+			expectedErr: true,
+		},
+		{
+			desc:        "remote with unsupported network",
+			config:      &types.AccessLog{Remote: &types.AccessLogRemote{Network: "unix", Address: "/tmp/foo.sock"}},
+			expectedErr: true,
+		},
+		{
+			desc:        "syslog with unknown facility",
+			config:      &types.AccessLog{Syslog: &types.AccessLogSyslog{Network: "udp", Address: "127.0.0.1:514", Facility: "foo"}},
+			expectedErr: true,
+		},
+		{
+			desc:        "syslog without address",
+			config:      &types.AccessLog{Syslog: &types.AccessLogSyslog{Network: "udp", Facility: "local0"}},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := newSink(test.config, func() {})
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NoError(t, s.Close())
+		})
+	}
+}
+
+func TestFileSink_rotationBySize(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "accesslog")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	logFilePath := filepath.Join(tmpDir, "access.log")
+
+	s, err := newFileSink(logFilePath, &types.AccessLogRotation{MaxBackups: 2, Compress: true})
+	require.NoError(t, err)
+
+	s.rotation.maxSize = 10
+	s.now = newFakeClock(time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC), time.Second)
+
+	for _, line := range []string{"line 1\n", "line 2\n", "line 3\n", "line 4\n", "line 5\n"} {
+		_, err = s.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, s.Close())
+
+	content, err := ioutil.ReadFile(logFilePath)
+	require.NoError(t, err)
+	assert.Equal(t, "line 5\n", string(content))
+
+	backups, err := s.rotation.backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+
+	for i, expected := range []string{"line 4\n", "line 3\n"} {
+		assert.Equal(t, ".gz", filepath.Ext(backups[i]))
+		assert.Equal(t, expected, readGzipFile(t, backups[i]))
+	}
+}
+
+func TestFileSink_rotationByAge(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "accesslog")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	logFilePath := filepath.Join(tmpDir, "access.log")
+
+	s, err := newFileSink(logFilePath, &types.AccessLogRotation{MaxSize: 100, MaxAge: types.Duration(time.Hour)})
+	require.NoError(t, err)
+
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	s.openedAt = now
+
+	_, err = s.Write([]byte("line 1\n"))
+	require.NoError(t, err)
+
+	now = now.Add(30 * time.Minute)
+	_, err = s.Write([]byte("line 2\n"))
+	require.NoError(t, err)
+
+	now = now.Add(30 * time.Minute)
+	_, err = s.Write([]byte("line 3\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, s.Close())
+
+	content, err := ioutil.ReadFile(logFilePath)
+	require.NoError(t, err)
+	assert.Equal(t, "line 3\n", string(content))
+
+	content, err = ioutil.ReadFile(filepath.Join(tmpDir, "access-2019-06-01T13-00-00.000.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "line 1\nline 2\n", string(content))
+}
+
+func TestFileSink_Rotate(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "accesslog")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	logFilePath := filepath.Join(tmpDir, "access.log")
+
+	s, err := newFileSink(logFilePath, nil)
+	require.NoError(t, err)
+
+	_, err = s.Write([]byte("line 1\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, os.Rename(logFilePath, logFilePath+".1"))
+	require.NoError(t, s.Rotate())
+
+	_, err = s.Write([]byte("line 2\n"))
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	content, err := ioutil.ReadFile(logFilePath)
+	require.NoError(t, err)
+	assert.Equal(t, "line 2\n", string(content))
+}
+
+func TestRemoteSink_reconnect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	lines := make(chan string, 100)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			// Each connection receives a single line, to force the sink to reconnect.
+			line, _ := bufio.NewReader(conn).ReadString('\n')
+			_ = conn.Close()
+			lines <- line
+		}
+	}()
+
+	var dropped int32
+	s := startRemoteSink("tcp", listener.Addr().String(), 10, time.Second, copyLine, func() { atomic.AddInt32(&dropped, 1) })
+	s.backOff = backoff.NewConstantBackOff(10 * time.Millisecond)
+
+	_, err = s.Write([]byte("line 1\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "line 1\n", receive(t, lines))
+
+	// The lines written to the closed connection might be lost, until the sink reconnects.
+	deadline := time.After(5 * time.Second)
+	for {
+		_, err = s.Write([]byte("line 2\n"))
+		require.NoError(t, err)
+
+		select {
+		case line := <-lines:
+			assert.Equal(t, "line 2\n", line)
+			assert.Equal(t, int32(0), atomic.LoadInt32(&dropped))
+			require.NoError(t, s.Close())
+			return
+		case <-time.After(50 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("the sink did not reconnect")
+		}
+	}
+}
+
+func TestRemoteSink_dropped(t *testing.T) {
+	// A closed listener, for the log collector to be unreachable.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, listener.Close())
+
+	var dropped int32
+	s := startRemoteSink("tcp", listener.Addr().String(), 1, 100*time.Millisecond, copyLine, func() { atomic.AddInt32(&dropped, 1) })
+	s.backOff = backoff.NewConstantBackOff(10 * time.Millisecond)
+
+	for i := 0; i < 5; i++ {
+		_, err = s.Write([]byte("line\n"))
+		require.NoError(t, err)
+	}
+
+	// At most one line is being sent, and another one is queued.
+	assert.True(t, atomic.LoadInt32(&dropped) >= 3)
+
+	require.NoError(t, s.Close())
+	assert.Equal(t, int32(5), atomic.LoadInt32(&dropped))
+
+	_, err = s.Write([]byte("line\n"))
+	require.NoError(t, err)
+	assert.Equal(t, int32(6), atomic.LoadInt32(&dropped))
+}
+
+func TestSyslogSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	s, err := newSyslogSink(&types.AccessLogSyslog{Network: "udp", Address: conn.LocalAddr().String(), Facility: "local0", Tag: "traefik"}, func() {})
+	require.NoError(t, err)
+
+	_, err = s.Write([]byte("foo bar\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	buf := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^<134>1 \d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{6}\S+ \S+ traefik \d+ - - foo bar$`, string(buf[:n]))
+
+	require.NoError(t, s.Close())
+}
+
+func TestSyslogFormatter(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		octetCounting bool
+		expected      string
+	}{
+		{
+			desc:     "datagram",
+			expected: "<134>1 2019-06-01T12:00:00.000000Z foo.bar traefik 42 - - foo bar",
+		},
+		{
+			desc:          "stream",
+			octetCounting: true,
+			expected:      "65 <134>1 2019-06-01T12:00:00.000000Z foo.bar traefik 42 - - foo bar",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			formatter := &syslogFormatter{
+				priority:      134,
+				hostname:      "foo.bar",
+				appName:       "traefik",
+				procID:        "42",
+				octetCounting: test.octetCounting,
+				now:           func() time.Time { return time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC) },
+			}
+
+			assert.Equal(t, test.expected, string(formatter.format([]byte("foo bar\n"))))
+		})
+	}
+}
+
+func TestRotation_backups(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "accesslog")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	for _, name := range []string{
+		"access.log",
+		"access-2019-06-01T12-00-00.000.log.gz",
+		"access-2019-06-01T13-00-00.000.log",
+		"access-foo.log",
+		"other-2019-06-01T12-00-00.000.log",
+	} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(tmpDir, name), nil, 0644))
+	}
+
+	r := newRotation(filepath.Join(tmpDir, "access.log"), &types.AccessLogRotation{})
+
+	backups, err := r.backups()
+	require.NoError(t, err)
+
+	expected := []string{
+		filepath.Join(tmpDir, "access-2019-06-01T13-00-00.000.log"),
+		filepath.Join(tmpDir, "access-2019-06-01T12-00-00.000.log.gz"),
+	}
+	assert.Equal(t, expected, backups)
+}
+
+func newFakeClock(start time.Time, step time.Duration) func() time.Time {
+	now := start
+	return func() time.Time {
+		now = now.Add(step)
+		return now
+	}
+}
+
+func readGzipFile(t *testing.T, path string) string {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	gz, err := gzip.NewReader(file)
+	require.NoError(t, err)
+
+	content, err := ioutil.ReadAll(gz)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func receive(t *testing.T, lines chan string) string {
+	t.Helper()
+
+	select {
+	case line := <-lines:
+		return line
+	case <-time.After(5 * time.Second):
+		t.Fatal("no line received")
+		return ""
+	}
+}
@@ -0,0 +1,96 @@
+package accesslog
+
+import (
+	"net"
+	"time"
+
+	"github.com/containous/traefik/pkg/tcp"
+	"github.com/containous/traefik/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+// tcpProtocol is the protocol written in the access log lines of the TCP connections.
+const tcpProtocol = "TCP"
+
+// WrapTCPHandler wraps a TCP handler to write an access log line for each connection it serves,
+// next being returned as is if the TCP access logs are disabled.
+func WrapTCPHandler(handler *Handler, routerName, serviceName string, next tcp.Handler) tcp.Handler {
+	if handler == nil || !handler.config.TCP {
+		return next
+	}
+
+	return tcp.HandlerFunc(func(conn net.Conn) {
+		handler.ServeTCP(conn, routerName, serviceName, next)
+	})
+}
+
+// ServeTCP forwards the connection to next, and writes its access log line once next is done with it.
+func (h *Handler) ServeTCP(conn net.Conn, routerName, serviceName string, next tcp.Handler) {
+	now := time.Now().UTC()
+
+	core := CoreLogData{
+		StartUTC:        now,
+		StartLocal:      now.Local(),
+		RequestCount:    nextRequestCount(),
+		RequestProtocol: tcpProtocol,
+		RouterName:      routerName,
+		ServiceName:     serviceName,
+	}
+
+	if addr := conn.RemoteAddr(); addr != nil {
+		core[ClientAddr] = addr.String()
+		core[ClientHost], core[ClientPort] = silentSplitHostPort(addr.String())
+	}
+
+	observed := tcp.Observe(conn)
+	next.ServeTCP(observed)
+
+	// n.b. take care to perform time arithmetic using UTC to avoid errors at DST boundaries.
+	core[Duration] = time.Now().UTC().Sub(now)
+
+	core[TLSServerName] = tcp.ServerName(observed)
+	core[RequestContentSize] = observed.BytesRead()
+	core[DownstreamContentSize] = observed.BytesWritten()
+	core[CloseReason] = observed.CloseReason()
+
+	if address := observed.BackendAddress(); len(address) > 0 {
+		core[ServiceAddr] = address
+		core[ServiceURL] = "tcp://" + address
+	}
+
+	logDataTable := &LogData{Core: core}
+
+	if h.config.BufferingSize > 0 {
+		h.enqueue(handlerParams{logDataTable: logDataTable})
+	} else {
+		h.logTheTCPConnection(logDataTable)
+	}
+}
+
+func (h *Handler) logTheTCPConnection(logDataTable *LogData) {
+	if !h.keepTCPAccessLog(logDataTable.Core[Duration].(time.Duration)) {
+		return
+	}
+
+	fields := logrus.Fields{}
+
+	for k, v := range logDataTable.Core {
+		if h.config.Fields.Keep(k) {
+			fields[k] = v
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.logger.WithFields(fields).Println()
+}
+
+// keepTCPAccessLog applies the duration filter to the TCP connections,
+// as the status codes and retry attempts filters are specific to HTTP.
+func (h *Handler) keepTCPAccessLog(duration time.Duration) bool {
+	if h.config.Filters == nil || h.config.Filters.MinDuration == 0 {
+		return true
+	}
+
+	return types.Duration(duration) > h.config.Filters.MinDuration
+}
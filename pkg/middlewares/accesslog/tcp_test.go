@@ -0,0 +1,113 @@
+package accesslog
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/pkg/metrics"
+	"github.com/containous/traefik/pkg/tcp"
+	"github.com/containous/traefik/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte("bye"))
+			_ = conn.Close()
+		}
+	}()
+
+	testCases := []struct {
+		desc          string
+		bufferingSize int64
+	}{
+		{
+			desc: "synchronous",
+		},
+		{
+			desc:          "buffered",
+			bufferingSize: 1024,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			tmpDir := createTempDir(t, JSONFormat)
+			defer os.RemoveAll(tmpDir)
+
+			logFilePath := filepath.Join(tmpDir, logFileNameSuffix)
+
+			logger, err := NewHandler(&types.AccessLog{
+				FilePath:      logFilePath,
+				Format:        JSONFormat,
+				BufferingSize: test.bufferingSize,
+				TCP:           true,
+			}, metrics.NewVoidRegistry())
+			require.NoError(t, err)
+
+			proxy, err := tcp.NewProxy(listener.Addr().String(), &net.Dialer{Timeout: time.Second}, nil)
+			require.NoError(t, err)
+
+			handler := WrapTCPHandler(logger, "foo@file", "bar@file", proxy)
+
+			clientConn, proxyConn := net.Pipe()
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				handler.ServeTCP(&tcp.Conn{Conn: proxyConn, ServerName: "foo.bar"})
+			}()
+
+			require.NoError(t, clientConn.SetReadDeadline(time.Now().Add(5*time.Second)))
+			data, err := ioutil.ReadAll(clientConn)
+			require.NoError(t, err)
+			assert.Equal(t, "bye", string(data))
+
+			<-done
+			require.NoError(t, logger.Close())
+
+			logData, err := ioutil.ReadFile(logFilePath)
+			require.NoError(t, err)
+
+			jsonData := make(map[string]interface{})
+			require.NoError(t, json.Unmarshal(logData, &jsonData))
+
+			assert.Equal(t, "TCP", jsonData[RequestProtocol])
+			assert.Equal(t, "foo@file", jsonData[RouterName])
+			assert.Equal(t, "bar@file", jsonData[ServiceName])
+			assert.Equal(t, "foo.bar", jsonData[TLSServerName])
+			assert.Equal(t, listener.Addr().String(), jsonData[ServiceAddr])
+			assert.Equal(t, "tcp://"+listener.Addr().String(), jsonData[ServiceURL])
+			assert.Equal(t, float64(0), jsonData[RequestContentSize])
+			assert.Equal(t, float64(3), jsonData[DownstreamContentSize])
+			assert.Equal(t, tcp.CloseReasonBackendClosed, jsonData[CloseReason])
+			assert.NotEmpty(t, jsonData[ClientAddr])
+			assert.NotZero(t, jsonData[Duration])
+		})
+	}
+}
+
+func TestWrapTCPHandler_disabled(t *testing.T) {
+	logger, err := NewHandler(&types.AccessLog{Format: CommonFormat}, metrics.NewVoidRegistry())
+	require.NoError(t, err)
+
+	next := &tcp.RRLoadBalancer{}
+
+	assert.Equal(t, next, WrapTCPHandler(logger, "foo@file", "bar@file", next))
+	assert.Equal(t, next, WrapTCPHandler(nil, "foo@file", "bar@file", next))
+}
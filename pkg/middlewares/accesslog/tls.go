@@ -0,0 +1,55 @@
+package accesslog
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	traefiktls "github.com/containous/traefik/pkg/tls"
+)
+
+// tlsCipherSuites are the names of the cipher suites, by ID.
+var tlsCipherSuites = make(map[uint16]string)
+
+func init() {
+	for name, id := range traefiktls.CipherSuites {
+		tlsCipherSuites[id] = name
+	}
+}
+
+// addTLSFields adds the details of the TLS connection with the client.
+func addTLSFields(core CoreLogData, state *tls.ConnectionState) {
+	core[TLSVersion] = tlsVersionName(state.Version)
+	core[TLSCipher] = tlsCipherSuiteName(state.CipherSuite)
+
+	if len(state.ServerName) > 0 {
+		core[TLSServerName] = state.ServerName
+	}
+	if len(state.NegotiatedProtocol) > 0 {
+		core[TLSALPN] = state.NegotiatedProtocol
+	}
+	if len(state.PeerCertificates) > 0 {
+		core[TLSClientSubject] = state.PeerCertificates[0].Subject.String()
+	}
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "1.0"
+	case tls.VersionTLS11:
+		return "1.1"
+	case tls.VersionTLS12:
+		return "1.2"
+	case tls.VersionTLS13:
+		return "1.3"
+	default:
+		return fmt.Sprintf("0x%04x", version)
+	}
+}
+
+func tlsCipherSuiteName(id uint16) string {
+	if name, ok := tlsCipherSuites[id]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%04x", id)
+}
@@ -0,0 +1,73 @@
+package accesslog
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddTLSFields(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		state    *tls.ConnectionState
+		expected CoreLogData
+	}{
+		{
+			desc: "minimal handshake",
+			state: &tls.ConnectionState{
+				Version:     tls.VersionTLS12,
+				CipherSuite: tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			},
+			expected: CoreLogData{
+				TLSVersion: "1.2",
+				TLSCipher:  "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+			},
+		},
+		{
+			desc: "client certificate, SNI and ALPN",
+			state: &tls.ConnectionState{
+				Version:            tls.VersionTLS13,
+				CipherSuite:        tls.TLS_AES_128_GCM_SHA256,
+				ServerName:         "foo.bar",
+				NegotiatedProtocol: "h2",
+				PeerCertificates: []*x509.Certificate{
+					{Subject: pkix.Name{CommonName: "client", Organization: []string{"containous"}}},
+					{Subject: pkix.Name{CommonName: "intermediate"}},
+				},
+			},
+			expected: CoreLogData{
+				TLSVersion:       "1.3",
+				TLSCipher:        "TLS_AES_128_GCM_SHA256",
+				TLSServerName:    "foo.bar",
+				TLSALPN:          "h2",
+				TLSClientSubject: "CN=client,O=containous",
+			},
+		},
+		{
+			desc: "unknown version and cipher suite",
+			state: &tls.ConnectionState{
+				Version:     0x0200,
+				CipherSuite: 0xffff,
+			},
+			expected: CoreLogData{
+				TLSVersion: "0x0200",
+				TLSCipher:  "0xffff",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			core := CoreLogData{}
+			addTLSFields(core, test.state)
+
+			assert.Equal(t, test.expected, core)
+		})
+	}
+}
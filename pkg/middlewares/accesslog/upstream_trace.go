@@ -0,0 +1,93 @@
+package accesslog
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// upstreamTrace records the timings of an attempt to forward the request to the origin server.
+// The httptrace hooks can be called by the goroutine dialing the server, even after the attempt is over,
+// hence the timings are kept apart from the log data, and copied to it once the attempt is over.
+type upstreamTrace struct {
+	start time.Time
+
+	mu           sync.Mutex
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	durations    map[string]time.Duration
+}
+
+func newUpstreamTrace() *upstreamTrace {
+	return &upstreamTrace{
+		start:     time.Now().UTC(),
+		durations: make(map[string]time.Duration),
+	}
+}
+
+func (t *upstreamTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mark(&t.dnsStart)
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.record(OriginDNSDuration, &t.dnsStart)
+		},
+		ConnectStart: func(network, addr string) {
+			t.mark(&t.connectStart)
+		},
+		ConnectDone: func(network, addr string, err error) {
+			if err == nil {
+				t.record(OriginConnectDuration, &t.connectStart)
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.mark(&t.tlsStart)
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				t.record(OriginTLSDuration, &t.tlsStart)
+			}
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.durations[OriginTimeToFirstByte] = time.Now().UTC().Sub(t.start)
+		},
+	}
+}
+
+// mark sets the start of a step, keeping the first one when the step is tried several times (e.g. to connect to several addresses).
+func (t *upstreamTrace) mark(start *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if start.IsZero() {
+		*start = time.Now().UTC()
+	}
+}
+
+func (t *upstreamTrace) record(key string, start *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !start.IsZero() {
+		t.durations[key] = time.Now().UTC().Sub(*start)
+	}
+}
+
+// addFields sets the timings of the attempt, replacing the ones of a previous attempt.
+func (t *upstreamTrace) addFields(core CoreLogData) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, key := range []string{OriginTimeToFirstByte, OriginDNSDuration, OriginConnectDuration, OriginTLSDuration} {
+		if duration, ok := t.durations[key]; ok {
+			core[key] = duration
+		} else {
+			delete(core, key)
+		}
+	}
+}
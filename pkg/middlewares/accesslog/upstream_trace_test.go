@@ -0,0 +1,74 @@
+package accesslog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddServiceFields_upstreamTimings(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	client := server.Client()
+
+	forward := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		outReq, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		resp, err := client.Do(outReq.WithContext(req.Context()))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	})
+
+	serve := func() CoreLogData {
+		logDataTable := &LogData{Core: make(CoreLogData)}
+		req := httptest.NewRequest(http.MethodGet, "http://foo.bar/", nil)
+		req = req.WithContext(context.WithValue(req.Context(), DataTableKey, logDataTable))
+		req.URL = serverURL
+
+		NewFieldHandler(forward, ServiceName, "foo", AddServiceFields).ServeHTTP(httptest.NewRecorder(), req)
+
+		return logDataTable.Core
+	}
+
+	core := serve()
+	assert.Contains(t, core, OriginTimeToFirstByte)
+	assert.Contains(t, core, OriginConnectDuration)
+	assert.Contains(t, core, OriginTLSDuration)
+	// The server address is an IP address, which is not resolved.
+	assert.NotContains(t, core, OriginDNSDuration)
+
+	// The connection is reused.
+	core = serve()
+	assert.Contains(t, core, OriginTimeToFirstByte)
+	assert.NotContains(t, core, OriginConnectDuration)
+	assert.NotContains(t, core, OriginTLSDuration)
+}
+
+func TestUpstreamTrace_addFields(t *testing.T) {
+	core := CoreLogData{
+		OriginConnectDuration: 1,
+		OriginDNSDuration:     1,
+	}
+
+	trace := newUpstreamTrace()
+	clientTrace := trace.clientTrace()
+	clientTrace.ConnectStart("tcp", "127.0.0.1:80")
+	clientTrace.ConnectDone("tcp", "127.0.0.1:80", nil)
+	trace.addFields(core)
+
+	assert.Contains(t, core, OriginConnectDuration)
+	assert.NotContains(t, core, OriginDNSDuration)
+	assert.NotContains(t, core, OriginTimeToFirstByte)
+}
@@ -0,0 +1,212 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/containous/alice"
+	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/metrics"
+	"github.com/containous/traefik/pkg/middlewares"
+	"github.com/containous/traefik/pkg/middlewares/retry"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+)
+
+const (
+	protoHTTP      = "http"
+	protoSSE       = "sse"
+	protoWebsocket = "websocket"
+	typeName       = "Metrics"
+	nameEntrypoint = "metrics-entrypoint"
+	nameRouter     = "metrics-router"
+	nameService    = "metrics-service"
+)
+
+type metricsMiddleware struct {
+	next                 http.Handler
+	reqsCounter          gokitmetrics.Counter
+	reqDurationHistogram gokitmetrics.Histogram
+	openConnsGauge       gokitmetrics.Gauge
+	baseLabels           []string
+
+	// openConns holds the number of open connections by labels, as the gauges can only be set.
+	openConnsLock sync.Mutex
+	openConns     map[string]int64
+}
+
+// NewEntryPointMiddleware creates a new metrics middleware for an entry point.
+func NewEntryPointMiddleware(ctx context.Context, next http.Handler, registry metrics.Registry, entryPointName string) http.Handler {
+	middlewares.GetLogger(ctx, nameEntrypoint, typeName).Debug("Creating middleware")
+
+	return &metricsMiddleware{
+		next:                 next,
+		reqsCounter:          registry.EntrypointReqsCounter(),
+		reqDurationHistogram: registry.EntrypointReqDurationHistogram(),
+		openConnsGauge:       registry.EntrypointOpenConnsGauge(),
+		baseLabels:           []string{"entrypoint", entryPointName},
+		openConns:            make(map[string]int64),
+	}
+}
+
+// NewRouterMiddleware creates a new metrics middleware for a router.
+func NewRouterMiddleware(ctx context.Context, next http.Handler, registry metrics.Registry, routerName string, serviceName string) http.Handler {
+	middlewares.GetLogger(ctx, nameRouter, typeName).Debug("Creating middleware")
+
+	return &routerMetricsMiddleware{
+		next:                 next,
+		reqsCounter:          registry.RouterReqsCounter(),
+		reqDurationHistogram: registry.RouterReqDurationHistogram(),
+		headerLabels:         registry.HeaderLabels,
+		baseLabels:           []string{"router", routerName, "service", serviceName},
+	}
+}
+
+// NewServiceMiddleware creates a new metrics middleware for a service.
+func NewServiceMiddleware(ctx context.Context, next http.Handler, registry metrics.Registry, serviceName string) http.Handler {
+	middlewares.GetLogger(ctx, nameService, typeName).Debug("Creating middleware")
+
+	return &metricsMiddleware{
+		next:                 next,
+		reqsCounter:          registry.BackendReqsCounter(),
+		reqDurationHistogram: registry.BackendReqDurationHistogram(),
+		openConnsGauge:       registry.BackendOpenConnsGauge(),
+		baseLabels:           []string{"backend", serviceName},
+		openConns:            make(map[string]int64),
+	}
+}
+
+// WrapEntryPointHandler Wraps metrics entrypoint to alice.Constructor.
+func WrapEntryPointHandler(ctx context.Context, registry metrics.Registry, entryPointName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return NewEntryPointMiddleware(ctx, next, registry, entryPointName), nil
+	}
+}
+
+// WrapRouterHandler Wraps metrics router to alice.Constructor.
+func WrapRouterHandler(ctx context.Context, registry metrics.Registry, routerName string, serviceName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return NewRouterMiddleware(ctx, next, registry, routerName, serviceName), nil
+	}
+}
+
+// WrapServiceHandler Wraps metrics service to alice.Constructor.
+func WrapServiceHandler(ctx context.Context, registry metrics.Registry, serviceName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return NewServiceMiddleware(ctx, next, registry, serviceName), nil
+	}
+}
+
+func (m *metricsMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	labels := []string{"method", getMethod(req), "protocol", getRequestProtocol(req)}
+	labels = append(labels, m.baseLabels...)
+
+	m.updateOpenConns(labels, 1)
+	defer m.updateOpenConns(labels, -1)
+
+	start := time.Now()
+	recorder := NewResponseRecorder(rw)
+	m.next.ServeHTTP(recorder, req)
+
+	labels = append(labels, "code", strconv.Itoa(recorder.GetCode()))
+	m.reqsCounter.With(labels...).Add(1)
+	m.reqDurationHistogram.With(labels...).Observe(time.Since(start).Seconds())
+}
+
+func (m *metricsMiddleware) updateOpenConns(labels []string, delta int64) {
+	key := strings.Join(labels, "\x00")
+
+	m.openConnsLock.Lock()
+	defer m.openConnsLock.Unlock()
+
+	m.openConns[key] += delta
+	m.openConnsGauge.With(labels...).Set(float64(m.openConns[key]))
+
+	if m.openConns[key] == 0 {
+		delete(m.openConns, key)
+	}
+}
+
+type routerMetricsMiddleware struct {
+	next                 http.Handler
+	reqsCounter          gokitmetrics.Counter
+	reqDurationHistogram gokitmetrics.Histogram
+	headerLabels         func(header http.Header) []string
+	baseLabels           []string
+}
+
+func (m *routerMetricsMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	labels := []string{"method", getMethod(req), "protocol", getRequestProtocol(req)}
+	labels = append(labels, m.baseLabels...)
+	labels = append(labels, m.headerLabels(req.Header)...)
+
+	start := time.Now()
+	recorder := NewResponseRecorder(rw)
+	m.next.ServeHTTP(recorder, req)
+
+	labels = append(labels, "code", strconv.Itoa(recorder.GetCode()))
+	m.reqsCounter.With(labels...).Add(1)
+	m.reqDurationHistogram.With(labels...).Observe(time.Since(start).Seconds())
+}
+
+func getRequestProtocol(req *http.Request) string {
+	switch {
+	case isWebsocketRequest(req):
+		return protoWebsocket
+	case isSSERequest(req):
+		return protoSSE
+	default:
+		return protoHTTP
+	}
+}
+
+// isWebsocketRequest determines if the specified HTTP request is a websocket handshake request.
+func isWebsocketRequest(req *http.Request) bool {
+	return containsHeader(req, "Connection", "upgrade") && containsHeader(req, "Upgrade", "websocket")
+}
+
+// isSSERequest determines if the specified HTTP request is a request for an event subscription.
+func isSSERequest(req *http.Request) bool {
+	return containsHeader(req, "Accept", "text/event-stream")
+}
+
+func containsHeader(req *http.Request, name, value string) bool {
+	items := strings.Split(req.Header.Get(name), ",")
+	for _, item := range items {
+		if value == strings.ToLower(strings.TrimSpace(item)) {
+			return true
+		}
+	}
+	return false
+}
+
+func getMethod(r *http.Request) string {
+	if !utf8.ValidString(r.Method) {
+		log.WithoutContext().Warnf("Invalid HTTP method encoding: %s", r.Method)
+		return "NON_UTF8_HTTP_METHOD"
+	}
+	return r.Method
+}
+
+type retryMetrics interface {
+	BackendRetriesCounter() gokitmetrics.Counter
+}
+
+// NewRetryListener instantiates a retry listener recording the retries of a service.
+func NewRetryListener(retryMetrics retryMetrics, serviceName string) retry.Listener {
+	return &retryListener{retryMetrics: retryMetrics, serviceName: serviceName}
+}
+
+type retryListener struct {
+	retryMetrics retryMetrics
+	serviceName  string
+}
+
+// Retried implements the retry.Listener interface.
+func (l *retryListener) Retried(req *http.Request, attempt int) {
+	l.retryMetrics.BackendRetriesCounter().With("backend", l.serviceName).Add(1)
+}
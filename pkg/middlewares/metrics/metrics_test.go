@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/pkg/metrics"
+	"github.com/containous/traefik/pkg/testhelpers"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+type collectingRegistry struct {
+	metrics.Registry
+	reqsCounter    *testhelpers.CollectingCounter
+	reqDurations   *collectingHistogram
+	openConnsGauge *testhelpers.CollectingGauge
+	retriesCounter *testhelpers.CollectingCounter
+}
+
+func newCollectingRegistry() *collectingRegistry {
+	return &collectingRegistry{
+		Registry:       metrics.NewVoidRegistry(),
+		reqsCounter:    &testhelpers.CollectingCounter{},
+		reqDurations:   &collectingHistogram{},
+		openConnsGauge: &testhelpers.CollectingGauge{},
+		retriesCounter: &testhelpers.CollectingCounter{},
+	}
+}
+
+func (r *collectingRegistry) EntrypointReqsCounter() gokitmetrics.Counter { return r.reqsCounter }
+func (r *collectingRegistry) EntrypointReqDurationHistogram() gokitmetrics.Histogram {
+	return r.reqDurations
+}
+func (r *collectingRegistry) EntrypointOpenConnsGauge() gokitmetrics.Gauge { return r.openConnsGauge }
+func (r *collectingRegistry) RouterReqsCounter() gokitmetrics.Counter      { return r.reqsCounter }
+func (r *collectingRegistry) RouterReqDurationHistogram() gokitmetrics.Histogram {
+	return r.reqDurations
+}
+func (r *collectingRegistry) HeaderLabels(header http.Header) []string {
+	return []string{"tenant", header.Get("X-Tenant")}
+}
+func (r *collectingRegistry) BackendReqsCounter() gokitmetrics.Counter { return r.reqsCounter }
+func (r *collectingRegistry) BackendReqDurationHistogram() gokitmetrics.Histogram {
+	return r.reqDurations
+}
+func (r *collectingRegistry) BackendOpenConnsGauge() gokitmetrics.Gauge   { return r.openConnsGauge }
+func (r *collectingRegistry) BackendRetriesCounter() gokitmetrics.Counter { return r.retriesCounter }
+
+type collectingHistogram struct {
+	observations    int
+	lastLabelValues []string
+}
+
+func (h *collectingHistogram) With(labelValues ...string) gokitmetrics.Histogram {
+	h.lastLabelValues = labelValues
+	return h
+}
+
+func (h *collectingHistogram) Observe(value float64) {
+	h.observations++
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		newMiddleware  func(next http.Handler, registry metrics.Registry) http.Handler
+		header         http.Header
+		expectedLabels []string
+	}{
+		{
+			desc: "entry point",
+			newMiddleware: func(next http.Handler, registry metrics.Registry) http.Handler {
+				return NewEntryPointMiddleware(context.Background(), next, registry, "web")
+			},
+			expectedLabels: []string{"method", http.MethodGet, "protocol", protoHTTP, "entrypoint", "web", "code", "418"},
+		},
+		{
+			desc: "service",
+			newMiddleware: func(next http.Handler, registry metrics.Registry) http.Handler {
+				return NewServiceMiddleware(context.Background(), next, registry, "foo@file")
+			},
+			expectedLabels: []string{"method", http.MethodGet, "protocol", protoHTTP, "backend", "foo@file", "code", "418"},
+		},
+		{
+			desc: "websocket",
+			newMiddleware: func(next http.Handler, registry metrics.Registry) http.Handler {
+				return NewEntryPointMiddleware(context.Background(), next, registry, "web")
+			},
+			header:         http.Header{"Connection": {"keep-alive, Upgrade"}, "Upgrade": {"websocket"}},
+			expectedLabels: []string{"method", http.MethodGet, "protocol", protoWebsocket, "entrypoint", "web", "code", "418"},
+		},
+		{
+			desc: "server-sent events",
+			newMiddleware: func(next http.Handler, registry metrics.Registry) http.Handler {
+				return NewEntryPointMiddleware(context.Background(), next, registry, "web")
+			},
+			header:         http.Header{"Accept": {"text/event-stream"}},
+			expectedLabels: []string{"method", http.MethodGet, "protocol", protoSSE, "entrypoint", "web", "code", "418"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			registry := newCollectingRegistry()
+
+			var openConns float64
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				openConns = registry.openConnsGauge.GaugeValue
+				rw.WriteHeader(http.StatusTeapot)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "http://foo.bar", nil)
+			for name, values := range test.header {
+				req.Header[name] = values
+			}
+
+			test.newMiddleware(next, registry).ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, float64(1), openConns)
+			assert.Equal(t, float64(0), registry.openConnsGauge.GaugeValue)
+
+			assert.Equal(t, float64(1), registry.reqsCounter.CounterValue)
+			assert.Equal(t, test.expectedLabels, registry.reqsCounter.LastLabelValues)
+
+			assert.Equal(t, 1, registry.reqDurations.observations)
+			assert.Equal(t, test.expectedLabels, registry.reqDurations.lastLabelValues)
+		})
+	}
+}
+
+func TestRouterMiddleware(t *testing.T) {
+	registry := newCollectingRegistry()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusTeapot)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "http://foo.bar", nil)
+	req.Header.Set("X-Tenant", "acme")
+
+	NewRouterMiddleware(context.Background(), next, registry, "foo@file", "bar@file").ServeHTTP(httptest.NewRecorder(), req)
+
+	expectedLabels := []string{"method", http.MethodPost, "protocol", protoHTTP, "router", "foo@file", "service", "bar@file", "tenant", "acme", "code", "418"}
+
+	assert.Equal(t, float64(1), registry.reqsCounter.CounterValue)
+	assert.Equal(t, expectedLabels, registry.reqsCounter.LastLabelValues)
+
+	assert.Equal(t, 1, registry.reqDurations.observations)
+	assert.Equal(t, expectedLabels, registry.reqDurations.lastLabelValues)
+}
+
+func TestRetryListener(t *testing.T) {
+	registry := newCollectingRegistry()
+
+	listener := NewRetryListener(registry, "foo@file")
+	listener.Retried(httptest.NewRequest(http.MethodGet, "http://foo.bar", nil), 2)
+	listener.Retried(httptest.NewRequest(http.MethodGet, "http://foo.bar", nil), 3)
+
+	assert.Equal(t, float64(2), registry.retriesCounter.CounterValue)
+	assert.Equal(t, []string{"backend", "foo@file"}, registry.retriesCounter.LastLabelValues)
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+)
+
+// ResponseRecorder is a response writer which records the status code of the response.
+type ResponseRecorder interface {
+	http.ResponseWriter
+	http.Flusher
+	GetCode() int
+}
+
+// NewResponseRecorder returns a ResponseRecorder wrapping the response writer.
+func NewResponseRecorder(rw http.ResponseWriter) ResponseRecorder {
+	rec := &responseRecorder{
+		ResponseWriter: rw,
+		statusCode:     http.StatusOK,
+	}
+	if _, ok := rw.(http.CloseNotifier); !ok {
+		return rec
+	}
+	return &responseRecorderWithCloseNotify{rec}
+}
+
+// responseRecorder captures information from the response and preserves it for
+// later analysis.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+type responseRecorderWithCloseNotify struct {
+	*responseRecorder
+}
+
+// CloseNotify returns a channel that receives at most a
+// single value (true) when the client connection has gone away.
+func (r *responseRecorderWithCloseNotify) CloseNotify() <-chan bool {
+	return r.ResponseWriter.(http.CloseNotifier).CloseNotify()
+}
+
+// GetCode returns the status code of the response.
+func (r *responseRecorder) GetCode() int {
+	return r.statusCode
+}
+
+// WriteHeader captures the status code for later retrieval.
+func (r *responseRecorder) WriteHeader(status int) {
+	r.ResponseWriter.WriteHeader(status)
+	r.statusCode = status
+}
+
+// Hijack hijacks the connection
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return r.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+// Flush sends any buffered data to the client.
+func (r *responseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
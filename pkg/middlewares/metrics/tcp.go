@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/pkg/metrics"
+	"github.com/containous/traefik/pkg/tcp"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+)
+
+const (
+	directionIn  = "in"
+	directionOut = "out"
+)
+
+type tcpMetrics struct {
+	connsOpenedCounter    gokitmetrics.Counter
+	connsClosedCounter    gokitmetrics.Counter
+	connDurationHistogram gokitmetrics.Histogram
+	bytesCounter          gokitmetrics.Counter
+	baseLabels            []string
+}
+
+func (m *tcpMetrics) opened() {
+	m.connsOpenedCounter.With(m.baseLabels...).Add(1)
+}
+
+func (m *tcpMetrics) closed(duration time.Duration, bytesIn, bytesOut int64) {
+	m.connsClosedCounter.With(m.baseLabels...).Add(1)
+	m.connDurationHistogram.With(m.baseLabels...).Observe(duration.Seconds())
+	m.bytesCounter.With(append([]string{"direction", directionIn}, m.baseLabels...)...).Add(float64(bytesIn))
+	m.bytesCounter.With(append([]string{"direction", directionOut}, m.baseLabels...)...).Add(float64(bytesOut))
+}
+
+// NewTCPEntryPointConn wraps a connection accepted on an entry point to record its metrics,
+// the connection being accounted for as closed when its Close method is called.
+func NewTCPEntryPointConn(conn net.Conn, registry metrics.Registry, entryPointName string) net.Conn {
+	m := &tcpMetrics{
+		connsOpenedCounter:    registry.TCPEntrypointConnsOpenedCounter(),
+		connsClosedCounter:    registry.TCPEntrypointConnsClosedCounter(),
+		connDurationHistogram: registry.TCPEntrypointConnDurationHistogram(),
+		bytesCounter:          registry.TCPEntrypointBytesCounter(),
+		baseLabels:            []string{"entrypoint", entryPointName},
+	}
+	m.opened()
+
+	return &entryPointConn{
+		ObservedConn: tcp.Observe(conn),
+		metrics:      m,
+		start:        time.Now(),
+	}
+}
+
+type entryPointConn struct {
+	*tcp.ObservedConn
+	metrics   *tcpMetrics
+	start     time.Time
+	closeOnce sync.Once
+}
+
+func (c *entryPointConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.metrics.closed(time.Since(c.start), c.BytesRead(), c.BytesWritten())
+	})
+	return c.ObservedConn.Close()
+}
+
+// NewTCPServiceHandler creates a TCP handler recording the metrics of the connections forwarded to a service.
+func NewTCPServiceHandler(next tcp.Handler, registry metrics.Registry, serviceName string) tcp.Handler {
+	return &tcpServiceHandler{
+		next: next,
+		metrics: &tcpMetrics{
+			connsOpenedCounter:    registry.TCPServiceConnsOpenedCounter(),
+			connsClosedCounter:    registry.TCPServiceConnsClosedCounter(),
+			connDurationHistogram: registry.TCPServiceConnDurationHistogram(),
+			bytesCounter:          registry.TCPServiceBytesCounter(),
+			baseLabels:            []string{"service", serviceName},
+		},
+		dialFailuresCounter: registry.TCPServiceDialFailuresCounter(),
+	}
+}
+
+type tcpServiceHandler struct {
+	next                tcp.Handler
+	metrics             *tcpMetrics
+	dialFailuresCounter gokitmetrics.Counter
+}
+
+func (h *tcpServiceHandler) ServeTCP(conn net.Conn) {
+	observed := tcp.Observe(conn)
+
+	h.metrics.opened()
+
+	// The connection may be observed by other handlers too, so only the bytes exchanged while it is served are counted.
+	start := time.Now()
+	bytesIn, bytesOut := observed.BytesRead(), observed.BytesWritten()
+
+	h.next.ServeTCP(observed)
+
+	if observed.CloseReason() == tcp.CloseReasonDialError {
+		h.dialFailuresCounter.With(h.metrics.baseLabels...).Add(1)
+	}
+
+	h.metrics.closed(time.Since(start), observed.BytesRead()-bytesIn, observed.BytesWritten()-bytesOut)
+}
@@ -0,0 +1,141 @@
+package metrics
+
+import (
+	"io"
+	"net"
+	"testing"
+
+	"github.com/containous/traefik/pkg/metrics"
+	"github.com/containous/traefik/pkg/tcp"
+	"github.com/containous/traefik/pkg/testhelpers"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tcpCollectingRegistry struct {
+	metrics.Registry
+	connsOpenedCounter  *testhelpers.CollectingCounter
+	connsClosedCounter  *testhelpers.CollectingCounter
+	connDurations       *collectingHistogram
+	bytesCounter        *testhelpers.CollectingCounter
+	dialFailuresCounter *testhelpers.CollectingCounter
+}
+
+func newTCPCollectingRegistry() *tcpCollectingRegistry {
+	return &tcpCollectingRegistry{
+		Registry:            metrics.NewVoidRegistry(),
+		connsOpenedCounter:  &testhelpers.CollectingCounter{},
+		connsClosedCounter:  &testhelpers.CollectingCounter{},
+		connDurations:       &collectingHistogram{},
+		bytesCounter:        &testhelpers.CollectingCounter{},
+		dialFailuresCounter: &testhelpers.CollectingCounter{},
+	}
+}
+
+func (r *tcpCollectingRegistry) TCPEntrypointConnsOpenedCounter() gokitmetrics.Counter {
+	return r.connsOpenedCounter
+}
+func (r *tcpCollectingRegistry) TCPEntrypointConnsClosedCounter() gokitmetrics.Counter {
+	return r.connsClosedCounter
+}
+func (r *tcpCollectingRegistry) TCPEntrypointConnDurationHistogram() gokitmetrics.Histogram {
+	return r.connDurations
+}
+func (r *tcpCollectingRegistry) TCPEntrypointBytesCounter() gokitmetrics.Counter {
+	return r.bytesCounter
+}
+func (r *tcpCollectingRegistry) TCPServiceConnsOpenedCounter() gokitmetrics.Counter {
+	return r.connsOpenedCounter
+}
+func (r *tcpCollectingRegistry) TCPServiceConnsClosedCounter() gokitmetrics.Counter {
+	return r.connsClosedCounter
+}
+func (r *tcpCollectingRegistry) TCPServiceConnDurationHistogram() gokitmetrics.Histogram {
+	return r.connDurations
+}
+func (r *tcpCollectingRegistry) TCPServiceBytesCounter() gokitmetrics.Counter {
+	return r.bytesCounter
+}
+func (r *tcpCollectingRegistry) TCPServiceDialFailuresCounter() gokitmetrics.Counter {
+	return r.dialFailuresCounter
+}
+
+func TestNewTCPEntryPointConn(t *testing.T) {
+	registry := newTCPCollectingRegistry()
+
+	client, server := net.Pipe()
+	defer client.Close()
+
+	conn := NewTCPEntryPointConn(server, registry, "tcp")
+	assert.Equal(t, float64(1), registry.connsOpenedCounter.CounterValue)
+	assert.Equal(t, []string{"entrypoint", "tcp"}, registry.connsOpenedCounter.LastLabelValues)
+
+	go func() {
+		_, _ = client.Write([]byte("foo"))
+		_, _ = io.ReadFull(client, make([]byte, 6))
+	}()
+
+	_, err := io.ReadFull(conn, make([]byte, 3))
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("foobar"))
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(0), registry.connsClosedCounter.CounterValue)
+
+	require.NoError(t, conn.Close())
+	_ = conn.Close()
+
+	assert.Equal(t, float64(1), registry.connsClosedCounter.CounterValue)
+	assert.Equal(t, 1, registry.connDurations.observations)
+	assert.Equal(t, []string{"entrypoint", "tcp"}, registry.connDurations.lastLabelValues)
+	assert.Equal(t, float64(9), registry.bytesCounter.CounterValue)
+	assert.Equal(t, []string{"direction", directionOut, "entrypoint", "tcp"}, registry.bytesCounter.LastLabelValues)
+}
+
+func TestNewTCPServiceHandler(t *testing.T) {
+	registry := newTCPCollectingRegistry()
+
+	client, server := net.Pipe()
+	defer client.Close()
+
+	go func() {
+		_, _ = io.ReadFull(client, make([]byte, 5))
+	}()
+
+	next := tcp.HandlerFunc(func(conn net.Conn) {
+		assert.Equal(t, float64(1), registry.connsOpenedCounter.CounterValue)
+
+		_, err := conn.Write([]byte("hello"))
+		require.NoError(t, err)
+	})
+
+	NewTCPServiceHandler(next, registry, "foo@file").ServeTCP(server)
+
+	assert.Equal(t, float64(1), registry.connsClosedCounter.CounterValue)
+	assert.Equal(t, []string{"service", "foo@file"}, registry.connsClosedCounter.LastLabelValues)
+	assert.Equal(t, 1, registry.connDurations.observations)
+	assert.Equal(t, float64(5), registry.bytesCounter.CounterValue)
+	assert.Equal(t, float64(0), registry.dialFailuresCounter.CounterValue)
+}
+
+func TestNewTCPServiceHandler_dialFailure(t *testing.T) {
+	registry := newTCPCollectingRegistry()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	proxy, err := tcp.NewProxy(address, nil, nil)
+	require.NoError(t, err)
+
+	client, server := net.Pipe()
+	defer client.Close()
+
+	NewTCPServiceHandler(proxy, registry, "foo@file").ServeTCP(server)
+
+	assert.Equal(t, float64(1), registry.dialFailuresCounter.CounterValue)
+	assert.Equal(t, []string{"service", "foo@file"}, registry.dialFailuresCounter.LastLabelValues)
+	assert.Equal(t, float64(1), registry.connsClosedCounter.CounterValue)
+}
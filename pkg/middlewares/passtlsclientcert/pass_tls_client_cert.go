@@ -58,10 +58,11 @@ func newDistinguishedNameOptions(info *config.TLSCLientCertificateDNInfo) *Disti
 
 // passTLSClientCert is a middleware that helps setup a few tls info features.
 type passTLSClientCert struct {
-	next http.Handler
-	name string
-	pem  bool                      // pass the sanitized pem to the backend in a specific header
-	info *tlsClientCertificateInfo // pass selected information from the client certificate
+	next  http.Handler
+	name  string
+	pem   bool                      // pass the sanitized pem to the backend in a specific header
+	info  *tlsClientCertificateInfo // pass selected information from the client certificate
+	chain bool                      // pass the verified chain instead of the certificates sent by the client
 }
 
 // New constructs a new PassTLSClientCert instance from supplied frontend header struct.
@@ -69,10 +70,11 @@ func New(ctx context.Context, next http.Handler, config config.PassTLSClientCert
 	middlewares.GetLogger(ctx, name, typeName).Debug("Creating middleware")
 
 	return &passTLSClientCert{
-		next: next,
-		name: name,
-		pem:  config.PEM,
-		info: newTLSClientInfo(config.Info),
+		next:  next,
+		name:  name,
+		pem:   config.PEM,
+		info:  newTLSClientInfo(config.Info),
+		chain: config.Chain,
 	}, nil
 }
 
@@ -218,17 +220,19 @@ func (p *passTLSClientCert) getXForwardedTLSClientCertInfo(certs []*x509.Certifi
 
 // modifyRequestHeaders set the wanted headers with the certificates information.
 func (p *passTLSClientCert) modifyRequestHeaders(logger logrus.FieldLogger, r *http.Request) {
+	certs := p.getCertificates(r)
+
 	if p.pem {
-		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
-			r.Header.Set(xForwardedTLSClientCert, getXForwardedTLSClientCert(logger, r.TLS.PeerCertificates))
+		if len(certs) > 0 {
+			r.Header.Set(xForwardedTLSClientCert, getXForwardedTLSClientCert(logger, certs))
 		} else {
 			logger.Warn("Try to extract certificate on a request without TLS")
 		}
 	}
 
 	if p.info != nil {
-		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
-			headerContent := p.getXForwardedTLSClientCertInfo(r.TLS.PeerCertificates)
+		if len(certs) > 0 {
+			headerContent := p.getXForwardedTLSClientCertInfo(certs)
 			r.Header.Set(xForwardedTLSClientCertInfo, url.QueryEscape(headerContent))
 		} else {
 			logger.Warn("Try to extract certificate on a request without TLS")
@@ -236,6 +240,20 @@ func (p *passTLSClientCert) modifyRequestHeaders(logger logrus.FieldLogger, r *h
 	}
 }
 
+// getCertificates returns the certificates to pass: the verified chain, up to the trusted root, if requested and available,
+// or the certificates sent by the client.
+func (p *passTLSClientCert) getCertificates(r *http.Request) []*x509.Certificate {
+	if r.TLS == nil {
+		return nil
+	}
+
+	if p.chain && len(r.TLS.VerifiedChains) > 0 {
+		return r.TLS.VerifiedChains[0]
+	}
+
+	return r.TLS.PeerCertificates
+}
+
 // sanitize As we pass the raw certificates, remove the useless data and make it http request compliant.
 func sanitize(cert []byte) string {
 	s := string(cert)
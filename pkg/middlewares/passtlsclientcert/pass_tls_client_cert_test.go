@@ -432,6 +432,53 @@ func TestTLSClientHeadersWithPEM(t *testing.T) {
 
 }
 
+func TestTLSClientHeadersWithChain(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         config.PassTLSClientCert
+		verifiedChain  []string
+		expectedHeader string
+	}{
+		{
+			desc:           "without chain option",
+			config:         config.PassTLSClientCert{PEM: true},
+			verifiedChain:  []string{minimalCheeseCrt, signingCA},
+			expectedHeader: getCleanCertContents([]string{minimalCheeseCrt}),
+		},
+		{
+			desc:           "with chain option",
+			config:         config.PassTLSClientCert{PEM: true, Chain: true},
+			verifiedChain:  []string{minimalCheeseCrt, signingCA},
+			expectedHeader: getCleanCertContents([]string{minimalCheeseCrt, signingCA}),
+		},
+		{
+			desc:           "with chain option, without verified chain",
+			config:         config.PassTLSClientCert{PEM: true, Chain: true},
+			expectedHeader: getCleanCertContents([]string{minimalCheeseCrt}),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			tlsClientHeaders, err := New(context.Background(), next, test.config, "foo")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://example.com/foo", nil)
+			req.TLS = buildTLSWith([]string{minimalCheeseCrt})
+			if len(test.verifiedChain) > 0 {
+				req.TLS.VerifiedChains = [][]*x509.Certificate{buildTLSWith(test.verifiedChain).PeerCertificates}
+			}
+
+			tlsClientHeaders.ServeHTTP(httptest.NewRecorder(), req)
+
+			require.Equal(t, test.expectedHeader, req.Header.Get(xForwardedTLSClientCert))
+		})
+	}
+}
+
 func TestGetSans(t *testing.T) {
 	urlFoo, err := url.Parse("my.foo.com")
 	require.NoError(t, err)
@@ -0,0 +1,100 @@
+package statistics
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/containous/alice"
+	"github.com/containous/traefik/pkg/middlewares"
+	"github.com/containous/traefik/pkg/middlewares/metrics"
+	"github.com/containous/traefik/pkg/statistics"
+)
+
+const (
+	typeName   = "Statistics"
+	nameRouter = "statistics-router"
+)
+
+type key string
+
+const requestDataKey key = "statistics-request-data"
+
+// requestData holds the information about the request gathered while forwarding it to the backend.
+type requestData struct {
+	lock       sync.Mutex
+	backendURL string
+	err        string
+}
+
+// SetBackendURL records the URL of the backend the request is forwarded to.
+func SetBackendURL(ctx context.Context, backendURL *url.URL) {
+	if data, ok := ctx.Value(requestDataKey).(*requestData); ok && backendURL != nil {
+		data.lock.Lock()
+		data.backendURL = (&url.URL{Scheme: backendURL.Scheme, Host: backendURL.Host}).String()
+		data.lock.Unlock()
+	}
+}
+
+// SetError records the error that prevented the request from being forwarded to the backend.
+func SetError(ctx context.Context, err error) {
+	if data, ok := ctx.Value(requestDataKey).(*requestData); ok && err != nil {
+		data.lock.Lock()
+		data.err = err.Error()
+		data.lock.Unlock()
+	}
+}
+
+type routerMiddleware struct {
+	next     http.Handler
+	recorder *statistics.Recorder
+	router   string
+	service  string
+}
+
+// NewRouterMiddleware creates a new middleware recording the outcome of the requests handled by a router.
+func NewRouterMiddleware(ctx context.Context, next http.Handler, recorder *statistics.Recorder, routerName string, serviceName string) http.Handler {
+	middlewares.GetLogger(ctx, nameRouter, typeName).Debug("Creating middleware")
+
+	return &routerMiddleware{
+		next:     next,
+		recorder: recorder,
+		router:   routerName,
+		service:  serviceName,
+	}
+}
+
+// WrapRouterHandler Wraps statistics router to alice.Constructor.
+func WrapRouterHandler(ctx context.Context, recorder *statistics.Recorder, routerName string, serviceName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return NewRouterMiddleware(ctx, next, recorder, routerName, serviceName), nil
+	}
+}
+
+func (m *routerMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	data := &requestData{}
+	req = req.WithContext(context.WithValue(req.Context(), requestDataKey, data))
+
+	start := time.Now()
+	recorder := metrics.NewResponseRecorder(rw)
+
+	m.next.ServeHTTP(recorder, req)
+
+	data.lock.Lock()
+	defer data.lock.Unlock()
+
+	m.recorder.Record(statistics.Entry{
+		Time:       start,
+		Router:     m.router,
+		Service:    m.service,
+		BackendURL: data.backendURL,
+		Method:     req.Method,
+		Host:       req.Host,
+		Path:       req.URL.Path,
+		Status:     recorder.GetCode(),
+		Duration:   time.Since(start),
+		Error:      data.err,
+	})
+}
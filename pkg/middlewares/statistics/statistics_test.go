@@ -0,0 +1,94 @@
+package statistics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/containous/traefik/pkg/statistics"
+	"github.com/containous/traefik/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterMiddleware(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		next     http.HandlerFunc
+		expected []statistics.ErrorEntry
+	}{
+		{
+			desc: "success",
+			next: func(rw http.ResponseWriter, req *http.Request) {
+				SetBackendURL(req.Context(), testhelpers.MustParseURL("http://10.0.0.1:8080/foo?bar=baz"))
+				rw.WriteHeader(http.StatusOK)
+			},
+			expected: []statistics.ErrorEntry{},
+		},
+		{
+			desc: "server error",
+			next: func(rw http.ResponseWriter, req *http.Request) {
+				SetBackendURL(req.Context(), testhelpers.MustParseURL("http://10.0.0.1:8080/foo?bar=baz"))
+				rw.WriteHeader(http.StatusServiceUnavailable)
+			},
+			expected: []statistics.ErrorEntry{{
+				Router:     "foo@file",
+				Service:    "bar@file",
+				BackendURL: "http://10.0.0.1:8080",
+				Method:     http.MethodGet,
+				Host:       "foo.bar",
+				Path:       "/baz",
+				Status:     http.StatusServiceUnavailable,
+				Message:    "Service Unavailable",
+			}},
+		},
+		{
+			desc: "proxy error",
+			next: func(rw http.ResponseWriter, req *http.Request) {
+				SetBackendURL(req.Context(), testhelpers.MustParseURL("http://10.0.0.1:8080/foo"))
+				SetError(req.Context(), errors.New("dial tcp 10.0.0.1:8080: connect: connection refused"))
+				rw.WriteHeader(http.StatusBadGateway)
+			},
+			expected: []statistics.ErrorEntry{{
+				Router:     "foo@file",
+				Service:    "bar@file",
+				BackendURL: "http://10.0.0.1:8080",
+				Method:     http.MethodGet,
+				Host:       "foo.bar",
+				Path:       "/baz",
+				Status:     http.StatusBadGateway,
+				Message:    "dial tcp 10.0.0.1:8080: connect: connection refused",
+			}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := statistics.NewRecorder(10)
+			handler := NewRouterMiddleware(context.Background(), test.next, recorder, "foo@file", "bar@file")
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://foo.bar/baz", nil))
+
+			recentErrors := recorder.RecentErrors()
+			require.Len(t, recentErrors, len(test.expected))
+			for i := range recentErrors {
+				assert.False(t, recentErrors[i].Time.IsZero())
+				recentErrors[i].Time = test.expected[i].Time
+			}
+			assert.Equal(t, test.expected, recentErrors)
+
+			assert.Equal(t, uint64(1), recorder.ServiceStats("bar@file").Requests)
+		})
+	}
+}
+
+func TestSetBackendURL_withoutMiddleware(t *testing.T) {
+	// Without the middleware, the information is dropped.
+	SetBackendURL(context.Background(), testhelpers.MustParseURL("http://10.0.0.1:8080"))
+	SetError(context.Background(), errors.New("foo"))
+}
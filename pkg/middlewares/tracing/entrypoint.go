@@ -33,13 +33,24 @@ type entryPointMiddleware struct {
 }
 
 func (e *entryPointMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
-	spanCtx, _ := e.Extract(opentracing.HTTPHeaders, tracing.HTTPHeadersCarrier(req.Header))
+	if e.IsExcluded(req) {
+		e.next.ServeHTTP(rw, req)
+		return
+	}
+
+	spanCtx, err := e.Extract(opentracing.HTTPHeaders, tracing.HTTPHeadersCarrier(req.Header))
 
 	span, req, finish := e.StartSpanf(req, ext.SpanKindRPCServerEnum, "EntryPoint", []string{e.entryPoint, req.Host}, " ", ext.RPCServerOption(spanCtx))
 	defer finish()
 
 	ext.Component.Set(span, e.ServiceName)
 	tracing.LogRequest(span, req)
+	e.LogRequestHeaders(span, req)
+
+	// The sampling decision of the incoming trace context, if any, is honored.
+	if err != nil {
+		req = e.Sample(span, req)
+	}
 
 	req = req.WithContext(tracing.WithTracing(req.Context(), e.Tracing))
 
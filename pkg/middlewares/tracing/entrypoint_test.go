@@ -22,6 +22,8 @@ func TestEntryPointMiddleware(t *testing.T) {
 		desc          string
 		entryPoint    string
 		spanNameLimit int
+		options       *tracing.Options
+		path          string
 		tracing       *trackingBackenMock
 		expected      expected
 	}{
@@ -61,15 +63,51 @@ func TestEntryPointMiddleware(t *testing.T) {
 				OperationName: "EntryPoint te... ww... 0c15301b",
 			},
 		},
+		{
+			desc:       "header tags",
+			entryPoint: "test",
+			options: &tracing.Options{
+				HeaderTags: map[string]string{"tenant": "X-Tenant", "user": "X-User"},
+			},
+			tracing: &trackingBackenMock{
+				tracer: &MockTracer{Span: &MockSpan{Tags: make(map[string]interface{})}},
+			},
+			expected: expected{
+				Tags: map[string]interface{}{
+					"span.kind":   ext.SpanKindRPCServerEnum,
+					"http.method": http.MethodGet,
+					"component":   "",
+					"http.url":    "http://www.test.com",
+					"http.host":   "www.test.com",
+					"tenant":      "acme",
+				},
+				OperationName: "EntryPoint test www.test.com",
+			},
+		},
+		{
+			desc:       "excluded path",
+			entryPoint: "test",
+			options: &tracing.Options{
+				ExcludedPaths: []string{"/ping"},
+			},
+			path: "/ping",
+			tracing: &trackingBackenMock{
+				tracer: &MockTracer{Span: &MockSpan{Tags: make(map[string]interface{})}},
+			},
+			expected: expected{
+				Tags: map[string]interface{}{},
+			},
+		},
 	}
 
 	for _, test := range testCases {
 		t.Run(test.desc, func(t *testing.T) {
 
-			newTracing, err := tracing.NewTracing("", test.spanNameLimit, test.tracing)
+			newTracing, err := tracing.NewTracing("", test.spanNameLimit, test.tracing, test.options)
 			require.NoError(t, err)
 
-			req := httptest.NewRequest(http.MethodGet, "http://www.test.com", nil)
+			req := httptest.NewRequest(http.MethodGet, "http://www.test.com"+test.path, nil)
+			req.Header.Set("X-Tenant", "acme")
 			rw := httptest.NewRecorder()
 
 			next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/containous/alice"
+	"github.com/containous/traefik/pkg/middlewares"
+	"github.com/containous/traefik/pkg/tracing"
+)
+
+const (
+	routerTypeName = "TracingRouter"
+)
+
+type routerMiddleware struct {
+	router string
+	next   http.Handler
+}
+
+// NewRouter creates a new middleware applying the sampling settings of the router to the trace of the request.
+func NewRouter(ctx context.Context, router string, next http.Handler) http.Handler {
+	middlewares.GetLogger(ctx, "tracing", routerTypeName).Debug("Creating middleware")
+
+	return &routerMiddleware{
+		router: router,
+		next:   next,
+	}
+}
+
+func (r *routerMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if tr, err := tracing.FromContext(req.Context()); err == nil {
+		tr.SampleRouter(req, r.router)
+	}
+
+	r.next.ServeHTTP(rw, req)
+}
+
+// WrapRouterHandler Wraps tracing to alice.Constructor.
+func WrapRouterHandler(ctx context.Context, routerName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return NewRouter(ctx, routerName, next), nil
+	}
+}
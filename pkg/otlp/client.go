@@ -0,0 +1,188 @@
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// Config configures the connection to an OpenTelemetry collector.
+type Config struct {
+	// Address is the host:port address of the collector.
+	Address string
+	// GRPC selects the OTLP/gRPC transport instead of the OTLP/HTTP one.
+	GRPC bool
+	// Insecure disables TLS.
+	Insecure bool
+	// Headers are sent with each export request.
+	Headers map[string]string
+	// Timeout of the export requests, 10 seconds if not set.
+	Timeout time.Duration
+}
+
+type signal struct {
+	httpPath   string
+	grpcMethod string
+}
+
+var (
+	tracesSignal = signal{
+		httpPath:   "/v1/traces",
+		grpcMethod: "/opentelemetry.proto.collector.trace.v1.TraceService/Export",
+	}
+	metricsSignal = signal{
+		httpPath:   "/v1/metrics",
+		grpcMethod: "/opentelemetry.proto.collector.metrics.v1.MetricsService/Export",
+	}
+)
+
+// Client exports the telemetry to an OpenTelemetry collector.
+type Client struct {
+	config     Config
+	httpClient *http.Client
+	conn       *grpc.ClientConn
+}
+
+// NewClient creates a client exporting to the collector configured by config.
+func NewClient(config Config) (*Client, error) {
+	if len(config.Address) == 0 {
+		return nil, errors.New("the address of the OpenTelemetry collector is required")
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	client := &Client{config: config}
+
+	if !config.GRPC {
+		client.httpClient = &http.Client{Timeout: config.Timeout}
+		return client, nil
+	}
+
+	transportOption := grpc.WithInsecure()
+	if !config.Insecure {
+		transportOption = grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{}))
+	}
+
+	// The connection is established in the background, so that Traefik starts even if the collector is not available.
+	conn, err := grpc.Dial(config.Address, transportOption)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to the OpenTelemetry collector %s: %v", config.Address, err)
+	}
+	client.conn = conn
+
+	return client, nil
+}
+
+// ExportTraces sends an encoded ExportTraceServiceRequest to the collector.
+func (c *Client) ExportTraces(ctx context.Context, payload []byte) error {
+	return c.export(ctx, tracesSignal, payload)
+}
+
+// ExportMetrics sends an encoded ExportMetricsServiceRequest to the collector.
+func (c *Client) ExportMetrics(ctx context.Context, payload []byte) error {
+	return c.export(ctx, metricsSignal, payload)
+}
+
+// Close closes the connection to the collector.
+func (c *Client) Close() error {
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
+
+func (c *Client) export(ctx context.Context, s signal, payload []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	if c.conn != nil {
+		return c.exportGRPC(ctx, s, payload)
+	}
+	return c.exportHTTP(ctx, s, payload)
+}
+
+func (c *Client) exportGRPC(ctx context.Context, s signal, payload []byte) error {
+	if len(c.config.Headers) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(c.config.Headers))
+	}
+
+	var reply []byte
+	if err := c.conn.Invoke(ctx, s.grpcMethod, payload, &reply, grpc.ForceCodec(rawCodec{})); err != nil {
+		return fmt.Errorf("unable to export to the OpenTelemetry collector %s: %v", c.config.Address, err)
+	}
+	return nil
+}
+
+func (c *Client) exportHTTP(ctx context.Context, s signal, payload []byte) error {
+	scheme := "https"
+	if c.config.Insecure {
+		scheme = "http"
+	}
+
+	req, err := http.NewRequest(http.MethodPost, scheme+"://"+c.config.Address+s.httpPath, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	for name, value := range c.config.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to export to the OpenTelemetry collector %s: %v", c.config.Address, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unable to export to the OpenTelemetry collector %s: %s: %s", c.config.Address, resp.Status, bytes.TrimSpace(body))
+	}
+
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	return nil
+}
+
+// rawCodec sends and receives the already encoded protobuf messages as is.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	switch msg := v.(type) {
+	case []byte:
+		return msg, nil
+	case *[]byte:
+		return *msg, nil
+	default:
+		return nil, fmt.Errorf("unexpected message type %T", v)
+	}
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+	*msg = append((*msg)[:0], data...)
+	return nil
+}
+
+// Name returns the content-subtype of the collectors, the messages being protobuf encoded.
+func (rawCodec) Name() string {
+	return "proto"
+}
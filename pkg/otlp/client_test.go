@@ -0,0 +1,144 @@
+package otlp
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type exportRequest struct {
+	path    string
+	headers map[string]string
+	payload []byte
+}
+
+// serverCodec is the rawCodec of the fake gRPC collector, as the servers require the legacy grpc.Codec interface.
+type serverCodec struct {
+	rawCodec
+}
+
+func (serverCodec) String() string {
+	return "proto"
+}
+
+func TestClient_HTTP(t *testing.T) {
+	requests := make(chan exportRequest, 1)
+
+	collector := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		payload, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		requests <- exportRequest{
+			path: req.URL.Path,
+			headers: map[string]string{
+				"content-type":  req.Header.Get("Content-Type"),
+				"authorization": req.Header.Get("Authorization"),
+			},
+			payload: payload,
+		}
+	}))
+	defer collector.Close()
+
+	client, err := NewClient(Config{
+		Address:  strings.TrimPrefix(collector.URL, "http://"),
+		Insecure: true,
+		Headers:  map[string]string{"Authorization": "Bearer foo"},
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.ExportTraces(context.Background(), []byte("traces")))
+
+	request := <-requests
+	assert.Equal(t, "/v1/traces", request.path)
+	assert.Equal(t, "application/x-protobuf", request.headers["content-type"])
+	assert.Equal(t, "Bearer foo", request.headers["authorization"])
+	assert.Equal(t, []byte("traces"), request.payload)
+
+	require.NoError(t, client.ExportMetrics(context.Background(), []byte("metrics")))
+
+	request = <-requests
+	assert.Equal(t, "/v1/metrics", request.path)
+	assert.Equal(t, []byte("metrics"), request.payload)
+}
+
+func TestClient_HTTPError(t *testing.T) {
+	collector := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		http.Error(rw, "invalid payload", http.StatusBadRequest)
+	}))
+	defer collector.Close()
+
+	client, err := NewClient(Config{Address: strings.TrimPrefix(collector.URL, "http://"), Insecure: true})
+	require.NoError(t, err)
+	defer client.Close()
+
+	err = client.ExportTraces(context.Background(), []byte("traces"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid payload")
+}
+
+func TestClient_GRPC(t *testing.T) {
+	requests := make(chan exportRequest, 1)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer(
+		grpc.CustomCodec(serverCodec{}),
+		grpc.UnknownServiceHandler(func(srv interface{}, stream grpc.ServerStream) error {
+			var payload []byte
+			if err := stream.RecvMsg(&payload); err != nil {
+				return err
+			}
+
+			method, _ := grpc.MethodFromServerStream(stream)
+			md, _ := metadata.FromIncomingContext(stream.Context())
+
+			requests <- exportRequest{
+				path:    method,
+				headers: map[string]string{"authorization": strings.Join(md.Get("authorization"), ",")},
+				payload: payload,
+			}
+
+			return stream.SendMsg([]byte{})
+		}),
+	)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	client, err := NewClient(Config{
+		Address:  listener.Addr().String(),
+		GRPC:     true,
+		Insecure: true,
+		Headers:  map[string]string{"Authorization": "Bearer foo"},
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.ExportTraces(context.Background(), []byte("traces")))
+
+	request := <-requests
+	assert.Equal(t, "/opentelemetry.proto.collector.trace.v1.TraceService/Export", request.path)
+	assert.Equal(t, "Bearer foo", request.headers["authorization"])
+	assert.Equal(t, []byte("traces"), request.payload)
+
+	require.NoError(t, client.ExportMetrics(context.Background(), []byte("metrics")))
+
+	request = <-requests
+	assert.Equal(t, "/opentelemetry.proto.collector.metrics.v1.MetricsService/Export", request.path)
+	assert.Equal(t, []byte("metrics"), request.payload)
+}
+
+func TestNewClient_noAddress(t *testing.T) {
+	_, err := NewClient(Config{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,377 @@
+package otlp
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/containous/traefik/pkg/version"
+	"github.com/golang/protobuf/proto"
+)
+
+// ScopeName is the name of the instrumentation scope of the spans and metrics exported by Traefik.
+const ScopeName = "github.com/containous/traefik"
+
+// KeyValue is an attribute of a resource, a span, an event or a data point.
+// Its value is a string, a bool, an integer or a float, any other type being exported as a string.
+type KeyValue struct {
+	Key   string
+	Value interface{}
+}
+
+// Resource returns the attributes of the resource exporting the telemetry.
+func Resource(serviceName string) []KeyValue {
+	return []KeyValue{
+		{Key: "service.name", Value: serviceName},
+		{Key: "service.version", Value: version.Version},
+	}
+}
+
+// SpanKind is the kind of a span.
+type SpanKind int32
+
+// Span kinds.
+const (
+	SpanKindUnspecified SpanKind = iota
+	SpanKindInternal
+	SpanKindServer
+	SpanKindClient
+	SpanKindProducer
+	SpanKindConsumer
+)
+
+// StatusCode is the status of a span.
+type StatusCode int32
+
+// Status codes.
+const (
+	StatusCodeUnset StatusCode = iota
+	StatusCodeOK
+	StatusCodeError
+)
+
+// Span is a finished span.
+type Span struct {
+	TraceID       [16]byte
+	SpanID        [8]byte
+	ParentSpanID  [8]byte
+	TraceState    string
+	Name          string
+	Kind          SpanKind
+	Start         time.Time
+	End           time.Time
+	Attributes    []KeyValue
+	Events        []Event
+	StatusCode    StatusCode
+	StatusMessage string
+}
+
+// Event is a timestamped event of a span.
+type Event struct {
+	Time       time.Time
+	Name       string
+	Attributes []KeyValue
+}
+
+// MetricKind is the kind of a metric.
+type MetricKind int
+
+// Metric kinds.
+const (
+	MetricGauge MetricKind = iota
+	MetricCounter
+	MetricHistogram
+)
+
+// Metric is a metric with its data points.
+// The data points of the counters and histograms are cumulative.
+type Metric struct {
+	Name            string
+	Kind            MetricKind
+	NumberPoints    []NumberDataPoint
+	HistogramPoints []HistogramDataPoint
+}
+
+// NumberDataPoint is a data point of a gauge or a counter.
+type NumberDataPoint struct {
+	Attributes []KeyValue
+	Start      time.Time
+	Time       time.Time
+	Value      float64
+}
+
+// HistogramDataPoint is a data point of a histogram.
+// BucketCounts has one more element than ExplicitBounds, for the values greater than the last bound.
+type HistogramDataPoint struct {
+	Attributes     []KeyValue
+	Start          time.Time
+	Time           time.Time
+	Count          uint64
+	Sum            float64
+	BucketCounts   []uint64
+	ExplicitBounds []float64
+}
+
+// EncodeTraces encodes the spans in an ExportTraceServiceRequest protobuf message.
+func EncodeTraces(resource []KeyValue, spans []Span) []byte {
+	e := &encoder{}
+	e.message(1, func(e *encoder) { // ResourceSpans
+		e.message(1, func(e *encoder) { e.attributes(1, resource) })
+		e.message(2, func(e *encoder) { // ScopeSpans
+			e.message(1, encodeScope)
+			for _, span := range spans {
+				span := span
+				e.message(2, func(e *encoder) { encodeSpan(e, span) })
+			}
+		})
+	})
+	return e.Bytes()
+}
+
+// EncodeMetrics encodes the metrics in an ExportMetricsServiceRequest protobuf message.
+func EncodeMetrics(resource []KeyValue, metrics []Metric) []byte {
+	e := &encoder{}
+	e.message(1, func(e *encoder) { // ResourceMetrics
+		e.message(1, func(e *encoder) { e.attributes(1, resource) })
+		e.message(2, func(e *encoder) { // ScopeMetrics
+			e.message(1, encodeScope)
+			for _, metric := range metrics {
+				metric := metric
+				e.message(2, func(e *encoder) { encodeMetric(e, metric) })
+			}
+		})
+	})
+	return e.Bytes()
+}
+
+func encodeScope(e *encoder) {
+	e.string(1, ScopeName)
+	e.string(2, version.Version)
+}
+
+func encodeSpan(e *encoder, span Span) {
+	e.bytes(1, span.TraceID[:])
+	e.bytes(2, span.SpanID[:])
+	e.string(3, span.TraceState)
+	if span.ParentSpanID != [8]byte{} {
+		e.bytes(4, span.ParentSpanID[:])
+	}
+	e.string(5, span.Name)
+	e.varint(6, uint64(span.Kind))
+	e.fixed64(7, timestamp(span.Start))
+	e.fixed64(8, timestamp(span.End))
+	e.attributes(9, span.Attributes)
+	for _, event := range span.Events {
+		event := event
+		e.message(11, func(e *encoder) {
+			e.fixed64(1, timestamp(event.Time))
+			e.string(2, event.Name)
+			e.attributes(3, event.Attributes)
+		})
+	}
+	e.message(15, func(e *encoder) {
+		e.string(2, span.StatusMessage)
+		e.varint(3, uint64(span.StatusCode))
+	})
+}
+
+// aggregationTemporalityCumulative is the AGGREGATION_TEMPORALITY_CUMULATIVE enum value.
+const aggregationTemporalityCumulative = 2
+
+func encodeMetric(e *encoder, metric Metric) {
+	e.string(1, metric.Name)
+
+	switch metric.Kind {
+	case MetricGauge:
+		e.message(5, func(e *encoder) {
+			for _, point := range metric.NumberPoints {
+				point := point
+				e.message(1, func(e *encoder) { encodeNumberDataPoint(e, point) })
+			}
+		})
+	case MetricCounter:
+		e.message(7, func(e *encoder) {
+			for _, point := range metric.NumberPoints {
+				point := point
+				e.message(1, func(e *encoder) { encodeNumberDataPoint(e, point) })
+			}
+			e.varint(2, aggregationTemporalityCumulative)
+			e.bool(3, true)
+		})
+	case MetricHistogram:
+		e.message(9, func(e *encoder) {
+			for _, point := range metric.HistogramPoints {
+				point := point
+				e.message(1, func(e *encoder) { encodeHistogramDataPoint(e, point) })
+			}
+			e.varint(2, aggregationTemporalityCumulative)
+		})
+	}
+}
+
+func encodeNumberDataPoint(e *encoder, point NumberDataPoint) {
+	e.fixed64(2, timestamp(point.Start))
+	e.fixed64(3, timestamp(point.Time))
+	e.double(4, point.Value)
+	e.attributes(7, point.Attributes)
+}
+
+func encodeHistogramDataPoint(e *encoder, point HistogramDataPoint) {
+	e.fixed64(2, timestamp(point.Start))
+	e.fixed64(3, timestamp(point.Time))
+	e.fixed64(4, point.Count)
+	e.double(5, point.Sum)
+	e.packedFixed64(6, point.BucketCounts)
+
+	bounds := make([]uint64, len(point.ExplicitBounds))
+	for i, bound := range point.ExplicitBounds {
+		bounds[i] = math.Float64bits(bound)
+	}
+	e.packedFixed64(7, bounds)
+
+	e.attributes(9, point.Attributes)
+}
+
+func timestamp(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.UnixNano())
+}
+
+// Protobuf wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+)
+
+// encoder writes the fields of a protobuf message,
+// the zero values being omitted as done by the proto3 encoders.
+// The tests decode the encoded messages with the messages generated from the OTLP protobuf definitions.
+type encoder struct {
+	proto.Buffer
+}
+
+func (e *encoder) tag(field, wireType int) {
+	_ = e.EncodeVarint(uint64(field<<3 | wireType))
+}
+
+func (e *encoder) varint(field int, value uint64) {
+	if value == 0 {
+		return
+	}
+	e.tag(field, wireVarint)
+	_ = e.EncodeVarint(value)
+}
+
+func (e *encoder) bool(field int, value bool) {
+	if value {
+		e.varint(field, 1)
+	}
+}
+
+func (e *encoder) fixed64(field int, value uint64) {
+	if value == 0 {
+		return
+	}
+	e.tag(field, wireFixed64)
+	_ = e.EncodeFixed64(value)
+}
+
+// double always writes the value, as the doubles of the OTLP messages are either in a oneof or optional.
+func (e *encoder) double(field int, value float64) {
+	e.tag(field, wireFixed64)
+	_ = e.EncodeFixed64(math.Float64bits(value))
+}
+
+func (e *encoder) string(field int, value string) {
+	if len(value) == 0 {
+		return
+	}
+	e.tag(field, wireBytes)
+	_ = e.EncodeStringBytes(value)
+}
+
+func (e *encoder) bytes(field int, value []byte) {
+	if len(value) == 0 {
+		return
+	}
+	e.tag(field, wireBytes)
+	_ = e.EncodeRawBytes(value)
+}
+
+func (e *encoder) packedFixed64(field int, values []uint64) {
+	if len(values) == 0 {
+		return
+	}
+	packed := &encoder{}
+	for _, value := range values {
+		_ = packed.EncodeFixed64(value)
+	}
+	e.bytes(field, packed.Bytes())
+}
+
+func (e *encoder) message(field int, encode func(e *encoder)) {
+	inner := &encoder{}
+	encode(inner)
+	e.tag(field, wireBytes)
+	_ = e.EncodeRawBytes(inner.Bytes())
+}
+
+func (e *encoder) attributes(field int, attributes []KeyValue) {
+	for _, attribute := range attributes {
+		attribute := attribute
+		e.message(field, func(e *encoder) {
+			e.string(1, attribute.Key)
+			e.message(2, func(e *encoder) { encodeAnyValue(e, attribute.Value) })
+		})
+	}
+}
+
+func encodeAnyValue(e *encoder, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		e.tag(1, wireBytes)
+		_ = e.EncodeStringBytes(v)
+	case bool:
+		e.tag(2, wireVarint)
+		if v {
+			_ = e.EncodeVarint(1)
+		} else {
+			_ = e.EncodeVarint(0)
+		}
+	case int:
+		encodeIntValue(e, int64(v))
+	case int8:
+		encodeIntValue(e, int64(v))
+	case int16:
+		encodeIntValue(e, int64(v))
+	case int32:
+		encodeIntValue(e, int64(v))
+	case int64:
+		encodeIntValue(e, v)
+	case uint:
+		encodeIntValue(e, int64(v))
+	case uint8:
+		encodeIntValue(e, int64(v))
+	case uint16:
+		encodeIntValue(e, int64(v))
+	case uint32:
+		encodeIntValue(e, int64(v))
+	case uint64:
+		encodeIntValue(e, int64(v))
+	case float32:
+		e.double(4, float64(v))
+	case float64:
+		e.double(4, v)
+	default:
+		e.tag(1, wireBytes)
+		_ = e.EncodeStringBytes(fmt.Sprint(v))
+	}
+}
+
+func encodeIntValue(e *encoder, value int64) {
+	e.tag(3, wireVarint)
+	_ = e.EncodeVarint(uint64(value))
+}
@@ -0,0 +1,274 @@
+package otlp
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// fields decodes the fields of a protobuf message, by field number.
+// The varint and fixed64 values are decoded as uint64 and the length-delimited ones as []byte.
+func fields(t *testing.T, msg []byte) map[int][]interface{} {
+	t.Helper()
+
+	decoded := make(map[int][]interface{})
+
+	for len(msg) > 0 {
+		key, n := binary.Uvarint(msg)
+		require.True(t, n > 0, "invalid key")
+		msg = msg[n:]
+
+		var value interface{}
+		switch key & 7 {
+		case wireVarint:
+			v, n := binary.Uvarint(msg)
+			require.True(t, n > 0, "invalid varint")
+			value, msg = v, msg[n:]
+		case wireFixed64:
+			require.True(t, len(msg) >= 8, "invalid fixed64")
+			value, msg = binary.LittleEndian.Uint64(msg), msg[8:]
+		case wireBytes:
+			length, n := binary.Uvarint(msg)
+			require.True(t, n > 0 && len(msg[n:]) >= int(length), "invalid length")
+			value, msg = msg[n:n+int(length)], msg[n+int(length):]
+		default:
+			t.Fatalf("unexpected wire type %d", key&7)
+		}
+
+		decoded[int(key>>3)] = append(decoded[int(key>>3)], value)
+	}
+
+	return decoded
+}
+
+func message(t *testing.T, msg []byte, path ...int) map[int][]interface{} {
+	t.Helper()
+
+	decoded := fields(t, msg)
+	for _, field := range path {
+		require.NotEmpty(t, decoded[field], "field %d", field)
+		decoded = fields(t, decoded[field][0].([]byte))
+	}
+	return decoded
+}
+
+func TestEncodeTraces(t *testing.T) {
+	start := time.Unix(1, 0)
+
+	payload := EncodeTraces(Resource("traefik"), []Span{{
+		TraceID:    [16]byte{1},
+		SpanID:     [8]byte{2},
+		Name:       "foo",
+		Kind:       SpanKindServer,
+		Start:      start,
+		End:        start.Add(time.Second),
+		Attributes: []KeyValue{{Key: "http.status_code", Value: 200}},
+		Events:     []Event{{Time: start, Name: "bar"}},
+		StatusCode: StatusCodeError,
+	}})
+
+	resource := message(t, payload, 1, 1)
+	attribute := fields(t, resource[1][0].([]byte))
+	assert.Equal(t, []byte("service.name"), attribute[1][0])
+	assert.Equal(t, []byte("traefik"), fields(t, attribute[2][0].([]byte))[1][0])
+
+	scope := message(t, payload, 1, 2, 1)
+	assert.Equal(t, []byte(ScopeName), scope[1][0])
+
+	span := message(t, payload, 1, 2, 2)
+	assert.Equal(t, []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, span[1][0])
+	assert.Equal(t, []byte{2, 0, 0, 0, 0, 0, 0, 0}, span[2][0])
+	assert.Empty(t, span[4])
+	assert.Equal(t, []byte("foo"), span[5][0])
+	assert.Equal(t, uint64(SpanKindServer), span[6][0])
+	assert.Equal(t, uint64(start.UnixNano()), span[7][0])
+	assert.Equal(t, uint64(start.Add(time.Second).UnixNano()), span[8][0])
+	assert.Len(t, span[9], 1)
+	assert.Len(t, span[11], 1)
+	assert.Equal(t, uint64(StatusCodeError), fields(t, span[15][0].([]byte))[3][0])
+}
+
+func TestEncodeMetrics(t *testing.T) {
+	now := time.Unix(1, 0)
+
+	payload := EncodeMetrics(Resource("traefik"), []Metric{
+		{
+			Name:         "foo",
+			Kind:         MetricCounter,
+			NumberPoints: []NumberDataPoint{{Time: now, Value: 3, Attributes: []KeyValue{{Key: "code", Value: "200"}}}},
+		},
+		{
+			Name: "bar",
+			Kind: MetricHistogram,
+			HistogramPoints: []HistogramDataPoint{{
+				Time:           now,
+				Count:          2,
+				Sum:            1.5,
+				BucketCounts:   []uint64{1, 1},
+				ExplicitBounds: []float64{1},
+			}},
+		},
+	})
+
+	metrics := message(t, payload, 1, 2)[2]
+	require.Len(t, metrics, 2)
+
+	counter := fields(t, metrics[0].([]byte))
+	assert.Equal(t, []byte("foo"), counter[1][0])
+	sum := fields(t, counter[7][0].([]byte))
+	assert.Equal(t, uint64(aggregationTemporalityCumulative), sum[2][0])
+	assert.Equal(t, uint64(1), sum[3][0])
+	point := fields(t, sum[1][0].([]byte))
+	assert.Equal(t, 3.0, math.Float64frombits(point[4][0].(uint64)))
+	assert.Len(t, point[7], 1)
+
+	histogram := fields(t, metrics[1].([]byte))
+	assert.Equal(t, []byte("bar"), histogram[1][0])
+	histogramPoint := fields(t, fields(t, histogram[9][0].([]byte))[1][0].([]byte))
+	assert.Equal(t, uint64(2), histogramPoint[4][0])
+	assert.Equal(t, 1.5, math.Float64frombits(histogramPoint[5][0].(uint64)))
+	assert.Len(t, histogramPoint[6][0], 16)
+	assert.Len(t, histogramPoint[7][0], 8)
+}
+
+// The payloads are decoded with the messages generated from the OTLP protobuf definitions.
+// TracesData and MetricsData have the same fields as the ExportTraceServiceRequest and ExportMetricsServiceRequest messages.
+
+func TestEncodeTraces_otlpMessages(t *testing.T) {
+	start := time.Unix(1, 0)
+
+	payload := EncodeTraces(Resource("traefik"), []Span{{
+		TraceID:       [16]byte{1},
+		SpanID:        [8]byte{2},
+		ParentSpanID:  [8]byte{3},
+		TraceState:    "foo=bar",
+		Name:          "foo",
+		Kind:          SpanKindClient,
+		Start:         start,
+		End:           start.Add(time.Second),
+		Attributes:    []KeyValue{{Key: "int", Value: 200}, {Key: "bool", Value: true}, {Key: "float", Value: 1.5}, {Key: "other", Value: time.Second}},
+		Events:        []Event{{Time: start, Name: "bar", Attributes: []KeyValue{{Key: "string", Value: "baz"}}}},
+		StatusCode:    StatusCodeError,
+		StatusMessage: "error",
+	}})
+
+	traces := &tracepb.TracesData{}
+	require.NoError(t, proto.Unmarshal(payload, traces))
+
+	require.Len(t, traces.GetResourceSpans(), 1)
+	resourceSpans := traces.GetResourceSpans()[0]
+	assert.Equal(t, []*commonpb.KeyValue{
+		stringKeyValue("service.name", "traefik"),
+		stringKeyValue("service.version", "dev"),
+	}, resourceSpans.GetResource().GetAttributes())
+
+	require.Len(t, resourceSpans.GetScopeSpans(), 1)
+	scopeSpans := resourceSpans.GetScopeSpans()[0]
+	assert.Equal(t, ScopeName, scopeSpans.GetScope().GetName())
+	assert.Equal(t, "dev", scopeSpans.GetScope().GetVersion())
+
+	require.Len(t, scopeSpans.GetSpans(), 1)
+	span := scopeSpans.GetSpans()[0]
+	assert.Equal(t, []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, span.GetTraceId())
+	assert.Equal(t, []byte{2, 0, 0, 0, 0, 0, 0, 0}, span.GetSpanId())
+	assert.Equal(t, []byte{3, 0, 0, 0, 0, 0, 0, 0}, span.GetParentSpanId())
+	assert.Equal(t, "foo=bar", span.GetTraceState())
+	assert.Equal(t, "foo", span.GetName())
+	assert.Equal(t, tracepb.Span_SPAN_KIND_CLIENT, span.GetKind())
+	assert.Equal(t, uint64(start.UnixNano()), span.GetStartTimeUnixNano())
+	assert.Equal(t, uint64(start.Add(time.Second).UnixNano()), span.GetEndTimeUnixNano())
+
+	require.Len(t, span.GetAttributes(), 4)
+	assert.Equal(t, int64(200), span.GetAttributes()[0].GetValue().GetIntValue())
+	assert.True(t, span.GetAttributes()[1].GetValue().GetBoolValue())
+	assert.Equal(t, 1.5, span.GetAttributes()[2].GetValue().GetDoubleValue())
+	assert.Equal(t, "1s", span.GetAttributes()[3].GetValue().GetStringValue())
+
+	require.Len(t, span.GetEvents(), 1)
+	assert.Equal(t, uint64(start.UnixNano()), span.GetEvents()[0].GetTimeUnixNano())
+	assert.Equal(t, "bar", span.GetEvents()[0].GetName())
+	assert.Equal(t, []*commonpb.KeyValue{stringKeyValue("string", "baz")}, span.GetEvents()[0].GetAttributes())
+
+	assert.Equal(t, tracepb.Status_STATUS_CODE_ERROR, span.GetStatus().GetCode())
+	assert.Equal(t, "error", span.GetStatus().GetMessage())
+}
+
+func TestEncodeMetrics_otlpMessages(t *testing.T) {
+	start := time.Unix(1, 0)
+	now := time.Unix(2, 0)
+
+	payload := EncodeMetrics(Resource("traefik"), []Metric{
+		{
+			Name:         "gauge",
+			Kind:         MetricGauge,
+			NumberPoints: []NumberDataPoint{{Time: now, Value: 0}},
+		},
+		{
+			Name:         "counter",
+			Kind:         MetricCounter,
+			NumberPoints: []NumberDataPoint{{Start: start, Time: now, Value: 3, Attributes: []KeyValue{{Key: "code", Value: "200"}}}},
+		},
+		{
+			Name: "histogram",
+			Kind: MetricHistogram,
+			HistogramPoints: []HistogramDataPoint{{
+				Start:          start,
+				Time:           now,
+				Count:          2,
+				Sum:            1.5,
+				BucketCounts:   []uint64{1, 1},
+				ExplicitBounds: []float64{1},
+			}},
+		},
+	})
+
+	metricsData := &metricspb.MetricsData{}
+	require.NoError(t, proto.Unmarshal(payload, metricsData))
+
+	require.Len(t, metricsData.GetResourceMetrics(), 1)
+	require.Len(t, metricsData.GetResourceMetrics()[0].GetScopeMetrics(), 1)
+	metrics := metricsData.GetResourceMetrics()[0].GetScopeMetrics()[0].GetMetrics()
+	require.Len(t, metrics, 3)
+
+	assert.Equal(t, "gauge", metrics[0].GetName())
+	require.Len(t, metrics[0].GetGauge().GetDataPoints(), 1)
+	gaugePoint := metrics[0].GetGauge().GetDataPoints()[0]
+	assert.Equal(t, uint64(now.UnixNano()), gaugePoint.GetTimeUnixNano())
+	// A zero value is still set in the oneof.
+	assert.IsType(t, &metricspb.NumberDataPoint_AsDouble{}, gaugePoint.GetValue())
+
+	assert.Equal(t, "counter", metrics[1].GetName())
+	sum := metrics[1].GetSum()
+	assert.Equal(t, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, sum.GetAggregationTemporality())
+	assert.True(t, sum.GetIsMonotonic())
+	require.Len(t, sum.GetDataPoints(), 1)
+	sumPoint := sum.GetDataPoints()[0]
+	assert.Equal(t, uint64(start.UnixNano()), sumPoint.GetStartTimeUnixNano())
+	assert.Equal(t, uint64(now.UnixNano()), sumPoint.GetTimeUnixNano())
+	assert.Equal(t, 3.0, sumPoint.GetAsDouble())
+	assert.Equal(t, []*commonpb.KeyValue{stringKeyValue("code", "200")}, sumPoint.GetAttributes())
+
+	assert.Equal(t, "histogram", metrics[2].GetName())
+	histogram := metrics[2].GetHistogram()
+	assert.Equal(t, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, histogram.GetAggregationTemporality())
+	require.Len(t, histogram.GetDataPoints(), 1)
+	histogramPoint := histogram.GetDataPoints()[0]
+	assert.Equal(t, uint64(start.UnixNano()), histogramPoint.GetStartTimeUnixNano())
+	assert.Equal(t, uint64(now.UnixNano()), histogramPoint.GetTimeUnixNano())
+	assert.Equal(t, uint64(2), histogramPoint.GetCount())
+	assert.Equal(t, 1.5, histogramPoint.GetSum())
+	assert.Equal(t, []uint64{1, 1}, histogramPoint.GetBucketCounts())
+	assert.Equal(t, []float64{1}, histogramPoint.GetExplicitBounds())
+}
+
+func stringKeyValue(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
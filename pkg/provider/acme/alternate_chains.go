@@ -0,0 +1,164 @@
+package acme
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/containous/traefik/pkg/version"
+	jose "gopkg.in/square/go-jose.v2"
+)
+
+// maxChainSize is the maximum size of a certificate chain returned by the CA.
+const maxChainSize = 1 << 20
+
+var linkExpr = regexp.MustCompile(`<(.+?)>;\s*rel="(.+?)"`)
+
+// fetchAlternateChains returns the alternate chains offered by the CA for the certificate.
+// The ACME client does not expose them, so the certificate is requested again with a POST-as-GET request,
+// and the chains are read from its "alternate" links.
+func (p *Provider) fetchAlternateChains(certURL string) ([][]byte, error) {
+	if len(certURL) == 0 {
+		return nil, nil
+	}
+
+	_, header, err := p.postAsGet(certURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var chains [][]byte
+	for _, link := range header["Link"] {
+		for _, m := range linkExpr.FindAllStringSubmatch(link, -1) {
+			if m[2] != "alternate" {
+				continue
+			}
+
+			chain, _, err := p.postAsGet(m[1])
+			if err != nil {
+				return chains, err
+			}
+
+			chains = append(chains, chain)
+		}
+	}
+
+	return chains, nil
+}
+
+// postAsGet performs a POST-as-GET request signed with the account key.
+// https://tools.ietf.org/html/rfc8555#section-6.3
+func (p *Provider) postAsGet(uri string) ([]byte, http.Header, error) {
+	if p.account == nil || p.account.Registration == nil {
+		return nil, nil, errors.New("the ACME account is not registered")
+	}
+
+	nonce, err := p.getNonce()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{
+			Algorithm: jose.RS256,
+			Key:       jose.JSONWebKey{Key: p.account.GetPrivateKey(), KeyID: p.account.Registration.URI},
+		},
+		&jose.SignerOptions{
+			NonceSource:  staticNonce(nonce),
+			ExtraHeaders: map[jose.HeaderKey]interface{}{"url": uri},
+		},
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to create the request signer: %v", err)
+	}
+
+	signed, err := signer.Sign([]byte{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to sign the request: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, uri, strings.NewReader(signed.FullSerialize()))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/jose+json")
+
+	resp, err := p.do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(nil, resp.Body, maxChainSize))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, nil, fmt.Errorf("unexpected status code %d from %s: %s", resp.StatusCode, uri, string(body))
+	}
+
+	return body, resp.Header, nil
+}
+
+// getNonce gets a new nonce from the newNonce resource of the CA directory.
+func (p *Provider) getNonce() (string, error) {
+	req, err := http.NewRequest(http.MethodGet, p.getCAServer(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := p.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var directory struct {
+		NewNonceURL string `json:"newNonce"`
+	}
+	if err = json.NewDecoder(http.MaxBytesReader(nil, resp.Body, maxChainSize)).Decode(&directory); err != nil {
+		return "", fmt.Errorf("unable to read the CA directory: %v", err)
+	}
+
+	req, err = http.NewRequest(http.MethodHead, directory.NewNonceURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err = p.do(req)
+	if err != nil {
+		return "", err
+	}
+	_ = resp.Body.Close()
+
+	nonce := resp.Header.Get("Replay-Nonce")
+	if len(nonce) == 0 {
+		return "", errors.New("no nonce returned by the CA")
+	}
+
+	return nonce, nil
+}
+
+func (p *Provider) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", fmt.Sprintf("containous-traefik/%s", version.Version))
+
+	client := p.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return client.Do(req)
+}
+
+// staticNonce provides the nonce got from the CA to the request signer.
+type staticNonce string
+
+// Nonce returns the nonce.
+func (n staticNonce) Nonce() (string, error) {
+	return string(n), nil
+}
@@ -0,0 +1,500 @@
+package acme
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/safe"
+	corev1 "k8s.io/api/core/v1"
+	kubeerror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// secretLockTTL is the duration after which the lock of an instance which stopped is released.
+	secretLockTTL = 5 * time.Minute
+	// secretLockRetryInterval is the initial interval between two attempts to acquire a lock held by another instance.
+	secretLockRetryInterval = time.Second
+	// secretUpdateTimeout is the maximum duration retrying an update of the Secret modified by other instances meanwhile.
+	secretUpdateTimeout = 30 * time.Second
+	// secretCacheRefreshInterval is the minimum interval between two reads of the Secret to serve the challenges.
+	secretCacheRefreshInterval = time.Second
+	// secretLockAnnotationPrefix prefixes the annotations of the Secret holding the locks.
+	secretLockAnnotationPrefix = "acme.traefik.containo.us/lock-"
+)
+
+var (
+	_ Store          = (*KubernetesStore)(nil)
+	_ ChallengeStore = (*KubernetesStore)(nil)
+	_ Locker         = (*KubernetesStore)(nil)
+
+	errLockHeld = errors.New("lock held by another instance")
+	errLockLost = errors.New("lock lost")
+)
+
+// KubernetesStorage contains the configuration of the Kubernetes Secret storing the ACME data.
+type KubernetesStorage struct {
+	Endpoint         string `description:"Kubernetes server endpoint (required for external cluster client)."`
+	Token            string `description:"Kubernetes bearer token (not needed for in-cluster client)."`
+	CertAuthFilePath string `description:"Kubernetes certificate authority file path (not needed for in-cluster client)."`
+	Namespace        string `description:"Namespace of the Secret." export:"true"`
+	SecretName       string `description:"Name of the Secret." export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (k *KubernetesStorage) SetDefaults() {
+	k.Namespace = "default"
+	k.SecretName = "traefik-acme"
+}
+
+// secretClient is the subset of the Kubernetes Secrets client used by KubernetesStore.
+type secretClient interface {
+	Get(name string, options metav1.GetOptions) (*corev1.Secret, error)
+	Create(secret *corev1.Secret) (*corev1.Secret, error)
+	Update(secret *corev1.Secret) (*corev1.Secret, error)
+}
+
+// KubernetesStore is an implementation of Store, ChallengeStore and Locker backed by a Kubernetes Secret,
+// which can be shared by several Traefik instances.
+// The data keys of the Secret are:
+// - <resolver name>.account
+// - <resolver name>.certificates (the certificates list is compressed)
+// - http.<token>.<domain>
+// - tls.<domain>
+// The locks are held with annotations of the Secret, renewed until they are released.
+// The challenges are served from a copy of the Secret data kept in memory,
+// as they are looked up during the TLS handshakes.
+type KubernetesStore struct {
+	client     secretClient
+	secretName string
+	identity   string
+	lockTTL    time.Duration
+
+	cacheLock sync.Mutex
+	cache     map[string][]byte
+	cachedAt  time.Time
+}
+
+// NewKubernetesStore initializes a new KubernetesStore, using the Kubernetes client configured as the Kubernetes providers.
+func NewKubernetesStore(storage *KubernetesStorage) (*KubernetesStore, error) {
+	config, err := newKubernetesClientConfig(storage)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return newKubernetesStore(clientset.CoreV1().Secrets(storage.Namespace), storage.SecretName)
+}
+
+func newKubernetesStore(client secretClient, secretName string) (*KubernetesStore, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+
+	return &KubernetesStore{
+		client:     client,
+		secretName: secretName,
+		identity:   hostname + "-" + strconv.FormatInt(time.Now().UnixNano(), 10),
+		lockTTL:    secretLockTTL,
+	}, nil
+}
+
+func newKubernetesClientConfig(storage *KubernetesStorage) (*rest.Config, error) {
+	switch {
+	case os.Getenv("KUBERNETES_SERVICE_HOST") != "" && os.Getenv("KUBERNETES_SERVICE_PORT") != "":
+		config, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create in-cluster configuration: %s", err)
+		}
+
+		if storage.Endpoint != "" {
+			config.Host = storage.Endpoint
+		}
+		return config, nil
+
+	case os.Getenv("KUBECONFIG") != "":
+		return clientcmd.BuildConfigFromFlags("", os.Getenv("KUBECONFIG"))
+
+	default:
+		if storage.Endpoint == "" {
+			return nil, errors.New("endpoint missing for external cluster client")
+		}
+
+		config := &rest.Config{
+			Host:        storage.Endpoint,
+			BearerToken: storage.Token,
+		}
+
+		if storage.CertAuthFilePath != "" {
+			caData, err := ioutil.ReadFile(storage.CertAuthFilePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA file %s: %s", storage.CertAuthFilePath, err)
+			}
+
+			config.TLSClientConfig = rest.TLSClientConfig{CAData: caData}
+		}
+		return config, nil
+	}
+}
+
+func (s *KubernetesStore) get(key string) ([]byte, error) {
+	secret, err := s.client.Get(s.secretName, metav1.GetOptions{})
+	if kubeerror.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return secret.Data[key], nil
+}
+
+// update applies the changes to the Secret, and retries when it has been modified by another instance meanwhile.
+func (s *KubernetesStore) update(apply func(secret *corev1.Secret) error) error {
+	operation := func() error {
+		secret, err := s.client.Get(s.secretName, metav1.GetOptions{})
+		notFound := kubeerror.IsNotFound(err)
+		if err != nil && !notFound {
+			return backoff.Permanent(err)
+		}
+
+		if notFound {
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: s.secretName},
+				Type:       corev1.SecretTypeOpaque,
+			}
+		}
+
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		if secret.Annotations == nil {
+			secret.Annotations = make(map[string]string)
+		}
+
+		if err := apply(secret); err != nil {
+			return backoff.Permanent(err)
+		}
+
+		var updated *corev1.Secret
+		if notFound {
+			updated, err = s.client.Create(secret)
+		} else {
+			updated, err = s.client.Update(secret)
+		}
+
+		if kubeerror.IsConflict(err) || kubeerror.IsAlreadyExists(err) {
+			return err
+		}
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+
+		s.setCache(updated.Data)
+		return nil
+	}
+
+	ebo := backoff.NewExponentialBackOff()
+	ebo.InitialInterval = 50 * time.Millisecond
+	ebo.MaxElapsedTime = secretUpdateTimeout
+
+	return backoff.Retry(operation, ebo)
+}
+
+// getCached returns the value of the key from the copy of the Secret data kept in memory.
+// The Secret is read again when the key is missing and the copy is older than secretCacheRefreshInterval,
+// and the copy is kept when the Secret cannot be read.
+func (s *KubernetesStore) getCached(key string) []byte {
+	s.cacheLock.Lock()
+	defer s.cacheLock.Unlock()
+
+	if value, ok := s.cache[key]; ok || time.Since(s.cachedAt) < secretCacheRefreshInterval {
+		return value
+	}
+
+	s.cachedAt = time.Now()
+
+	secret, err := s.client.Get(s.secretName, metav1.GetOptions{})
+	if kubeerror.IsNotFound(err) {
+		s.cache = nil
+		return nil
+	}
+	if err != nil {
+		log.WithoutContext().Errorf("Unable to read the Secret %s: %v", s.secretName, err)
+		return nil
+	}
+
+	s.cache = secret.Data
+	return s.cache[key]
+}
+
+func (s *KubernetesStore) setCache(data map[string][]byte) {
+	s.cacheLock.Lock()
+	defer s.cacheLock.Unlock()
+
+	s.cache = data
+	s.cachedAt = time.Now()
+}
+
+func (s *KubernetesStore) set(key string, value []byte) error {
+	return s.update(func(secret *corev1.Secret) error {
+		secret.Data[key] = value
+		return nil
+	})
+}
+
+func (s *KubernetesStore) delete(key string) error {
+	return s.update(func(secret *corev1.Secret) error {
+		delete(secret.Data, key)
+		return nil
+	})
+}
+
+// GetAccount returns ACME Account
+func (s *KubernetesStore) GetAccount(resolverName string) (*Account, error) {
+	data, err := s.get(resolverName + ".account")
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+
+	account := &Account{}
+	if err := json.Unmarshal(data, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// SaveAccount stores ACME Account
+func (s *KubernetesStore) SaveAccount(resolverName string, account *Account) error {
+	data, err := json.Marshal(account)
+	if err != nil {
+		return err
+	}
+
+	return s.set(resolverName+".account", data)
+}
+
+// GetCertificates returns ACME Certificates list
+func (s *KubernetesStore) GetCertificates(resolverName string) ([]*Certificate, error) {
+	data, err := s.get(resolverName + ".certificates")
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err = ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var certificates []*Certificate
+	if err := json.Unmarshal(data, &certificates); err != nil {
+		return nil, err
+	}
+	return certificates, nil
+}
+
+// SaveCertificates stores ACME Certificates list
+func (s *KubernetesStore) SaveCertificates(resolverName string, certificates []*Certificate) error {
+	data, err := json.Marshal(certificates)
+	if err != nil {
+		return err
+	}
+
+	// Because the size of a Secret is limited to 1MiB, the certificates list is compressed.
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return s.set(resolverName+".certificates", buffer.Bytes())
+}
+
+// GetHTTPChallengeToken Get the http challenge token from the store
+func (s *KubernetesStore) GetHTTPChallengeToken(token, domain string) ([]byte, error) {
+	data := s.getCached("http." + token + "." + domain)
+	if data == nil {
+		return nil, fmt.Errorf("cannot find challenge for token %v", token)
+	}
+	return data, nil
+}
+
+// SetHTTPChallengeToken Set the http challenge token in the store
+func (s *KubernetesStore) SetHTTPChallengeToken(token, domain string, keyAuth []byte) error {
+	return s.set("http."+token+"."+domain, keyAuth)
+}
+
+// RemoveHTTPChallengeToken Remove the http challenge token in the store
+func (s *KubernetesStore) RemoveHTTPChallengeToken(token, domain string) error {
+	return s.delete("http." + token + "." + domain)
+}
+
+// AddTLSChallenge Add a certificate to the ACME TLS-ALPN-01 certificates storage
+func (s *KubernetesStore) AddTLSChallenge(domain string, cert *Certificate) error {
+	data, err := json.Marshal(cert)
+	if err != nil {
+		return err
+	}
+
+	return s.set("tls."+domain, data)
+}
+
+// GetTLSChallenge Get a certificate from the ACME TLS-ALPN-01 certificates storage
+func (s *KubernetesStore) GetTLSChallenge(domain string) (*Certificate, error) {
+	data := s.getCached("tls." + domain)
+	if data == nil {
+		return nil, nil
+	}
+
+	cert := &Certificate{}
+	if err := json.Unmarshal(data, cert); err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+// RemoveTLSChallenge Remove a certificate from the ACME TLS-ALPN-01 certificates storage
+func (s *KubernetesStore) RemoveTLSChallenge(domain string) error {
+	return s.delete("tls." + domain)
+}
+
+// Lock acquires the lock identified by the key, held with an annotation of the Secret.
+// The annotation value is the identity of the instance holding the lock, and the expiration date of the lock,
+// which is postponed in the background until the lock is released.
+func (s *KubernetesStore) Lock(ctx context.Context, key string) (func() error, error) {
+	annotation := secretLockAnnotation(key)
+
+	operation := func() error {
+		err := s.update(func(secret *corev1.Secret) error {
+			if holder, expiration := parseSecretLock(secret.Annotations[annotation]); holder != "" && time.Now().Before(expiration) {
+				return errLockHeld
+			}
+
+			secret.Annotations[annotation] = s.lockValue()
+			return nil
+		})
+
+		if err != nil && err != errLockHeld {
+			return backoff.Permanent(err)
+		}
+		return err
+	}
+
+	notify := func(err error, d time.Duration) {
+		log.WithoutContext().Debugf("Waiting for the lock %q held by another instance, retrying in %s.", key, d)
+	}
+
+	ebo := backoff.NewExponentialBackOff()
+	ebo.InitialInterval = secretLockRetryInterval
+	ebo.MaxInterval = 10 * secretLockRetryInterval
+	ebo.MaxElapsedTime = 0
+
+	if err := backoff.RetryNotify(operation, backoff.WithContext(ebo, ctx), notify); err != nil {
+		return nil, err
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	safe.Go(func() {
+		defer close(done)
+		s.renewLock(key, annotation, stop)
+	})
+
+	return func() error {
+		close(stop)
+		<-done
+
+		return s.update(func(secret *corev1.Secret) error {
+			if holder, _ := parseSecretLock(secret.Annotations[annotation]); holder == s.identity {
+				delete(secret.Annotations, annotation)
+			}
+			return nil
+		})
+	}, nil
+}
+
+// renewLock postpones the expiration of the lock until stop is closed, or the lock is lost.
+func (s *KubernetesStore) renewLock(key, annotation string, stop <-chan struct{}) {
+	ticker := time.NewTicker(s.lockTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		err := s.update(func(secret *corev1.Secret) error {
+			if holder, _ := parseSecretLock(secret.Annotations[annotation]); holder != s.identity {
+				return errLockLost
+			}
+
+			secret.Annotations[annotation] = s.lockValue()
+			return nil
+		})
+
+		if err == errLockLost {
+			log.WithoutContext().Errorf("The lock %q has been acquired by another instance.", key)
+			return
+		}
+		if err != nil {
+			log.WithoutContext().Errorf("Unable to renew the lock %q: %v", key, err)
+		}
+	}
+}
+
+func (s *KubernetesStore) lockValue() string {
+	return s.identity + " " + time.Now().Add(s.lockTTL).UTC().Format(time.RFC3339)
+}
+
+// secretLockAnnotation returns the annotation of the lock, the key being hashed to be a valid annotation name.
+func secretLockAnnotation(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return secretLockAnnotationPrefix + hex.EncodeToString(hash[:])[:32]
+}
+
+func parseSecretLock(value string) (string, time.Time) {
+	parts := strings.SplitN(value, " ", 2)
+	if len(parts) != 2 {
+		return "", time.Time{}
+	}
+
+	expiration, err := time.Parse(time.RFC3339, parts[1])
+	if err != nil {
+		return "", time.Time{}
+	}
+
+	return parts[0], expiration
+}
@@ -0,0 +1,315 @@
+package acme
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kubeerror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var secretsResource = schema.GroupResource{Resource: "secrets"}
+
+// fakeSecretClient checks the resource version of the updated secrets, as the API server does.
+type fakeSecretClient struct {
+	lock    sync.Mutex
+	secrets map[string]*corev1.Secret
+	version int
+	gets    int
+}
+
+func newFakeSecretClient() *fakeSecretClient {
+	return &fakeSecretClient{secrets: make(map[string]*corev1.Secret)}
+}
+
+func (f *fakeSecretClient) Get(name string, options metav1.GetOptions) (*corev1.Secret, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.gets++
+	secret, ok := f.secrets[name]
+	if !ok {
+		return nil, kubeerror.NewNotFound(secretsResource, name)
+	}
+	return secret.DeepCopy(), nil
+}
+
+func (f *fakeSecretClient) Create(secret *corev1.Secret) (*corev1.Secret, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if _, ok := f.secrets[secret.Name]; ok {
+		return nil, kubeerror.NewAlreadyExists(secretsResource, secret.Name)
+	}
+	return f.save(secret), nil
+}
+
+func (f *fakeSecretClient) Update(secret *corev1.Secret) (*corev1.Secret, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	current, ok := f.secrets[secret.Name]
+	if !ok {
+		return nil, kubeerror.NewNotFound(secretsResource, secret.Name)
+	}
+	if current.ResourceVersion != secret.ResourceVersion {
+		return nil, kubeerror.NewConflict(secretsResource, secret.Name, nil)
+	}
+	return f.save(secret), nil
+}
+
+func (f *fakeSecretClient) save(secret *corev1.Secret) *corev1.Secret {
+	f.version++
+	secret = secret.DeepCopy()
+	secret.ResourceVersion = strconv.Itoa(f.version)
+	f.secrets[secret.Name] = secret
+	return secret.DeepCopy()
+}
+
+func TestKubernetesStore(t *testing.T) {
+	client := newFakeSecretClient()
+	s, err := newKubernetesStore(client, "traefik-acme")
+	require.NoError(t, err)
+
+	account, err := s.GetAccount("foo")
+	require.NoError(t, err)
+	assert.Nil(t, account)
+
+	err = s.SaveAccount("foo", &Account{Email: "foo@example.com"})
+	require.NoError(t, err)
+
+	account, err = s.GetAccount("foo")
+	require.NoError(t, err)
+	require.NotNil(t, account)
+	assert.Equal(t, "foo@example.com", account.Email)
+
+	certificates := []*Certificate{{Domain: types.Domain{Main: "foo.com"}, Certificate: []byte("cert"), Key: []byte("key")}}
+	err = s.SaveCertificates("foo", certificates)
+	require.NoError(t, err)
+
+	storedCertificates, err := s.GetCertificates("foo")
+	require.NoError(t, err)
+	assert.Equal(t, certificates, storedCertificates)
+
+	storedCertificates, err = s.GetCertificates("bar")
+	require.NoError(t, err)
+	assert.Empty(t, storedCertificates)
+
+	secret, err := client.Get("traefik-acme", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, corev1.SecretTypeOpaque, secret.Type)
+	assert.Contains(t, secret.Data, "foo.account")
+	// The certificates list is compressed with gzip.
+	assert.Equal(t, []byte{0x1f, 0x8b}, secret.Data["foo.certificates"][:2])
+}
+
+func TestKubernetesStore_challenges(t *testing.T) {
+	s, err := newKubernetesStore(newFakeSecretClient(), "traefik-acme")
+	require.NoError(t, err)
+
+	_, err = s.GetHTTPChallengeToken("token", "foo.com")
+	require.Error(t, err)
+
+	err = s.SetHTTPChallengeToken("token", "foo.com", []byte("keyAuth"))
+	require.NoError(t, err)
+
+	keyAuth, err := s.GetHTTPChallengeToken("token", "foo.com")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("keyAuth"), keyAuth)
+
+	err = s.RemoveHTTPChallengeToken("token", "foo.com")
+	require.NoError(t, err)
+
+	_, err = s.GetHTTPChallengeToken("token", "foo.com")
+	require.Error(t, err)
+
+	err = s.AddTLSChallenge("foo.com", &Certificate{Domain: types.Domain{Main: "foo.com"}, Certificate: []byte("cert"), Key: []byte("key")})
+	require.NoError(t, err)
+
+	cert, err := s.GetTLSChallenge("foo.com")
+	require.NoError(t, err)
+	require.NotNil(t, cert)
+	assert.Equal(t, []byte("cert"), cert.Certificate)
+
+	err = s.RemoveTLSChallenge("foo.com")
+	require.NoError(t, err)
+
+	cert, err = s.GetTLSChallenge("foo.com")
+	require.NoError(t, err)
+	assert.Nil(t, cert)
+}
+
+func TestKubernetesStore_challengesCache(t *testing.T) {
+	client := newFakeSecretClient()
+
+	s, err := newKubernetesStore(client, "traefik-acme")
+	require.NoError(t, err)
+
+	other, err := newKubernetesStore(client, "traefik-acme")
+	require.NoError(t, err)
+
+	// The challenges added by another instance are read from the Secret.
+	err = other.AddTLSChallenge("foo.com", &Certificate{Domain: types.Domain{Main: "foo.com"}, Certificate: []byte("cert"), Key: []byte("key")})
+	require.NoError(t, err)
+
+	cert, err := s.GetTLSChallenge("foo.com")
+	require.NoError(t, err)
+	require.NotNil(t, cert)
+	assert.Equal(t, []byte("cert"), cert.Certificate)
+
+	// The challenges are then served from memory.
+	gets := client.gets
+	for i := 0; i < 10; i++ {
+		cert, err = s.GetTLSChallenge("foo.com")
+		require.NoError(t, err)
+		assert.NotNil(t, cert)
+
+		cert, err = s.GetTLSChallenge("bar.com")
+		require.NoError(t, err)
+		assert.Nil(t, cert)
+	}
+	assert.Equal(t, gets, client.gets)
+}
+
+func TestKubernetesStore_concurrentUpdates(t *testing.T) {
+	client := newFakeSecretClient()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		s, err := newKubernetesStore(client, "traefik-acme")
+		require.NoError(t, err)
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, s.SetHTTPChallengeToken("token"+strconv.Itoa(i), "foo.com", []byte("keyAuth")))
+		}(i)
+	}
+	wg.Wait()
+
+	secret, err := client.Get("traefik-acme", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Len(t, secret.Data, 10)
+}
+
+func TestKubernetesStore_Lock(t *testing.T) {
+	client := newFakeSecretClient()
+
+	s, err := newKubernetesStore(client, "traefik-acme")
+	require.NoError(t, err)
+
+	testLocker(t, s)
+
+	secret, err := client.Get("traefik-acme", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, secret.Annotations)
+}
+
+func TestKubernetesStore_Lock_renewed(t *testing.T) {
+	client := newFakeSecretClient()
+
+	s, err := newKubernetesStore(client, "traefik-acme")
+	require.NoError(t, err)
+	s.lockTTL = 3 * time.Second
+
+	unlock, err := s.Lock(context.Background(), "foo/domains/foo.com")
+	require.NoError(t, err)
+
+	secret, err := client.Get("traefik-acme", metav1.GetOptions{})
+	require.NoError(t, err)
+	_, expiration := parseSecretLock(secret.Annotations[secretLockAnnotation("foo/domains/foo.com")])
+
+	time.Sleep(2500 * time.Millisecond)
+
+	secret, err = client.Get("traefik-acme", metav1.GetOptions{})
+	require.NoError(t, err)
+	holder, renewed := parseSecretLock(secret.Annotations[secretLockAnnotation("foo/domains/foo.com")])
+	assert.Equal(t, s.identity, holder)
+	assert.True(t, renewed.After(expiration))
+
+	require.NoError(t, unlock())
+}
+
+func TestKubernetesStore_Lock_expired(t *testing.T) {
+	client := newFakeSecretClient()
+
+	s, err := newKubernetesStore(client, "traefik-acme")
+	require.NoError(t, err)
+
+	// The lock of a stopped instance is released once expired.
+	err = s.update(func(secret *corev1.Secret) error {
+		secret.Annotations[secretLockAnnotation("foo/domains/foo.com")] = "stopped " + time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+		return nil
+	})
+	require.NoError(t, err)
+
+	unlock, err := s.Lock(context.Background(), "foo/domains/foo.com")
+	require.NoError(t, err)
+	require.NoError(t, unlock())
+}
+
+// testLocker checks that a lock can only be held by one caller at a time.
+func testLocker(t *testing.T, locker Locker) {
+	t.Helper()
+
+	unlock, err := locker.Lock(context.Background(), "foo/domains/foo.com")
+	require.NoError(t, err)
+
+	// Another lock can be acquired.
+	unlockBar, err := locker.Lock(context.Background(), "foo/domains/bar.com")
+	require.NoError(t, err)
+	require.NoError(t, unlockBar())
+
+	var events []string
+	var eventsLock sync.Mutex
+	addEvent := func(event string) {
+		eventsLock.Lock()
+		defer eventsLock.Unlock()
+		events = append(events, event)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		unlockOther, errLock := locker.Lock(context.Background(), "foo/domains/foo.com")
+		if errLock != nil {
+			addEvent(errLock.Error())
+			return
+		}
+		addEvent("locked")
+		_ = unlockOther()
+		addEvent("unlocked")
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	addEvent("released")
+	require.NoError(t, unlock())
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the lock has not been acquired after being released")
+	}
+
+	assert.Equal(t, []string{"released", "locked", "unlocked"}, events)
+
+	// The attempt to acquire a held lock stops when the context is done.
+	unlock, err = locker.Lock(context.Background(), "foo/domains/foo.com")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = locker.Lock(ctx, "foo/domains/foo.com")
+	require.Error(t, err)
+	require.NoError(t, unlock())
+}
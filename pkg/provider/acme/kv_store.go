@@ -0,0 +1,208 @@
+package acme
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/abronan/valkeyrie/store"
+	"github.com/containous/traefik/pkg/safe"
+)
+
+// kvLockTTL is the duration after which the lock of an instance which stopped is released.
+const kvLockTTL = 5 * time.Minute
+
+var (
+	_ Store          = (*KVStore)(nil)
+	_ ChallengeStore = (*KVStore)(nil)
+	_ Locker         = (*KVStore)(nil)
+)
+
+// KVStore is an implementation of Store, ChallengeStore and Locker backed by a key-value store,
+// which can be shared by several Traefik instances.
+// As no key-value store client is built in, it is used by passing the client of the key-value store to NewKVStore.
+// The keys are:
+// - <prefix>/<resolver name>/account
+// - <prefix>/<resolver name>/certificates (the certificates list is compressed)
+// - <prefix>/challenges/http/<token>/<domain>
+// - <prefix>/challenges/tls/<domain>
+// - <prefix>/locks/<resolver name>/<lock key>
+type KVStore struct {
+	kv     store.Store
+	prefix string
+}
+
+// NewKVStore initializes a new KVStore, storing the ACME data under the prefix.
+func NewKVStore(kv store.Store, prefix string) *KVStore {
+	return &KVStore{kv: kv, prefix: prefix}
+}
+
+func (s *KVStore) get(key string) ([]byte, error) {
+	pair, err := s.kv.Get(s.prefix+"/"+key, &store.ReadOptions{Consistent: true})
+	if err == store.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pair.Value, nil
+}
+
+// GetAccount returns ACME Account
+func (s *KVStore) GetAccount(resolverName string) (*Account, error) {
+	data, err := s.get(resolverName + "/account")
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+
+	account := &Account{}
+	if err := json.Unmarshal(data, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// SaveAccount stores ACME Account
+func (s *KVStore) SaveAccount(resolverName string, account *Account) error {
+	data, err := json.Marshal(account)
+	if err != nil {
+		return err
+	}
+
+	return s.kv.Put(s.prefix+"/"+resolverName+"/account", data, nil)
+}
+
+// GetCertificates returns ACME Certificates list
+func (s *KVStore) GetCertificates(resolverName string) ([]*Certificate, error) {
+	data, err := s.get(resolverName + "/certificates")
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err = ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var certificates []*Certificate
+	if err := json.Unmarshal(data, &certificates); err != nil {
+		return nil, err
+	}
+	return certificates, nil
+}
+
+// SaveCertificates stores ACME Certificates list
+func (s *KVStore) SaveCertificates(resolverName string, certificates []*Certificate) error {
+	data, err := json.Marshal(certificates)
+	if err != nil {
+		return err
+	}
+
+	// Because key-value stores have limited entry size, the certificates list is compressed.
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return s.kv.Put(s.prefix+"/"+resolverName+"/certificates", buffer.Bytes(), nil)
+}
+
+// GetHTTPChallengeToken Get the http challenge token from the store
+func (s *KVStore) GetHTTPChallengeToken(token, domain string) ([]byte, error) {
+	data, err := s.get("challenges/http/" + token + "/" + domain)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, fmt.Errorf("cannot find challenge for token %v", token)
+	}
+	return data, nil
+}
+
+// SetHTTPChallengeToken Set the http challenge token in the store
+func (s *KVStore) SetHTTPChallengeToken(token, domain string, keyAuth []byte) error {
+	return s.kv.Put(s.prefix+"/challenges/http/"+token+"/"+domain, keyAuth, nil)
+}
+
+// RemoveHTTPChallengeToken Remove the http challenge token in the store
+func (s *KVStore) RemoveHTTPChallengeToken(token, domain string) error {
+	err := s.kv.Delete(s.prefix + "/challenges/http/" + token + "/" + domain)
+	if err == store.ErrKeyNotFound {
+		return nil
+	}
+	return err
+}
+
+// AddTLSChallenge Add a certificate to the ACME TLS-ALPN-01 certificates storage
+func (s *KVStore) AddTLSChallenge(domain string, cert *Certificate) error {
+	data, err := json.Marshal(cert)
+	if err != nil {
+		return err
+	}
+
+	return s.kv.Put(s.prefix+"/challenges/tls/"+domain, data, nil)
+}
+
+// GetTLSChallenge Get a certificate from the ACME TLS-ALPN-01 certificates storage
+func (s *KVStore) GetTLSChallenge(domain string) (*Certificate, error) {
+	data, err := s.get("challenges/tls/" + domain)
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	cert := &Certificate{}
+	if err := json.Unmarshal(data, cert); err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+// RemoveTLSChallenge Remove a certificate from the ACME TLS-ALPN-01 certificates storage
+func (s *KVStore) RemoveTLSChallenge(domain string) error {
+	err := s.kv.Delete(s.prefix + "/challenges/tls/" + domain)
+	if err == store.ErrKeyNotFound {
+		return nil
+	}
+	return err
+}
+
+// Lock acquires the lock of the key-value store identified by the key.
+func (s *KVStore) Lock(ctx context.Context, key string) (func() error, error) {
+	locker, err := s.kv.NewLock(s.prefix+"/locks/"+key, &store.LockOptions{TTL: kvLockTTL})
+	if err != nil {
+		return nil, err
+	}
+
+	// The attempt to acquire the lock is stopped when the context is done.
+	stopChan := make(chan struct{})
+	acquired := make(chan struct{})
+	defer close(acquired)
+
+	safe.Go(func() {
+		select {
+		case <-ctx.Done():
+			close(stopChan)
+		case <-acquired:
+		}
+	})
+
+	if _, err := locker.Lock(stopChan); err != nil {
+		return nil, err
+	}
+
+	return locker.Unlock, nil
+}
@@ -0,0 +1,161 @@
+package acme
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/abronan/valkeyrie/store"
+	"github.com/containous/traefik/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeKV struct {
+	store.Store
+	lock  sync.Mutex
+	pairs map[string][]byte
+	locks map[string]chan struct{}
+}
+
+func newFakeKV() *fakeKV {
+	return &fakeKV{
+		pairs: make(map[string][]byte),
+		locks: make(map[string]chan struct{}),
+	}
+}
+
+func (f *fakeKV) Put(key string, value []byte, options *store.WriteOptions) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.pairs[key] = value
+	return nil
+}
+
+func (f *fakeKV) Get(key string, options *store.ReadOptions) (*store.KVPair, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	value, ok := f.pairs[key]
+	if !ok {
+		return nil, store.ErrKeyNotFound
+	}
+	return &store.KVPair{Key: key, Value: value}, nil
+}
+
+func (f *fakeKV) Delete(key string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if _, ok := f.pairs[key]; !ok {
+		return store.ErrKeyNotFound
+	}
+	delete(f.pairs, key)
+	return nil
+}
+
+func (f *fakeKV) NewLock(key string, options *store.LockOptions) (store.Locker, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.locks[key] == nil {
+		f.locks[key] = make(chan struct{}, 1)
+	}
+	return &fakeKVLocker{held: f.locks[key]}, nil
+}
+
+type fakeKVLocker struct {
+	held chan struct{}
+}
+
+func (l *fakeKVLocker) Lock(stopChan chan struct{}) (<-chan struct{}, error) {
+	select {
+	case l.held <- struct{}{}:
+		return make(chan struct{}), nil
+	case <-stopChan:
+		return nil, store.ErrCannotLock
+	}
+}
+
+func (l *fakeKVLocker) Unlock() error {
+	<-l.held
+	return nil
+}
+
+func TestKVStore(t *testing.T) {
+	kv := newFakeKV()
+	s := NewKVStore(kv, "traefik/acme")
+
+	account, err := s.GetAccount("foo")
+	require.NoError(t, err)
+	assert.Nil(t, account)
+
+	err = s.SaveAccount("foo", &Account{Email: "foo@example.com"})
+	require.NoError(t, err)
+
+	account, err = s.GetAccount("foo")
+	require.NoError(t, err)
+	require.NotNil(t, account)
+	assert.Equal(t, "foo@example.com", account.Email)
+
+	account, err = s.GetAccount("bar")
+	require.NoError(t, err)
+	assert.Nil(t, account)
+
+	certificates := []*Certificate{{Domain: types.Domain{Main: "foo.com"}, Certificate: []byte("cert"), Key: []byte("key")}}
+	err = s.SaveCertificates("foo", certificates)
+	require.NoError(t, err)
+
+	// The certificates list is compressed with gzip.
+	assert.Equal(t, []byte{0x1f, 0x8b}, kv.pairs["traefik/acme/foo/certificates"][:2])
+
+	storedCertificates, err := s.GetCertificates("foo")
+	require.NoError(t, err)
+	assert.Equal(t, certificates, storedCertificates)
+
+	storedCertificates, err = s.GetCertificates("bar")
+	require.NoError(t, err)
+	assert.Empty(t, storedCertificates)
+}
+
+func TestKVStore_challenges(t *testing.T) {
+	s := NewKVStore(newFakeKV(), "traefik/acme")
+
+	_, err := s.GetHTTPChallengeToken("token", "foo.com")
+	require.Error(t, err)
+
+	err = s.SetHTTPChallengeToken("token", "foo.com", []byte("keyAuth"))
+	require.NoError(t, err)
+
+	keyAuth, err := s.GetHTTPChallengeToken("token", "foo.com")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("keyAuth"), keyAuth)
+
+	err = s.RemoveHTTPChallengeToken("token", "foo.com")
+	require.NoError(t, err)
+
+	_, err = s.GetHTTPChallengeToken("token", "foo.com")
+	require.Error(t, err)
+
+	cert, err := s.GetTLSChallenge("foo.com")
+	require.NoError(t, err)
+	assert.Nil(t, cert)
+
+	err = s.AddTLSChallenge("foo.com", &Certificate{Domain: types.Domain{Main: "foo.com"}, Certificate: []byte("cert"), Key: []byte("key")})
+	require.NoError(t, err)
+
+	cert, err = s.GetTLSChallenge("foo.com")
+	require.NoError(t, err)
+	require.NotNil(t, cert)
+	assert.Equal(t, []byte("cert"), cert.Certificate)
+
+	err = s.RemoveTLSChallenge("foo.com")
+	require.NoError(t, err)
+
+	err = s.RemoveTLSChallenge("foo.com")
+	require.NoError(t, err)
+}
+
+func TestKVStore_Lock(t *testing.T) {
+	testLocker(t, NewKVStore(newFakeKV(), "traefik/acme"))
+}
@@ -0,0 +1,92 @@
+package acme
+
+import (
+	"fmt"
+	"sync"
+)
+
+var _ ChallengeStore = (*LocalChallengeStore)(nil)
+
+// LocalChallengeStore is an in-memory implementation of ChallengeStore.
+type LocalChallengeStore struct {
+	httpChallenges map[string]map[string][]byte
+	tlsChallenges  map[string]*Certificate
+	lock           sync.RWMutex
+}
+
+// NewLocalChallengeStore initializes a new LocalChallengeStore.
+func NewLocalChallengeStore() *LocalChallengeStore {
+	return &LocalChallengeStore{
+		httpChallenges: make(map[string]map[string][]byte),
+		tlsChallenges:  make(map[string]*Certificate),
+	}
+}
+
+// GetHTTPChallengeToken Get the http challenge token from the store
+func (s *LocalChallengeStore) GetHTTPChallengeToken(token, domain string) ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if _, ok := s.httpChallenges[token]; !ok {
+		return nil, fmt.Errorf("cannot find challenge for token %v", token)
+	}
+
+	result, ok := s.httpChallenges[token][domain]
+	if !ok {
+		return nil, fmt.Errorf("cannot find challenge for token %v", token)
+	}
+	return result, nil
+}
+
+// SetHTTPChallengeToken Set the http challenge token in the store
+func (s *LocalChallengeStore) SetHTTPChallengeToken(token, domain string, keyAuth []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.httpChallenges[token]; !ok {
+		s.httpChallenges[token] = map[string][]byte{}
+	}
+
+	s.httpChallenges[token][domain] = keyAuth
+	return nil
+}
+
+// RemoveHTTPChallengeToken Remove the http challenge token in the store
+func (s *LocalChallengeStore) RemoveHTTPChallengeToken(token, domain string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.httpChallenges[token]; ok {
+		delete(s.httpChallenges[token], domain)
+		if len(s.httpChallenges[token]) == 0 {
+			delete(s.httpChallenges, token)
+		}
+	}
+	return nil
+}
+
+// AddTLSChallenge Add a certificate to the ACME TLS-ALPN-01 certificates storage
+func (s *LocalChallengeStore) AddTLSChallenge(domain string, cert *Certificate) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.tlsChallenges[domain] = cert
+	return nil
+}
+
+// GetTLSChallenge Get a certificate from the ACME TLS-ALPN-01 certificates storage
+func (s *LocalChallengeStore) GetTLSChallenge(domain string) (*Certificate, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.tlsChallenges[domain], nil
+}
+
+// RemoveTLSChallenge Remove a certificate from the ACME TLS-ALPN-01 certificates storage
+func (s *LocalChallengeStore) RemoveTLSChallenge(domain string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.tlsChallenges, domain)
+	return nil
+}
@@ -2,7 +2,6 @@ package acme
 
 import (
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
 	"os"
 	"regexp"
@@ -17,24 +16,24 @@ var _ Store = (*LocalStore)(nil)
 // LocalStore Stores implementation for local file
 type LocalStore struct {
 	filename     string
-	storedData   *StoredData
-	SaveDataChan chan *StoredData `json:"-"`
+	storedData   map[string]*StoredData
+	saveDataChan chan []byte
 	lock         sync.RWMutex
+	// legacyData holds the data of a file written by a single ACME configuration,
+	// it is used to initialize the data of every certificates resolver using the file.
+	legacyData *StoredData
 }
 
 // NewLocalStore initializes a new LocalStore with a file name
 func NewLocalStore(filename string) *LocalStore {
-	store := &LocalStore{filename: filename, SaveDataChan: make(chan *StoredData)}
+	store := &LocalStore{filename: filename, saveDataChan: make(chan []byte)}
 	store.listenSaveAction()
 	return store
 }
 
-func (s *LocalStore) get() (*StoredData, error) {
+func (s *LocalStore) get(resolverName string) (*StoredData, error) {
 	if s.storedData == nil {
-		s.storedData = &StoredData{
-			HTTPChallenges: make(map[string]map[string][]byte),
-			TLSChallenges:  make(map[string]*Certificate),
-		}
+		s.storedData = make(map[string]*StoredData)
 
 		hasData, err := CheckFile(s.filename)
 		if err != nil {
@@ -42,8 +41,6 @@ func (s *LocalStore) get() (*StoredData, error) {
 		}
 
 		if hasData {
-			logger := log.WithoutContext().WithField(log.ProviderName, "acme")
-
 			f, err := os.Open(s.filename)
 			if err != nil {
 				return nil, err
@@ -56,197 +53,165 @@ func (s *LocalStore) get() (*StoredData, error) {
 			}
 
 			if len(file) > 0 {
-				if err := json.Unmarshal(file, s.storedData); err != nil {
+				if err := s.unmarshal(file); err != nil {
 					return nil, err
 				}
 			}
-
-			// Check if ACME Account is in ACME V1 format
-			if s.storedData.Account != nil && s.storedData.Account.Registration != nil {
-				isOldRegistration, err := regexp.MatchString(RegistrationURLPathV1Regexp, s.storedData.Account.Registration.URI)
-				if err != nil {
-					return nil, err
-				}
-				if isOldRegistration {
-					logger.Debug("Reseting ACME account.")
-					s.storedData.Account = nil
-					s.SaveDataChan <- s.storedData
-				}
-			}
-
-			// Delete all certificates with no value
-			var certificates []*Certificate
-			for _, certificate := range s.storedData.Certificates {
-				if len(certificate.Certificate) == 0 || len(certificate.Key) == 0 {
-					logger.Debugf("Deleting empty certificate %v for %v", certificate, certificate.Domain.ToStrArray())
-					continue
-				}
-				certificates = append(certificates, certificate)
-			}
-
-			if len(certificates) < len(s.storedData.Certificates) {
-				s.storedData.Certificates = certificates
-				s.SaveDataChan <- s.storedData
-			}
 		}
 	}
 
-	return s.storedData, nil
-}
+	if s.storedData[resolverName] == nil {
+		storedData := &StoredData{}
+		if s.legacyData != nil {
+			log.WithoutContext().WithField(log.ProviderName, resolverName+".acme").
+				Infof("Using the ACME account and certificates of %s for the certificates resolver %q.", s.filename, resolverName)
 
-// listenSaveAction listens to a chan to store ACME data in json format into LocalStore.filename
-func (s *LocalStore) listenSaveAction() {
-	safe.Go(func() {
-		logger := log.WithoutContext().WithField(log.ProviderName, "acme")
-		for object := range s.SaveDataChan {
-			data, err := json.MarshalIndent(object, "", "  ")
-			if err != nil {
-				logger.Error(err)
+			// The resolvers must not share the same account and certificates instances.
+			if s.legacyData.Account != nil {
+				account := *s.legacyData.Account
+				storedData.Account = &account
 			}
-
-			err = ioutil.WriteFile(s.filename, data, 0600)
-			if err != nil {
-				logger.Error(err)
+			for _, legacyCertificate := range s.legacyData.Certificates {
+				certificate := *legacyCertificate
+				storedData.Certificates = append(storedData.Certificates, &certificate)
 			}
 		}
-	})
-}
 
-// GetAccount returns ACME Account
-func (s *LocalStore) GetAccount() (*Account, error) {
-	storedData, err := s.get()
-	if err != nil {
-		return nil, err
+		if err := s.cleanStoredData(resolverName, storedData); err != nil {
+			return nil, err
+		}
+
+		s.storedData[resolverName] = storedData
 	}
 
-	return storedData.Account, nil
+	return s.storedData[resolverName], nil
 }
 
-// SaveAccount stores ACME Account
-func (s *LocalStore) SaveAccount(account *Account) error {
-	storedData, err := s.get()
-	if err != nil {
+// unmarshal reads the content of the file, which is a map of StoredData by resolver name,
+// or the StoredData of a single ACME configuration.
+func (s *LocalStore) unmarshal(file []byte) error {
+	var content map[string]json.RawMessage
+	if err := json.Unmarshal(file, &content); err != nil {
 		return err
 	}
 
-	storedData.Account = account
-	s.SaveDataChan <- storedData
-
-	return nil
-}
-
-// GetCertificates returns ACME Certificates list
-func (s *LocalStore) GetCertificates() ([]*Certificate, error) {
-	storedData, err := s.get()
-	if err != nil {
-		return nil, err
+	_, hasAccount := content["Account"]
+	_, hasCertificates := content["Certificates"]
+	if hasAccount || hasCertificates {
+		s.legacyData = &StoredData{}
+		return json.Unmarshal(file, s.legacyData)
 	}
 
-	return storedData.Certificates, nil
+	return json.Unmarshal(file, &s.storedData)
 }
 
-// SaveCertificates stores ACME Certificates list
-func (s *LocalStore) SaveCertificates(certificates []*Certificate) error {
-	storedData, err := s.get()
-	if err != nil {
-		return err
+// cleanStoredData resets an ACME V1 account, and deletes the certificates with no value.
+func (s *LocalStore) cleanStoredData(resolverName string, storedData *StoredData) error {
+	logger := log.WithoutContext().WithField(log.ProviderName, resolverName+".acme")
+
+	// Check if ACME Account is in ACME V1 format
+	if storedData.Account != nil && storedData.Account.Registration != nil {
+		isOldRegistration, err := regexp.MatchString(RegistrationURLPathV1Regexp, storedData.Account.Registration.URI)
+		if err != nil {
+			return err
+		}
+		if isOldRegistration {
+			logger.Debug("Reseting ACME account.")
+			storedData.Account = nil
+		}
 	}
 
+	// Delete all certificates with no value
+	var certificates []*Certificate
+	for _, certificate := range storedData.Certificates {
+		if len(certificate.Certificate) == 0 || len(certificate.Key) == 0 {
+			logger.Debugf("Deleting empty certificate %v for %v", certificate, certificate.Domain.ToStrArray())
+			continue
+		}
+		certificates = append(certificates, certificate)
+	}
 	storedData.Certificates = certificates
-	s.SaveDataChan <- storedData
 
 	return nil
 }
 
-// GetHTTPChallengeToken Get the http challenge token from the store
-func (s *LocalStore) GetHTTPChallengeToken(token, domain string) ([]byte, error) {
-	s.lock.RLock()
-	defer s.lock.RUnlock()
-
-	if s.storedData.HTTPChallenges == nil {
-		s.storedData.HTTPChallenges = map[string]map[string][]byte{}
-	}
-
-	if _, ok := s.storedData.HTTPChallenges[token]; !ok {
-		return nil, fmt.Errorf("cannot find challenge for token %v", token)
+// save sends the data of all the certificates resolvers to be written in the file.
+// It must be called with the lock held.
+func (s *LocalStore) save() error {
+	data, err := json.MarshalIndent(s.storedData, "", "  ")
+	if err != nil {
+		return err
 	}
 
-	result, ok := s.storedData.HTTPChallenges[token][domain]
-	if !ok {
-		return nil, fmt.Errorf("cannot find challenge for token %v", token)
-	}
-	return result, nil
+	s.saveDataChan <- data
+	return nil
 }
 
-// SetHTTPChallengeToken Set the http challenge token in the store
-func (s *LocalStore) SetHTTPChallengeToken(token, domain string, keyAuth []byte) error {
-	s.lock.Lock()
-	defer s.lock.Unlock()
-
-	if s.storedData.HTTPChallenges == nil {
-		s.storedData.HTTPChallenges = map[string]map[string][]byte{}
-	}
-
-	if _, ok := s.storedData.HTTPChallenges[token]; !ok {
-		s.storedData.HTTPChallenges[token] = map[string][]byte{}
-	}
-
-	s.storedData.HTTPChallenges[token][domain] = keyAuth
-	return nil
+// listenSaveAction listens to a chan to store ACME data in json format into LocalStore.filename
+func (s *LocalStore) listenSaveAction() {
+	safe.Go(func() {
+		logger := log.WithoutContext().WithField(log.ProviderName, "acme")
+		for data := range s.saveDataChan {
+			err := ioutil.WriteFile(s.filename, data, 0600)
+			if err != nil {
+				logger.Error(err)
+			}
+		}
+	})
 }
 
-// RemoveHTTPChallengeToken Remove the http challenge token in the store
-func (s *LocalStore) RemoveHTTPChallengeToken(token, domain string) error {
+// GetAccount returns ACME Account
+func (s *LocalStore) GetAccount(resolverName string) (*Account, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	if s.storedData.HTTPChallenges == nil {
-		return nil
+	storedData, err := s.get(resolverName)
+	if err != nil {
+		return nil, err
 	}
 
-	if _, ok := s.storedData.HTTPChallenges[token]; ok {
-		delete(s.storedData.HTTPChallenges[token], domain)
-		if len(s.storedData.HTTPChallenges[token]) == 0 {
-			delete(s.storedData.HTTPChallenges, token)
-		}
-	}
-	return nil
+	return storedData.Account, nil
 }
 
-// AddTLSChallenge Add a certificate to the ACME TLS-ALPN-01 certificates storage
-func (s *LocalStore) AddTLSChallenge(domain string, cert *Certificate) error {
+// SaveAccount stores ACME Account
+func (s *LocalStore) SaveAccount(resolverName string, account *Account) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	if s.storedData.TLSChallenges == nil {
-		s.storedData.TLSChallenges = make(map[string]*Certificate)
+	storedData, err := s.get(resolverName)
+	if err != nil {
+		return err
 	}
 
-	s.storedData.TLSChallenges[domain] = cert
-	return nil
+	storedData.Account = account
+
+	return s.save()
 }
 
-// GetTLSChallenge Get a certificate from the ACME TLS-ALPN-01 certificates storage
-func (s *LocalStore) GetTLSChallenge(domain string) (*Certificate, error) {
+// GetCertificates returns ACME Certificates list
+func (s *LocalStore) GetCertificates(resolverName string) ([]*Certificate, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	if s.storedData.TLSChallenges == nil {
-		s.storedData.TLSChallenges = make(map[string]*Certificate)
+	storedData, err := s.get(resolverName)
+	if err != nil {
+		return nil, err
 	}
 
-	return s.storedData.TLSChallenges[domain], nil
+	return storedData.Certificates, nil
 }
 
-// RemoveTLSChallenge Remove a certificate from the ACME TLS-ALPN-01 certificates storage
-func (s *LocalStore) RemoveTLSChallenge(domain string) error {
+// SaveCertificates stores ACME Certificates list
+func (s *LocalStore) SaveCertificates(resolverName string, certificates []*Certificate) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	if s.storedData.TLSChallenges == nil {
-		return nil
+	storedData, err := s.get(resolverName)
+	if err != nil {
+		return err
 	}
 
-	delete(s.storedData.TLSChallenges, domain)
-	return nil
+	storedData.Certificates = certificates
+
+	return s.save()
 }
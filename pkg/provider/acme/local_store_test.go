@@ -0,0 +1,108 @@
+package acme
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containous/traefik/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLocalStore(t *testing.T, content string) *LocalStore {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "traefik_acme")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	filename := filepath.Join(dir, "acme.json")
+	err = ioutil.WriteFile(filename, []byte(content), 0600)
+	require.NoError(t, err)
+
+	return &LocalStore{filename: filename, saveDataChan: make(chan []byte, 1)}
+}
+
+func TestLocalStore_resolvers(t *testing.T) {
+	store := newTestLocalStore(t, `{
+  "foo": {
+    "Account": {"Email": "foo@example.com"},
+    "Certificates": [{"Domain": {"main": "foo.com"}, "Certificate": "Y2VydA==", "Key": "a2V5"}]
+  },
+  "bar": {
+    "Account": {"Email": "bar@example.com"}
+  }
+}`)
+
+	account, err := store.GetAccount("foo")
+	require.NoError(t, err)
+	require.NotNil(t, account)
+	assert.Equal(t, "foo@example.com", account.Email)
+
+	certificates, err := store.GetCertificates("foo")
+	require.NoError(t, err)
+	require.Len(t, certificates, 1)
+	assert.Equal(t, types.Domain{Main: "foo.com"}, certificates[0].Domain)
+
+	account, err = store.GetAccount("bar")
+	require.NoError(t, err)
+	require.NotNil(t, account)
+	assert.Equal(t, "bar@example.com", account.Email)
+
+	certificates, err = store.GetCertificates("bar")
+	require.NoError(t, err)
+	assert.Empty(t, certificates)
+
+	account, err = store.GetAccount("baz")
+	require.NoError(t, err)
+	assert.Nil(t, account)
+
+	err = store.SaveCertificates("baz", []*Certificate{{Domain: types.Domain{Main: "baz.com"}, Certificate: []byte("cert"), Key: []byte("key")}})
+	require.NoError(t, err)
+
+	var saved map[string]*StoredData
+	err = json.Unmarshal(<-store.saveDataChan, &saved)
+	require.NoError(t, err)
+
+	require.Len(t, saved, 3)
+	require.Len(t, saved["foo"].Certificates, 1)
+	assert.Empty(t, saved["bar"].Certificates)
+	require.Len(t, saved["baz"].Certificates, 1)
+	assert.Equal(t, "baz.com", saved["baz"].Certificates[0].Domain.Main)
+}
+
+func TestLocalStore_legacyFormat(t *testing.T) {
+	store := newTestLocalStore(t, `{
+  "Account": {"Email": "foo@example.com"},
+  "Certificates": [
+    {"Domain": {"Main": "foo.com"}, "Certificate": "Y2VydA==", "Key": "a2V5"},
+    {"Domain": {"Main": "empty.com"}}
+  ],
+  "HTTPChallenges": {},
+  "TLSChallenges": {}
+}`)
+
+	for _, resolverName := range []string{"foo", "bar"} {
+		account, err := store.GetAccount(resolverName)
+		require.NoError(t, err)
+		require.NotNil(t, account)
+		assert.Equal(t, "foo@example.com", account.Email)
+
+		certificates, err := store.GetCertificates(resolverName)
+		require.NoError(t, err)
+		require.Len(t, certificates, 1)
+		assert.Equal(t, "foo.com", certificates[0].Domain.Main)
+	}
+
+	fooCertificates, err := store.GetCertificates("foo")
+	require.NoError(t, err)
+	barCertificates, err := store.GetCertificates("bar")
+	require.NoError(t, err)
+
+	// The resolvers must not share the same certificates.
+	fooCertificates[0].Key = []byte("foo")
+	assert.Equal(t, []byte("key"), barCertificates[0].Key)
+}
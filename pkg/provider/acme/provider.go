@@ -4,10 +4,12 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	fmtlog "log"
+	"net/http"
 	"net/url"
 	"reflect"
 	"strings"
@@ -31,9 +33,13 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-var (
-	// oscpMustStaple enables OSCP stapling as from https://github.com/go-acme/lego/issues/270
-	oscpMustStaple = false
+const (
+	// defaultRenewPeriod is the default duration before the expiration of the certificates to renew them.
+	defaultRenewPeriod = 30 * 24 * time.Hour
+	// defaultRenewCheckInterval is the default interval between two checks of the certificates expiration.
+	defaultRenewCheckInterval = 24 * time.Hour
+	// lockTimeout is the maximum duration waiting for a lock held by another instance sharing the store.
+	lockTimeout = 10 * time.Minute
 )
 
 // Configuration holds ACME configuration provided by users
@@ -44,11 +50,18 @@ type Configuration struct {
 	Storage       string         `description:"Storage to use."`
 	EntryPoint    string         `description:"EntryPoint to use."`
 	KeyType       string         `description:"KeyType used for generating certificate private key. Allow value 'EC256', 'EC384', 'RSA2048', 'RSA4096', 'RSA8192'."`
-	OnHostRule    bool           `description:"Enable certificate generation on router Host rules."`
 	DNSChallenge  *DNSChallenge  `description:"Activate DNS-01 Challenge." label:"allowEmpty"`
 	HTTPChallenge *HTTPChallenge `description:"Activate HTTP-01 Challenge." label:"allowEmpty"`
 	TLSChallenge  *TLSChallenge  `description:"Activate TLS-ALPN-01 Challenge." label:"allowEmpty"`
 	Domains       []types.Domain `description:"The list of domains for which certificates are generated on startup. Wildcard domains only accepted with DNSChallenge."`
+
+	KubernetesStorage *KubernetesStorage `description:"Store the ACME data in a Kubernetes Secret shared by the Traefik instances." label:"allowEmpty"`
+
+	EAB                *EAB           `description:"External Account Binding to use for the registration." label:"allowEmpty"`
+	RenewPeriod        types.Duration `description:"Duration before the expiration of the certificates to renew them."`
+	RenewCheckInterval types.Duration `description:"Interval between two checks of the certificates expiration."`
+	PreferredChain     string         `description:"Common Name of the issuer of the top certificate of the preferred chain."`
+	MustStaple         bool           `description:"Request the certificates with the OCSP must-staple extension."`
 }
 
 // SetDefaults sets the default values.
@@ -56,6 +69,14 @@ func (a *Configuration) SetDefaults() {
 	a.CAServer = lego.LEDirectoryProduction
 	a.Storage = "acme.json"
 	a.KeyType = "RSA4096"
+	a.RenewPeriod = types.Duration(defaultRenewPeriod)
+	a.RenewCheckInterval = types.Duration(defaultRenewCheckInterval)
+}
+
+// EAB contains the External Account Binding credentials provided by the CA.
+type EAB struct {
+	KID         string `description:"Key identifier from the CA."`
+	HMACEncoded string `description:"Base64 encoded HMAC key from the CA."`
 }
 
 // Certificate is a struct which contains all data needed from an ACME certificate
@@ -81,14 +102,24 @@ type HTTPChallenge struct {
 // TLSChallenge contains TLS challenge Configuration
 type TLSChallenge struct{}
 
+// certificateUpdate is sent to the certificates watcher, saved is closed once the certificate is stored.
+type certificateUpdate struct {
+	certificate *Certificate
+	saved       chan struct{}
+}
+
 // Provider holds configurations of the provider.
 type Provider struct {
 	*Configuration
-	Store                  Store
+	ResolverName           string
+	Store                  Store          `json:"-"`
+	ChallengeStore         ChallengeStore `json:"-"`
 	certificates           []*Certificate
 	account                *Account
 	client                 *lego.Client
-	certsChan              chan *Certificate
+	httpClient             *http.Client
+	certsChan              chan *certificateUpdate
+	certsWatcherDone       <-chan struct{}
 	configurationChan      chan<- config.Message
 	tlsManager             *traefiktls.Manager
 	clientMutex            sync.Mutex
@@ -115,9 +146,9 @@ func (p *Provider) ListenConfiguration(config config.Configuration) {
 
 // ListenRequest resolves new certificates for a domain from an incoming request and return a valid Certificate to serve (onDemand option)
 func (p *Provider) ListenRequest(domain string) (*tls.Certificate, error) {
-	ctx := log.With(context.Background(), log.Str(log.ProviderName, "acme"))
+	ctx := log.With(context.Background(), log.Str(log.ProviderName, p.ResolverName+".acme"))
 
-	acmeCert, err := p.resolveCertificate(ctx, types.Domain{Main: domain}, false)
+	acmeCert, err := p.resolveCertificate(ctx, types.Domain{Main: domain}, false, true)
 	if acmeCert == nil || err != nil {
 		return nil, err
 	}
@@ -129,8 +160,7 @@ func (p *Provider) ListenRequest(domain string) (*tls.Certificate, error) {
 
 // Init for compatibility reason the BaseProvider implements an empty Init
 func (p *Provider) Init() error {
-
-	ctx := log.With(context.Background(), log.Str(log.ProviderName, "acme"))
+	ctx := log.With(context.Background(), log.Str(log.ProviderName, p.ResolverName+".acme"))
 	logger := log.FromContext(ctx)
 
 	if p.ACMELogging {
@@ -142,10 +172,25 @@ func (p *Provider) Init() error {
 	if len(p.Configuration.Storage) == 0 {
 		return errors.New("unable to initialize ACME provider with no storage location for the certificates")
 	}
-	p.Store = NewLocalStore(p.Configuration.Storage)
+
+	if p.Store == nil {
+		return errors.New("no store found for the ACME provider")
+	}
+
+	if p.ChallengeStore == nil {
+		return errors.New("no challenge store found for the ACME provider")
+	}
+
+	if p.EAB != nil && (len(p.EAB.KID) == 0 || len(p.EAB.HMACEncoded) == 0) {
+		return errors.New("both the key identifier and the HMAC key are required for the External Account Binding")
+	}
+
+	if p.RenewPeriod < 0 || p.RenewCheckInterval < 0 {
+		return errors.New("the renew period and the renew check interval cannot be negative")
+	}
 
 	var err error
-	p.account, err = p.Store.GetAccount()
+	p.account, err = p.Store.GetAccount(p.ResolverName)
 	if err != nil {
 		return fmt.Errorf("unable to get ACME account : %v", err)
 	}
@@ -156,7 +201,7 @@ func (p *Provider) Init() error {
 		p.account = nil
 	}
 
-	p.certificates, err = p.Store.GetCertificates()
+	p.certificates, err = p.Store.GetCertificates(p.ResolverName)
 	if err != nil {
 		return fmt.Errorf("unable to get ACME certificates : %v", err)
 	}
@@ -188,7 +233,7 @@ func isAccountMatchingCaServer(ctx context.Context, accountURI string, serverURI
 // Provide allows the file provider to provide configurations to traefik
 // using the given Configuration channel.
 func (p *Provider) Provide(configurationChan chan<- config.Message, pool *safe.Pool) error {
-	ctx := log.With(context.Background(), log.Str(log.ProviderName, "acme"))
+	ctx := log.With(context.Background(), log.Str(log.ProviderName, p.ResolverName+".acme"))
 
 	p.pool = pool
 
@@ -202,8 +247,8 @@ func (p *Provider) Provide(configurationChan chan<- config.Message, pool *safe.P
 	for i := 0; i < len(p.Domains); i++ {
 		domain := p.Domains[i]
 		safe.Go(func() {
-			if _, err := p.resolveCertificate(ctx, domain, true); err != nil {
-				log.WithoutContext().WithField(log.ProviderName, "acme").
+			if _, err := p.resolveCertificate(ctx, domain, true, false); err != nil {
+				log.WithoutContext().WithField(log.ProviderName, p.ResolverName+".acme").
 					Errorf("Unable to obtain ACME certificate for domains %q : %v", strings.Join(domain.ToStrArray(), ","), err)
 			}
 		})
@@ -211,7 +256,12 @@ func (p *Provider) Provide(configurationChan chan<- config.Message, pool *safe.P
 
 	p.renewCertificates(ctx)
 
-	ticker := time.NewTicker(24 * time.Hour)
+	renewCheckInterval := time.Duration(p.RenewCheckInterval)
+	if renewCheckInterval == 0 {
+		renewCheckInterval = defaultRenewCheckInterval
+	}
+
+	ticker := time.NewTicker(renewCheckInterval)
 	pool.Go(func(stop chan bool) {
 		for {
 			select {
@@ -231,13 +281,24 @@ func (p *Provider) getClient() (*lego.Client, error) {
 	p.clientMutex.Lock()
 	defer p.clientMutex.Unlock()
 
-	ctx := log.With(context.Background(), log.Str(log.ProviderName, "acme"))
+	ctx := log.With(context.Background(), log.Str(log.ProviderName, p.ResolverName+".acme"))
 	logger := log.FromContext(ctx)
 
 	if p.client != nil {
 		return p.client, nil
 	}
 
+	// The instances sharing the store register the account only once.
+	unlock, err := p.lock(ctx, "account")
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err = p.loadStoredAccount(); err != nil {
+		return nil, err
+	}
+
 	account, err := p.initAccount(ctx)
 	if err != nil {
 		return nil, err
@@ -245,10 +306,7 @@ func (p *Provider) getClient() (*lego.Client, error) {
 
 	logger.Debug("Building ACME client...")
 
-	caServer := lego.LEDirectoryProduction
-	if len(p.CAServer) > 0 {
-		caServer = p.CAServer
-	}
+	caServer := p.getCAServer()
 	logger.Debug(caServer)
 
 	config := lego.NewConfig(account)
@@ -260,12 +318,24 @@ func (p *Provider) getClient() (*lego.Client, error) {
 	if err != nil {
 		return nil, err
 	}
+	p.httpClient = config.HTTPClient
 
 	// New users will need to register; be sure to save it
 	if account.GetRegistration() == nil {
 		logger.Info("Register...")
 
-		reg, errR := client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
+		var reg *registration.Resource
+		var errR error
+		if p.EAB != nil {
+			logger.Debugf("Using External Account Binding with the key identifier %s", p.EAB.KID)
+			reg, errR = client.Registration.RegisterWithExternalAccountBinding(registration.RegisterEABOptions{
+				TermsOfServiceAgreed: true,
+				Kid:                  p.EAB.KID,
+				HmacEncoded:          p.EAB.HMACEncoded,
+			})
+		} else {
+			reg, errR = client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
+		}
 		if errR != nil {
 			return nil, errR
 		}
@@ -275,7 +345,7 @@ func (p *Provider) getClient() (*lego.Client, error) {
 
 	// Save the account once before all the certificates generation/storing
 	// No certificate can be generated if account is not initialized
-	err = p.Store.SaveAccount(account)
+	err = p.Store.SaveAccount(p.ResolverName, account)
 	if err != nil {
 		return nil, err
 	}
@@ -308,7 +378,7 @@ func (p *Provider) getClient() (*lego.Client, error) {
 	case p.HTTPChallenge != nil && len(p.HTTPChallenge.EntryPoint) > 0:
 		logger.Debug("Using HTTP Challenge provider.")
 
-		err = client.Challenge.SetHTTP01Provider(&challengeHTTP{Store: p.Store})
+		err = client.Challenge.SetHTTP01Provider(&challengeHTTP{Store: p.ChallengeStore})
 		if err != nil {
 			return nil, err
 		}
@@ -316,7 +386,7 @@ func (p *Provider) getClient() (*lego.Client, error) {
 	case p.TLSChallenge != nil:
 		logger.Debug("Using TLS Challenge provider.")
 
-		err = client.Challenge.SetTLSALPN01Provider(&challengeTLSALPN{Store: p.Store})
+		err = client.Challenge.SetTLSALPN01Provider(&challengeTLSALPN{Store: p.ChallengeStore})
 		if err != nil {
 			return nil, err
 		}
@@ -362,7 +432,7 @@ func (p *Provider) resolveDomains(ctx context.Context, domains []string) {
 		}
 
 		safe.Go(func() {
-			if _, err := p.resolveCertificate(ctx, domain, false); err != nil {
+			if _, err := p.resolveCertificate(ctx, domain, false, true); err != nil {
 				log.FromContext(ctx).Errorf("Unable to obtain ACME certificate for domains %q: %v", strings.Join(domains, ","), err)
 			}
 		})
@@ -376,11 +446,16 @@ func (p *Provider) watchNewDomains(ctx context.Context) {
 			case config := <-p.configFromListenerChan:
 				if config.TCP != nil {
 					for routerName, route := range config.TCP.Routers {
-						if route.TLS == nil {
+						if route.TLS == nil || route.TLS.CertResolver != p.ResolverName {
 							continue
 						}
 						ctxRouter := log.With(ctx, log.Str(log.RouterName, routerName), log.Str(log.Rule, route.Rule))
 
+						if len(route.TLS.Domains) > 0 {
+							p.resolveRouterDomains(ctxRouter, route.TLS.Domains)
+							continue
+						}
+
 						domains, err := rules.ParseHostSNI(route.Rule)
 						if err != nil {
 							log.FromContext(ctxRouter).Errorf("Error parsing domains in provider ACME: %v", err)
@@ -391,11 +466,16 @@ func (p *Provider) watchNewDomains(ctx context.Context) {
 				}
 
 				for routerName, route := range config.HTTP.Routers {
-					if route.TLS == nil {
+					if route.TLS == nil || route.TLS.CertResolver != p.ResolverName {
 						continue
 					}
 					ctxRouter := log.With(ctx, log.Str(log.RouterName, routerName), log.Str(log.Rule, route.Rule))
 
+					if len(route.TLS.Domains) > 0 {
+						p.resolveRouterDomains(ctxRouter, route.TLS.Domains)
+						continue
+					}
+
 					domains, err := rules.ParseDomains(route.Rule)
 					if err != nil {
 						log.FromContext(ctxRouter).Errorf("Error parsing domains in provider ACME: %v", err)
@@ -410,14 +490,27 @@ func (p *Provider) watchNewDomains(ctx context.Context) {
 	})
 }
 
-func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain, domainFromConfigurationFile bool) (*certificate.Resource, error) {
-	domains, err := p.getValidDomains(ctx, domain, domainFromConfigurationFile)
+// resolveRouterDomains obtains the certificates for the domains defined in the TLS configuration of a router.
+// Unlike the domains parsed from the router rule, they can be wildcard domains.
+func (p *Provider) resolveRouterDomains(ctx context.Context, domains []types.Domain) {
+	for _, domain := range domains {
+		domain := domain
+		safe.Go(func() {
+			if _, err := p.resolveCertificate(ctx, domain, true, true); err != nil {
+				log.FromContext(ctx).Errorf("Unable to obtain ACME certificate for domains %q: %v", strings.Join(domain.ToStrArray(), ","), err)
+			}
+		})
+	}
+}
+
+func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain, wildcardAllowed bool, checkConfigurationDomains bool) (*certificate.Resource, error) {
+	domains, err := p.getValidDomains(ctx, domain, wildcardAllowed)
 	if err != nil {
 		return nil, err
 	}
 
 	// Check provided certificates
-	uncheckedDomains := p.getUncheckedDomains(ctx, domains, !domainFromConfigurationFile)
+	uncheckedDomains := p.getUncheckedDomains(ctx, domains, checkConfigurationDomains)
 	if len(uncheckedDomains) == 0 {
 		return nil, nil
 	}
@@ -425,7 +518,27 @@ func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain,
 	p.addResolvingDomains(uncheckedDomains)
 	defer p.removeResolvingDomains(uncheckedDomains)
 
+	unlock, err := p.lock(ctx, "domains/"+strings.Join(uncheckedDomains, ","))
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if len(uncheckedDomains) > 1 {
+		domain = types.Domain{Main: uncheckedDomains[0], SANs: uncheckedDomains[1:]}
+	} else {
+		domain = types.Domain{Main: uncheckedDomains[0]}
+	}
+
 	logger := log.FromContext(ctx)
+
+	// Another instance sharing the store may have obtained the certificate while the lock was held.
+	if storedCert := p.getStoredCertificate(ctx, domain); storedCert != nil {
+		logger.Debugf("Using the certificate obtained by another instance for domains %+v", uncheckedDomains)
+		p.addCertificateForDomain(domain, storedCert.Certificate, storedCert.Key)
+		return &certificate.Resource{Domain: domain.Main, Certificate: storedCert.Certificate, PrivateKey: storedCert.Key}, nil
+	}
+
 	logger.Debugf("Loading ACME certificates %+v...", uncheckedDomains)
 
 	client, err := p.getClient()
@@ -436,7 +549,7 @@ func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain,
 	request := certificate.ObtainRequest{
 		Domains:    domains,
 		Bundle:     true,
-		MustStaple: oscpMustStaple,
+		MustStaple: p.MustStaple,
 	}
 
 	cert, err := client.Certificate.Obtain(request)
@@ -452,11 +565,7 @@ func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain,
 
 	logger.Debugf("Certificates obtained for domains %+v", uncheckedDomains)
 
-	if len(uncheckedDomains) > 1 {
-		domain = types.Domain{Main: uncheckedDomains[0], SANs: uncheckedDomains[1:]}
-	} else {
-		domain = types.Domain{Main: uncheckedDomains[0]}
-	}
+	cert.Certificate = p.selectPreferredChain(ctx, cert)
 	p.addCertificateForDomain(domain, cert.Certificate, cert.PrivateKey)
 
 	return cert, nil
@@ -480,8 +589,128 @@ func (p *Provider) addResolvingDomains(resolvingDomains []string) {
 	}
 }
 
+// addCertificateForDomain adds the certificate to the provider, and waits until it is stored.
+// It gives up once the certificates watcher is stopped.
 func (p *Provider) addCertificateForDomain(domain types.Domain, certificate []byte, key []byte) {
-	p.certsChan <- &Certificate{Certificate: certificate, Key: key, Domain: domain}
+	saved := make(chan struct{})
+
+	select {
+	case p.certsChan <- &certificateUpdate{certificate: &Certificate{Certificate: certificate, Key: key, Domain: domain}, saved: saved}:
+	case <-p.certsWatcherDone:
+		return
+	}
+
+	select {
+	case <-saved:
+	case <-p.certsWatcherDone:
+	}
+}
+
+// getCAServer returns the directory URL of the CA.
+func (p *Provider) getCAServer() string {
+	if len(p.CAServer) > 0 {
+		return p.CAServer
+	}
+	return lego.LEDirectoryProduction
+}
+
+// lock acquires the lock identified by the key when the store is shared by several instances,
+// and returns the function releasing it.
+func (p *Provider) lock(ctx context.Context, key string) (func(), error) {
+	locker, ok := p.Store.(Locker)
+	if !ok {
+		return func() {}, nil
+	}
+
+	key = p.ResolverName + "/" + key
+
+	logger := log.FromContext(ctx)
+	logger.Debugf("Acquiring the lock %q...", key)
+
+	ctxLock, cancel := context.WithTimeout(ctx, lockTimeout)
+	defer cancel()
+
+	unlock, err := locker.Lock(ctxLock, key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to acquire the lock %q: %v", key, err)
+	}
+
+	return func() {
+		if err := unlock(); err != nil {
+			logger.Errorf("Unable to release the lock %q: %v", key, err)
+		}
+	}, nil
+}
+
+// loadStoredAccount loads the account registered by another instance sharing the store.
+func (p *Provider) loadStoredAccount() error {
+	if _, ok := p.Store.(Locker); !ok || p.account != nil {
+		return nil
+	}
+
+	account, err := p.Store.GetAccount(p.ResolverName)
+	if err != nil {
+		return fmt.Errorf("unable to get ACME account : %v", err)
+	}
+
+	if account != nil && account.Registration != nil && isAccountMatchingCaServer(context.Background(), account.Registration.URI, p.CAServer) {
+		p.account = account
+	}
+	return nil
+}
+
+// getStoredCertificate returns the valid certificate of the domain obtained by another instance sharing the store.
+func (p *Provider) getStoredCertificate(ctx context.Context, domain types.Domain) *Certificate {
+	if _, ok := p.Store.(Locker); !ok {
+		return nil
+	}
+
+	certificates, err := p.Store.GetCertificates(p.ResolverName)
+	if err != nil {
+		log.FromContext(ctx).Errorf("Unable to get the stored ACME certificates: %v", err)
+		return nil
+	}
+
+	for _, cert := range certificates {
+		if reflect.DeepEqual(cert.Domain, domain) && !p.needsRenewal(ctx, cert) {
+			return cert
+		}
+	}
+	return nil
+}
+
+// loadStoredCertificates merges the certificates obtained by the other instances sharing the store.
+func (p *Provider) loadStoredCertificates(ctx context.Context) error {
+	if _, ok := p.Store.(Locker); !ok {
+		return nil
+	}
+
+	certificates, err := p.Store.GetCertificates(p.ResolverName)
+	if err != nil {
+		return fmt.Errorf("unable to get ACME certificates : %v", err)
+	}
+
+	for _, storedCert := range certificates {
+		found := false
+		for _, cert := range p.certificates {
+			if !reflect.DeepEqual(cert.Domain, storedCert.Domain) {
+				continue
+			}
+
+			found = true
+			if isMoreRecent(ctx, storedCert, cert) {
+				cert.Certificate = storedCert.Certificate
+				cert.Key = storedCert.Key
+			}
+			break
+		}
+
+		if !found {
+			p.certificates = append(p.certificates, storedCert)
+		}
+	}
+
+	return nil
 }
 
 // deleteUnnecessaryDomains deletes from the configuration :
@@ -542,29 +771,22 @@ func (p *Provider) deleteUnnecessaryDomains(ctx context.Context) {
 }
 
 func (p *Provider) watchCertificate(ctx context.Context) {
-	p.certsChan = make(chan *Certificate)
+	p.certsChan = make(chan *certificateUpdate)
+
+	ctxWatcher, cancel := context.WithCancel(ctx)
+	p.certsWatcherDone = ctxWatcher.Done()
 
 	p.pool.Go(func(stop chan bool) {
+		defer cancel()
+
 		for {
 			select {
-			case cert := <-p.certsChan:
-				certUpdated := false
-				for _, domainsCertificate := range p.certificates {
-					if reflect.DeepEqual(cert.Domain, domainsCertificate.Domain) {
-						domainsCertificate.Certificate = cert.Certificate
-						domainsCertificate.Key = cert.Key
-						certUpdated = true
-						break
-					}
-				}
-				if !certUpdated {
-					p.certificates = append(p.certificates, cert)
-				}
-
-				err := p.saveCertificates()
+			case update := <-p.certsChan:
+				err := p.updateCertificate(ctx, update.certificate)
 				if err != nil {
 					log.FromContext(ctx).Error(err)
 				}
+				close(update.saved)
 			case <-stop:
 				return
 			}
@@ -572,8 +794,36 @@ func (p *Provider) watchCertificate(ctx context.Context) {
 	})
 }
 
+func (p *Provider) updateCertificate(ctx context.Context, cert *Certificate) error {
+	// The certificates list is shared by the instances sharing the store.
+	unlock, err := p.lock(ctx, "certificates")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err = p.loadStoredCertificates(ctx); err != nil {
+		return err
+	}
+
+	certUpdated := false
+	for _, domainsCertificate := range p.certificates {
+		if reflect.DeepEqual(cert.Domain, domainsCertificate.Domain) {
+			domainsCertificate.Certificate = cert.Certificate
+			domainsCertificate.Key = cert.Key
+			certUpdated = true
+			break
+		}
+	}
+	if !certUpdated {
+		p.certificates = append(p.certificates, cert)
+	}
+
+	return p.saveCertificates()
+}
+
 func (p *Provider) saveCertificates() error {
-	err := p.Store.SaveCertificates(p.certificates)
+	err := p.Store.SaveCertificates(p.ResolverName, p.certificates)
 
 	p.refreshCertificates()
 
@@ -582,7 +832,7 @@ func (p *Provider) saveCertificates() error {
 
 func (p *Provider) refreshCertificates() {
 	conf := config.Message{
-		ProviderName: "ACME",
+		ProviderName: p.ResolverName + ".acme",
 		Configuration: &config.Configuration{
 			HTTP: &config.HTTPConfiguration{
 				Routers:     map[string]*config.Router{},
@@ -605,37 +855,140 @@ func (p *Provider) renewCertificates(ctx context.Context) {
 
 	logger.Info("Testing certificate renew...")
 	for _, cert := range p.certificates {
-		crt, err := getX509Certificate(ctx, cert)
-		// If there's an error, we assume the cert is broken, and needs update
-		// <= 30 days left, renew certificate
-		if err != nil || crt == nil || crt.NotAfter.Before(time.Now().Add(24*30*time.Hour)) {
-			client, err := p.getClient()
-			if err != nil {
-				logger.Infof("Error renewing certificate from LE : %+v, %v", cert.Domain, err)
-				continue
-			}
+		if p.needsRenewal(ctx, cert) {
+			p.renewCertificate(ctx, cert)
+		}
+	}
+}
 
-			logger.Infof("Renewing certificate from LE : %+v", cert.Domain)
+func (p *Provider) renewCertificate(ctx context.Context, cert *Certificate) {
+	logger := log.FromContext(ctx)
 
-			renewedCert, err := client.Certificate.Renew(certificate.Resource{
-				Domain:      cert.Domain.Main,
-				PrivateKey:  cert.Key,
-				Certificate: cert.Certificate,
-			}, true, oscpMustStaple)
+	unlock, err := p.lock(ctx, "domains/"+strings.Join(cert.Domain.ToStrArray(), ","))
+	if err != nil {
+		logger.Errorf("Error renewing certificate from LE: %v, %v", cert.Domain, err)
+		return
+	}
+	defer unlock()
 
-			if err != nil {
-				logger.Errorf("Error renewing certificate from LE: %v, %v", cert.Domain, err)
-				continue
-			}
+	// Another instance sharing the store may have renewed the certificate while the lock was held.
+	if storedCert := p.getStoredCertificate(ctx, cert.Domain); storedCert != nil {
+		logger.Infof("Using the certificate renewed by another instance: %+v", cert.Domain)
+		p.addCertificateForDomain(cert.Domain, storedCert.Certificate, storedCert.Key)
+		return
+	}
 
-			if len(renewedCert.Certificate) == 0 || len(renewedCert.PrivateKey) == 0 {
-				logger.Errorf("domains %v renew certificate with no value: %v", cert.Domain.ToStrArray(), cert)
-				continue
-			}
+	client, err := p.getClient()
+	if err != nil {
+		logger.Infof("Error renewing certificate from LE : %+v, %v", cert.Domain, err)
+		return
+	}
+
+	logger.Infof("Renewing certificate from LE : %+v", cert.Domain)
+
+	renewedCert, err := client.Certificate.Renew(certificate.Resource{
+		Domain:      cert.Domain.Main,
+		PrivateKey:  cert.Key,
+		Certificate: cert.Certificate,
+	}, true, p.MustStaple)
 
-			p.addCertificateForDomain(cert.Domain, renewedCert.Certificate, renewedCert.PrivateKey)
+	if err != nil {
+		logger.Errorf("Error renewing certificate from LE: %v, %v", cert.Domain, err)
+		return
+	}
+
+	if len(renewedCert.Certificate) == 0 || len(renewedCert.PrivateKey) == 0 {
+		logger.Errorf("domains %v renew certificate with no value: %v", cert.Domain.ToStrArray(), cert)
+		return
+	}
+
+	p.addCertificateForDomain(cert.Domain, p.selectPreferredChain(ctx, renewedCert), renewedCert.PrivateKey)
+}
+
+// needsRenewal returns true if the certificate expires in less than the renew period.
+// If there's an error, we assume the cert is broken, and needs update.
+func (p *Provider) needsRenewal(ctx context.Context, cert *Certificate) bool {
+	renewPeriod := time.Duration(p.RenewPeriod)
+	if renewPeriod == 0 {
+		renewPeriod = defaultRenewPeriod
+	}
+
+	crt, err := getX509Certificate(ctx, cert)
+	return err != nil || crt == nil || crt.NotAfter.Before(time.Now().Add(renewPeriod))
+}
+
+// selectPreferredChain returns the chain whose top certificate is issued by the preferred chain issuer,
+// among the chain returned by the CA and the alternate chains it offers for the certificate.
+// The chain returned by the CA is used if none of the chains are issued by the preferred issuer.
+func (p *Provider) selectPreferredChain(ctx context.Context, cert *certificate.Resource) []byte {
+	if len(p.PreferredChain) == 0 {
+		return cert.Certificate
+	}
+
+	logger := log.FromContext(ctx)
+
+	if chain, ok := p.trimChain(ctx, cert.Certificate); ok {
+		return chain
+	}
+
+	alternates, err := p.fetchAlternateChains(cert.CertURL)
+	if err != nil {
+		logger.Warnf("Unable to fetch the alternate chains of the certificate: %v", err)
+	}
+
+	for _, alternate := range alternates {
+		if chain, ok := p.trimChain(ctx, alternate); ok {
+			return chain
+		}
+	}
+
+	logger.Debugf("No chain is issued by %q, using the default chain.", p.PreferredChain)
+	return cert.Certificate
+}
+
+// trimChain trims the bundle after the first certificate issued by the preferred chain issuer,
+// and reports whether such a certificate was found.
+func (p *Provider) trimChain(ctx context.Context, bundle []byte) ([]byte, bool) {
+	rest := bundle
+	var chain []byte
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, false
+		}
+
+		chain = append(chain, pem.EncodeToMemory(block)...)
+
+		if block.Type != "CERTIFICATE" {
+			continue
 		}
+
+		crt, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			log.FromContext(ctx).Warnf("Unable to parse the certificate chain to select the preferred chain: %v", err)
+			return nil, false
+		}
+
+		if crt.Issuer.CommonName == p.PreferredChain {
+			return chain, true
+		}
+	}
+}
+
+// isMoreRecent returns true if the certificate expires after the other one.
+func isMoreRecent(ctx context.Context, cert *Certificate, other *Certificate) bool {
+	crt, err := getX509Certificate(ctx, cert)
+	if err != nil || crt == nil {
+		return false
 	}
+
+	otherCrt, err := getX509Certificate(ctx, other)
+	if err != nil || otherCrt == nil {
+		return true
+	}
+
+	return crt.NotAfter.After(otherCrt.NotAfter)
 }
 
 // Get provided certificate which check a domains list (Main and SANs)
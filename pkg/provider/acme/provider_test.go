@@ -2,13 +2,31 @@ package acme
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/containous/traefik/pkg/safe"
+	"github.com/containous/traefik/pkg/tls/generate"
 	"github.com/containous/traefik/pkg/types"
 	"github.com/go-acme/lego/certcrypto"
+	"github.com/go-acme/lego/certificate"
+	"github.com/go-acme/lego/registration"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jose "gopkg.in/square/go-jose.v2"
 )
 
 func TestGetUncheckedCertificates(t *testing.T) {
@@ -600,3 +618,283 @@ func TestInitAccount(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadStoredCertificates(t *testing.T) {
+	fooCert, fooKey, err := generate.KeyPair("foo.com", time.Now().Add(60*24*time.Hour))
+	require.NoError(t, err)
+	renewedCert, renewedKey, err := generate.KeyPair("foo.com", time.Now().Add(90*24*time.Hour))
+	require.NoError(t, err)
+	barCert, barKey, err := generate.KeyPair("bar.com", time.Now().Add(60*24*time.Hour))
+	require.NoError(t, err)
+
+	store, err := newKubernetesStore(newFakeSecretClient(), "traefik-acme")
+	require.NoError(t, err)
+
+	err = store.SaveCertificates("foo", []*Certificate{
+		{Domain: types.Domain{Main: "foo.com"}, Certificate: renewedCert, Key: renewedKey},
+		{Domain: types.Domain{Main: "bar.com"}, Certificate: barCert, Key: barKey},
+	})
+	require.NoError(t, err)
+
+	acmeProvider := Provider{
+		Configuration: &Configuration{},
+		ResolverName:  "foo",
+		Store:         store,
+		certificates:  []*Certificate{{Domain: types.Domain{Main: "foo.com"}, Certificate: fooCert, Key: fooKey}},
+	}
+
+	err = acmeProvider.loadStoredCertificates(context.Background())
+	require.NoError(t, err)
+
+	expected := []*Certificate{
+		{Domain: types.Domain{Main: "foo.com"}, Certificate: renewedCert, Key: renewedKey},
+		{Domain: types.Domain{Main: "bar.com"}, Certificate: barCert, Key: barKey},
+	}
+	assert.Equal(t, expected, acmeProvider.certificates)
+
+	storedCert := acmeProvider.getStoredCertificate(context.Background(), types.Domain{Main: "bar.com"})
+	require.NotNil(t, storedCert)
+	assert.Equal(t, barCert, storedCert.Certificate)
+
+	assert.Nil(t, acmeProvider.getStoredCertificate(context.Background(), types.Domain{Main: "baz.com"}))
+}
+
+func TestGetStoredCertificate_notSharedStore(t *testing.T) {
+	acmeProvider := Provider{ResolverName: "foo", Store: &LocalStore{}}
+
+	assert.Nil(t, acmeProvider.getStoredCertificate(context.Background(), types.Domain{Main: "foo.com"}))
+	assert.NoError(t, acmeProvider.loadStoredCertificates(context.Background()))
+}
+
+func TestNeedsRenewal(t *testing.T) {
+	cert, key, err := generate.KeyPair("foo.com", time.Now().Add(20*24*time.Hour))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc        string
+		renewPeriod types.Duration
+		expected    bool
+	}{
+		{
+			desc:     "default renew period",
+			expected: true,
+		},
+		{
+			desc:        "expiring within the renew period",
+			renewPeriod: types.Duration(30 * 24 * time.Hour),
+			expected:    true,
+		},
+		{
+			desc:        "not expiring within the renew period",
+			renewPeriod: types.Duration(10 * 24 * time.Hour),
+			expected:    false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			acmeProvider := Provider{Configuration: &Configuration{RenewPeriod: test.renewPeriod}}
+
+			actual := acmeProvider.needsRenewal(context.Background(), &Certificate{Domain: types.Domain{Main: "foo.com"}, Certificate: cert, Key: key})
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestSelectPreferredChain(t *testing.T) {
+	crossSigningRoot := generateCACertificate(t, "Old Root", nil)
+	root := generateCACertificate(t, "New Root", crossSigningRoot)
+	intermediate := generateCACertificate(t, "Intermediate", root)
+	leaf := generateCACertificate(t, "foo.com", intermediate)
+
+	bundle := append(append(append([]byte{}, leaf.pem...), intermediate.pem...), root.pem...)
+
+	testCases := []struct {
+		desc           string
+		preferredChain string
+		expected       []byte
+	}{
+		{
+			desc:     "no preferred chain",
+			expected: bundle,
+		},
+		{
+			desc:           "default chain issued by the preferred issuer",
+			preferredChain: "Old Root",
+			expected:       bundle,
+		},
+		{
+			desc:           "shorter chain issued by the preferred issuer",
+			preferredChain: "New Root",
+			expected:       append(append([]byte{}, leaf.pem...), intermediate.pem...),
+		},
+		{
+			desc:           "unknown preferred issuer",
+			preferredChain: "Unknown Root",
+			expected:       bundle,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			acmeProvider := Provider{Configuration: &Configuration{PreferredChain: test.preferredChain}}
+
+			assert.Equal(t, test.expected, acmeProvider.selectPreferredChain(context.Background(), &certificate.Resource{Certificate: bundle}))
+		})
+	}
+}
+
+func TestSelectPreferredChain_alternateChains(t *testing.T) {
+	rootA := generateCACertificate(t, "Root A", nil)
+	rootB := generateCACertificate(t, "Root B", nil)
+	intermediateA := generateCACertificate(t, "Intermediate A", rootA)
+	intermediateB := generateCACertificate(t, "Intermediate B", rootB)
+	leaf := generateCACertificate(t, "foo.com", intermediateA)
+
+	defaultChain := append(append([]byte{}, leaf.pem...), intermediateA.pem...)
+	alternateChain := append(append([]byte{}, leaf.pem...), intermediateB.pem...)
+
+	accountKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/directory", func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = fmt.Fprintf(rw, `{"newNonce": %q}`, server.URL+"/nonce")
+	})
+	mux.HandleFunc("/nonce", func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Replay-Nonce", "nonce")
+	})
+
+	serveChain := func(chain []byte, links ...string) http.HandlerFunc {
+		return func(rw http.ResponseWriter, req *http.Request) {
+			body, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				http.Error(rw, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			signed, err := jose.ParseSigned(string(body))
+			if err != nil {
+				http.Error(rw, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			payload, err := signed.Verify(&accountKey.PublicKey)
+			if err != nil || len(payload) != 0 {
+				http.Error(rw, "invalid signature", http.StatusUnauthorized)
+				return
+			}
+
+			protected := signed.Signatures[0].Protected
+			if protected.KeyID != "kid" || protected.Nonce != "nonce" || protected.ExtraHeaders["url"] != server.URL+req.URL.Path {
+				http.Error(rw, "invalid protected header", http.StatusUnauthorized)
+				return
+			}
+
+			for _, link := range links {
+				rw.Header().Add("Link", fmt.Sprintf(`<%s>;rel="alternate"`, server.URL+link))
+			}
+			_, _ = rw.Write(chain)
+		}
+	}
+	mux.HandleFunc("/cert", serveChain(defaultChain, "/cert/1"))
+	mux.HandleFunc("/cert/1", serveChain(alternateChain))
+
+	testCases := []struct {
+		desc           string
+		preferredChain string
+		certURL        string
+		expected       []byte
+	}{
+		{
+			desc:           "default chain issued by the preferred issuer",
+			preferredChain: "Root A",
+			certURL:        server.URL + "/cert",
+			expected:       defaultChain,
+		},
+		{
+			desc:           "alternate chain issued by the preferred issuer",
+			preferredChain: "Root B",
+			certURL:        server.URL + "/cert",
+			expected:       alternateChain,
+		},
+		{
+			desc:           "unknown preferred issuer",
+			preferredChain: "Unknown Root",
+			certURL:        server.URL + "/cert",
+			expected:       defaultChain,
+		},
+		{
+			desc:           "unavailable certificate",
+			preferredChain: "Root B",
+			certURL:        server.URL + "/unknown",
+			expected:       defaultChain,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			acmeProvider := Provider{
+				Configuration: &Configuration{CAServer: server.URL + "/directory", PreferredChain: test.preferredChain},
+				account: &Account{
+					PrivateKey:   x509.MarshalPKCS1PrivateKey(accountKey),
+					Registration: &registration.Resource{URI: "kid"},
+				},
+			}
+
+			cert := &certificate.Resource{Certificate: defaultChain, CertURL: test.certURL}
+
+			assert.Equal(t, test.expected, acmeProvider.selectPreferredChain(context.Background(), cert))
+		})
+	}
+}
+
+type testCertificate struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	pem         []byte
+}
+
+// generateCACertificate generates a certificate signed by the parent, or a self-signed certificate if the parent is nil.
+func generateCACertificate(t *testing.T, commonName string, parent *testCertificate) *testCertificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	issuer, signer := template, key
+	if parent != nil {
+		issuer, signer = parent.certificate, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	require.NoError(t, err)
+
+	crt, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCertificate{
+		certificate: crt,
+		key:         key,
+		pem:         pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
@@ -1,20 +1,25 @@
 package acme
 
+import "context"
+
 // StoredData represents the data managed by Store
 type StoredData struct {
-	Account        *Account
-	Certificates   []*Certificate
-	HTTPChallenges map[string]map[string][]byte
-	TLSChallenges  map[string]*Certificate
+	Account      *Account
+	Certificates []*Certificate
 }
 
-// Store is a generic interface that represents a storage
+// Store is a generic interface that represents a storage.
+// The account and the certificates are stored per certificates resolver.
 type Store interface {
-	GetAccount() (*Account, error)
-	SaveAccount(*Account) error
-	GetCertificates() ([]*Certificate, error)
-	SaveCertificates([]*Certificate) error
+	GetAccount(resolverName string) (*Account, error)
+	SaveAccount(resolverName string, account *Account) error
+	GetCertificates(resolverName string) ([]*Certificate, error)
+	SaveCertificates(resolverName string, certificates []*Certificate) error
+}
 
+// ChallengeStore is a generic interface that represents a storage for the challenges,
+// shared by all the certificates resolvers.
+type ChallengeStore interface {
 	GetHTTPChallengeToken(token, domain string) ([]byte, error)
 	SetHTTPChallengeToken(token, domain string, keyAuth []byte) error
 	RemoveHTTPChallengeToken(token, domain string) error
@@ -23,3 +28,11 @@ type Store interface {
 	GetTLSChallenge(domain string) (*Certificate, error)
 	RemoveTLSChallenge(domain string) error
 }
+
+// Locker is implemented by the stores shared by several Traefik instances,
+// to prevent them from registering the same account or obtaining the same certificates at the same time.
+type Locker interface {
+	// Lock blocks until the lock identified by the key is acquired, or the context is done,
+	// and returns the function releasing it.
+	Lock(ctx context.Context, key string) (unlock func() error, err error)
+}
@@ -77,6 +77,12 @@ func ipv4(ip string) func(*network.EndpointSettings) {
 	}
 }
 
+func networkID(id string) func(*network.EndpointSettings) {
+	return func(s *network.EndpointSettings) {
+		s.NetworkID = id
+	}
+}
+
 func swarmTask(id string, ops ...func(*swarm.Task)) swarm.Task {
 	task := &swarm.Task{
 		ID: id,
@@ -89,6 +95,12 @@ func swarmTask(id string, ops ...func(*swarm.Task)) swarm.Task {
 	return *task
 }
 
+func taskNodeID(id string) func(*swarm.Task) {
+	return func(task *swarm.Task) {
+		task.NodeID = id
+	}
+}
+
 func taskSlot(slot int) func(*swarm.Task) {
 	return func(task *swarm.Task) {
 		task.Slot = slot
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
 
 	"github.com/containous/traefik/pkg/config"
@@ -228,7 +229,7 @@ func (p *Provider) getIPPort(ctx context.Context, container dockerData, serverPo
 	return ip, port, nil
 }
 
-func (p Provider) getIPAddress(ctx context.Context, container dockerData) string {
+func (p *Provider) getIPAddress(ctx context.Context, container dockerData) string {
 	logger := log.FromContext(ctx)
 
 	if container.ExtraConf.Docker.Network != "" {
@@ -239,7 +240,7 @@ func (p Provider) getIPAddress(ctx context.Context, container dockerData) string
 				return network.Addr
 			}
 
-			logger.Warnf("Could not find network named '%s' for container '%s'! Maybe you're missing the project's prefix in the label? Defaulting to first available network.", container.ExtraConf.Docker.Network, container.Name)
+			logger.Warnf("Could not find network named '%s' for container '%s'! Maybe you're missing the project's prefix in the label? Defaulting to first shared network.", container.ExtraConf.Docker.Network, container.Name)
 		}
 	}
 
@@ -266,7 +267,7 @@ func (p Provider) getIPAddress(ctx context.Context, container dockerData) string
 		return p.getIPAddress(ctx, parseContainer(containerInspected))
 	}
 
-	for _, network := range container.NetworkSettings.Networks {
+	if network := p.getDefaultNetwork(container); network != nil {
 		return network.Addr
 	}
 
@@ -274,6 +275,31 @@ func (p Provider) getIPAddress(ctx context.Context, container dockerData) string
 	return ""
 }
 
+// getDefaultNetwork returns the first network, by name, shared by the container and Traefik,
+// or the first network of the container if none is shared.
+func (p *Provider) getDefaultNetwork(container dockerData) *networkData {
+	var names []string
+	for name := range container.NetworkSettings.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	p.sharedNetworksMu.RLock()
+	defer p.sharedNetworksMu.RUnlock()
+
+	for _, name := range names {
+		network := container.NetworkSettings.Networks[name]
+		if _, ok := p.sharedNetworks[network.ID]; ok {
+			return network
+		}
+	}
+
+	if len(names) > 0 {
+		return container.NetworkSettings.Networks[names[0]]
+	}
+	return nil
+}
+
 func (p *Provider) getPortBinding(container dockerData, serverPort string) (*nat.PortBinding, error) {
 	port := getPort(container, serverPort)
 	for netPort, portBindings := range container.NetworkSettings.Ports {
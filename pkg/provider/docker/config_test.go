@@ -2720,6 +2720,81 @@ func TestDockerGetIPAddress(t *testing.T) {
 	}
 }
 
+func TestDockerGetIPAddressDefaultNetwork(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		container      docker.ContainerJSON
+		network        string
+		sharedNetworks map[string]struct{}
+		expected       string
+	}{
+		{
+			desc: "several networks, none shared",
+			container: containerJSON(
+				withNetwork("webnet", networkID("1"), ipv4("10.11.12.13")),
+				withNetwork("backnet", networkID("2"), ipv4("10.11.12.14")),
+				withNetwork("frontnet", networkID("3"), ipv4("10.11.12.15")),
+			),
+			expected: "10.11.12.14",
+		},
+		{
+			desc: "several networks, one shared",
+			container: containerJSON(
+				withNetwork("webnet", networkID("1"), ipv4("10.11.12.13")),
+				withNetwork("backnet", networkID("2"), ipv4("10.11.12.14")),
+				withNetwork("frontnet", networkID("3"), ipv4("10.11.12.15")),
+			),
+			sharedNetworks: map[string]struct{}{"3": {}},
+			expected:       "10.11.12.15",
+		},
+		{
+			desc: "several networks, several shared",
+			container: containerJSON(
+				withNetwork("webnet", networkID("1"), ipv4("10.11.12.13")),
+				withNetwork("backnet", networkID("2"), ipv4("10.11.12.14")),
+				withNetwork("frontnet", networkID("3"), ipv4("10.11.12.15")),
+			),
+			sharedNetworks: map[string]struct{}{"1": {}, "3": {}},
+			expected:       "10.11.12.15",
+		},
+		{
+			desc: "network label not found, fallback on shared network",
+			container: containerJSON(
+				withNetwork("webnet", networkID("1"), ipv4("10.11.12.13")),
+				withNetwork("backnet", networkID("2"), ipv4("10.11.12.14")),
+			),
+			network:        "foonet",
+			sharedNetworks: map[string]struct{}{"1": {}},
+			expected:       "10.11.12.13",
+		},
+		{
+			desc: "network label takes precedence over shared network",
+			container: containerJSON(
+				withNetwork("webnet", networkID("1"), ipv4("10.11.12.13")),
+				withNetwork("backnet", networkID("2"), ipv4("10.11.12.14")),
+			),
+			network:        "backnet",
+			sharedNetworks: map[string]struct{}{"1": {}},
+			expected:       "10.11.12.14",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &Provider{sharedNetworks: test.sharedNetworks}
+
+			dData := parseContainer(test.container)
+			dData.ExtraConf.Docker.Network = test.network
+
+			actual := provider.getIPAddress(context.Background(), dData)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
 func TestSwarmGetIPAddress(t *testing.T) {
 	testCases := []struct {
 		service  swarm.Service
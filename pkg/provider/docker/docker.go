@@ -6,8 +6,10 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
@@ -36,6 +38,17 @@ const (
 
 	// SwarmAPIVersion is a constant holding the version of the Provider API traefik will use.
 	SwarmAPIVersion = "1.24"
+
+	// SwarmEventsAPIVersion is a constant holding the minimal version of the Provider API which provides swarm events.
+	SwarmEventsAPIVersion = "1.30"
+)
+
+const (
+	// labelSwarmTaskID is the label set by Docker on the containers of the swarm tasks.
+	labelSwarmTaskID = "com.docker.swarm.task.id"
+
+	// swarmEventsThrottle is the delay used to gather the swarm events of a single change (e.g. a service update) into one refresh.
+	swarmEventsThrottle = 1 * time.Second
 )
 
 // DefaultTemplateRule The default template for the default rule.
@@ -56,6 +69,8 @@ type Provider struct {
 	Network                 string           `description:"Default Docker network used." export:"true"`
 	SwarmModeRefreshSeconds types.Duration   `description:"Polling interval for swarm mode." export:"true"`
 	defaultRuleTpl          *template.Template
+	sharedNetworksMu        sync.RWMutex
+	sharedNetworks          map[string]struct{}
 }
 
 // SetDefaults sets the default values.
@@ -108,6 +123,15 @@ type networkData struct {
 }
 
 func (p *Provider) createClient() (client.APIClient, error) {
+	apiVersion := DockerAPIVersion
+	if p.SwarmMode {
+		apiVersion = SwarmAPIVersion
+	}
+
+	return p.createClientWithVersion(apiVersion)
+}
+
+func (p *Provider) createClientWithVersion(apiVersion string) (client.APIClient, error) {
 	var httpClient *http.Client
 
 	if p.TLS != nil {
@@ -137,11 +161,6 @@ func (p *Provider) createClient() (client.APIClient, error) {
 		"User-Agent": "Traefik " + version.Version,
 	}
 
-	apiVersion := DockerAPIVersion
-	if p.SwarmMode {
-		apiVersion = SwarmAPIVersion
-	}
-
 	return client.NewClient(p.Endpoint, apiVersion, httpClient, httpHeaders)
 }
 
@@ -170,6 +189,12 @@ func (p *Provider) Provide(configurationChan chan<- config.Message, pool *safe.P
 				return err
 			}
 			logger.Debugf("Provider connection established with docker %s (API %s)", serverVersion.Version, serverVersion.APIVersion)
+
+			sharedNetworks := lookupSharedNetworks(ctx, dockerClient)
+			p.sharedNetworksMu.Lock()
+			p.sharedNetworks = sharedNetworks
+			p.sharedNetworksMu.Unlock()
+
 			var dockerDataList []dockerData
 			if p.SwarmMode {
 				dockerDataList, err = p.listServices(ctx, dockerClient)
@@ -193,32 +218,85 @@ func (p *Provider) Provide(configurationChan chan<- config.Message, pool *safe.P
 			if p.Watch {
 				if p.SwarmMode {
 					errChan := make(chan error)
-					// TODO: This need to be change. Linked to Swarm events docker/docker#23827
+
+					// The task state changes are not published as swarm events (docker/docker#23827),
+					// so the services are still refreshed periodically.
 					ticker := time.NewTicker(time.Duration(p.SwarmModeRefreshSeconds))
+
+					var eventsc <-chan eventtypes.Message
+					var errc <-chan error
+					if versions.GreaterThanOrEqualTo(serverVersion.APIVersion, SwarmEventsAPIVersion) {
+						eventsClient, err := p.createClientWithVersion(SwarmEventsAPIVersion)
+						if err != nil {
+							logger.Errorf("Failed to create a client for docker swarm events, error: %s", err)
+							ticker.Stop()
+							return err
+						}
+
+						eventsc, errc = eventsClient.Events(ctx, dockertypes.EventsOptions{Filters: swarmEventsFilters()})
+					} else {
+						logger.Warnf("Docker API %s does not provide swarm events, services are only refreshed every %s", serverVersion.APIVersion, time.Duration(p.SwarmModeRefreshSeconds))
+					}
+
 					pool.GoCtx(func(ctx context.Context) {
 
 						ctx = log.With(ctx, log.Str(log.ProviderName, "docker"))
 						logger := log.FromContext(ctx)
 
 						defer close(errChan)
+
+						refresh := func() error {
+							services, err := p.listServices(ctx, dockerClient)
+							if err != nil {
+								logger.Errorf("Failed to list services for docker, error %s", err)
+								return err
+							}
+
+							configuration := p.buildConfiguration(ctx, services)
+							if configuration != nil {
+								configurationChan <- config.Message{
+									ProviderName:  "docker",
+									Configuration: configuration,
+								}
+							}
+							return nil
+						}
+
+						// throttle is only set while a refresh triggered by an event is pending.
+						var throttle <-chan time.Time
 						for {
 							select {
 							case <-ticker.C:
-								services, err := p.listServices(ctx, dockerClient)
-								if err != nil {
-									logger.Errorf("Failed to list services for docker, error %s", err)
+								if err := refresh(); err != nil {
 									errChan <- err
 									return
 								}
 
-								configuration := p.buildConfiguration(ctx, services)
-								if configuration != nil {
-									configurationChan <- config.Message{
-										ProviderName:  "docker",
-										Configuration: configuration,
-									}
+							case event := <-eventsc:
+								if !isSwarmEvent(event) {
+									continue
 								}
 
+								logger.Debugf("Provider event received %+v", event)
+								if throttle == nil {
+									throttle = time.After(swarmEventsThrottle)
+								}
+
+							case <-throttle:
+								throttle = nil
+								if err := refresh(); err != nil {
+									errChan <- err
+									return
+								}
+
+							case err := <-errc:
+								if err == io.EOF {
+									logger.Debug("Provider event stream closed")
+								}
+								ticker.Stop()
+								errChan <- err
+								return
+
 							case <-ctx.Done():
 								ticker.Stop()
 								return
@@ -499,10 +577,13 @@ func listTasks(ctx context.Context, dockerClient client.APIClient, serviceID str
 
 	var dockerDataList []dockerData
 	for _, task := range taskList {
+		// Swarm only reports a task as running once the healthcheck of its container passes,
+		// and replaces the unhealthy tasks, whatever the node they run on.
 		if task.Status.State != swarmtypes.TaskStateRunning {
 			continue
 		}
 		dData := parseTasks(ctx, task, serviceDockerData, networkMap, isGlobalSvc)
+
 		if len(dData.NetworkSettings.Networks) > 0 {
 			dockerDataList = append(dockerDataList, dData)
 		}
@@ -548,3 +629,59 @@ func parseTasks(ctx context.Context, task swarmtypes.Task, serviceDockerData doc
 	}
 	return dData
 }
+
+func swarmEventsFilters() filters.Args {
+	f := filters.NewArgs()
+	f.Add("type", eventtypes.ServiceEventType)
+	f.Add("type", eventtypes.NodeEventType)
+	f.Add("type", eventtypes.ContainerEventType)
+	return f
+}
+
+// isSwarmEvent returns true if the event can change the routable tasks of the services.
+func isSwarmEvent(event eventtypes.Message) bool {
+	switch event.Type {
+	case eventtypes.ServiceEventType, eventtypes.NodeEventType:
+		return true
+	case eventtypes.ContainerEventType:
+		// Only the containers of the tasks are relevant, and their health is only known by the local node.
+		if event.Actor.Attributes[labelSwarmTaskID] == "" {
+			return false
+		}
+		return event.Action == "start" ||
+			event.Action == "die" ||
+			strings.HasPrefix(event.Action, "health_status")
+	default:
+		return false
+	}
+}
+
+// lookupSharedNetworks returns the IDs of the networks of the Traefik container,
+// which are preferred when a container or a task is attached to several networks.
+func lookupSharedNetworks(ctx context.Context, dockerClient client.ContainerAPIClient) map[string]struct{} {
+	logger := log.FromContext(ctx)
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		logger.Debugf("Failed to retrieve the hostname: %s", err)
+		return nil
+	}
+
+	containerInspected, err := dockerClient.ContainerInspect(ctx, hostname)
+	if err != nil {
+		logger.Debugf("Traefik container not found, no network is preferred: %s", err)
+		return nil
+	}
+
+	if containerInspected.NetworkSettings == nil {
+		return nil
+	}
+
+	networks := make(map[string]struct{})
+	for _, network := range containerInspected.NetworkSettings.Networks {
+		if network != nil && network.NetworkID != "" {
+			networks[network.NetworkID] = struct{}{}
+		}
+	}
+	return networks
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	docker "github.com/docker/docker/api/types"
 	dockertypes "github.com/docker/docker/api/types"
+	eventtypes "github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/swarm"
 	dockerclient "github.com/docker/docker/client"
 	"github.com/stretchr/testify/assert"
@@ -77,6 +78,38 @@ func TestListTasks(t *testing.T) {
 				},
 			},
 		},
+		{
+			service: swarmService(serviceName("container")),
+			tasks: []swarm.Task{
+				swarmTask("id1",
+					taskSlot(1),
+					taskNodeID("node1"),
+					taskNetworkAttachment("1", "network1", "overlay", []string{"127.0.0.1"}),
+					taskStatus(taskState(swarm.TaskStateRunning)),
+				),
+				swarmTask("id2",
+					taskSlot(2),
+					taskNodeID("node2"),
+					taskNetworkAttachment("1", "network1", "overlay", []string{"127.0.0.2"}),
+					taskStatus(taskState(swarm.TaskStateRunning)),
+				),
+				swarmTask("id3",
+					taskSlot(3),
+					taskNodeID("node2"),
+					taskNetworkAttachment("1", "network1", "overlay", []string{"127.0.0.3"}),
+					taskStatus(taskState(swarm.TaskStateStarting)),
+				),
+			},
+			expectedTasks: []string{
+				"container.1",
+				"container.2",
+			},
+			networks: map[string]*docker.NetworkResource{
+				"1": {
+					Name: "foo",
+				},
+			},
+		},
 	}
 
 	for caseID, test := range testCases {
@@ -410,3 +443,68 @@ func TestSwarmTaskParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestIsSwarmEvent(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		event    eventtypes.Message
+		expected bool
+	}{
+		{
+			desc:     "service update",
+			event:    eventtypes.Message{Type: eventtypes.ServiceEventType, Action: "update"},
+			expected: true,
+		},
+		{
+			desc:     "node update",
+			event:    eventtypes.Message{Type: eventtypes.NodeEventType, Action: "update"},
+			expected: true,
+		},
+		{
+			desc: "task container health status",
+			event: eventtypes.Message{
+				Type:   eventtypes.ContainerEventType,
+				Action: "health_status: unhealthy",
+				Actor:  eventtypes.Actor{Attributes: map[string]string{labelSwarmTaskID: "id1"}},
+			},
+			expected: true,
+		},
+		{
+			desc: "task container die",
+			event: eventtypes.Message{
+				Type:   eventtypes.ContainerEventType,
+				Action: "die",
+				Actor:  eventtypes.Actor{Attributes: map[string]string{labelSwarmTaskID: "id1"}},
+			},
+			expected: true,
+		},
+		{
+			desc: "task container exec",
+			event: eventtypes.Message{
+				Type:   eventtypes.ContainerEventType,
+				Action: "exec_start: sh",
+				Actor:  eventtypes.Actor{Attributes: map[string]string{labelSwarmTaskID: "id1"}},
+			},
+			expected: false,
+		},
+		{
+			desc:     "standalone container",
+			event:    eventtypes.Message{Type: eventtypes.ContainerEventType, Action: "start"},
+			expected: false,
+		},
+		{
+			desc:     "secret",
+			event:    eventtypes.Message{Type: eventtypes.SecretEventType, Action: "create"},
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, isSwarmEvent(test.event))
+		})
+	}
+}
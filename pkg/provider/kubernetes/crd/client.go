@@ -50,11 +50,14 @@ type Client interface {
 	GetIngressRouteTCPs() []*v1alpha1.IngressRouteTCP
 	GetMiddlewares() []*v1alpha1.Middleware
 
+	GetNamespaces() []*corev1.Namespace
 	GetIngresses() []*extensionsv1beta1.Ingress
 	GetService(namespace, name string) (*corev1.Service, bool, error)
 	GetSecret(namespace, name string) (*corev1.Secret, bool, error)
 	GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error)
 	UpdateIngressStatus(namespace, name, ip, hostname string) error
+	UpdateIngressRouteStatus(ingressRoute *v1alpha1.IngressRoute) error
+	UpdateIngressRouteTCPStatus(ingressRouteTCP *v1alpha1.IngressRouteTCP) error
 }
 
 // TODO: add tests for the clientWrapper (and its methods) itself.
@@ -62,11 +65,16 @@ type clientWrapper struct {
 	csCrd  *versioned.Clientset
 	csKube *kubernetes.Clientset
 
-	factoriesCrd  map[string]externalversions.SharedInformerFactory
-	factoriesKube map[string]informers.SharedInformerFactory
+	factoriesCrd      map[string]externalversions.SharedInformerFactory
+	factoriesKube     map[string]informers.SharedInformerFactory
+	factoryNamespaces informers.SharedInformerFactory
 
 	labelSelector labels.Selector
 
+	// watchNamespaces enables the cluster-wide watch of the Namespace objects,
+	// which requires extra RBAC permissions.
+	watchNamespaces bool
+
 	isNamespaceAll    bool
 	watchedNamespaces []string
 }
@@ -168,11 +176,25 @@ func (c *clientWrapper) WatchAll(namespaces []string, stopCh <-chan struct{}) (<
 		c.factoriesKube[ns] = factoryKube
 	}
 
+	if c.watchNamespaces {
+		c.factoryNamespaces = informers.NewSharedInformerFactory(c.csKube, resyncPeriod)
+		c.factoryNamespaces.Core().V1().Namespaces().Informer().AddEventHandler(eventHandler)
+		c.factoryNamespaces.Start(stopCh)
+	}
+
 	for _, ns := range namespaces {
 		c.factoriesCrd[ns].Start(stopCh)
 		c.factoriesKube[ns].Start(stopCh)
 	}
 
+	if c.factoryNamespaces != nil {
+		for t, ok := range c.factoryNamespaces.WaitForCacheSync(stopCh) {
+			if !ok {
+				return nil, fmt.Errorf("timed out waiting for controller caches to sync %s", t.String())
+			}
+		}
+	}
+
 	for _, ns := range namespaces {
 		for t, ok := range c.factoriesCrd[ns].WaitForCacheSync(stopCh) {
 			if !ok {
@@ -241,6 +263,21 @@ func (c *clientWrapper) GetMiddlewares() []*v1alpha1.Middleware {
 	return result
 }
 
+// GetNamespaces returns all the Namespaces of the cluster,
+// or nil if the Namespaces are not watched.
+func (c *clientWrapper) GetNamespaces() []*corev1.Namespace {
+	if c.factoryNamespaces == nil {
+		return nil
+	}
+
+	namespaces, err := c.factoryNamespaces.Core().V1().Namespaces().Lister().List(labels.Everything())
+	if err != nil {
+		log.Errorf("Failed to list namespaces: %s", err)
+	}
+
+	return namespaces
+}
+
 // GetIngresses returns all Ingresses for observed namespaces in the cluster.
 func (c *clientWrapper) GetIngresses() []*extensionsv1beta1.Ingress {
 	var result []*extensionsv1beta1.Ingress
@@ -283,6 +320,34 @@ func (c *clientWrapper) UpdateIngressStatus(namespace, name, ip, hostname string
 	return nil
 }
 
+// UpdateIngressRouteStatus updates the status of an IngressRoute.
+func (c *clientWrapper) UpdateIngressRouteStatus(ingressRoute *v1alpha1.IngressRoute) error {
+	if !c.isWatchedNamespace(ingressRoute.Namespace) {
+		return fmt.Errorf("failed to update IngressRoute status %s/%s: namespace is not within watched namespaces", ingressRoute.Namespace, ingressRoute.Name)
+	}
+
+	_, err := c.csCrd.TraefikV1alpha1().IngressRoutes(ingressRoute.Namespace).UpdateStatus(ingressRoute)
+	if err != nil {
+		return fmt.Errorf("failed to update IngressRoute status %s/%s: %v", ingressRoute.Namespace, ingressRoute.Name, err)
+	}
+	log.Debugf("Updated status on IngressRoute %s/%s", ingressRoute.Namespace, ingressRoute.Name)
+	return nil
+}
+
+// UpdateIngressRouteTCPStatus updates the status of an IngressRouteTCP.
+func (c *clientWrapper) UpdateIngressRouteTCPStatus(ingressRouteTCP *v1alpha1.IngressRouteTCP) error {
+	if !c.isWatchedNamespace(ingressRouteTCP.Namespace) {
+		return fmt.Errorf("failed to update IngressRouteTCP status %s/%s: namespace is not within watched namespaces", ingressRouteTCP.Namespace, ingressRouteTCP.Name)
+	}
+
+	_, err := c.csCrd.TraefikV1alpha1().IngressRouteTCPs(ingressRouteTCP.Namespace).UpdateStatus(ingressRouteTCP)
+	if err != nil {
+		return fmt.Errorf("failed to update IngressRouteTCP status %s/%s: %v", ingressRouteTCP.Namespace, ingressRouteTCP.Name, err)
+	}
+	log.Debugf("Updated status on IngressRouteTCP %s/%s", ingressRouteTCP.Namespace, ingressRouteTCP.Name)
+	return nil
+}
+
 // GetService returns the named service from the given namespace.
 func (c *clientWrapper) GetService(namespace, name string) (*corev1.Service, bool, error) {
 	if !c.isWatchedNamespace(namespace) {
@@ -24,10 +24,11 @@ func init() {
 }
 
 type clientMock struct {
-	ingresses []*extensionsv1beta1.Ingress
-	services  []*corev1.Service
-	secrets   []*corev1.Secret
-	endpoints []*corev1.Endpoints
+	ingresses  []*extensionsv1beta1.Ingress
+	services   []*corev1.Service
+	secrets    []*corev1.Secret
+	endpoints  []*corev1.Endpoints
+	namespaces []*corev1.Namespace
 
 	apiServiceError       error
 	apiSecretError        error
@@ -38,6 +39,9 @@ type clientMock struct {
 	ingressRouteTCPs []*v1alpha1.IngressRouteTCP
 	middlewares      []*v1alpha1.Middleware
 
+	// updatedStatuses records the conditions written by the status updates, by resource kind/namespace/name.
+	updatedStatuses map[string][]v1alpha1.Condition
+
 	watchChan chan interface{}
 }
 
@@ -67,6 +71,8 @@ func newClientMock(paths ...string) clientMock {
 				c.ingresses = append(c.ingresses, o)
 			case *corev1.Secret:
 				c.secrets = append(c.secrets, o)
+			case *corev1.Namespace:
+				c.namespaces = append(c.namespaces, o)
 			default:
 				panic(fmt.Sprintf("Unknown runtime object %+v %T", o, o))
 			}
@@ -88,6 +94,10 @@ func (c clientMock) GetMiddlewares() []*v1alpha1.Middleware {
 	return c.middlewares
 }
 
+func (c clientMock) GetNamespaces() []*corev1.Namespace {
+	return c.namespaces
+}
+
 func (c clientMock) GetIngresses() []*extensionsv1beta1.Ingress {
 	return c.ingresses
 }
@@ -139,3 +149,17 @@ func (c clientMock) WatchAll(namespaces []string, stopCh <-chan struct{}) (<-cha
 func (c clientMock) UpdateIngressStatus(namespace, name, ip, hostname string) error {
 	return c.apiIngressStatusError
 }
+
+func (c clientMock) UpdateIngressRouteStatus(ingressRoute *v1alpha1.IngressRoute) error {
+	if c.updatedStatuses != nil {
+		c.updatedStatuses[makeStatusKey(kindIngressRoute, ingressRoute.Namespace, ingressRoute.Name)] = ingressRoute.Status.Conditions
+	}
+	return nil
+}
+
+func (c clientMock) UpdateIngressRouteTCPStatus(ingressRouteTCP *v1alpha1.IngressRouteTCP) error {
+	if c.updatedStatuses != nil {
+		c.updatedStatuses[makeStatusKey(kindIngressRouteTCP, ingressRouteTCP.Namespace, ingressRouteTCP.Name)] = ingressRouteTCP.Status.Conditions
+	}
+	return nil
+}
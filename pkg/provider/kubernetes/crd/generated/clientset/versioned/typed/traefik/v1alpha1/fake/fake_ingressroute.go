@@ -108,6 +108,18 @@ func (c *FakeIngressRoutes) Update(ingressRoute *v1alpha1.IngressRoute) (result
 	return obj.(*v1alpha1.IngressRoute), err
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeIngressRoutes) UpdateStatus(ingressRoute *v1alpha1.IngressRoute) (*v1alpha1.IngressRoute, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(ingressroutesResource, "status", c.ns, ingressRoute), &v1alpha1.IngressRoute{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.IngressRoute), err
+}
+
 // Delete takes name of the ingressRoute and deletes it. Returns an error if one occurs.
 func (c *FakeIngressRoutes) Delete(name string, options *v1.DeleteOptions) error {
 	_, err := c.Fake.
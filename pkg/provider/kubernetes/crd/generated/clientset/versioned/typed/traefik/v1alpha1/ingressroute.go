@@ -45,6 +45,7 @@ type IngressRoutesGetter interface {
 type IngressRouteInterface interface {
 	Create(*v1alpha1.IngressRoute) (*v1alpha1.IngressRoute, error)
 	Update(*v1alpha1.IngressRoute) (*v1alpha1.IngressRoute, error)
+	UpdateStatus(*v1alpha1.IngressRoute) (*v1alpha1.IngressRoute, error)
 	Delete(name string, options *v1.DeleteOptions) error
 	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
 	Get(name string, options v1.GetOptions) (*v1alpha1.IngressRoute, error)
@@ -128,6 +129,22 @@ func (c *ingressRoutes) Update(ingressRoute *v1alpha1.IngressRoute) (result *v1a
 	return
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+
+func (c *ingressRoutes) UpdateStatus(ingressRoute *v1alpha1.IngressRoute) (result *v1alpha1.IngressRoute, err error) {
+	result = &v1alpha1.IngressRoute{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("ingressroutes").
+		Name(ingressRoute.Name).
+		SubResource("status").
+		Body(ingressRoute).
+		Do().
+		Into(result)
+	return
+}
+
 // Delete takes name of the ingressRoute and deletes it. Returns an error if one occurs.
 func (c *ingressRoutes) Delete(name string, options *v1.DeleteOptions) error {
 	return c.client.Delete().
@@ -18,15 +18,22 @@ import (
 	"github.com/containous/traefik/pkg/job"
 	"github.com/containous/traefik/pkg/log"
 	"github.com/containous/traefik/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+	"github.com/containous/traefik/pkg/provider/kubernetes/k8s"
 	"github.com/containous/traefik/pkg/safe"
 	"github.com/containous/traefik/pkg/tls"
+	"github.com/containous/traefik/pkg/types"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
 const (
 	annotationKubernetesIngressClass = "kubernetes.io/ingress.class"
 	traefikDefaultIngressClass       = "traefik"
+
+	// annotationAllowedNamespaces lists the namespaces (comma separated, or "*" for all of them)
+	// allowed to reference the annotated object from another namespace.
+	annotationAllowedNamespaces = "traefik.containo.us/allowed-namespaces"
 )
 
 // Provider holds configurations of the provider.
@@ -38,7 +45,18 @@ type Provider struct {
 	Namespaces             []string `description:"Kubernetes namespaces." export:"true"`
 	LabelSelector          string   `description:"Kubernetes label selector to use." export:"true"`
 	IngressClass           string   `description:"Value of kubernetes.io/ingress.class annotation to watch for." export:"true"`
+	NamespaceSelector      string   `description:"Kubernetes label selector to select the namespaces to process." export:"true"`
+	AllowCrossNamespace    bool     `description:"Allow references to middlewares and services of other namespaces." export:"true"`
+	UpdateStatus           bool     `description:"Write the conditions of the IngressRoute and IngressRouteTCP resources in their status." export:"true"`
 	lastConfiguration      safe.Safe
+	routeStatuses          safe.Safe
+	runtimeRouters         safe.Safe
+	statusUpdates          chan struct{}
+}
+
+// SetDefaults sets the default values.
+func (p *Provider) SetDefaults() {
+	p.AllowCrossNamespace = true
 }
 
 func (p *Provider) newK8sClient(ctx context.Context, labelSelector string) (*clientWrapper, error) {
@@ -68,6 +86,7 @@ func (p *Provider) newK8sClient(ctx context.Context, labelSelector string) (*cli
 
 	if err == nil {
 		client.labelSelector = labelSel
+		client.watchNamespaces = p.NamespaceSelector != ""
 	}
 
 	return client, err
@@ -75,13 +94,16 @@ func (p *Provider) newK8sClient(ctx context.Context, labelSelector string) (*cli
 
 // Init the provider.
 func (p *Provider) Init() error {
+	if p.UpdateStatus {
+		p.statusUpdates = make(chan struct{}, 1)
+	}
 	return nil
 }
 
 // Provide allows the k8s provider to provide configurations to traefik
 // using the given configuration channel.
 func (p *Provider) Provide(configurationChan chan<- config.Message, pool *safe.Pool) error {
-	ctxLog := log.With(context.Background(), log.Str(log.ProviderName, "kubernetescrd"))
+	ctxLog := log.With(context.Background(), log.Str(log.ProviderName, providerName))
 	logger := log.FromContext(ctxLog)
 	// Tell glog (used by client-go) to log into STDERR. Otherwise, we risk
 	// certain kinds of API errors getting logged into a directory not
@@ -92,6 +114,10 @@ func (p *Provider) Provide(configurationChan chan<- config.Message, pool *safe.P
 		return err
 	}
 
+	if _, err = labels.Parse(p.NamespaceSelector); err != nil {
+		return fmt.Errorf("invalid namespace selector: %q", p.NamespaceSelector)
+	}
+
 	logger.Debugf("Using label selector: %q", p.LabelSelector)
 	k8sClient, err := p.newK8sClient(ctxLog, p.LabelSelector)
 	if err != nil {
@@ -117,6 +143,8 @@ func (p *Provider) Provide(configurationChan chan<- config.Message, pool *safe.P
 				select {
 				case <-stop:
 					return nil
+				case <-p.statusUpdates:
+					p.updateStatuses(ctxLog, k8sClient)
 				case event := <-eventsChan:
 					conf := p.loadConfigurationFromIngresses(ctxLog, k8sClient)
 
@@ -125,10 +153,14 @@ func (p *Provider) Provide(configurationChan chan<- config.Message, pool *safe.P
 					} else {
 						p.lastConfiguration.Set(conf)
 						configurationChan <- config.Message{
-							ProviderName:  "kubernetescrd",
+							ProviderName:  providerName,
 							Configuration: conf,
 						}
 					}
+
+					if p.UpdateStatus {
+						p.updateStatuses(ctxLog, k8sClient)
+					}
 				}
 			}
 		}
@@ -160,24 +192,25 @@ func loadTCPServers(client Client, namespace string, svc v1alpha1.ServiceTCP) ([
 		return nil, errors.New("service not found")
 	}
 
-	var portSpec *corev1.ServicePort
-	for _, p := range service.Spec.Ports {
-		if svc.Port == p.Port {
-			portSpec = &p
-			break
-		}
-	}
-
-	if portSpec == nil {
-		return nil, errors.New("service port not found")
+	portSpec, err := getServicePort(service, svc.Port)
+	if err != nil {
+		return nil, err
 	}
 
 	var servers []config.TCPServer
-	if service.Spec.Type == corev1.ServiceTypeExternalName {
+	switch {
+	case service.Spec.Type == corev1.ServiceTypeExternalName:
 		servers = append(servers, config.TCPServer{
 			Address: fmt.Sprintf("%s:%d", service.Spec.ExternalName, portSpec.Port),
 		})
-	} else {
+	case svc.NativeLB:
+		address, err := getNativeServiceAddress(service, portSpec)
+		if err != nil {
+			return nil, err
+		}
+
+		servers = append(servers, config.TCPServer{Address: address})
+	default:
 		endpoints, endpointsExists, endpointsErr := client.GetEndpoints(namespace, svc.Name)
 		if endpointsErr != nil {
 			return nil, endpointsErr
@@ -233,24 +266,27 @@ func loadServers(client Client, namespace string, svc v1alpha1.Service) ([]confi
 		return nil, errors.New("service not found")
 	}
 
-	var portSpec *corev1.ServicePort
-	for _, p := range service.Spec.Ports {
-		if svc.Port == p.Port {
-			portSpec = &p
-			break
-		}
-	}
-
-	if portSpec == nil {
-		return nil, errors.New("service port not found")
+	portSpec, err := getServicePort(service, svc.Port)
+	if err != nil {
+		return nil, err
 	}
 
 	var servers []config.Server
-	if service.Spec.Type == corev1.ServiceTypeExternalName {
+	switch {
+	case service.Spec.Type == corev1.ServiceTypeExternalName:
 		servers = append(servers, config.Server{
 			URL: fmt.Sprintf("http://%s:%d", service.Spec.ExternalName, portSpec.Port),
 		})
-	} else {
+	case svc.NativeLB:
+		address, err := getNativeServiceAddress(service, portSpec)
+		if err != nil {
+			return nil, err
+		}
+
+		servers = append(servers, config.Server{
+			URL: fmt.Sprintf("%s://%s", k8s.GetProtocol(portSpec.Name, portSpec.Port), address),
+		})
+	default:
 		endpoints, endpointsExists, endpointsErr := client.GetEndpoints(namespace, svc.Name)
 		if endpointsErr != nil {
 			return nil, endpointsErr
@@ -277,10 +313,7 @@ func loadServers(client Client, namespace string, svc v1alpha1.Service) ([]confi
 				return nil, errors.New("cannot define a port")
 			}
 
-			protocol := "http"
-			if port == 443 || strings.HasPrefix(portSpec.Name, "https") {
-				protocol = "https"
-			}
+			protocol := k8s.GetProtocol(portSpec.Name, port)
 
 			for _, addr := range subset.Addresses {
 				servers = append(servers, config.Server{
@@ -293,6 +326,31 @@ func loadServers(client Client, namespace string, svc v1alpha1.Service) ([]confi
 	return servers, nil
 }
 
+// getServicePort returns the port of the service matching the given port number.
+// An ExternalName service does not need to declare its ports.
+func getServicePort(service *corev1.Service, port int32) (*corev1.ServicePort, error) {
+	for _, p := range service.Spec.Ports {
+		if port == p.Port {
+			return &p, nil
+		}
+	}
+
+	if service.Spec.Type == corev1.ServiceTypeExternalName {
+		return &corev1.ServicePort{Port: port}, nil
+	}
+
+	return nil, errors.New("service port not found")
+}
+
+// getNativeServiceAddress returns the address of the ClusterIP of the service.
+func getNativeServiceAddress(service *corev1.Service, portSpec *corev1.ServicePort) (string, error) {
+	if service.Spec.ClusterIP == "" || service.Spec.ClusterIP == corev1.ClusterIPNone {
+		return "", fmt.Errorf("no clusterIP found for service %s/%s", service.Namespace, service.Name)
+	}
+
+	return fmt.Sprintf("%s:%d", service.Spec.ClusterIP, portSpec.Port), nil
+}
+
 func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Client) *config.Configuration {
 	conf := &config.Configuration{
 		HTTP: &config.HTTPConfiguration{
@@ -307,17 +365,45 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 	}
 	tlsConfigs := make(map[string]*tls.Configuration)
 
+	statuses := make(map[string]*resourceStatus)
+	defer p.routeStatuses.Set(statuses)
+
+	selectedNamespaces, err := p.selectNamespaces(client)
+	if err != nil {
+		log.FromContext(ctx).Error(err)
+		return conf
+	}
+
+	middlewares := make(map[string]*v1alpha1.Middleware)
+	for _, middleware := range client.GetMiddlewares() {
+		if !isNamespaceSelected(selectedNamespaces, middleware.Namespace) {
+			continue
+		}
+
+		id := makeID(middleware.Namespace, middleware.Name)
+		middlewares[id] = middleware
+		conf.HTTP.Middlewares[id] = &middleware.Spec
+	}
+
 	for _, ingressRoute := range client.GetIngressRoutes() {
 		logger := log.FromContext(log.With(ctx, log.Str("ingress", ingressRoute.Name), log.Str("namespace", ingressRoute.Namespace)))
 
+		if !isNamespaceSelected(selectedNamespaces, ingressRoute.Namespace) {
+			continue
+		}
+
 		// TODO keep the name ingressClass?
 		if !shouldProcessIngress(p.IngressClass, ingressRoute.Annotations[annotationKubernetesIngressClass]) {
 			continue
 		}
 
+		status := &resourceStatus{}
+		statuses[makeStatusKey(kindIngressRoute, ingressRoute.Namespace, ingressRoute.Name)] = status
+
 		err := getTLSHTTP(ctx, ingressRoute, client, tlsConfigs)
 		if err != nil {
 			logger.Errorf("Error configuring TLS: %v", err)
+			status.errors = append(status.errors, fmt.Sprintf("error configuring TLS: %v", err))
 		}
 
 		ingressName := ingressRoute.Name
@@ -328,27 +414,42 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 		for _, route := range ingressRoute.Spec.Routes {
 			if route.Kind != "Rule" {
 				logger.Errorf("Unsupported match kind: %s. Only \"Rule\" is supported for now.", route.Kind)
+				status.errors = append(status.errors, fmt.Sprintf("unsupported match kind: %s", route.Kind))
 				continue
 			}
 
 			if len(route.Match) == 0 {
 				logger.Errorf("Empty match rule")
+				status.errors = append(status.errors, "empty match rule")
 				continue
 			}
 
 			if err := checkStringQuoteValidity(route.Match); err != nil {
 				logger.Errorf("Invalid syntax for match rule: %s", route.Match)
+				status.errors = append(status.errors, fmt.Sprintf("invalid syntax for match rule: %s", route.Match))
 				continue
 			}
 
+			var refErrors []string
 			var allServers []config.Server
 			for _, service := range route.Services {
-				servers, err := loadServers(client, ingressRoute.Namespace, service)
+				namespace := service.Namespace
+				if len(namespace) == 0 {
+					namespace = ingressRoute.Namespace
+				}
+
+				if err := p.checkServiceReference(client, ingressRoute.Namespace, namespace, service.Name, selectedNamespaces); err != nil {
+					refErrors = append(refErrors, err.Error())
+					continue
+				}
+
+				servers, err := loadServers(client, namespace, service)
 				if err != nil {
 					logger.
 						WithField("serviceName", service.Name).
 						WithField("servicePort", service.Port).
 						Errorf("Cannot create service: %v", err)
+					status.errors = append(status.errors, fmt.Sprintf("cannot create service %s/%s:%d: %v", namespace, service.Name, service.Port, err))
 					continue
 				}
 
@@ -366,16 +467,32 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 				if len(ns) == 0 {
 					ns = ingressRoute.Namespace
 				}
-				mds = append(mds, makeID(ns, mi.Name))
+
+				id := makeID(ns, mi.Name)
+				if middleware, ok := middlewares[id]; ok {
+					if err := p.checkReference(ingressRoute.Namespace, "middleware", middleware.ObjectMeta, selectedNamespaces); err != nil {
+						refErrors = append(refErrors, err.Error())
+					}
+				}
+
+				mds = append(mds, id)
+			}
+
+			if len(refErrors) > 0 {
+				logger.Errorf("Route %q skipped: %s", route.Match, strings.Join(refErrors, ", "))
+				status.errors = append(status.errors, refErrors...)
+				continue
 			}
 
 			key, err := makeServiceKey(route.Match, ingressName)
 			if err != nil {
 				logger.Error(err)
+				status.errors = append(status.errors, err.Error())
 				continue
 			}
 
 			serviceName := makeID(ingressRoute.Namespace, key)
+			status.routers = append(status.routers, serviceName)
 
 			conf.HTTP.Routers[serviceName] = &config.Router{
 				Middlewares: mds,
@@ -383,9 +500,13 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 				EntryPoints: ingressRoute.Spec.EntryPoints,
 				Rule:        route.Match,
 				Service:     serviceName,
+				AccessLog:   route.AccessLog,
 			}
 			if ingressRoute.Spec.TLS != nil {
-				conf.HTTP.Routers[serviceName].TLS = &config.RouterTLSConfig{}
+				conf.HTTP.Routers[serviceName].TLS = &config.RouterTLSConfig{
+					CertResolver: ingressRoute.Spec.TLS.CertResolver,
+					Domains:      ingressRoute.Spec.TLS.Domains,
+				}
 			}
 			conf.HTTP.Services[serviceName] = &config.Service{
 				LoadBalancer: &config.LoadBalancerService{
@@ -397,21 +518,25 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 		}
 	}
 
-	for _, middleware := range client.GetMiddlewares() {
-		conf.HTTP.Middlewares[makeID(middleware.Namespace, middleware.Name)] = &middleware.Spec
-	}
-
 	for _, ingressRouteTCP := range client.GetIngressRouteTCPs() {
 		logger := log.FromContext(log.With(ctx, log.Str("ingress", ingressRouteTCP.Name), log.Str("namespace", ingressRouteTCP.Namespace)))
 
+		if !isNamespaceSelected(selectedNamespaces, ingressRouteTCP.Namespace) {
+			continue
+		}
+
 		if !shouldProcessIngress(p.IngressClass, ingressRouteTCP.Annotations[annotationKubernetesIngressClass]) {
 			continue
 		}
 
+		status := &resourceStatus{}
+		statuses[makeStatusKey(kindIngressRouteTCP, ingressRouteTCP.Namespace, ingressRouteTCP.Name)] = status
+
 		if ingressRouteTCP.Spec.TLS != nil && !ingressRouteTCP.Spec.TLS.Passthrough {
 			err := getTLSTCP(ctx, ingressRouteTCP, client, tlsConfigs)
 			if err != nil {
 				logger.Errorf("Error configuring TLS: %v", err)
+				status.errors = append(status.errors, fmt.Sprintf("error configuring TLS: %v", err))
 			}
 		}
 
@@ -423,35 +548,72 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 		for _, route := range ingressRouteTCP.Spec.Routes {
 			if len(route.Match) == 0 {
 				logger.Errorf("Empty match rule")
+				status.errors = append(status.errors, "empty match rule")
 				continue
 			}
 
 			if err := checkStringQuoteValidity(route.Match); err != nil {
 				logger.Errorf("Invalid syntax for match rule: %s", route.Match)
+				status.errors = append(status.errors, fmt.Sprintf("invalid syntax for match rule: %s", route.Match))
 				continue
 			}
 
-			var allServers []config.TCPServer
+			var refErrors []string
+			var weightedServices []weightedTCPServers
 			for _, service := range route.Services {
-				servers, err := loadTCPServers(client, ingressRouteTCP.Namespace, service)
+				namespace := service.Namespace
+				if len(namespace) == 0 {
+					namespace = ingressRouteTCP.Namespace
+				}
+
+				if err := p.checkServiceReference(client, ingressRouteTCP.Namespace, namespace, service.Name, selectedNamespaces); err != nil {
+					refErrors = append(refErrors, err.Error())
+					continue
+				}
+
+				servers, err := loadTCPServers(client, namespace, service)
 				if err != nil {
 					logger.
 						WithField("serviceName", service.Name).
 						WithField("servicePort", service.Port).
 						Errorf("Cannot create service: %v", err)
+					status.errors = append(status.errors, fmt.Sprintf("cannot create service %s/%s:%d: %v", namespace, service.Name, service.Port, err))
 					continue
 				}
 
-				allServers = append(allServers, servers...)
+				loadBalancer, err := loadTCPServersOptions(client, ingressRouteTCP.Namespace, service)
+				if err != nil {
+					logger.
+						WithField("serviceName", service.Name).
+						WithField("servicePort", service.Port).
+						Errorf("Cannot configure the connections to the service: %v", err)
+					status.errors = append(status.errors, fmt.Sprintf("cannot configure the connections to the service %s/%s:%d: %v", namespace, service.Name, service.Port, err))
+					continue
+				}
+				loadBalancer.Servers = servers
+
+				weightedServices = append(weightedServices, weightedTCPServers{
+					name:         fmt.Sprintf("%s-%s-%d", namespace, service.Name, service.Port),
+					weight:       service.Weight,
+					loadBalancer: loadBalancer,
+				})
+			}
+
+			if len(refErrors) > 0 {
+				logger.Errorf("Route %q skipped: %s", route.Match, strings.Join(refErrors, ", "))
+				status.errors = append(status.errors, refErrors...)
+				continue
 			}
 
 			key, e := makeServiceKey(route.Match, ingressName)
 			if e != nil {
 				logger.Error(e)
+				status.errors = append(status.errors, e.Error())
 				continue
 			}
 
 			serviceName := makeID(ingressRouteTCP.Namespace, key)
+			status.routers = append(status.routers, serviceName)
 			conf.TCP.Routers[serviceName] = &config.TCPRouter{
 				EntryPoints: ingressRouteTCP.Spec.EntryPoints,
 				Rule:        route.Match,
@@ -460,15 +622,43 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 
 			if ingressRouteTCP.Spec.TLS != nil {
 				conf.TCP.Routers[serviceName].TLS = &config.RouterTCPTLSConfig{
-					Passthrough: ingressRouteTCP.Spec.TLS.Passthrough,
+					Passthrough:  ingressRouteTCP.Spec.TLS.Passthrough,
+					CertResolver: ingressRouteTCP.Spec.TLS.CertResolver,
+					Domains:      ingressRouteTCP.Spec.TLS.Domains,
 				}
 			}
 
-			conf.TCP.Services[serviceName] = &config.TCPService{
-				LoadBalancer: &config.TCPLoadBalancerService{
-					Servers: allServers,
-				},
+			if !hasWeight(route.Services) && isMergeable(weightedServices) {
+				loadBalancer := &config.TCPLoadBalancerService{}
+				for _, service := range weightedServices {
+					servers := loadBalancer.Servers
+					*loadBalancer = *service.loadBalancer
+					loadBalancer.Servers = append(servers, service.loadBalancer.Servers...)
+				}
+
+				conf.TCP.Services[serviceName] = &config.TCPService{LoadBalancer: loadBalancer}
+				continue
+			}
+
+			weighted := &config.TCPWeightedRoundRobin{}
+			for _, service := range weightedServices {
+				childName := serviceName + "-" + service.name
+				conf.TCP.Services[childName] = &config.TCPService{
+					LoadBalancer: service.loadBalancer,
+				}
+
+				weight := 1
+				if service.weight != nil {
+					weight = *service.weight
+				}
+
+				weighted.Services = append(weighted.Services, config.TCPWRRService{
+					Name:   childName,
+					Weight: &weight,
+				})
 			}
+
+			conf.TCP.Services[serviceName] = &config.TCPService{Weighted: weighted}
 		}
 	}
 
@@ -477,6 +667,152 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 	return conf
 }
 
+// weightedTCPServers are the servers of a service of an IngressRouteTCP route.
+type weightedTCPServers struct {
+	name         string
+	weight       *int
+	loadBalancer *config.TCPLoadBalancerService
+}
+
+// isMergeable returns true if the servers of the services can share a single load balancer,
+// i.e. if the services are configured with the same connection options.
+func isMergeable(services []weightedTCPServers) bool {
+	for i := 1; i < len(services); i++ {
+		if !services[0].loadBalancer.Mergeable(services[i].loadBalancer) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadTCPServersOptions returns a load balancer, without servers, configured with the connection options of the service.
+func loadTCPServersOptions(client Client, namespace string, svc v1alpha1.ServiceTCP) (*config.TCPLoadBalancerService, error) {
+	loadBalancer := &config.TCPLoadBalancerService{
+		DialTimeout: types.Duration(time.Duration(svc.DialTimeoutSeconds) * time.Second),
+		KeepAlive:   types.Duration(time.Duration(svc.KeepAliveSeconds) * time.Second),
+	}
+
+	if svc.TLS == nil {
+		return loadBalancer, nil
+	}
+
+	loadBalancer.TLS = &config.TCPServersTLS{
+		ServerName:         svc.TLS.ServerName,
+		InsecureSkipVerify: svc.TLS.InsecureSkipVerify,
+	}
+
+	for _, secretName := range svc.TLS.RootCAsSecrets {
+		secret, exists, err := client.GetSecret(namespace, secretName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch secret %s/%s: %v", namespace, secretName, err)
+		}
+		if !exists {
+			return nil, fmt.Errorf("secret %s/%s does not exist", namespace, secretName)
+		}
+
+		ca, ok := secret.Data["ca.crt"]
+		if !ok || len(ca) == 0 {
+			return nil, fmt.Errorf("secret %s/%s is missing the ca.crt data entry", namespace, secretName)
+		}
+
+		loadBalancer.TLS.RootCAs = append(loadBalancer.TLS.RootCAs, tls.FileOrContent(ca))
+	}
+
+	for _, secretName := range svc.TLS.CertificatesSecrets {
+		tlsConf, err := getTLS(client, secretName, namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		loadBalancer.TLS.Certificates = append(loadBalancer.TLS.Certificates, *tlsConf.Certificate)
+	}
+
+	return loadBalancer, nil
+}
+
+// hasWeight returns true if a weight is set on at least one of the services.
+func hasWeight(services []v1alpha1.ServiceTCP) bool {
+	for _, service := range services {
+		if service.Weight != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// selectNamespaces returns the set of namespaces matching the namespace selector,
+// or nil if all the namespaces are selected.
+func (p *Provider) selectNamespaces(client Client) (map[string]bool, error) {
+	if p.NamespaceSelector == "" {
+		return nil, nil
+	}
+
+	selector, err := labels.Parse(p.NamespaceSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace selector: %q", p.NamespaceSelector)
+	}
+
+	selected := make(map[string]bool)
+	for _, namespace := range client.GetNamespaces() {
+		if selector.Matches(labels.Set(namespace.Labels)) {
+			selected[namespace.Name] = true
+		}
+	}
+
+	return selected, nil
+}
+
+func isNamespaceSelected(selectedNamespaces map[string]bool, namespace string) bool {
+	return selectedNamespaces == nil || selectedNamespaces[namespace]
+}
+
+func (p *Provider) checkServiceReference(client Client, fromNamespace, namespace, name string, selectedNamespaces map[string]bool) error {
+	if fromNamespace == namespace {
+		return nil
+	}
+
+	service, exists, err := client.GetService(namespace, name)
+	if err != nil || !exists {
+		// Let the servers loading report the error.
+		return nil
+	}
+
+	return p.checkReference(fromNamespace, "service", service.ObjectMeta, selectedNamespaces)
+}
+
+// checkReference checks whether the given object can be referenced by an object of the namespace fromNamespace.
+// The allowed namespaces annotation of the referenced object takes precedence over the provider option.
+func (p *Provider) checkReference(fromNamespace, kind string, meta metav1.ObjectMeta, selectedNamespaces map[string]bool) error {
+	if meta.Namespace == fromNamespace {
+		return nil
+	}
+
+	if !isNamespaceSelected(selectedNamespaces, meta.Namespace) {
+		return fmt.Errorf("%s %s/%s cannot be referenced from namespace %s: namespace %s is not selected",
+			kind, meta.Namespace, meta.Name, fromNamespace, meta.Namespace)
+	}
+
+	allowedNamespaces, ok := meta.Annotations[annotationAllowedNamespaces]
+	if !ok {
+		if p.AllowCrossNamespace {
+			return nil
+		}
+
+		return fmt.Errorf("%s %s/%s cannot be referenced from namespace %s: cross-namespace references are not allowed",
+			kind, meta.Namespace, meta.Name, fromNamespace)
+	}
+
+	for _, namespace := range strings.Split(allowedNamespaces, ",") {
+		namespace = strings.TrimSpace(namespace)
+		if namespace == "*" || namespace == fromNamespace {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s %s/%s cannot be referenced from namespace %s: namespace not listed in the %s annotation",
+		kind, meta.Namespace, meta.Name, fromNamespace, annotationAllowedNamespaces)
+}
+
 func makeServiceKey(rule, ingressName string) (string, error) {
 	h := sha256.New()
 	if _, err := h.Write([]byte(rule)); err != nil {
@@ -0,0 +1,209 @@
+package crd
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/containous/traefik/pkg/config"
+	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	providerName = "kubernetescrd"
+
+	kindIngressRoute    = "IngressRoute"
+	kindIngressRouteTCP = "IngressRouteTCP"
+)
+
+// resourceStatus holds what the provider knows about the routers built from an IngressRoute or an IngressRouteTCP.
+type resourceStatus struct {
+	// routers are the names of the routers built from the resource.
+	routers []string
+	// errors are the problems found by the provider while building the routers.
+	errors []string
+}
+
+// runtimeRouters holds the errors of the routers of the provider, as reported by the last runtime configuration.
+type runtimeRouters struct {
+	http map[string]string
+	tcp  map[string]string
+}
+
+func makeStatusKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// ListenRuntimeConfiguration collects the errors of the routers built by the provider,
+// and triggers the update of the resources status.
+func (p *Provider) ListenRuntimeConfiguration(conf *config.RuntimeConfiguration) {
+	routers := &runtimeRouters{
+		http: make(map[string]string),
+		tcp:  make(map[string]string),
+	}
+
+	prefix := providerName + "@"
+
+	for name, router := range conf.Routers {
+		if strings.HasPrefix(name, prefix) {
+			routers.http[strings.TrimPrefix(name, prefix)] = router.Err
+		}
+	}
+
+	for name, router := range conf.TCPRouters {
+		if strings.HasPrefix(name, prefix) {
+			routers.tcp[strings.TrimPrefix(name, prefix)] = router.Err
+		}
+	}
+
+	p.runtimeRouters.Set(routers)
+
+	if p.statusUpdates == nil {
+		return
+	}
+
+	select {
+	case p.statusUpdates <- struct{}{}:
+	default:
+		// An update is already pending.
+	}
+}
+
+// updateStatuses writes the conditions of the IngressRoute and IngressRouteTCP resources,
+// only for the resources whose conditions have changed.
+func (p *Provider) updateStatuses(ctx context.Context, client Client) {
+	statuses, _ := p.routeStatuses.Get().(map[string]*resourceStatus)
+	if statuses == nil {
+		return
+	}
+
+	routers, _ := p.runtimeRouters.Get().(*runtimeRouters)
+
+	var httpErrors, tcpErrors map[string]string
+	if routers != nil {
+		httpErrors, tcpErrors = routers.http, routers.tcp
+	}
+
+	now := metav1.Now()
+
+	for _, ingressRoute := range client.GetIngressRoutes() {
+		status, ok := statuses[makeStatusKey(kindIngressRoute, ingressRoute.Namespace, ingressRoute.Name)]
+		if !ok {
+			continue
+		}
+
+		conditions := buildConditions(status, httpErrors, ingressRoute.Status.Conditions, now)
+		if isConditionsEqual(ingressRoute.Status.Conditions, conditions) {
+			continue
+		}
+
+		ingressRouteCopy := ingressRoute.DeepCopy()
+		ingressRouteCopy.Status.Conditions = conditions
+
+		if err := client.UpdateIngressRouteStatus(ingressRouteCopy); err != nil {
+			log.FromContext(ctx).Errorf("Error while updating status of IngressRoute %s/%s: %v", ingressRoute.Namespace, ingressRoute.Name, err)
+		}
+	}
+
+	for _, ingressRouteTCP := range client.GetIngressRouteTCPs() {
+		status, ok := statuses[makeStatusKey(kindIngressRouteTCP, ingressRouteTCP.Namespace, ingressRouteTCP.Name)]
+		if !ok {
+			continue
+		}
+
+		conditions := buildConditions(status, tcpErrors, ingressRouteTCP.Status.Conditions, now)
+		if isConditionsEqual(ingressRouteTCP.Status.Conditions, conditions) {
+			continue
+		}
+
+		ingressRouteTCPCopy := ingressRouteTCP.DeepCopy()
+		ingressRouteTCPCopy.Status.Conditions = conditions
+
+		if err := client.UpdateIngressRouteTCPStatus(ingressRouteTCPCopy); err != nil {
+			log.FromContext(ctx).Errorf("Error while updating status of IngressRouteTCP %s/%s: %v", ingressRouteTCP.Namespace, ingressRouteTCP.Name, err)
+		}
+	}
+}
+
+// buildConditions computes the conditions of a resource.
+// routerErrors are the errors of the routers in the runtime configuration, nil if it is not known yet.
+// The transition time of a condition is kept as long as its status does not change.
+func buildConditions(status *resourceStatus, routerErrors map[string]string, current []v1alpha1.Condition, now metav1.Time) []v1alpha1.Condition {
+	accepted := v1alpha1.Condition{
+		Type:   v1alpha1.ConditionAccepted,
+		Status: corev1.ConditionTrue,
+		Reason: "RoutesBuilt",
+	}
+	if len(status.errors) > 0 {
+		accepted.Status = corev1.ConditionFalse
+		accepted.Reason = "InvalidRoutes"
+		accepted.Message = strings.Join(status.errors, "; ")
+	}
+
+	ready := v1alpha1.Condition{
+		Type:   v1alpha1.ConditionReady,
+		Status: corev1.ConditionTrue,
+		Reason: "RoutersServed",
+	}
+
+	var errs []string
+	pending := routerErrors == nil
+	for _, name := range status.routers {
+		err, ok := routerErrors[name]
+		if !ok {
+			pending = true
+			continue
+		}
+
+		if err != "" {
+			errs = append(errs, providerName+"@"+name+": "+err)
+		}
+	}
+	sort.Strings(errs)
+
+	switch {
+	case len(errs) > 0:
+		ready.Status = corev1.ConditionFalse
+		ready.Reason = "RouterErrors"
+		ready.Message = strings.Join(errs, "; ")
+	case len(status.routers) == 0:
+		ready.Status = corev1.ConditionFalse
+		ready.Reason = "NoRouters"
+		ready.Message = "no router was built from the resource"
+	case pending:
+		ready.Status = corev1.ConditionUnknown
+		ready.Reason = "Pending"
+		ready.Message = "the routers are not served yet"
+	}
+
+	conditions := []v1alpha1.Condition{accepted, ready}
+	for i := range conditions {
+		conditions[i].LastTransitionTime = now
+		for _, condition := range current {
+			if condition.Type == conditions[i].Type && condition.Status == conditions[i].Status {
+				conditions[i].LastTransitionTime = condition.LastTransitionTime
+			}
+		}
+	}
+
+	return conditions
+}
+
+// isConditionsEqual returns true if the given conditions are equal, regardless of their transition time.
+func isConditionsEqual(aConditions, bConditions []v1alpha1.Condition) bool {
+	if len(aConditions) != len(bConditions) {
+		return false
+	}
+
+	for i := range aConditions {
+		a, b := aConditions[i], bConditions[i]
+		if a.Type != b.Type || a.Status != b.Status || a.Reason != b.Reason || a.Message != b.Message {
+			return false
+		}
+	}
+
+	return true
+}
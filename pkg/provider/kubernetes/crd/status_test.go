@@ -0,0 +1,163 @@
+package crd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/pkg/config"
+	"github.com/containous/traefik/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildConditions(t *testing.T) {
+	before := metav1.NewTime(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	now := metav1.NewTime(time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC))
+
+	testCases := []struct {
+		desc         string
+		status       *resourceStatus
+		routerErrors map[string]string
+		current      []v1alpha1.Condition
+		expected     []v1alpha1.Condition
+	}{
+		{
+			desc:         "routers served",
+			status:       &resourceStatus{routers: []string{"default/foo"}},
+			routerErrors: map[string]string{"default/foo": ""},
+			expected: []v1alpha1.Condition{
+				{Type: v1alpha1.ConditionAccepted, Status: corev1.ConditionTrue, Reason: "RoutesBuilt", LastTransitionTime: now},
+				{Type: v1alpha1.ConditionReady, Status: corev1.ConditionTrue, Reason: "RoutersServed", LastTransitionTime: now},
+			},
+		},
+		{
+			desc:         "invalid routes",
+			status:       &resourceStatus{routers: []string{"default/foo"}, errors: []string{"empty match rule", "unsupported match kind: Foo"}},
+			routerErrors: map[string]string{"default/foo": ""},
+			expected: []v1alpha1.Condition{
+				{Type: v1alpha1.ConditionAccepted, Status: corev1.ConditionFalse, Reason: "InvalidRoutes", Message: "empty match rule; unsupported match kind: Foo", LastTransitionTime: now},
+				{Type: v1alpha1.ConditionReady, Status: corev1.ConditionTrue, Reason: "RoutersServed", LastTransitionTime: now},
+			},
+		},
+		{
+			desc:         "router errors",
+			status:       &resourceStatus{routers: []string{"default/foo", "default/bar"}},
+			routerErrors: map[string]string{"default/foo": "the service \"kubernetescrd@default/foo\" does not exist", "default/bar": ""},
+			expected: []v1alpha1.Condition{
+				{Type: v1alpha1.ConditionAccepted, Status: corev1.ConditionTrue, Reason: "RoutesBuilt", LastTransitionTime: now},
+				{Type: v1alpha1.ConditionReady, Status: corev1.ConditionFalse, Reason: "RouterErrors", Message: "kubernetescrd@default/foo: the service \"kubernetescrd@default/foo\" does not exist", LastTransitionTime: now},
+			},
+		},
+		{
+			desc:   "runtime configuration not known yet",
+			status: &resourceStatus{routers: []string{"default/foo"}},
+			expected: []v1alpha1.Condition{
+				{Type: v1alpha1.ConditionAccepted, Status: corev1.ConditionTrue, Reason: "RoutesBuilt", LastTransitionTime: now},
+				{Type: v1alpha1.ConditionReady, Status: corev1.ConditionUnknown, Reason: "Pending", Message: "the routers are not served yet", LastTransitionTime: now},
+			},
+		},
+		{
+			desc:         "router not in the runtime configuration yet",
+			status:       &resourceStatus{routers: []string{"default/foo", "default/bar"}},
+			routerErrors: map[string]string{"default/foo": ""},
+			expected: []v1alpha1.Condition{
+				{Type: v1alpha1.ConditionAccepted, Status: corev1.ConditionTrue, Reason: "RoutesBuilt", LastTransitionTime: now},
+				{Type: v1alpha1.ConditionReady, Status: corev1.ConditionUnknown, Reason: "Pending", Message: "the routers are not served yet", LastTransitionTime: now},
+			},
+		},
+		{
+			desc:         "no routers",
+			status:       &resourceStatus{errors: []string{"empty match rule"}},
+			routerErrors: map[string]string{},
+			expected: []v1alpha1.Condition{
+				{Type: v1alpha1.ConditionAccepted, Status: corev1.ConditionFalse, Reason: "InvalidRoutes", Message: "empty match rule", LastTransitionTime: now},
+				{Type: v1alpha1.ConditionReady, Status: corev1.ConditionFalse, Reason: "NoRouters", Message: "no router was built from the resource", LastTransitionTime: now},
+			},
+		},
+		{
+			desc:         "transition time kept when the status does not change",
+			status:       &resourceStatus{routers: []string{"default/foo"}},
+			routerErrors: map[string]string{"default/foo": "error"},
+			current: []v1alpha1.Condition{
+				{Type: v1alpha1.ConditionAccepted, Status: corev1.ConditionTrue, Reason: "RoutesBuilt", LastTransitionTime: before},
+				{Type: v1alpha1.ConditionReady, Status: corev1.ConditionTrue, Reason: "RoutersServed", LastTransitionTime: before},
+			},
+			expected: []v1alpha1.Condition{
+				{Type: v1alpha1.ConditionAccepted, Status: corev1.ConditionTrue, Reason: "RoutesBuilt", LastTransitionTime: before},
+				{Type: v1alpha1.ConditionReady, Status: corev1.ConditionFalse, Reason: "RouterErrors", Message: "kubernetescrd@default/foo: error", LastTransitionTime: now},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			conditions := buildConditions(test.status, test.routerErrors, test.current, now)
+			assert.Equal(t, test.expected, conditions)
+		})
+	}
+}
+
+func TestUpdateStatuses(t *testing.T) {
+	client := newClientMock("services.yml", "simple.yml", "tcp/services.yml", "tcp/simple.yml")
+	client.updatedStatuses = map[string][]v1alpha1.Condition{}
+
+	p := Provider{UpdateStatus: true}
+	p.SetDefaults()
+	require.NoError(t, p.Init())
+
+	conf := p.loadConfigurationFromIngresses(context.Background(), client)
+	require.Len(t, conf.HTTP.Routers, 1)
+	require.Len(t, conf.TCP.Routers, 1)
+
+	rtConf := &config.RuntimeConfiguration{
+		Routers:    map[string]*config.RouterInfo{},
+		TCPRouters: map[string]*config.TCPRouterInfo{},
+	}
+	for name, router := range conf.HTTP.Routers {
+		rtConf.Routers[providerName+"@"+name] = &config.RouterInfo{Router: router, Err: "router error"}
+	}
+	for name, router := range conf.TCP.Routers {
+		rtConf.TCPRouters[providerName+"@"+name] = &config.TCPRouterInfo{TCPRouter: router}
+	}
+	rtConf.Routers["file@foo"] = &config.RouterInfo{Router: &config.Router{}, Err: "ignored"}
+
+	p.ListenRuntimeConfiguration(rtConf)
+
+	select {
+	case <-p.statusUpdates:
+	default:
+		t.Fatal("a status update should be pending")
+	}
+
+	p.updateStatuses(context.Background(), client)
+
+	require.Len(t, client.updatedStatuses, 2)
+
+	httpConditions := client.updatedStatuses[makeStatusKey(kindIngressRoute, "default", "test.crd")]
+	require.Len(t, httpConditions, 2)
+	assert.Equal(t, corev1.ConditionTrue, httpConditions[0].Status)
+	assert.Equal(t, corev1.ConditionFalse, httpConditions[1].Status)
+	assert.Contains(t, httpConditions[1].Message, "router error")
+
+	tcpConditions := client.updatedStatuses[makeStatusKey(kindIngressRouteTCP, "default", "test.crd")]
+	require.Len(t, tcpConditions, 2)
+	assert.Equal(t, corev1.ConditionTrue, tcpConditions[0].Status)
+	assert.Equal(t, corev1.ConditionTrue, tcpConditions[1].Status)
+
+	// Once written, the same conditions are not written again.
+	client.ingressRoutes[0].Status.Conditions = httpConditions
+	client.ingressRouteTCPs[0].Status.Conditions = tcpConditions
+	for key := range client.updatedStatuses {
+		delete(client.updatedStatuses, key)
+	}
+
+	p.updateStatuses(context.Background(), client)
+
+	assert.Empty(t, client.updatedStatuses)
+}
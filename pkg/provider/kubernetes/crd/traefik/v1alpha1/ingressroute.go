@@ -1,6 +1,9 @@
 package v1alpha1
 
 import (
+	"github.com/containous/traefik/pkg/config"
+	"github.com/containous/traefik/pkg/types"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -13,11 +16,12 @@ type IngressRouteSpec struct {
 
 // Route contains the set of routes.
 type Route struct {
-	Match       string          `json:"match"`
-	Kind        string          `json:"kind"`
-	Priority    int             `json:"priority"`
-	Services    []Service       `json:"services,omitempty"`
-	Middlewares []MiddlewareRef `json:"middlewares"`
+	Match       string                  `json:"match"`
+	Kind        string                  `json:"kind"`
+	Priority    int                     `json:"priority"`
+	Services    []Service               `json:"services,omitempty"`
+	Middlewares []MiddlewareRef         `json:"middlewares"`
+	AccessLog   *config.RouterAccessLog `json:"accessLog,omitempty"`
 }
 
 // TLS contains the TLS certificates configuration of the routes. To enable
@@ -32,14 +36,26 @@ type TLS struct {
 	// certificate details.
 	SecretName string `json:"secretName"`
 	// TODO MinimumProtocolVersion string `json:"minimumProtocolVersion,omitempty"`
+	// CertResolver is the name of the certificates resolver used to obtain
+	// the certificate of the routes.
+	CertResolver string `json:"certResolver,omitempty"`
+	// Domains overrides the domains, parsed from the routes match rules,
+	// of the certificate obtained by the certificates resolver.
+	Domains []types.Domain `json:"domains,omitempty"`
 }
 
 // Service defines an upstream to proxy traffic.
 type Service struct {
-	Name        string       `json:"name"`
+	Name string `json:"name"`
+	// Namespace of the referenced Kubernetes Service, defaults to the
+	// namespace of the IngressRoute.
+	Namespace   string       `json:"namespace,omitempty"`
 	Port        int32        `json:"port"`
 	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
 	Strategy    string       `json:"strategy,omitempty"`
+	// NativeLB routes to the ClusterIP of the Kubernetes Service,
+	// instead of its endpoints.
+	NativeLB bool `json:"nativeLB,omitempty"`
 }
 
 // MiddlewareRef is a ref to the Middleware resources.
@@ -58,6 +74,31 @@ type HealthCheck struct {
 	Headers         map[string]string `json:"headers"`
 }
 
+// IngressRouteStatus is the state of an IngressRoute or an IngressRouteTCP,
+// as observed by Traefik.
+type IngressRouteStatus struct {
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// ConditionType is the type of a Condition.
+type ConditionType string
+
+const (
+	// ConditionAccepted tells whether the provider built all the routes of the resource.
+	ConditionAccepted ConditionType = "Accepted"
+	// ConditionReady tells whether all the routers built from the resource are served.
+	ConditionReady ConditionType = "Ready"
+)
+
+// Condition describes the state of a resource at a certain point.
+type Condition struct {
+	Type               ConditionType          `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+}
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -66,7 +107,8 @@ type IngressRoute struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
 
-	Spec IngressRouteSpec `json:"spec"`
+	Spec   IngressRouteSpec   `json:"spec"`
+	Status IngressRouteStatus `json:"status,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
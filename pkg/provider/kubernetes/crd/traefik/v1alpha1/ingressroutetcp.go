@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	"github.com/containous/traefik/pkg/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -29,12 +30,50 @@ type TLSTCP struct {
 	// certificate details.
 	SecretName  string `json:"secretName"`
 	Passthrough bool   `json:"passthrough"`
+	// CertResolver is the name of the certificates resolver used to obtain
+	// the certificate of the routes.
+	CertResolver string `json:"certResolver,omitempty"`
+	// Domains overrides the domains, parsed from the routes match rules,
+	// of the certificate obtained by the certificates resolver.
+	Domains []types.Domain `json:"domains,omitempty"`
 }
 
 // ServiceTCP defines an upstream to proxy traffic.
 type ServiceTCP struct {
 	Name string `json:"name"`
-	Port int32  `json:"port"`
+	// Namespace of the referenced Kubernetes Service, defaults to the
+	// namespace of the IngressRouteTCP.
+	Namespace string `json:"namespace,omitempty"`
+	Port      int32  `json:"port"`
+	// Weight is the share of the connections sent to this service, relatively
+	// to the other services of the route. Defaults to 1 when a weight is set
+	// on another service of the route.
+	Weight *int `json:"weight,omitempty"`
+	// NativeLB routes to the ClusterIP of the Kubernetes Service,
+	// instead of its endpoints.
+	NativeLB bool `json:"nativeLB,omitempty"`
+	// TLS originates TLS to the servers of the service.
+	TLS *ServiceTCPTLS `json:"tls,omitempty"`
+	// DialTimeoutSeconds is the timeout of the connections to the servers,
+	// defaults to 30 seconds.
+	DialTimeoutSeconds int64 `json:"dialTimeoutSeconds,omitempty"`
+	// KeepAliveSeconds is the TCP keep-alive period of the connections to the
+	// servers, a negative value disables the keep-alives.
+	KeepAliveSeconds int64 `json:"keepAliveSeconds,omitempty"`
+}
+
+// ServiceTCPTLS contains the TLS configuration used to connect to the servers of a service.
+type ServiceTCPTLS struct {
+	// ServerName is used to verify the server certificates, and sent in the
+	// SNI extension. Defaults to the server address.
+	ServerName         string `json:"serverName,omitempty"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty"`
+	// RootCAsSecrets are the names of the Kubernetes Secrets holding, in their
+	// ca.crt entry, the CAs used to verify the server certificates.
+	RootCAsSecrets []string `json:"rootCAsSecrets,omitempty"`
+	// CertificatesSecrets are the names of the Kubernetes Secrets holding the
+	// client certificates presented to the servers.
+	CertificatesSecrets []string `json:"certificatesSecrets,omitempty"`
 }
 
 // +genclient
@@ -45,7 +84,8 @@ type IngressRouteTCP struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
 
-	Spec IngressRouteTCPSpec `json:"spec"`
+	Spec   IngressRouteTCPSpec `json:"spec"`
+	Status IngressRouteStatus  `json:"status,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
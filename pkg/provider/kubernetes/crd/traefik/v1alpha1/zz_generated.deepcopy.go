@@ -29,9 +29,28 @@ THE SOFTWARE.
 package v1alpha1
 
 import (
+	config "github.com/containous/traefik/pkg/config"
+	types "github.com/containous/traefik/pkg/types"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HealthCheck) DeepCopyInto(out *HealthCheck) {
 	*out = *in
@@ -61,6 +80,7 @@ func (in *IngressRoute) DeepCopyInto(out *IngressRoute) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -133,7 +153,7 @@ func (in *IngressRouteSpec) DeepCopyInto(out *IngressRouteSpec) {
 	if in.TLS != nil {
 		in, out := &in.TLS, &out.TLS
 		*out = new(TLS)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -148,12 +168,36 @@ func (in *IngressRouteSpec) DeepCopy() *IngressRouteSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressRouteStatus) DeepCopyInto(out *IngressRouteStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressRouteStatus.
+func (in *IngressRouteStatus) DeepCopy() *IngressRouteStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressRouteStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IngressRouteTCP) DeepCopyInto(out *IngressRouteTCP) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -226,7 +270,7 @@ func (in *IngressRouteTCPSpec) DeepCopyInto(out *IngressRouteTCPSpec) {
 	if in.TLS != nil {
 		in, out := &in.TLS, &out.TLS
 		*out = new(TLSTCP)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -332,6 +376,11 @@ func (in *Route) DeepCopyInto(out *Route) {
 		*out = make([]MiddlewareRef, len(*in))
 		copy(*out, *in)
 	}
+	if in.AccessLog != nil {
+		in, out := &in.AccessLog, &out.AccessLog
+		*out = new(config.RouterAccessLog)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -351,7 +400,9 @@ func (in *RouteTCP) DeepCopyInto(out *RouteTCP) {
 	if in.Services != nil {
 		in, out := &in.Services, &out.Services
 		*out = make([]ServiceTCP, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
@@ -390,6 +441,16 @@ func (in *Service) DeepCopy() *Service {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceTCP) DeepCopyInto(out *ServiceTCP) {
 	*out = *in
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ServiceTCPTLS)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -403,9 +464,42 @@ func (in *ServiceTCP) DeepCopy() *ServiceTCP {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceTCPTLS) DeepCopyInto(out *ServiceTCPTLS) {
+	*out = *in
+	if in.RootCAsSecrets != nil {
+		in, out := &in.RootCAsSecrets, &out.RootCAsSecrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CertificatesSecrets != nil {
+		in, out := &in.CertificatesSecrets, &out.CertificatesSecrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceTCPTLS.
+func (in *ServiceTCPTLS) DeepCopy() *ServiceTCPTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceTCPTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLS) DeepCopyInto(out *TLS) {
 	*out = *in
+	if in.Domains != nil {
+		in, out := &in.Domains, &out.Domains
+		*out = make([]types.Domain, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -422,6 +516,13 @@ func (in *TLS) DeepCopy() *TLS {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSTCP) DeepCopyInto(out *TLSTCP) {
 	*out = *in
+	if in.Domains != nil {
+		in, out := &in.Domains, &out.Domains
+		*out = make([]types.Domain, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -0,0 +1,88 @@
+package ingress
+
+import (
+	"strings"
+
+	"github.com/containous/traefik/pkg/config"
+	"github.com/containous/traefik/pkg/config/label"
+)
+
+const annotationsPrefix = "traefik.ingress.kubernetes.io/"
+
+// RouterConfig is the router's root configuration from annotations.
+type RouterConfig struct {
+	Router *RouterIng `json:"router,omitempty"`
+}
+
+// RouterIng is the router's configuration from annotations.
+type RouterIng struct {
+	EntryPoints []string                `json:"entryPoints,omitempty"`
+	Middlewares []string                `json:"middlewares,omitempty"`
+	Priority    int                     `json:"priority,omitempty"`
+	TLS         *config.RouterTLSConfig `json:"tls,omitempty" label:"allowEmpty"`
+}
+
+// ServiceConfig is the service's root configuration from annotations.
+type ServiceConfig struct {
+	Service *ServiceIng `json:"service,omitempty"`
+}
+
+// ServiceIng is the service's configuration from annotations.
+type ServiceIng struct {
+	Sticky         *config.Stickiness `json:"sticky,omitempty" label:"allowEmpty"`
+	PassHostHeader *bool              `json:"passHostHeader,omitempty"`
+	NativeLB       bool               `json:"nativeLB"`
+}
+
+func parseRouterConfig(annotations map[string]string) (*RouterConfig, error) {
+	labels := convertAnnotations(annotations)
+	if len(labels) == 0 {
+		return nil, nil
+	}
+
+	cfg := &RouterConfig{}
+
+	err := label.Decode(labels, cfg, "traefik.router.")
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func parseServiceConfig(annotations map[string]string) (*ServiceConfig, error) {
+	labels := convertAnnotations(annotations)
+	if len(labels) == 0 {
+		return nil, nil
+	}
+
+	cfg := &ServiceConfig{}
+
+	err := label.Decode(labels, cfg, "traefik.service.")
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// convertAnnotations converts the annotations to labels understood by the label parser,
+// e.g. traefik.ingress.kubernetes.io/router.priority becomes traefik.router.priority.
+func convertAnnotations(annotations map[string]string) map[string]string {
+	if len(annotations) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string)
+
+	for key, value := range annotations {
+		if !strings.HasPrefix(key, annotationsPrefix) {
+			continue
+		}
+
+		newKey := strings.ReplaceAll(key, "ingress.kubernetes.io/", "")
+		result[newKey] = value
+	}
+
+	return result
+}
@@ -0,0 +1,200 @@
+package ingress
+
+import (
+	"testing"
+
+	"github.com/containous/traefik/pkg/config"
+	"github.com/containous/traefik/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseRouterConfig(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		annotations map[string]string
+		expected    *RouterConfig
+	}{
+		{
+			desc: "router annotations",
+			annotations: map[string]string{
+				"ingress.kubernetes.io/foo":                                "bar",
+				"traefik.ingress.kubernetes.io/foo":                        "bar",
+				"traefik.ingress.kubernetes.io/router.entrypoints":         "foobar,foobar",
+				"traefik.ingress.kubernetes.io/router.middlewares":         "foobar,foobar",
+				"traefik.ingress.kubernetes.io/router.priority":            "42",
+				"traefik.ingress.kubernetes.io/router.tls":                 "true",
+				"traefik.ingress.kubernetes.io/router.tls.certresolver":    "foobar",
+				"traefik.ingress.kubernetes.io/router.tls.domains[0].main": "foobar",
+				"traefik.ingress.kubernetes.io/router.tls.domains[0].sans": "foobar,foobar",
+				"traefik.ingress.kubernetes.io/router.tls.options":         "foobar",
+				"traefik.ingress.kubernetes.io/service.sticky":             "true",
+				"traefik.ingress.kubernetes.io/service.unrelatedkey":       "foobar",
+			},
+			expected: &RouterConfig{
+				Router: &RouterIng{
+					EntryPoints: []string{"foobar", "foobar"},
+					Middlewares: []string{"foobar", "foobar"},
+					Priority:    42,
+					TLS: &config.RouterTLSConfig{
+						CertResolver: "foobar",
+						Domains: []types.Domain{
+							{
+								Main: "foobar",
+								SANs: []string{"foobar", "foobar"},
+							},
+						},
+						Options: "foobar",
+					},
+				},
+			},
+		},
+		{
+			desc: "router TLS without options",
+			annotations: map[string]string{
+				"traefik.ingress.kubernetes.io/router.tls": "true",
+			},
+			expected: &RouterConfig{
+				Router: &RouterIng{
+					TLS: &config.RouterTLSConfig{},
+				},
+			},
+		},
+		{
+			desc:        "empty map",
+			annotations: map[string]string{},
+		},
+		{
+			desc: "nil map",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := parseRouterConfig(test.annotations)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, cfg)
+		})
+	}
+}
+
+func Test_parseServiceConfig(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		annotations map[string]string
+		expected    *ServiceConfig
+	}{
+		{
+			desc: "service annotations",
+			annotations: map[string]string{
+				"ingress.kubernetes.io/foo":                                   "bar",
+				"traefik.ingress.kubernetes.io/foo":                           "bar",
+				"traefik.ingress.kubernetes.io/router.priority":               "42",
+				"traefik.ingress.kubernetes.io/service.passhostheader":        "false",
+				"traefik.ingress.kubernetes.io/service.nativelb":              "true",
+				"traefik.ingress.kubernetes.io/service.sticky.cookiename":     "foobar",
+				"traefik.ingress.kubernetes.io/service.sticky.httponlycookie": "true",
+			},
+			expected: &ServiceConfig{
+				Service: &ServiceIng{
+					Sticky: &config.Stickiness{
+						CookieName:     "foobar",
+						HTTPOnlyCookie: true,
+					},
+					PassHostHeader: boolPtr(false),
+					NativeLB:       true,
+				},
+			},
+		},
+		{
+			desc: "sticky without options",
+			annotations: map[string]string{
+				"traefik.ingress.kubernetes.io/service.sticky": "true",
+			},
+			expected: &ServiceConfig{
+				Service: &ServiceIng{
+					Sticky: &config.Stickiness{},
+				},
+			},
+		},
+		{
+			desc:        "empty map",
+			annotations: map[string]string{},
+		},
+		{
+			desc: "nil map",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := parseServiceConfig(test.annotations)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, cfg)
+		})
+	}
+}
+
+func Test_convertAnnotations(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		annotations map[string]string
+		expected    map[string]string
+	}{
+		{
+			desc: "router annotations",
+			annotations: map[string]string{
+				"ingress.kubernetes.io/foo":                        "bar",
+				"traefik.ingress.kubernetes.io/foo":                "bar",
+				"traefik.ingress.kubernetes.io/router.entrypoints": "foobar,foobar",
+				"traefik.ingress.kubernetes.io/router.priority":    "42",
+			},
+			expected: map[string]string{
+				"traefik.foo":                "bar",
+				"traefik.router.entrypoints": "foobar,foobar",
+				"traefik.router.priority":    "42",
+			},
+		},
+		{
+			desc: "service annotations",
+			annotations: map[string]string{
+				"traefik.ingress.kubernetes.io/service.passhostheader": "true",
+				"traefik.ingress.kubernetes.io/service.sticky":         "true",
+			},
+			expected: map[string]string{
+				"traefik.service.passhostheader": "true",
+				"traefik.service.sticky":         "true",
+			},
+		},
+		{
+			desc:        "empty map",
+			annotations: map[string]string{},
+		},
+		{
+			desc: "nil map",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			labels := convertAnnotations(test.annotations)
+
+			assert.Equal(t, test.expected, labels)
+		})
+	}
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
@@ -46,7 +46,7 @@ type Client interface {
 	GetService(namespace, name string) (*corev1.Service, bool, error)
 	GetSecret(namespace, name string) (*corev1.Secret, bool, error)
 	GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error)
-	UpdateIngressStatus(namespace, name, ip, hostname string) error
+	UpdateIngressStatus(ing *extensionsv1beta1.Ingress, ingStatus []corev1.LoadBalancerIngress) error
 }
 
 type clientWrapper struct {
@@ -178,31 +178,30 @@ func (c *clientWrapper) GetIngresses() []*extensionsv1beta1.Ingress {
 }
 
 // UpdateIngressStatus updates an Ingress with a provided status.
-func (c *clientWrapper) UpdateIngressStatus(namespace, name, ip, hostname string) error {
-	if !c.isWatchedNamespace(namespace) {
-		return fmt.Errorf("failed to get ingress %s/%s: namespace is not within watched namespaces", namespace, name)
+func (c *clientWrapper) UpdateIngressStatus(src *extensionsv1beta1.Ingress, ingStatus []corev1.LoadBalancerIngress) error {
+	if !c.isWatchedNamespace(src.Namespace) {
+		return fmt.Errorf("failed to get ingress %s/%s: namespace is not within watched namespaces", src.Namespace, src.Name)
 	}
 
-	ing, err := c.factories[c.lookupNamespace(namespace)].Extensions().V1beta1().Ingresses().Lister().Ingresses(namespace).Get(name)
+	ing, err := c.factories[c.lookupNamespace(src.Namespace)].Extensions().V1beta1().Ingresses().Lister().Ingresses(src.Namespace).Get(src.Name)
 	if err != nil {
-		return fmt.Errorf("failed to get ingress %s/%s: %v", namespace, name, err)
+		return fmt.Errorf("failed to get ingress %s/%s: %v", src.Namespace, src.Name, err)
 	}
 
-	if len(ing.Status.LoadBalancer.Ingress) > 0 {
-		if ing.Status.LoadBalancer.Ingress[0].Hostname == hostname && ing.Status.LoadBalancer.Ingress[0].IP == ip {
-			// If status is already set, skip update
-			log.Debugf("Skipping status update on ingress %s/%s", ing.Namespace, ing.Name)
-			return nil
-		}
+	if isLoadBalancerIngressEquals(ing.Status.LoadBalancer.Ingress, ingStatus) {
+		// If status is already set, skip update
+		log.Debugf("Skipping status update on ingress %s/%s", ing.Namespace, ing.Name)
+		return nil
 	}
+
 	ingCopy := ing.DeepCopy()
-	ingCopy.Status = extensionsv1beta1.IngressStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: ip, Hostname: hostname}}}}
+	ingCopy.Status = extensionsv1beta1.IngressStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: ingStatus}}
 
 	_, err = c.clientset.ExtensionsV1beta1().Ingresses(ingCopy.Namespace).UpdateStatus(ingCopy)
 	if err != nil {
-		return fmt.Errorf("failed to update ingress status %s/%s: %v", namespace, name, err)
+		return fmt.Errorf("failed to update ingress status %s/%s: %v", src.Namespace, src.Name, err)
 	}
-	log.Infof("Updated status on ingress %s/%s", namespace, name)
+	log.Infof("Updated status on ingress %s/%s", src.Namespace, src.Name)
 	return nil
 }
 
@@ -298,3 +297,27 @@ func (c *clientWrapper) isWatchedNamespace(ns string) bool {
 	}
 	return false
 }
+
+// isLoadBalancerIngressEquals returns true if the given slices are equal, regardless of their order.
+func isLoadBalancerIngressEquals(aSlice, bSlice []corev1.LoadBalancerIngress) bool {
+	if len(aSlice) != len(bSlice) {
+		return false
+	}
+
+	type address struct{ ip, hostname string }
+
+	counts := make(map[address]int)
+	for _, aIngress := range aSlice {
+		counts[address{ip: aIngress.IP, hostname: aIngress.Hostname}]++
+	}
+
+	for _, bIngress := range bSlice {
+		key := address{ip: bIngress.IP, hostname: bIngress.Hostname}
+		if counts[key] == 0 {
+			return false
+		}
+		counts[key]--
+	}
+
+	return true
+}
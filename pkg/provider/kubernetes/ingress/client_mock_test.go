@@ -23,6 +23,9 @@ type clientMock struct {
 	apiEndpointsError     error
 	apiIngressStatusError error
 
+	// updatedStatuses records the statuses written by UpdateIngressStatus, by ingress namespace/name.
+	updatedStatuses map[string][]corev1.LoadBalancerIngress
+
 	watchChan chan interface{}
 }
 
@@ -103,6 +106,13 @@ func (c clientMock) WatchAll(namespaces []string, stopCh <-chan struct{}) (<-cha
 	return c.watchChan, nil
 }
 
-func (c clientMock) UpdateIngressStatus(namespace, name, ip, hostname string) error {
-	return c.apiIngressStatusError
+func (c clientMock) UpdateIngressStatus(ing *extensionsv1beta1.Ingress, ingStatus []corev1.LoadBalancerIngress) error {
+	if c.apiIngressStatusError != nil {
+		return c.apiIngressStatusError
+	}
+
+	if c.updatedStatuses != nil {
+		c.updatedStatuses[ing.Namespace+"/"+ing.Name] = ingStatus
+	}
+	return nil
 }
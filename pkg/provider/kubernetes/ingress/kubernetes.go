@@ -17,6 +17,7 @@ import (
 	"github.com/containous/traefik/pkg/config"
 	"github.com/containous/traefik/pkg/job"
 	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/provider/kubernetes/k8s"
 	"github.com/containous/traefik/pkg/safe"
 	"github.com/containous/traefik/pkg/tls"
 	corev1 "k8s.io/api/core/v1"
@@ -174,7 +175,11 @@ func loadService(client Client, namespace string, backend v1beta1.IngressBackend
 		return nil, errors.New("service not found")
 	}
 
-	var servers []config.Server
+	svcConfig, err := parseServiceConfig(service.Annotations)
+	if err != nil {
+		return nil, err
+	}
+
 	var portName string
 	var portSpec corev1.ServicePort
 	var match bool
@@ -188,15 +193,31 @@ func loadService(client Client, namespace string, backend v1beta1.IngressBackend
 		}
 	}
 
+	if !match && service.Spec.Type == corev1.ServiceTypeExternalName && backend.ServicePort.Type == intstr.Int {
+		// An ExternalName service does not need to declare its ports.
+		portSpec = corev1.ServicePort{Port: backend.ServicePort.IntVal}
+		match = true
+	}
+
 	if !match {
 		return nil, errors.New("service port not found")
 	}
 
-	if service.Spec.Type == corev1.ServiceTypeExternalName {
+	var servers []config.Server
+	switch {
+	case service.Spec.Type == corev1.ServiceTypeExternalName:
 		servers = append(servers, config.Server{
 			URL: fmt.Sprintf("http://%s:%d", service.Spec.ExternalName, portSpec.Port),
 		})
-	} else {
+	case svcConfig != nil && svcConfig.Service != nil && svcConfig.Service.NativeLB:
+		if service.Spec.ClusterIP == "" || service.Spec.ClusterIP == corev1.ClusterIPNone {
+			return nil, fmt.Errorf("no clusterIP found for service %s/%s", service.Namespace, service.Name)
+		}
+
+		servers = append(servers, config.Server{
+			URL: fmt.Sprintf("%s://%s:%d", k8s.GetProtocol(portName, portSpec.Port), service.Spec.ClusterIP, portSpec.Port),
+		})
+	default:
 		endpoints, endpointsExists, endpointsErr := client.GetEndpoints(namespace, backend.ServiceName)
 		if endpointsErr != nil {
 			return nil, endpointsErr
@@ -224,10 +245,7 @@ func loadService(client Client, namespace string, backend v1beta1.IngressBackend
 				return nil, errors.New("cannot define a port")
 			}
 
-			protocol := "http"
-			if port == 443 || strings.HasPrefix(portName, "https") {
-				protocol = "https"
-			}
+			protocol := k8s.GetProtocol(portName, port)
 
 			for _, addr := range subset.Addresses {
 				servers = append(servers, config.Server{
@@ -237,12 +255,19 @@ func loadService(client Client, namespace string, backend v1beta1.IngressBackend
 		}
 	}
 
-	return &config.Service{
-		LoadBalancer: &config.LoadBalancerService{
-			Servers:        servers,
-			PassHostHeader: true,
-		},
-	}, nil
+	lb := &config.LoadBalancerService{
+		Servers:        servers,
+		PassHostHeader: true,
+	}
+
+	if svcConfig != nil && svcConfig.Service != nil {
+		lb.Stickiness = svcConfig.Service.Sticky
+		if svcConfig.Service.PassHostHeader != nil {
+			lb.PassHostHeader = *svcConfig.Service.PassHostHeader
+		}
+	}
+
+	return &config.Service{LoadBalancer: lb}, nil
 }
 
 func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Client) *config.Configuration {
@@ -265,7 +290,19 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 			continue
 		}
 
-		err := getTLS(ctx, ingress, client, tlsConfigs)
+		// The status is published for every ingress handled by Traefik, even when some of its backends cannot be loaded.
+		err := p.updateIngressStatus(ingress, client)
+		if err != nil {
+			log.FromContext(ctx).Errorf("Error while updating ingress status: %v", err)
+		}
+
+		rtConfig, err := parseRouterConfig(ingress.Annotations)
+		if err != nil {
+			log.FromContext(ctx).Errorf("Failed to parse annotations: %v", err)
+			continue
+		}
+
+		err = getTLS(ctx, ingress, client, tlsConfigs)
 		if err != nil {
 			log.FromContext(ctx).Errorf("Error configuring TLS: %v", err)
 		}
@@ -286,12 +323,20 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 					continue
 				}
 
-				conf.HTTP.Routers["/"] = &config.Router{
+				rt := &config.Router{
 					Rule:     "PathPrefix(`/`)",
 					Priority: math.MinInt32,
 					Service:  "default-backend",
 				}
 
+				if rtConfig != nil && rtConfig.Router != nil {
+					rt.EntryPoints = rtConfig.Router.EntryPoints
+					rt.Middlewares = rtConfig.Router.Middlewares
+					rt.TLS = rtConfig.Router.TLS
+				}
+
+				conf.HTTP.Routers["/"] = rt
+
 				conf.HTTP.Services["default-backend"] = service
 			}
 		}
@@ -327,17 +372,22 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 					rules = append(rules, "PathPrefix(`"+p.Path+"`)")
 				}
 
-				conf.HTTP.Routers[strings.Replace(rule.Host, ".", "-", -1)+p.Path] = &config.Router{
+				rt := &config.Router{
 					Rule:    strings.Join(rules, " && "),
 					Service: serviceName,
 				}
 
+				if rtConfig != nil && rtConfig.Router != nil {
+					rt.EntryPoints = rtConfig.Router.EntryPoints
+					rt.Middlewares = rtConfig.Router.Middlewares
+					rt.Priority = rtConfig.Router.Priority
+					rt.TLS = rtConfig.Router.TLS
+				}
+
+				conf.HTTP.Routers[strings.Replace(rule.Host, ".", "-", -1)+p.Path] = rt
+
 				conf.HTTP.Services[serviceName] = service
 			}
-			err := p.updateIngressStatus(ingress, client)
-			if err != nil {
-				log.FromContext(ctx).Errorf("Error while updating ingress status: %v", err)
-			}
 		}
 	}
 
@@ -446,7 +496,8 @@ func (p *Provider) updateIngressStatus(i *v1beta1.Ingress, k8sClient Client) err
 			return errors.New("publishedService or ip or hostname must be defined")
 		}
 
-		return k8sClient.UpdateIngressStatus(i.Namespace, i.Name, p.IngressEndpoint.IP, p.IngressEndpoint.Hostname)
+		ingStatus := []corev1.LoadBalancerIngress{{IP: p.IngressEndpoint.IP, Hostname: p.IngressEndpoint.Hostname}}
+		return k8sClient.UpdateIngressStatus(i, ingStatus)
 	}
 
 	serviceInfo := strings.Split(p.IngressEndpoint.PublishedService, "/")
@@ -460,7 +511,7 @@ func (p *Provider) updateIngressStatus(i *v1beta1.Ingress, k8sClient Client) err
 		return fmt.Errorf("cannot get service %s, received error: %s", p.IngressEndpoint.PublishedService, err)
 	}
 
-	if exists && service.Status.LoadBalancer.Ingress == nil {
+	if exists && len(service.Status.LoadBalancer.Ingress) == 0 {
 		// service exists, but has no Load Balancer status
 		log.Debugf("Skipping updating Ingress %s/%s due to service %s having no status set", i.Namespace, i.Name, p.IngressEndpoint.PublishedService)
 		return nil
@@ -470,5 +521,5 @@ func (p *Provider) updateIngressStatus(i *v1beta1.Ingress, k8sClient Client) err
 		return fmt.Errorf("missing service: %s", p.IngressEndpoint.PublishedService)
 	}
 
-	return k8sClient.UpdateIngressStatus(i.Namespace, i.Name, service.Status.LoadBalancer.Ingress[0].IP, service.Status.LoadBalancer.Ingress[0].Hostname)
+	return k8sClient.UpdateIngressStatus(i, service.Status.LoadBalancer.Ingress)
 }
@@ -15,6 +15,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 var _ provider.Provider = (*Provider)(nil)
@@ -66,6 +67,46 @@ func TestLoadConfigurationFromIngresses(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "Ingress with annotations",
+			expected: &config.Configuration{
+				TCP: &config.TCPConfiguration{},
+				HTTP: &config.HTTPConfiguration{
+					Middlewares: map[string]*config.Middleware{},
+					Routers: map[string]*config.Router{
+						"/bar": {
+							EntryPoints: []string{"ep1", "ep2"},
+							Middlewares: []string{"md1", "kubernetescrd@default/md2"},
+							Priority:    42,
+							Rule:        "PathPrefix(`/bar`)",
+							Service:     "testing/service1/80",
+							TLS: &config.RouterTLSConfig{
+								Options: "foobar",
+							},
+						},
+					},
+					Services: map[string]*config.Service{
+						"testing/service1/80": {
+							LoadBalancer: &config.LoadBalancerService{
+								PassHostHeader: false,
+								Stickiness: &config.Stickiness{
+									CookieName:   "foobar",
+									SecureCookie: true,
+								},
+								Servers: []config.Server{
+									{
+										URL: "http://10.10.0.1:8080",
+									},
+									{
+										URL: "http://10.21.0.1:8080",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			desc: "Ingress with two different rules with one path",
 			expected: &config.Configuration{
@@ -677,6 +718,98 @@ func TestLoadConfigurationFromIngresses(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "Ingress with https service with externalName",
+			expected: &config.Configuration{
+				TCP: &config.TCPConfiguration{},
+				HTTP: &config.HTTPConfiguration{
+					Middlewares: map[string]*config.Middleware{},
+					Routers: map[string]*config.Router{
+						"traefik-tchouk/bar": {
+							Rule:    "Host(`traefik.tchouk`) && PathPrefix(`/bar`)",
+							Service: "testing/service1/443",
+						},
+					},
+					Services: map[string]*config.Service{
+						"testing/service1/443": {
+							LoadBalancer: &config.LoadBalancerService{
+								PassHostHeader: true,
+								Servers: []config.Server{
+									{
+										URL: "http://traefik.wtf:443",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "Ingress with externalName service without ports",
+			expected: &config.Configuration{
+				TCP: &config.TCPConfiguration{},
+				HTTP: &config.HTTPConfiguration{
+					Middlewares: map[string]*config.Middleware{},
+					Routers: map[string]*config.Router{
+						"traefik-tchouk/bar": {
+							Rule:    "Host(`traefik.tchouk`) && PathPrefix(`/bar`)",
+							Service: "testing/service1/8080",
+						},
+					},
+					Services: map[string]*config.Service{
+						"testing/service1/8080": {
+							LoadBalancer: &config.LoadBalancerService{
+								PassHostHeader: true,
+								Servers: []config.Server{
+									{
+										URL: "http://traefik.wtf:8080",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "Ingress with native service",
+			expected: &config.Configuration{
+				TCP: &config.TCPConfiguration{},
+				HTTP: &config.HTTPConfiguration{
+					Middlewares: map[string]*config.Middleware{},
+					Routers: map[string]*config.Router{
+						"traefik-tchouk/bar": {
+							Rule:    "Host(`traefik.tchouk`) && PathPrefix(`/bar`)",
+							Service: "testing/service1/8080",
+						},
+					},
+					Services: map[string]*config.Service{
+						"testing/service1/8080": {
+							LoadBalancer: &config.LoadBalancerService{
+								PassHostHeader: true,
+								Servers: []config.Server{
+									{
+										URL: "http://10.0.0.1:8080",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "Ingress with native headless service",
+			expected: &config.Configuration{
+				TCP: &config.TCPConfiguration{},
+				HTTP: &config.HTTPConfiguration{
+					Middlewares: map[string]*config.Middleware{},
+					Routers:     map[string]*config.Router{},
+					Services:    map[string]*config.Service{},
+				},
+			},
+		},
 		{
 			desc: "TLS support",
 			expected: &config.Configuration{
@@ -1120,3 +1253,132 @@ func TestGetTLS(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateIngressStatus(t *testing.T) {
+	ingress := &v1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "testing",
+		},
+	}
+
+	publishedService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "traefik",
+			Namespace: "kube-system",
+		},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{
+					{IP: "10.0.0.1"},
+					{Hostname: "lb.example.com"},
+				},
+			},
+		},
+	}
+
+	pendingService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pending",
+			Namespace: "kube-system",
+		},
+	}
+
+	testCases := []struct {
+		desc             string
+		ingressEndpoint  *EndpointIngress
+		expectedStatuses map[string][]corev1.LoadBalancerIngress
+		expectedError    string
+	}{
+		{
+			desc:             "no ingress endpoint",
+			expectedStatuses: map[string][]corev1.LoadBalancerIngress{},
+		},
+		{
+			desc:             "empty ingress endpoint",
+			ingressEndpoint:  &EndpointIngress{},
+			expectedStatuses: map[string][]corev1.LoadBalancerIngress{},
+			expectedError:    "publishedService or ip or hostname must be defined",
+		},
+		{
+			desc:            "static IP and hostname",
+			ingressEndpoint: &EndpointIngress{IP: "1.2.3.4", Hostname: "traefik.example.com"},
+			expectedStatuses: map[string][]corev1.LoadBalancerIngress{
+				"testing/foo": {{IP: "1.2.3.4", Hostname: "traefik.example.com"}},
+			},
+		},
+		{
+			desc:            "published service with several addresses",
+			ingressEndpoint: &EndpointIngress{PublishedService: "kube-system/traefik"},
+			expectedStatuses: map[string][]corev1.LoadBalancerIngress{
+				"testing/foo": {{IP: "10.0.0.1"}, {Hostname: "lb.example.com"}},
+			},
+		},
+		{
+			desc:             "published service without load balancer status",
+			ingressEndpoint:  &EndpointIngress{PublishedService: "kube-system/pending"},
+			expectedStatuses: map[string][]corev1.LoadBalancerIngress{},
+		},
+		{
+			desc:             "missing published service",
+			ingressEndpoint:  &EndpointIngress{PublishedService: "kube-system/missing"},
+			expectedStatuses: map[string][]corev1.LoadBalancerIngress{},
+			expectedError:    "missing service: kube-system/missing",
+		},
+		{
+			desc:             "invalid published service",
+			ingressEndpoint:  &EndpointIngress{PublishedService: "traefik"},
+			expectedStatuses: map[string][]corev1.LoadBalancerIngress{},
+			expectedError:    "invalid publishedService format (expected 'namespace/service' format): traefik",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			client := clientMock{
+				services:        []*corev1.Service{publishedService, pendingService},
+				updatedStatuses: map[string][]corev1.LoadBalancerIngress{},
+			}
+
+			p := Provider{IngressEndpoint: test.ingressEndpoint}
+
+			err := p.updateIngressStatus(ingress, client)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectedStatuses, client.updatedStatuses)
+		})
+	}
+}
+
+func TestLoadConfigurationFromIngressesUpdatesStatus(t *testing.T) {
+	withRules := buildIngress(iNamespace("testing"), iRules(iRule(iHost("foo.example.com")), iRule(iHost("bar.example.com"))))
+	withRules.Name = "with-rules"
+	for i := range withRules.Spec.Rules {
+		withRules.Spec.Rules[i].HTTP = &v1beta1.HTTPIngressRuleValue{}
+	}
+
+	withDefaultBackend := buildIngress(iNamespace("testing"))
+	withDefaultBackend.Name = "with-default-backend"
+	withDefaultBackend.Spec.Backend = &v1beta1.IngressBackend{ServiceName: "missing", ServicePort: intstr.FromInt(80)}
+
+	client := clientMock{
+		ingresses:       []*v1beta1.Ingress{withRules, withDefaultBackend},
+		updatedStatuses: map[string][]corev1.LoadBalancerIngress{},
+	}
+
+	p := Provider{IngressEndpoint: &EndpointIngress{IP: "1.2.3.4"}}
+	p.loadConfigurationFromIngresses(context.Background(), client)
+
+	expected := map[string][]corev1.LoadBalancerIngress{
+		"testing/with-rules":           {{IP: "1.2.3.4"}},
+		"testing/with-default-backend": {{IP: "1.2.3.4"}},
+	}
+	assert.Equal(t, expected, client.updatedStatuses)
+}
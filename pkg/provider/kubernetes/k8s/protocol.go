@@ -0,0 +1,11 @@
+package k8s
+
+import "strings"
+
+// GetProtocol returns the protocol used to reach a service port: https for the port 443 or a port named https*, http otherwise.
+func GetProtocol(portName string, port int32) string {
+	if port == 443 || strings.HasPrefix(portName, "https") {
+		return "https"
+	}
+	return "http"
+}
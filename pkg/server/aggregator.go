@@ -9,9 +9,10 @@ import (
 func mergeConfiguration(configurations config.Configurations) config.Configuration {
 	conf := config.Configuration{
 		HTTP: &config.HTTPConfiguration{
-			Routers:     make(map[string]*config.Router),
-			Middlewares: make(map[string]*config.Middleware),
-			Services:    make(map[string]*config.Service),
+			Routers:           make(map[string]*config.Router),
+			Middlewares:       make(map[string]*config.Middleware),
+			Services:          make(map[string]*config.Service),
+			ServersTransports: make(map[string]*config.ServersTransport),
 		},
 		TCP: &config.TCPConfiguration{
 			Routers:  make(map[string]*config.TCPRouter),
@@ -32,6 +33,9 @@ func mergeConfiguration(configurations config.Configurations) config.Configurati
 			for serviceName, service := range configuration.HTTP.Services {
 				conf.HTTP.Services[internal.MakeQualifiedName(provider, serviceName)] = service
 			}
+			for serversTransportName, serversTransport := range configuration.HTTP.ServersTransports {
+				conf.HTTP.ServersTransports[internal.MakeQualifiedName(provider, serversTransportName)] = serversTransport
+			}
 		}
 
 		if configuration.TCP != nil {
@@ -42,7 +46,12 @@ func mergeConfiguration(configurations config.Configurations) config.Configurati
 				conf.TCP.Services[internal.MakeQualifiedName(provider, serviceName)] = service
 			}
 		}
-		conf.TLS = append(conf.TLS, configuration.TLS...)
+		for _, tlsConf := range configuration.TLS {
+			// The configuration of the provider must not be modified.
+			cert := *tlsConf
+			cert.Provider = provider
+			conf.TLS = append(conf.TLS, &cert)
+		}
 
 		for key, store := range configuration.TLSStores {
 			conf.TLSStores[key] = store
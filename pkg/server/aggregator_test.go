@@ -17,9 +17,10 @@ func TestAggregator(t *testing.T) {
 			desc:  "Nil returns an empty configuration",
 			given: nil,
 			expected: &config.HTTPConfiguration{
-				Routers:     make(map[string]*config.Router),
-				Middlewares: make(map[string]*config.Middleware),
-				Services:    make(map[string]*config.Service),
+				Routers:           make(map[string]*config.Router),
+				Middlewares:       make(map[string]*config.Middleware),
+				Services:          make(map[string]*config.Service),
+				ServersTransports: make(map[string]*config.ServersTransport),
 			},
 		},
 		{
@@ -49,6 +50,7 @@ func TestAggregator(t *testing.T) {
 				Services: map[string]*config.Service{
 					"provider-1@service-1": {},
 				},
+				ServersTransports: make(map[string]*config.ServersTransport),
 			},
 		},
 		{
@@ -78,6 +80,9 @@ func TestAggregator(t *testing.T) {
 						Services: map[string]*config.Service{
 							"service-1": {},
 						},
+						ServersTransports: map[string]*config.ServersTransport{
+							"transport-1": {},
+						},
 					},
 				},
 			},
@@ -94,6 +99,9 @@ func TestAggregator(t *testing.T) {
 					"provider-1@service-1": {},
 					"provider-2@service-1": {},
 				},
+				ServersTransports: map[string]*config.ServersTransport{
+					"provider-2@transport-1": {},
+				},
 			},
 		},
 	}
@@ -11,6 +11,7 @@ type contextKey int
 
 const (
 	providerKey contextKey = iota
+	serviceKey
 )
 
 // AddProviderInContext Adds the provider name in the context
@@ -28,6 +29,17 @@ func AddProviderInContext(ctx context.Context, elementName string) context.Conte
 	return context.WithValue(ctx, providerKey, parts[0])
 }
 
+// AddServiceInContext Adds the name of the service of the router in the context
+func AddServiceInContext(ctx context.Context, serviceName string) context.Context {
+	return context.WithValue(ctx, serviceKey, serviceName)
+}
+
+// GetServiceName Gets the name of the service of the router from the context
+func GetServiceName(ctx context.Context) (string, bool) {
+	serviceName, ok := ctx.Value(serviceKey).(string)
+	return serviceName, ok
+}
+
 // GetQualifiedName Gets the fully qualified name.
 func GetQualifiedName(ctx context.Context, elementName string) string {
 	parts := strings.Split(elementName, "@")
@@ -9,6 +9,8 @@ import (
 
 	"github.com/containous/alice"
 	"github.com/containous/traefik/pkg/config"
+	"github.com/containous/traefik/pkg/metrics"
+	"github.com/containous/traefik/pkg/middlewares/accesslog"
 	"github.com/containous/traefik/pkg/middlewares/addprefix"
 	"github.com/containous/traefik/pkg/middlewares/auth"
 	"github.com/containous/traefik/pkg/middlewares/buffering"
@@ -19,6 +21,7 @@ import (
 	"github.com/containous/traefik/pkg/middlewares/headers"
 	"github.com/containous/traefik/pkg/middlewares/ipwhitelist"
 	"github.com/containous/traefik/pkg/middlewares/maxconnection"
+	metricsmiddleware "github.com/containous/traefik/pkg/middlewares/metrics"
 	"github.com/containous/traefik/pkg/middlewares/passtlsclientcert"
 	"github.com/containous/traefik/pkg/middlewares/ratelimiter"
 	"github.com/containous/traefik/pkg/middlewares/redirect"
@@ -39,8 +42,9 @@ const (
 
 // Builder the middleware builder
 type Builder struct {
-	configs        map[string]*config.MiddlewareInfo
-	serviceBuilder serviceBuilder
+	configs         map[string]*config.MiddlewareInfo
+	serviceBuilder  serviceBuilder
+	metricsRegistry metrics.Registry
 }
 
 type serviceBuilder interface {
@@ -48,8 +52,8 @@ type serviceBuilder interface {
 }
 
 // NewBuilder creates a new Builder
-func NewBuilder(configs map[string]*config.MiddlewareInfo, serviceBuilder serviceBuilder) *Builder {
-	return &Builder{configs: configs, serviceBuilder: serviceBuilder}
+func NewBuilder(configs map[string]*config.MiddlewareInfo, serviceBuilder serviceBuilder, metricsRegistry metrics.Registry) *Builder {
+	return &Builder{configs: configs, serviceBuilder: serviceBuilder, metricsRegistry: metricsRegistry}
 }
 
 // BuildChain creates a middleware chain
@@ -88,6 +92,18 @@ func (b *Builder) BuildChain(ctx context.Context, middlewares []string) *alice.C
 	return &chain
 }
 
+// buildRetryListeners returns the listeners of the retries of the service of the router being built.
+func (b *Builder) buildRetryListeners(ctx context.Context) retry.Listeners {
+	listeners := retry.Listeners{&accesslog.SaveRetries{}}
+
+	serviceName, ok := internal.GetServiceName(ctx)
+	if ok && b.metricsRegistry != nil && b.metricsRegistry.IsEnabled() {
+		listeners = append(listeners, metricsmiddleware.NewRetryListener(b.metricsRegistry, serviceName))
+	}
+
+	return listeners
+}
+
 func checkRecursion(ctx context.Context, middlewareName string) (context.Context, error) {
 	currentStack, ok := ctx.Value(middlewareStackKey).([]string)
 	if !ok {
@@ -288,8 +304,7 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 			return nil, badConf
 		}
 		middleware = func(next http.Handler) (http.Handler, error) {
-			// FIXME missing metrics / accessLog
-			return retry.New(ctx, next, *config.Retry, retry.Listeners{}, middlewareName)
+			return retry.New(ctx, next, *config.Retry, b.buildRetryListeners(ctx), middlewareName)
 		}
 	}
 
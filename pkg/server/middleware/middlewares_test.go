@@ -17,7 +17,7 @@ func TestBuilder_BuildChainNilConfig(t *testing.T) {
 	testConfig := map[string]*config.MiddlewareInfo{
 		"empty": {},
 	}
-	middlewaresBuilder := NewBuilder(testConfig, nil)
+	middlewaresBuilder := NewBuilder(testConfig, nil, nil)
 
 	chain := middlewaresBuilder.BuildChain(context.Background(), []string{"empty"})
 	_, err := chain.Then(nil)
@@ -28,7 +28,7 @@ func TestBuilder_BuildChainNonExistentChain(t *testing.T) {
 	testConfig := map[string]*config.MiddlewareInfo{
 		"foobar": {},
 	}
-	middlewaresBuilder := NewBuilder(testConfig, nil)
+	middlewaresBuilder := NewBuilder(testConfig, nil, nil)
 
 	chain := middlewaresBuilder.BuildChain(context.Background(), []string{"empty"})
 	_, err := chain.Then(nil)
@@ -269,7 +269,7 @@ func TestBuilder_BuildChainWithContext(t *testing.T) {
 					Middlewares: test.configuration,
 				},
 			})
-			builder := NewBuilder(rtConf.Middlewares, nil)
+			builder := NewBuilder(rtConf.Middlewares, nil, nil)
 
 			result := builder.BuildChain(ctx, test.buildChain)
 
@@ -320,7 +320,7 @@ func TestBuilder_buildConstructor(t *testing.T) {
 			Middlewares: testConfig,
 		},
 	})
-	middlewaresBuilder := NewBuilder(rtConf.Middlewares, nil)
+	middlewaresBuilder := NewBuilder(rtConf.Middlewares, nil, nil)
 
 	testCases := []struct {
 		desc          string
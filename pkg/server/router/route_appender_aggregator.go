@@ -10,6 +10,8 @@ import (
 	"github.com/containous/traefik/pkg/config/static"
 	"github.com/containous/traefik/pkg/log"
 	"github.com/containous/traefik/pkg/metrics"
+	"github.com/containous/traefik/pkg/statistics"
+	"github.com/containous/traefik/pkg/tls"
 	"github.com/containous/traefik/pkg/types"
 )
 
@@ -20,7 +22,7 @@ type chainBuilder interface {
 
 // NewRouteAppenderAggregator Creates a new RouteAppenderAggregator
 func NewRouteAppenderAggregator(ctx context.Context, chainBuilder chainBuilder, conf static.Configuration,
-	entryPointName string, runtimeConfiguration *config.RuntimeConfiguration) *RouteAppenderAggregator {
+	entryPointName string, runtimeConfiguration *config.RuntimeConfiguration, tlsManager *tls.Manager, statsRecorder *statistics.Recorder) *RouteAppenderAggregator {
 	aggregator := &RouteAppenderAggregator{}
 
 	if conf.Providers != nil && conf.Providers.Rest != nil {
@@ -30,7 +32,7 @@ func NewRouteAppenderAggregator(ctx context.Context, chainBuilder chainBuilder,
 	if conf.API != nil && conf.API.EntryPoint == entryPointName {
 		chain := chainBuilder.BuildChain(ctx, conf.API.Middlewares)
 		aggregator.AddAppender(&WithMiddleware{
-			appender:          api.New(conf, runtimeConfiguration),
+			appender:          api.New(conf, runtimeConfiguration, tlsManager, statsRecorder),
 			routerMiddlewares: chain,
 		})
 	}
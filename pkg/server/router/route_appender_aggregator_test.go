@@ -91,7 +91,7 @@ func TestNewRouteAppenderAggregator(t *testing.T) {
 
 			ctx := context.Background()
 
-			router := NewRouteAppenderAggregator(ctx, chainBuilder, test.staticConf, "traefik", nil)
+			router := NewRouteAppenderAggregator(ctx, chainBuilder, test.staticConf, "traefik", nil, nil, nil)
 
 			internalMuxRouter := mux.NewRouter()
 			router.Append(internalMuxRouter)
@@ -7,15 +7,19 @@ import (
 	"github.com/containous/traefik/pkg/config/static"
 	"github.com/containous/traefik/pkg/provider/acme"
 	"github.com/containous/traefik/pkg/server/middleware"
+	"github.com/containous/traefik/pkg/statistics"
+	"github.com/containous/traefik/pkg/tls"
 	"github.com/containous/traefik/pkg/types"
 )
 
 // NewRouteAppenderFactory Creates a new RouteAppenderFactory
-func NewRouteAppenderFactory(staticConfiguration static.Configuration, entryPointName string, acmeProvider *acme.Provider) *RouteAppenderFactory {
+func NewRouteAppenderFactory(staticConfiguration static.Configuration, entryPointName string, acmeProviders []*acme.Provider, tlsManager *tls.Manager, statsRecorder *statistics.Recorder) *RouteAppenderFactory {
 	return &RouteAppenderFactory{
 		staticConfiguration: staticConfiguration,
 		entryPointName:      entryPointName,
-		acmeProvider:        acmeProvider,
+		acmeProviders:       acmeProviders,
+		tlsManager:          tlsManager,
+		statsRecorder:       statsRecorder,
 	}
 }
 
@@ -23,15 +27,21 @@ func NewRouteAppenderFactory(staticConfiguration static.Configuration, entryPoin
 type RouteAppenderFactory struct {
 	staticConfiguration static.Configuration
 	entryPointName      string
-	acmeProvider        *acme.Provider
+	acmeProviders       []*acme.Provider
+	tlsManager          *tls.Manager
+	statsRecorder       *statistics.Recorder
 }
 
 // NewAppender Creates a new RouteAppender
 func (r *RouteAppenderFactory) NewAppender(ctx context.Context, middlewaresBuilder *middleware.Builder, runtimeConfiguration *config.RuntimeConfiguration) types.RouteAppender {
-	aggregator := NewRouteAppenderAggregator(ctx, middlewaresBuilder, r.staticConfiguration, r.entryPointName, runtimeConfiguration)
+	aggregator := NewRouteAppenderAggregator(ctx, middlewaresBuilder, r.staticConfiguration, r.entryPointName, runtimeConfiguration, r.tlsManager, r.statsRecorder)
 
-	if r.acmeProvider != nil && r.acmeProvider.HTTPChallenge != nil && r.acmeProvider.HTTPChallenge.EntryPoint == r.entryPointName {
-		aggregator.AddAppender(r.acmeProvider)
+	// The challenges are shared by all the providers, so only one of them needs to serve them.
+	for _, acmeProvider := range r.acmeProviders {
+		if acmeProvider.HTTPChallenge != nil && acmeProvider.HTTPChallenge.EntryPoint == r.entryPointName {
+			aggregator.AddAppender(acmeProvider)
+			break
+		}
 	}
 
 	return aggregator
@@ -7,14 +7,18 @@ import (
 	"github.com/containous/alice"
 	"github.com/containous/traefik/pkg/config"
 	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/metrics"
 	"github.com/containous/traefik/pkg/middlewares/accesslog"
+	metricsmiddleware "github.com/containous/traefik/pkg/middlewares/metrics"
 	"github.com/containous/traefik/pkg/middlewares/recovery"
+	statisticsmiddleware "github.com/containous/traefik/pkg/middlewares/statistics"
 	"github.com/containous/traefik/pkg/middlewares/tracing"
 	"github.com/containous/traefik/pkg/responsemodifiers"
 	"github.com/containous/traefik/pkg/rules"
 	"github.com/containous/traefik/pkg/server/internal"
 	"github.com/containous/traefik/pkg/server/middleware"
 	"github.com/containous/traefik/pkg/server/service"
+	"github.com/containous/traefik/pkg/statistics"
 )
 
 const (
@@ -26,12 +30,20 @@ func NewManager(conf *config.RuntimeConfiguration,
 	serviceManager *service.Manager,
 	middlewaresBuilder *middleware.Builder,
 	modifierBuilder *responsemodifiers.Builder,
+	metricsRegistry metrics.Registry,
+	statsRecorder *statistics.Recorder,
 ) *Manager {
+	if metricsRegistry == nil {
+		metricsRegistry = metrics.NewVoidRegistry()
+	}
+
 	return &Manager{
 		routerHandlers:     make(map[string]http.Handler),
 		serviceManager:     serviceManager,
 		middlewaresBuilder: middlewaresBuilder,
 		modifierBuilder:    modifierBuilder,
+		metricsRegistry:    metricsRegistry,
+		statsRecorder:      statsRecorder,
 		conf:               conf,
 	}
 }
@@ -42,6 +54,8 @@ type Manager struct {
 	serviceManager     *service.Manager
 	middlewaresBuilder *middleware.Builder
 	modifierBuilder    *responsemodifiers.Builder
+	metricsRegistry    metrics.Registry
+	statsRecorder      *statistics.Recorder
 	conf               *config.RuntimeConfiguration
 }
 
@@ -68,7 +82,7 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string, t
 		}
 
 		handlerWithAccessLog, err := alice.New(func(next http.Handler) (http.Handler, error) {
-			return accesslog.NewFieldHandler(next, log.EntryPointName, entryPointName, accesslog.AddOriginFields), nil
+			return accesslog.NewFieldHandler(next, accesslog.EntryPointName, entryPointName, accesslog.AddOriginFields), nil
 		}).Then(handler)
 		if err != nil {
 			log.FromContext(ctx).Error(err)
@@ -123,14 +137,36 @@ func (m *Manager) buildRouterHandler(ctx context.Context, routerName string, rou
 		return handler, nil
 	}
 
+	var accessLogOptions accesslog.FieldApply
+	if routerConfig.AccessLog != nil {
+		if err := accesslog.ValidateSampleRate(routerConfig.AccessLog.SampleRate); err != nil {
+			return nil, err
+		}
+		accessLogOptions = accesslog.AddRouterOptions(routerConfig.AccessLog)
+	}
+
 	handler, err := m.buildHTTPHandler(ctx, routerConfig, routerName)
 	if err != nil {
 		return nil, err
 	}
 
-	handlerWithAccessLog, err := alice.New(func(next http.Handler) (http.Handler, error) {
-		return accesslog.NewFieldHandler(next, accesslog.RouterName, routerName, nil), nil
-	}).Then(handler)
+	chain := alice.New(func(next http.Handler) (http.Handler, error) {
+		return accesslog.NewFieldHandler(next, accesslog.RouterName, routerName, accessLogOptions), nil
+	})
+
+	if m.metricsRegistry.IsEnabled() {
+		serviceName := internal.GetQualifiedName(ctx, routerConfig.Service)
+		chain = chain.Append(metricsmiddleware.WrapRouterHandler(ctx, m.metricsRegistry, routerName, serviceName))
+	}
+
+	if m.statsRecorder != nil {
+		serviceName := internal.GetQualifiedName(ctx, routerConfig.Service)
+		chain = chain.Append(statisticsmiddleware.WrapRouterHandler(ctx, m.statsRecorder, routerName, serviceName))
+	}
+
+	chain = chain.Append(tracing.WrapRouterHandler(ctx, routerName))
+
+	handlerWithAccessLog, err := chain.Then(handler)
 	if err != nil {
 		log.FromContext(ctx).Error(err)
 		m.routerHandlers[routerName] = handler
@@ -153,7 +189,7 @@ func (m *Manager) buildHTTPHandler(ctx context.Context, router *config.RouterInf
 		return nil, err
 	}
 
-	mHandler := m.middlewaresBuilder.BuildChain(ctx, router.Middlewares)
+	mHandler := m.middlewaresBuilder.BuildChain(internal.AddServiceInContext(ctx, internal.GetQualifiedName(ctx, router.Service)), router.Middlewares)
 
 	tHandler := func(next http.Handler) (http.Handler, error) {
 		return tracing.NewForwarder(ctx, routerName, router.Service, next), nil
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/containous/traefik/pkg/config"
+	"github.com/containous/traefik/pkg/metrics"
 	"github.com/containous/traefik/pkg/middlewares/accesslog"
 	"github.com/containous/traefik/pkg/middlewares/requestdecorator"
 	"github.com/containous/traefik/pkg/responsemodifiers"
@@ -305,10 +306,10 @@ func TestRouterManager_Get(t *testing.T) {
 					Middlewares: test.middlewaresConfig,
 				},
 			})
-			serviceManager := service.NewManager(rtConf.Services, http.DefaultTransport)
-			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager)
+			serviceManager := service.NewManager(rtConf.Services, service.NewRoundTripperManager(http.DefaultTransport), nil)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
 			responseModifierFactory := responsemodifiers.NewBuilder(rtConf.Middlewares)
-			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory)
+			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, nil, nil)
 
 			handlers := routerManager.BuildHandlers(context.Background(), test.entryPoints, false)
 
@@ -406,10 +407,10 @@ func TestAccessLog(t *testing.T) {
 					Middlewares: test.middlewaresConfig,
 				},
 			})
-			serviceManager := service.NewManager(rtConf.Services, http.DefaultTransport)
-			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager)
+			serviceManager := service.NewManager(rtConf.Services, service.NewRoundTripperManager(http.DefaultTransport), nil)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
 			responseModifierFactory := responsemodifiers.NewBuilder(rtConf.Middlewares)
-			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory)
+			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, nil, nil)
 
 			handlers := routerManager.BuildHandlers(context.Background(), test.entryPoints, false)
 
@@ -418,7 +419,7 @@ func TestAccessLog(t *testing.T) {
 
 			accesslogger, err := accesslog.NewHandler(&types.AccessLog{
 				Format: "json",
-			})
+			}, metrics.NewVoidRegistry())
 			require.NoError(t, err)
 
 			reqHost := requestdecorator.New(nil)
@@ -675,6 +676,35 @@ func TestRuntimeConfiguration(t *testing.T) {
 			},
 			expectedError: 2,
 		},
+		{
+			desc: "Router with invalid access log sample rate",
+			serviceConfig: map[string]*config.Service{
+				"foo-service": {
+					LoadBalancer: &config.LoadBalancerService{
+						Servers: []config.Server{
+							{
+								URL: "http://127.0.0.1",
+							},
+						},
+					},
+				},
+			},
+			routerConfig: map[string]*config.Router{
+				"foo": {
+					EntryPoints: []string{"web"},
+					Service:     "foo-service",
+					Rule:        "Host(`bar.foo`)",
+					AccessLog:   &config.RouterAccessLog{SampleRate: 2},
+				},
+				"bar": {
+					EntryPoints: []string{"web"},
+					Service:     "foo-service",
+					Rule:        "Host(`foo.bar`)",
+					AccessLog:   &config.RouterAccessLog{SampleRate: 0.5},
+				},
+			},
+			expectedError: 1,
+		},
 	}
 
 	for _, test := range testCases {
@@ -692,10 +722,10 @@ func TestRuntimeConfiguration(t *testing.T) {
 					Middlewares: test.middlewareConfig,
 				},
 			})
-			serviceManager := service.NewManager(rtConf.Services, http.DefaultTransport)
-			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager)
+			serviceManager := service.NewManager(rtConf.Services, service.NewRoundTripperManager(http.DefaultTransport), nil)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
 			responseModifierFactory := responsemodifiers.NewBuilder(map[string]*config.MiddlewareInfo{})
-			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory)
+			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, nil, nil)
 
 			_ = routerManager.BuildHandlers(context.Background(), entryPoints, false)
 
@@ -766,10 +796,10 @@ func BenchmarkRouterServe(b *testing.B) {
 			Middlewares: map[string]*config.Middleware{},
 		},
 	})
-	serviceManager := service.NewManager(rtConf.Services, &staticTransport{res})
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager)
+	serviceManager := service.NewManager(rtConf.Services, service.NewRoundTripperManager(&staticTransport{res}), nil)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
 	responseModifierFactory := responsemodifiers.NewBuilder(rtConf.Middlewares)
-	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory)
+	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, responseModifierFactory, nil, nil)
 
 	handlers := routerManager.BuildHandlers(context.Background(), entryPoints, false)
 
@@ -807,7 +837,7 @@ func BenchmarkService(b *testing.B) {
 			Services: serviceConfig,
 		},
 	})
-	serviceManager := service.NewManager(rtConf.Services, &staticTransport{res})
+	serviceManager := service.NewManager(rtConf.Services, service.NewRoundTripperManager(&staticTransport{res}), nil)
 	w := httptest.NewRecorder()
 	req := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/", nil)
 
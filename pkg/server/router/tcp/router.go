@@ -7,6 +7,7 @@ import (
 
 	"github.com/containous/traefik/pkg/config"
 	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/middlewares/accesslog"
 	"github.com/containous/traefik/pkg/rules"
 	"github.com/containous/traefik/pkg/server/internal"
 	tcpservice "github.com/containous/traefik/pkg/server/service/tcp"
@@ -20,12 +21,14 @@ func NewManager(conf *config.RuntimeConfiguration,
 	httpHandlers map[string]http.Handler,
 	httpsHandlers map[string]http.Handler,
 	tlsManager *tls.Manager,
+	accessLog *accesslog.Handler,
 ) *Manager {
 	return &Manager{
 		serviceManager: serviceManager,
 		httpHandlers:   httpHandlers,
 		httpsHandlers:  httpsHandlers,
 		tlsManager:     tlsManager,
+		accessLog:      accessLog,
 		conf:           conf,
 	}
 }
@@ -36,6 +39,7 @@ type Manager struct {
 	httpHandlers   map[string]http.Handler
 	httpsHandlers  map[string]http.Handler
 	tlsManager     *tls.Manager
+	accessLog      *accesslog.Handler
 	conf           *config.RuntimeConfiguration
 }
 
@@ -134,6 +138,8 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			continue
 		}
 
+		handler = accesslog.WrapTCPHandler(m.accessLog, routerName, internal.GetQualifiedName(ctxRouter, routerConfig.Service), handler)
+
 		domains, err := rules.ParseHostSNI(routerConfig.Rule)
 		if err != nil {
 			routerErr := fmt.Errorf("unknown rule %s", routerConfig.Rule)
@@ -199,8 +199,8 @@ func TestRuntimeConfiguration(t *testing.T) {
 				TCPServices: test.serviceConfig,
 				TCPRouters:  test.routerConfig,
 			}
-			serviceManager := tcp.NewManager(conf)
-			tlsManager := tls.NewManager()
+			serviceManager := tcp.NewManager(conf, nil)
+			tlsManager := tls.NewManager(nil)
 			tlsManager.UpdateConfigs(
 				map[string]tls.Store{},
 				map[string]tls.TLS{
@@ -214,7 +214,7 @@ func TestRuntimeConfiguration(t *testing.T) {
 				[]*tls.Configuration{})
 
 			routerManager := NewManager(conf, serviceManager,
-				nil, nil, tlsManager)
+				nil, nil, tlsManager, nil)
 
 			_ = routerManager.BuildHandlers(context.Background(), entryPoints)
 
@@ -18,11 +18,14 @@ import (
 	"github.com/containous/traefik/pkg/provider"
 	"github.com/containous/traefik/pkg/safe"
 	"github.com/containous/traefik/pkg/server/middleware"
+	"github.com/containous/traefik/pkg/server/service"
+	"github.com/containous/traefik/pkg/statistics"
 	"github.com/containous/traefik/pkg/tls"
 	"github.com/containous/traefik/pkg/tracing"
 	"github.com/containous/traefik/pkg/tracing/datadog"
 	"github.com/containous/traefik/pkg/tracing/instana"
 	"github.com/containous/traefik/pkg/tracing/jaeger"
+	"github.com/containous/traefik/pkg/tracing/opentelemetry"
 	"github.com/containous/traefik/pkg/tracing/zipkin"
 	"github.com/containous/traefik/pkg/types"
 )
@@ -39,13 +42,15 @@ type Server struct {
 	accessLoggerMiddleware     *accesslog.Handler
 	tracer                     *tracing.Tracing
 	routinesPool               *safe.Pool
-	defaultRoundTripper        http.RoundTripper
+	roundTripperManager        *service.RoundTripperManager
 	metricsRegistry            metrics.Registry
 	provider                   provider.Provider
 	configurationListeners     []func(config.Configuration)
+	runtimeListeners           []func(*config.RuntimeConfiguration)
 	requestDecorator           *requestdecorator.RequestDecorator
 	providersThrottleDuration  time.Duration
 	tlsManager                 *tls.Manager
+	statsRecorder              *statistics.Recorder
 }
 
 // RouteAppenderFactory the route appender factory interface
@@ -63,6 +68,8 @@ func setupTracing(conf *static.Tracing) tracing.TrackingBackend {
 		return conf.DataDog
 	case instana.Name:
 		return conf.Instana
+	case opentelemetry.Name:
+		return conf.OpenTelemetry
 	default:
 		log.WithoutContext().Warnf("Could not initialize tracing: unknown tracer %q", conf.Backend)
 		return nil
@@ -70,7 +77,7 @@ func setupTracing(conf *static.Tracing) tracing.TrackingBackend {
 }
 
 // NewServer returns an initialized Server.
-func NewServer(staticConfiguration static.Configuration, provider provider.Provider, entryPoints TCPEntryPoints, tlsManager *tls.Manager) *Server {
+func NewServer(staticConfiguration static.Configuration, provider provider.Provider, entryPoints TCPEntryPoints, tlsManager *tls.Manager, statsRecorder *statistics.Recorder) *Server {
 	server := &Server{}
 
 	server.provider = provider
@@ -84,6 +91,7 @@ func NewServer(staticConfiguration static.Configuration, provider provider.Provi
 	server.currentConfigurations.Set(currentConfigurations)
 	server.providerConfigUpdateMap = make(map[string]chan config.Message)
 	server.tlsManager = tlsManager
+	server.statsRecorder = statsRecorder
 
 	if staticConfiguration.Providers != nil {
 		server.providersThrottleDuration = time.Duration(staticConfiguration.Providers.ProvidersThrottleDuration)
@@ -92,9 +100,9 @@ func NewServer(staticConfiguration static.Configuration, provider provider.Provi
 	transport, err := createHTTPTransport(staticConfiguration.ServersTransport)
 	if err != nil {
 		log.WithoutContext().Errorf("Could not configure HTTP Transport, fallbacking on default transport: %v", err)
-		server.defaultRoundTripper = http.DefaultTransport
+		server.roundTripperManager = service.NewRoundTripperManager(http.DefaultTransport)
 	} else {
-		server.defaultRoundTripper = transport
+		server.roundTripperManager = service.NewRoundTripperManager(transport)
 	}
 
 	server.routinesPool = safe.NewPool(context.Background())
@@ -102,7 +110,13 @@ func NewServer(staticConfiguration static.Configuration, provider provider.Provi
 	if staticConfiguration.Tracing != nil {
 		trackingBackend := setupTracing(staticConfiguration.Tracing)
 		var err error
-		server.tracer, err = tracing.NewTracing(staticConfiguration.Tracing.ServiceName, staticConfiguration.Tracing.SpanNameLimit, trackingBackend)
+		options := &tracing.Options{
+			Propagation:   staticConfiguration.Tracing.Propagation,
+			HeaderTags:    staticConfiguration.Tracing.HeaderTags,
+			Sampling:      staticConfiguration.Tracing.Sampling,
+			ExcludedPaths: staticConfiguration.Tracing.ExcludedPaths,
+		}
+		server.tracer, err = tracing.NewTracing(staticConfiguration.Tracing.ServiceName, staticConfiguration.Tracing.SpanNameLimit, trackingBackend, options)
 		if err != nil {
 			log.WithoutContext().Warnf("Unable to create tracer: %v", err)
 		}
@@ -114,7 +128,7 @@ func NewServer(staticConfiguration static.Configuration, provider provider.Provi
 
 	if staticConfiguration.AccessLog != nil {
 		var err error
-		server.accessLoggerMiddleware, err = accesslog.NewHandler(staticConfiguration.AccessLog)
+		server.accessLoggerMiddleware, err = accesslog.NewHandler(staticConfiguration.AccessLog, server.metricsRegistry)
 		if err != nil {
 			log.WithoutContext().Warnf("Unable to create access logger : %v", err)
 		}
@@ -201,6 +215,10 @@ func (s *Server) Close() {
 		s.tracer.Close()
 	}
 
+	if s.tlsManager != nil {
+		s.tlsManager.Close()
+	}
+
 	cancel()
 }
 
@@ -213,6 +231,9 @@ func (s *Server) startTCPServers() {
 
 	for entryPointName, serverEntryPoint := range s.entryPointsTCP {
 		ctx := log.With(context.Background(), log.Str(log.EntryPointName, entryPointName))
+		if s.metricsRegistry.IsEnabled() {
+			serverEntryPoint.enableMetrics(s.metricsRegistry, entryPointName)
+		}
 		go serverEntryPoint.startTCP(ctx)
 	}
 }
@@ -243,6 +264,12 @@ func (s *Server) AddListener(listener func(config.Configuration)) {
 	s.configurationListeners = append(s.configurationListeners, listener)
 }
 
+// AddRuntimeListener adds a new listener function used when a new runtime configuration is built.
+// The runtime configuration must not be modified by the listener.
+func (s *Server) AddRuntimeListener(listener func(*config.RuntimeConfiguration)) {
+	s.runtimeListeners = append(s.runtimeListeners, listener)
+}
+
 func (s *Server) startProvider() {
 	jsonConf, err := json.Marshal(s.provider)
 	if err != nil {
@@ -297,6 +324,16 @@ func registerMetricClients(metricsConfig *types.Metrics) metrics.Registry {
 			metricsConfig.InfluxDB.Address, metricsConfig.InfluxDB.PushInterval)
 	}
 
+	if metricsConfig.OpenTelemetry != nil {
+		ctx := log.With(context.Background(), log.Str(log.MetricsProviderName, "opentelemetry"))
+		openTelemetryRegister := metrics.RegisterOpenTelemetry(ctx, metricsConfig.OpenTelemetry)
+		if openTelemetryRegister != nil {
+			registries = append(registries, openTelemetryRegister)
+			log.FromContext(ctx).Debugf("Configured OpenTelemetry metrics: pushing to %s once every %s",
+				metricsConfig.OpenTelemetry.Address, metricsConfig.OpenTelemetry.PushInterval)
+		}
+	}
+
 	return metrics.NewMultiRegistry(registries)
 }
 
@@ -304,4 +341,5 @@ func stopMetricsClients() {
 	metrics.StopDatadog()
 	metrics.StopStatsd()
 	metrics.StopInfluxDB()
+	metrics.StopOpenTelemetry()
 }
@@ -5,13 +5,16 @@ import (
 	"encoding/json"
 	"net/http"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/containous/alice"
 	"github.com/containous/mux"
 	"github.com/containous/traefik/pkg/config"
 	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/metrics"
 	"github.com/containous/traefik/pkg/middlewares/accesslog"
+	metricsmiddleware "github.com/containous/traefik/pkg/middlewares/metrics"
 	"github.com/containous/traefik/pkg/middlewares/requestdecorator"
 	"github.com/containous/traefik/pkg/middlewares/tracing"
 	"github.com/containous/traefik/pkg/responsemodifiers"
@@ -67,12 +70,27 @@ func (s *Server) loadConfigurationTCP(configurations config.Configurations) map[
 	conf := mergeConfiguration(configurations)
 
 	s.tlsManager.UpdateConfigs(conf.TLSStores, conf.TLSOptions, conf.TLS)
+	s.updateTLSCertsMetrics()
+	s.roundTripperManager.Update(conf.HTTP.ServersTransports)
 
 	rtConf := config.NewRuntimeConfig(conf)
+
+	if s.statsRecorder != nil {
+		services := make([]string, 0, len(rtConf.Services))
+		for serviceName := range rtConf.Services {
+			services = append(services, serviceName)
+		}
+		s.statsRecorder.PruneServices(services)
+	}
+
 	handlersNonTLS, handlersTLS := s.createHTTPHandlers(ctx, rtConf, entryPoints)
 	routersTCP := s.createTCPRouters(ctx, rtConf, entryPoints, handlersNonTLS, handlersTLS)
 	rtConf.PopulateUsedBy()
 
+	for _, listener := range s.runtimeListeners {
+		listener(rtConf)
+	}
+
 	return routersTCP
 }
 
@@ -82,19 +100,19 @@ func (s *Server) createTCPRouters(ctx context.Context, configuration *config.Run
 		return make(map[string]*tcpCore.Router)
 	}
 
-	serviceManager := tcp.NewManager(configuration)
+	serviceManager := tcp.NewManager(configuration, s.metricsRegistry)
 
-	routerManager := routertcp.NewManager(configuration, serviceManager, handlers, handlersTLS, s.tlsManager)
+	routerManager := routertcp.NewManager(configuration, serviceManager, handlers, handlersTLS, s.tlsManager, s.accessLoggerMiddleware)
 
 	return routerManager.BuildHandlers(ctx, entryPoints)
 }
 
 // createHTTPHandlers returns, for the given configuration and entryPoints, the HTTP handlers for non-TLS connections, and for the TLS ones. the given configuration must not be nil. its fields will get mutated.
 func (s *Server) createHTTPHandlers(ctx context.Context, configuration *config.RuntimeConfiguration, entryPoints []string) (map[string]http.Handler, map[string]http.Handler) {
-	serviceManager := service.NewManager(configuration.Services, s.defaultRoundTripper)
-	middlewaresBuilder := middleware.NewBuilder(configuration.Middlewares, serviceManager)
+	serviceManager := service.NewManager(configuration.Services, s.roundTripperManager, s.metricsRegistry)
+	middlewaresBuilder := middleware.NewBuilder(configuration.Middlewares, serviceManager, s.metricsRegistry)
 	responseModifierFactory := responsemodifiers.NewBuilder(configuration.Middlewares)
-	routerManager := router.NewManager(configuration, serviceManager, middlewaresBuilder, responseModifierFactory)
+	routerManager := router.NewManager(configuration, serviceManager, middlewaresBuilder, responseModifierFactory, s.metricsRegistry, s.statsRecorder)
 
 	handlersNonTLS := routerManager.BuildHandlers(ctx, entryPoints, false)
 	handlersTLS := routerManager.BuildHandlers(ctx, entryPoints, true)
@@ -130,6 +148,10 @@ func (s *Server) createHTTPHandlers(ctx context.Context, configuration *config.R
 			chain = chain.Append(tracing.WrapEntryPointHandler(ctx, s.tracer, entryPointName))
 		}
 
+		if s.metricsRegistry.IsEnabled() {
+			chain = chain.Append(metricsmiddleware.WrapEntryPointHandler(ctx, s.metricsRegistry, entryPointName))
+		}
+
 		chain = chain.Append(requestdecorator.WrapHandler(s.requestDecorator))
 
 		handler, err := chain.Then(internalMuxRouter.NotFoundHandler)
@@ -167,6 +189,7 @@ func isEmptyConfiguration(conf *config.Configuration) bool {
 	return conf.HTTP.Routers == nil &&
 		conf.HTTP.Services == nil &&
 		conf.HTTP.Middlewares == nil &&
+		conf.HTTP.ServersTransports == nil &&
 		conf.TLS == nil &&
 		conf.TCP.Routers == nil &&
 		conf.TCP.Services == nil
@@ -254,13 +277,35 @@ func (s *Server) throttleProviderConfigReload(throttle time.Duration, publish ch
 	}
 }
 
+// updateTLSCertsMetrics sets the expiration dates of the certificates served by Traefik.
+func (s *Server) updateTLSCertsMetrics() {
+	if !s.metricsRegistry.IsEnabled() {
+		return
+	}
+
+	gauge := s.metricsRegistry.TLSCertsNotAfterTimestampGauge()
+
+	var serials []string
+	for _, cert := range s.tlsManager.GetCertificates() {
+		gauge.With("cn", cert.CommonName, "serial", cert.SerialNumber, "sans", strings.Join(cert.SANs, ",")).
+			Set(float64(cert.NotAfter.Unix()))
+		serials = append(serials, cert.SerialNumber)
+	}
+
+	metrics.OnTLSCertificatesUpdate(serials)
+}
+
 func (s *Server) postLoadConfiguration() {
-	// FIXME metrics
-	// if s.metricsRegistry.IsEnabled() {
-	// 	activeConfig := s.currentConfigurations.Get().(config.Configurations)
-	// 	metrics.OnConfigurationUpdate(activeConfig)
-	// }
+	if s.metricsRegistry.IsEnabled() {
+		activeConfig := s.currentConfigurations.Get().(config.Configurations)
+
+		var entryPoints []string
+		for entryPointName := range s.entryPointsTCP {
+			entryPoints = append(entryPoints, entryPointName)
+		}
 
+		metrics.OnConfigurationUpdate(activeConfig, entryPoints)
+	}
 }
 
 func buildDefaultHTTPRouter() *mux.Router {
@@ -10,7 +10,9 @@ import (
 	"github.com/containous/traefik/pkg/config"
 	"github.com/containous/traefik/pkg/config/static"
 	th "github.com/containous/traefik/pkg/testhelpers"
+	"github.com/containous/traefik/pkg/tls"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestReuseService(t *testing.T) {
@@ -44,7 +46,7 @@ func TestReuseService(t *testing.T) {
 		),
 	)
 
-	srv := NewServer(staticConfig, nil, entryPoints, nil)
+	srv := NewServer(staticConfig, nil, entryPoints, nil, nil)
 
 	rtConf := config.NewRuntimeConfig(config.Configuration{HTTP: dynamicConfigs})
 	entrypointsHandlers, _ := srv.createHTTPHandlers(context.Background(), rtConf, []string{"http"})
@@ -65,6 +67,34 @@ func TestReuseService(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, responseRecorderUnauthorized.Result().StatusCode, "status code")
 }
 
+func TestRuntimeListener(t *testing.T) {
+	entryPoints := TCPEntryPoints{
+		"http": &TCPEntryPoint{},
+	}
+
+	srv := NewServer(static.Configuration{}, nil, entryPoints, tls.NewManager(nil), nil)
+
+	var rtConf *config.RuntimeConfiguration
+	srv.AddRuntimeListener(func(conf *config.RuntimeConfiguration) {
+		rtConf = conf
+	})
+
+	dynamicConfigs := th.BuildConfiguration(
+		th.WithRouters(
+			th.WithRouter("foo",
+				th.WithEntryPoints("http"),
+				th.WithServiceName("bar"),
+				th.WithRule("Path(`/ok`)")),
+		),
+	)
+
+	srv.loadConfigurationTCP(config.Configurations{"file": &config.Configuration{HTTP: dynamicConfigs}})
+
+	require.NotNil(t, rtConf)
+	require.Contains(t, rtConf.Routers, "file@foo")
+	assert.NotEmpty(t, rtConf.Routers["file@foo"].Err)
+}
+
 func TestThrottleProviderConfigReload(t *testing.T) {
 	throttleDuration := 30 * time.Millisecond
 	publishConfig := make(chan config.Message)
@@ -75,7 +105,7 @@ func TestThrottleProviderConfigReload(t *testing.T) {
 	}()
 
 	staticConfiguration := static.Configuration{}
-	server := NewServer(staticConfiguration, nil, nil, nil)
+	server := NewServer(staticConfiguration, nil, nil, nil, nil)
 
 	go server.throttleProviderConfigReload(throttleDuration, publishConfig, providerConfig, stop)
 
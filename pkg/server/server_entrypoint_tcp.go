@@ -13,8 +13,10 @@ import (
 	"github.com/containous/traefik/pkg/h2c"
 	"github.com/containous/traefik/pkg/ip"
 	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/metrics"
 	"github.com/containous/traefik/pkg/middlewares"
 	"github.com/containous/traefik/pkg/middlewares/forwardedheaders"
+	metricsmiddleware "github.com/containous/traefik/pkg/middlewares/metrics"
 	"github.com/containous/traefik/pkg/safe"
 	"github.com/containous/traefik/pkg/tcp"
 )
@@ -54,6 +56,8 @@ type TCPEntryPoint struct {
 	tracker                *connectionTracker
 	httpServer             *httpServer
 	httpsServer            *httpServer
+	metricsRegistry        metrics.Registry
+	name                   string
 }
 
 // NewTCPEntryPoint creates a new TCPEntryPoint
@@ -105,11 +109,23 @@ func (e *TCPEntryPoint) startTCP(ctx context.Context) {
 		}
 
 		safe.Go(func() {
-			e.switcher.ServeTCP(newTrackedConnection(conn, e.tracker))
+			var trackedConn net.Conn = newTrackedConnection(conn, e.tracker)
+			if e.metricsRegistry != nil {
+				trackedConn = metricsmiddleware.NewTCPEntryPointConn(trackedConn, e.metricsRegistry, e.name)
+			}
+
+			e.switcher.ServeTCP(trackedConn)
 		})
 	}
 }
 
+// enableMetrics records the metrics of the connections accepted on the entry point, named entryPointName.
+// It must be called before the entry point is started.
+func (e *TCPEntryPoint) enableMetrics(registry metrics.Registry, entryPointName string) {
+	e.metricsRegistry = registry
+	e.name = entryPointName
+}
+
 // Shutdown stops the TCP connections
 func (e *TCPEntryPoint) Shutdown(ctx context.Context) {
 	logger := log.FromContext(ctx)
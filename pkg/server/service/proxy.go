@@ -12,6 +12,7 @@ import (
 
 	"github.com/containous/traefik/pkg/config"
 	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/middlewares/statistics"
 	"github.com/containous/traefik/pkg/types"
 )
 
@@ -21,7 +22,7 @@ const StatusClientClosedRequest = 499
 // StatusClientClosedRequestText non-standard HTTP status for client disconnection
 const StatusClientClosedRequestText = "Client Closed Request"
 
-func buildProxy(passHostHeader bool, responseForwarding *config.ResponseForwarding, defaultRoundTripper http.RoundTripper, bufferPool httputil.BufferPool, responseModifier func(*http.Response) error) (http.Handler, error) {
+func buildProxy(passHostHeader bool, responseForwarding *config.ResponseForwarding, roundTripper http.RoundTripper, bufferPool httputil.BufferPool, responseModifier func(*http.Response) error) (http.Handler, error) {
 	var flushInterval types.Duration
 	if responseForwarding != nil {
 		err := flushInterval.Set(responseForwarding.FlushInterval)
@@ -57,8 +58,10 @@ func buildProxy(passHostHeader bool, responseForwarding *config.ResponseForwardi
 				outReq.Host = outReq.URL.Host
 			}
 
+			statistics.SetBackendURL(outReq.Context(), outReq.URL)
+
 		},
-		Transport:      defaultRoundTripper,
+		Transport:      roundTripper,
 		FlushInterval:  time.Duration(flushInterval),
 		ModifyResponse: responseModifier,
 		BufferPool:     bufferPool,
@@ -81,6 +84,7 @@ func buildProxy(passHostHeader bool, responseForwarding *config.ResponseForwardi
 			}
 
 			log.Debugf("'%d %s' caused by: %v", statusCode, statusText(statusCode), err)
+			statistics.SetError(request.Context(), err)
 			w.WriteHeader(statusCode)
 			_, werr := w.Write([]byte(statusText(statusCode)))
 			if werr != nil {
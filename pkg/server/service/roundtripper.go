@@ -0,0 +1,267 @@
+package service
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/pkg/config"
+	"github.com/containous/traefik/pkg/log"
+	traefiktls "github.com/containous/traefik/pkg/tls"
+	"golang.org/x/net/http2"
+)
+
+type h2cTransportWrapper struct {
+	*http2.Transport
+}
+
+func (t *h2cTransportWrapper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = "http"
+	return t.Transport.RoundTrip(req)
+}
+
+// RoundTripperManager builds and caches the round trippers of the servers transports.
+type RoundTripperManager struct {
+	lock                sync.RWMutex
+	defaultRoundTripper http.RoundTripper
+	configs             map[string]*config.ServersTransport
+	roundTrippers       map[string]http.RoundTripper
+	errors              map[string]error
+}
+
+// NewRoundTripperManager creates a new RoundTripperManager, using defaultRoundTripper for the services without servers transport.
+func NewRoundTripperManager(defaultRoundTripper http.RoundTripper) *RoundTripperManager {
+	return &RoundTripperManager{
+		defaultRoundTripper: defaultRoundTripper,
+		configs:             make(map[string]*config.ServersTransport),
+		roundTrippers:       make(map[string]http.RoundTripper),
+		errors:              make(map[string]error),
+	}
+}
+
+// Update updates the servers transports, only the modified ones are rebuilt.
+func (r *RoundTripperManager) Update(newConfigs map[string]*config.ServersTransport) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for name, roundTripper := range r.roundTrippers {
+		if newConfig, ok := newConfigs[name]; ok && reflect.DeepEqual(newConfig, r.configs[name]) {
+			continue
+		}
+
+		if transport, ok := roundTripper.(interface{ CloseIdleConnections() }); ok {
+			transport.CloseIdleConnections()
+		}
+		delete(r.roundTrippers, name)
+	}
+
+	r.errors = make(map[string]error)
+	for name, newConfig := range newConfigs {
+		if _, ok := r.roundTrippers[name]; ok {
+			continue
+		}
+
+		roundTripper, err := NewRoundTripper(newConfig)
+		if err != nil {
+			log.WithoutContext().Errorf("Could not configure the servers transport %s: %v", name, err)
+			r.errors[name] = err
+			continue
+		}
+		r.roundTrippers[name] = roundTripper
+	}
+
+	r.configs = newConfigs
+}
+
+// Get returns the round tripper of the servers transport, or the default round tripper if the name is empty.
+func (r *RoundTripperManager) Get(name string) (http.RoundTripper, error) {
+	if len(name) == 0 {
+		return r.defaultRoundTripper, nil
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if err, ok := r.errors[name]; ok {
+		return nil, fmt.Errorf("invalid servers transport %s: %v", name, err)
+	}
+
+	roundTripper, ok := r.roundTrippers[name]
+	if !ok {
+		return nil, fmt.Errorf("servers transport not found: %s", name)
+	}
+	return roundTripper, nil
+}
+
+// NewRoundTripper creates an http.RoundTripper configured with the servers transport settings.
+// For the settings that can't be configured in Traefik it uses the default http.Transport settings.
+// An exception to this is the MaxIdleConns setting as we only provide the option MaxIdleConnsPerHost
+// in Traefik at this point in time. Setting this value to the default of 100 could lead to confusing
+// behavior and backwards compatibility issues.
+func NewRoundTripper(cfg *config.ServersTransport) (http.RoundTripper, error) {
+	if cfg == nil {
+		return nil, errors.New("no transport configuration given")
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		DualStack: true,
+	}
+
+	// The timeouts which are not set keep their default values, as the dynamic configuration may not be defaulted.
+	if cfg.ForwardingTimeouts != nil && cfg.ForwardingTimeouts.DialTimeout != 0 {
+		dialer.Timeout = time.Duration(cfg.ForwardingTimeouts.DialTimeout)
+	}
+
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	transport.RegisterProtocol("h2c", &h2cTransportWrapper{
+		Transport: &http2.Transport{
+			DialTLS: func(netw, addr string, cfg *tls.Config) (net.Conn, error) {
+				return net.Dial(netw, addr)
+			},
+			AllowHTTP: true,
+		},
+	})
+
+	if cfg.ForwardingTimeouts != nil {
+		transport.ResponseHeaderTimeout = time.Duration(cfg.ForwardingTimeouts.ResponseHeaderTimeout)
+		if cfg.ForwardingTimeouts.IdleConnTimeout != 0 {
+			transport.IdleConnTimeout = time.Duration(cfg.ForwardingTimeouts.IdleConnTimeout)
+		}
+	}
+
+	tlsConfig, err := createClientTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if cfg.DisableHTTP2 {
+		// A non-nil empty map disables the HTTP/2 upgrade of the TLS connections.
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+		return transport, nil
+	}
+
+	if err := http2.ConfigureTransport(transport); err != nil {
+		return nil, err
+	}
+
+	return transport, nil
+}
+
+func createClientTLSConfig(cfg *config.ServersTransport) (*tls.Config, error) {
+	if !cfg.InsecureSkipVerify && len(cfg.RootCAs) == 0 && len(cfg.Certificates) == 0 && len(cfg.ServerName) == 0 && len(cfg.PeerSANs) == 0 {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if len(cfg.RootCAs) > 0 {
+		tlsConfig.RootCAs = createRootCACertPool(cfg.RootCAs)
+	}
+
+	for _, certificate := range cfg.Certificates {
+		certContent, err := certificate.CertFile.Read()
+		if err != nil {
+			return nil, err
+		}
+
+		keyContent, err := certificate.KeyFile.Read()
+		if err != nil {
+			return nil, err
+		}
+
+		cert, err := tls.X509KeyPair(certContent, keyContent)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.Certificates = append(tlsConfig.Certificates, cert)
+	}
+
+	if len(cfg.PeerSANs) > 0 {
+		// The peer certificate is verified against the expected SANs instead of the server name.
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = verifyPeerSANs(tlsConfig.RootCAs, cfg.PeerSANs, cfg.InsecureSkipVerify)
+	}
+
+	return tlsConfig, nil
+}
+
+// verifyPeerSANs returns a function checking that the peer certificate is valid for one of the SANs,
+// and, unless insecureSkipVerify is set, that it is signed by one of the roots.
+func verifyPeerSANs(roots *x509.CertPool, sans []string, insecureSkipVerify bool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("no peer certificate")
+		}
+
+		certs := make([]*x509.Certificate, len(rawCerts))
+		for i, rawCert := range rawCerts {
+			cert, err := x509.ParseCertificate(rawCert)
+			if err != nil {
+				return err
+			}
+			certs[i] = cert
+		}
+
+		if !insecureSkipVerify {
+			intermediates := x509.NewCertPool()
+			for _, cert := range certs[1:] {
+				intermediates.AddCert(cert)
+			}
+
+			_, err := certs[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, san := range sans {
+			if certs[0].VerifyHostname(san) == nil {
+				return nil
+			}
+
+			for _, uri := range certs[0].URIs {
+				if uri.String() == san {
+					return nil
+				}
+			}
+		}
+
+		return fmt.Errorf("the peer certificate is not valid for any of %v", sans)
+	}
+}
+
+func createRootCACertPool(rootCAs []traefiktls.FileOrContent) *x509.CertPool {
+	roots := x509.NewCertPool()
+
+	for _, cert := range rootCAs {
+		certContent, err := cert.Read()
+		if err != nil {
+			log.WithoutContext().Error("Error while read RootCAs", err)
+			continue
+		}
+		roots.AppendCertsFromPEM(certContent)
+	}
+
+	return roots
+}
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/pkg/config"
+	traefiktls "github.com/containous/traefik/pkg/tls"
+	"github.com/containous/traefik/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTripperManager_Update(t *testing.T) {
+	defaultRoundTripper := &http.Transport{}
+	manager := NewRoundTripperManager(defaultRoundTripper)
+
+	manager.Update(map[string]*config.ServersTransport{
+		"foo@file": {MaxIdleConnsPerHost: 10},
+		"bar@file": {MaxIdleConnsPerHost: 20},
+	})
+
+	roundTripper, err := manager.Get("")
+	require.NoError(t, err)
+	assert.True(t, roundTripper == defaultRoundTripper)
+
+	foo, err := manager.Get("foo@file")
+	require.NoError(t, err)
+	bar, err := manager.Get("bar@file")
+	require.NoError(t, err)
+
+	manager.Update(map[string]*config.ServersTransport{
+		"foo@file": {MaxIdleConnsPerHost: 10},
+		"bar@file": {MaxIdleConnsPerHost: 30},
+	})
+
+	roundTripper, err = manager.Get("foo@file")
+	require.NoError(t, err)
+	assert.True(t, roundTripper == foo, "unmodified servers transport should not be rebuilt")
+
+	roundTripper, err = manager.Get("bar@file")
+	require.NoError(t, err)
+	assert.False(t, roundTripper == bar, "modified servers transport should be rebuilt")
+
+	manager.Update(map[string]*config.ServersTransport{
+		"bar@file": {MaxIdleConnsPerHost: 30},
+		"baz@file": {RootCAs: []traefiktls.FileOrContent{"not a certificate"}, Certificates: traefiktls.Certificates{{CertFile: "not a certificate", KeyFile: "not a key"}}},
+	})
+
+	_, err = manager.Get("foo@file")
+	assert.Error(t, err)
+
+	_, err = manager.Get("baz@file")
+	assert.Error(t, err)
+}
+
+func TestNewRoundTripper_serverVerification(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	rootCA := traefiktls.FileOrContent(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+
+	testCases := []struct {
+		desc          string
+		config        *config.ServersTransport
+		expectedError bool
+	}{
+		{
+			desc:          "unknown authority",
+			config:        &config.ServersTransport{},
+			expectedError: true,
+		},
+		{
+			desc:   "insecure skip verify",
+			config: &config.ServersTransport{InsecureSkipVerify: true},
+		},
+		{
+			desc:   "root CA",
+			config: &config.ServersTransport{RootCAs: []traefiktls.FileOrContent{rootCA}},
+		},
+		{
+			desc:   "root CA and valid server name",
+			config: &config.ServersTransport{RootCAs: []traefiktls.FileOrContent{rootCA}, ServerName: "example.com"},
+		},
+		{
+			desc:          "root CA and invalid server name",
+			config:        &config.ServersTransport{RootCAs: []traefiktls.FileOrContent{rootCA}, ServerName: "foo.com"},
+			expectedError: true,
+		},
+		{
+			desc:   "root CA and valid peer SANs",
+			config: &config.ServersTransport{RootCAs: []traefiktls.FileOrContent{rootCA}, ServerName: "foo.com", PeerSANs: []string{"foo.com", "example.com"}},
+		},
+		{
+			desc:          "root CA and invalid peer SANs",
+			config:        &config.ServersTransport{RootCAs: []traefiktls.FileOrContent{rootCA}, PeerSANs: []string{"foo.com"}},
+			expectedError: true,
+		},
+		{
+			desc:          "peer SANs without root CA",
+			config:        &config.ServersTransport{PeerSANs: []string{"example.com"}},
+			expectedError: true,
+		},
+		{
+			desc:   "insecure skip verify and valid peer SANs",
+			config: &config.ServersTransport{InsecureSkipVerify: true, PeerSANs: []string{"example.com"}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			roundTripper, err := NewRoundTripper(test.config)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, srv.URL, nil)
+			resp, err := roundTripper.RoundTrip(req)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func TestNewRoundTripper_clientCertificate(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+
+	certPEM, keyPEM := generateClientCertificate(t)
+
+	testCases := []struct {
+		desc          string
+		certificates  traefiktls.Certificates
+		expectedError bool
+	}{
+		{
+			desc:          "without client certificate",
+			expectedError: true,
+		},
+		{
+			desc:         "with client certificate",
+			certificates: traefiktls.Certificates{{CertFile: traefiktls.FileOrContent(certPEM), KeyFile: traefiktls.FileOrContent(keyPEM)}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			roundTripper, err := NewRoundTripper(&config.ServersTransport{
+				InsecureSkipVerify: true,
+				Certificates:       test.certificates,
+			})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, srv.URL, nil)
+			resp, err := roundTripper.RoundTrip(req)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func TestNewRoundTripper_disableHTTP2(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	testCases := []struct {
+		desc          string
+		disableHTTP2  bool
+		expectedProto int
+	}{
+		{
+			desc:          "HTTP/2 enabled",
+			expectedProto: 2,
+		},
+		{
+			desc:          "HTTP/2 disabled",
+			disableHTTP2:  true,
+			expectedProto: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			roundTripper, err := NewRoundTripper(&config.ServersTransport{InsecureSkipVerify: true, DisableHTTP2: test.disableHTTP2})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, srv.URL, nil)
+			resp, err := roundTripper.RoundTrip(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedProto, resp.ProtoMajor)
+		})
+	}
+}
+
+func TestNewRoundTripper_forwardingTimeouts(t *testing.T) {
+	testCases := []struct {
+		desc                          string
+		forwardingTimeouts            *config.ForwardingTimeouts
+		expectedResponseHeaderTimeout time.Duration
+		expectedIdleConnTimeout       time.Duration
+	}{
+		{
+			desc:                    "no timeouts",
+			expectedIdleConnTimeout: 90 * time.Second,
+		},
+		{
+			desc:                          "only the response header timeout",
+			forwardingTimeouts:            &config.ForwardingTimeouts{ResponseHeaderTimeout: types.Duration(time.Second)},
+			expectedResponseHeaderTimeout: time.Second,
+			expectedIdleConnTimeout:       90 * time.Second,
+		},
+		{
+			desc:                    "idle connection timeout",
+			forwardingTimeouts:      &config.ForwardingTimeouts{IdleConnTimeout: types.Duration(time.Minute)},
+			expectedIdleConnTimeout: time.Minute,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			roundTripper, err := NewRoundTripper(&config.ServersTransport{ForwardingTimeouts: test.forwardingTimeouts})
+			require.NoError(t, err)
+
+			transport, ok := roundTripper.(*http.Transport)
+			require.True(t, ok)
+
+			assert.Equal(t, test.expectedResponseHeaderTimeout, transport.ResponseHeaderTimeout)
+			assert.Equal(t, test.expectedIdleConnTimeout, transport.IdleConnTimeout)
+		})
+	}
+}
+
+func TestManager_BuildWithServersTransport(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	roundTripperManager := NewRoundTripperManager(&http.Transport{})
+	roundTripperManager.Update(map[string]*config.ServersTransport{
+		"provider-1@insecure": {InsecureSkipVerify: true},
+	})
+
+	services := map[string]*config.ServiceInfo{
+		"provider-1@secure": {
+			Service: &config.Service{
+				LoadBalancer: &config.LoadBalancerService{
+					Servers: []config.Server{{URL: srv.URL}},
+				},
+			},
+		},
+		"provider-1@insecure": {
+			Service: &config.Service{
+				LoadBalancer: &config.LoadBalancerService{
+					Servers:          []config.Server{{URL: srv.URL}},
+					ServersTransport: "insecure",
+				},
+			},
+		},
+		"provider-1@unknown": {
+			Service: &config.Service{
+				LoadBalancer: &config.LoadBalancerService{
+					Servers:          []config.Server{{URL: srv.URL}},
+					ServersTransport: "unknown",
+				},
+			},
+		},
+	}
+
+	manager := NewManager(services, roundTripperManager, nil)
+
+	_, err := manager.BuildHTTP(context.Background(), "provider-1@unknown", nil)
+	assert.Error(t, err)
+
+	testCases := []struct {
+		serviceName    string
+		expectedStatus int
+	}{
+		{serviceName: "provider-1@secure", expectedStatus: http.StatusInternalServerError},
+		{serviceName: "provider-1@insecure", expectedStatus: http.StatusOK},
+	}
+
+	for _, test := range testCases {
+		handler, err := manager.BuildHTTP(context.Background(), test.serviceName, nil)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo.com", nil))
+
+		assert.Equal(t, test.expectedStatus, recorder.Code, test.serviceName)
+	}
+}
+
+func generateClientCertificate(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
@@ -12,8 +12,10 @@ import (
 	"github.com/containous/traefik/pkg/config"
 	"github.com/containous/traefik/pkg/healthcheck"
 	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/metrics"
 	"github.com/containous/traefik/pkg/middlewares/accesslog"
 	"github.com/containous/traefik/pkg/middlewares/emptybackendhandler"
+	metricsmiddleware "github.com/containous/traefik/pkg/middlewares/metrics"
 	"github.com/containous/traefik/pkg/middlewares/pipelining"
 	"github.com/containous/traefik/pkg/server/cookie"
 	"github.com/containous/traefik/pkg/server/internal"
@@ -26,10 +28,15 @@ const (
 )
 
 // NewManager creates a new Manager
-func NewManager(configs map[string]*config.ServiceInfo, defaultRoundTripper http.RoundTripper) *Manager {
+func NewManager(configs map[string]*config.ServiceInfo, roundTripperManager *RoundTripperManager, metricsRegistry metrics.Registry) *Manager {
+	if metricsRegistry == nil {
+		metricsRegistry = metrics.NewVoidRegistry()
+	}
+
 	return &Manager{
 		bufferPool:          newBufferPool(),
-		defaultRoundTripper: defaultRoundTripper,
+		roundTripperManager: roundTripperManager,
+		metricsRegistry:     metricsRegistry,
 		balancers:           make(map[string][]healthcheck.BalancerHandler),
 		configs:             configs,
 	}
@@ -38,7 +45,8 @@ func NewManager(configs map[string]*config.ServiceInfo, defaultRoundTripper http
 // Manager The service manager
 type Manager struct {
 	bufferPool          httputil.BufferPool
-	defaultRoundTripper http.RoundTripper
+	roundTripperManager *RoundTripperManager
+	metricsRegistry     metrics.Registry
 	balancers           map[string][]healthcheck.BalancerHandler
 	configs             map[string]*config.ServiceInfo
 }
@@ -77,7 +85,12 @@ func (m *Manager) getLoadBalancerServiceHandler(
 	service *config.LoadBalancerService,
 	responseModifier func(*http.Response) error,
 ) (http.Handler, error) {
-	fwd, err := buildProxy(service.PassHostHeader, service.ResponseForwarding, m.defaultRoundTripper, m.bufferPool, responseModifier)
+	roundTripper, err := m.getRoundTripper(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+
+	fwd, err := buildProxy(service.PassHostHeader, service.ResponseForwarding, roundTripper, m.bufferPool, responseModifier)
 	if err != nil {
 		return nil, err
 	}
@@ -100,7 +113,13 @@ func (m *Manager) getLoadBalancerServiceHandler(
 	m.balancers[serviceName] = append(m.balancers[serviceName], balancer)
 
 	// Empty (backend with no servers)
-	return emptybackendhandler.New(balancer), nil
+	lb := emptybackendhandler.New(balancer)
+
+	if m.metricsRegistry.IsEnabled() {
+		return metricsmiddleware.NewServiceMiddleware(ctx, lb, m.metricsRegistry, serviceName), nil
+	}
+
+	return lb, nil
 }
 
 // LaunchHealthCheck Launches the health checks.
@@ -121,7 +140,13 @@ func (m *Manager) LaunchHealthCheck() {
 		if hcOpts := buildHealthCheckOptions(ctx, balancer, serviceName, service.HealthCheck); hcOpts != nil {
 			log.FromContext(ctx).Debugf("Setting up healthcheck for service %s with %s", serviceName, *hcOpts)
 
-			hcOpts.Transport = m.defaultRoundTripper
+			roundTripper, err := m.getRoundTripper(internal.AddProviderInContext(ctx, serviceName), service)
+			if err != nil {
+				log.FromContext(ctx).Errorf("Unable to set up the healthcheck: %v", err)
+				continue
+			}
+
+			hcOpts.Transport = roundTripper
 			backendHealthCheck = healthcheck.NewBackendConfig(*hcOpts, serviceName)
 		}
 
@@ -130,8 +155,17 @@ func (m *Manager) LaunchHealthCheck() {
 		}
 	}
 
-	// FIXME metrics and context
-	healthcheck.GetHealthCheck().SetBackendsConfiguration(context.TODO(), backendConfigs)
+	// FIXME context
+	healthcheck.GetHealthCheck(m.metricsRegistry).SetBackendsConfiguration(context.TODO(), backendConfigs)
+}
+
+// getRoundTripper returns the round tripper of the servers transport of the service.
+func (m *Manager) getRoundTripper(ctx context.Context, service *config.LoadBalancerService) (http.RoundTripper, error) {
+	if len(service.ServersTransport) == 0 {
+		return m.roundTripperManager.Get("")
+	}
+
+	return m.roundTripperManager.Get(internal.GetQualifiedName(ctx, service.ServersTransport))
 }
 
 func buildHealthCheckOptions(ctx context.Context, lb healthcheck.BalancerHandler, backend string, hc *config.HealthCheck) *healthcheck.Options {
@@ -203,14 +237,14 @@ func (m *Manager) getLoadBalancer(ctx context.Context, serviceName string, servi
 	}
 
 	lbsu := healthcheck.NewLBStatusUpdater(lb, m.configs[serviceName])
-	if err := m.upsertServers(ctx, lbsu, service.Servers); err != nil {
+	if err := m.upsertServers(ctx, serviceName, lbsu, service.Servers); err != nil {
 		return nil, fmt.Errorf("error configuring load balancer for service %s: %v", serviceName, err)
 	}
 
 	return lb, nil
 }
 
-func (m *Manager) upsertServers(ctx context.Context, lb healthcheck.BalancerHandler, servers []config.Server) error {
+func (m *Manager) upsertServers(ctx context.Context, serviceName string, lb healthcheck.BalancerHandler, servers []config.Server) error {
 	logger := log.FromContext(ctx)
 
 	for name, srv := range servers {
@@ -225,7 +259,9 @@ func (m *Manager) upsertServers(ctx context.Context, lb healthcheck.BalancerHand
 			return fmt.Errorf("error adding server %s to load balancer: %v", srv.URL, err)
 		}
 
-		// FIXME Handle Metrics
+		if m.metricsRegistry.IsEnabled() {
+			m.metricsRegistry.BackendServerUpGauge().With("backend", serviceName, "url", u.String()).Set(1)
+		}
 	}
 	return nil
 }
@@ -4,43 +4,95 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"time"
 
 	"github.com/containous/traefik/pkg/config"
 	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/metrics"
+	metricsmiddleware "github.com/containous/traefik/pkg/middlewares/metrics"
 	"github.com/containous/traefik/pkg/server/internal"
 	"github.com/containous/traefik/pkg/tcp"
 )
 
+const defaultDialTimeout = 30 * time.Second
+
 // Manager is the TCPHandlers factory
 type Manager struct {
-	configs map[string]*config.TCPServiceInfo
+	configs         map[string]*config.TCPServiceInfo
+	metricsRegistry metrics.Registry
 }
 
 // NewManager creates a new manager
-func NewManager(conf *config.RuntimeConfiguration) *Manager {
+func NewManager(conf *config.RuntimeConfiguration, metricsRegistry metrics.Registry) *Manager {
+	if metricsRegistry == nil {
+		metricsRegistry = metrics.NewVoidRegistry()
+	}
+
 	return &Manager{
-		configs: conf.TCPServices,
+		configs:         conf.TCPServices,
+		metricsRegistry: metricsRegistry,
 	}
 }
 
 // BuildTCP Creates a tcp.Handler for a service configuration.
 func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Handler, error) {
+	return m.buildTCP(rootCtx, serviceName, nil)
+}
+
+// buildTCP creates a tcp.Handler for a service configuration,
+// parents being the weighted services already traversed to reach it.
+func (m *Manager) buildTCP(rootCtx context.Context, serviceName string, parents []string) (tcp.Handler, error) {
+	handler, err := m.buildTCPService(rootCtx, serviceName, parents)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.metricsRegistry.IsEnabled() {
+		handler = metricsmiddleware.NewTCPServiceHandler(handler, m.metricsRegistry, internal.GetQualifiedName(rootCtx, serviceName))
+	}
+
+	return handler, nil
+}
+
+func (m *Manager) buildTCPService(rootCtx context.Context, serviceName string, parents []string) (tcp.Handler, error) {
 	serviceQualifiedName := internal.GetQualifiedName(rootCtx, serviceName)
 	ctx := internal.AddProviderInContext(rootCtx, serviceQualifiedName)
 	ctx = log.With(ctx, log.Str(log.ServiceName, serviceName))
 
-	// FIXME Check if the service is declared multiple times with different types
 	conf, ok := m.configs[serviceQualifiedName]
 	if !ok {
 		return nil, fmt.Errorf("the service %q does not exist", serviceQualifiedName)
 	}
-	if conf.LoadBalancer == nil {
+
+	switch {
+	case conf.LoadBalancer != nil && conf.Weighted != nil:
+		conf.Err = fmt.Errorf("the service %q cannot be both a TCP load balancer and a weighted service", serviceQualifiedName)
+		return nil, conf.Err
+	case conf.Weighted != nil:
+		return m.buildWeighted(ctx, serviceQualifiedName, conf, parents)
+	case conf.LoadBalancer == nil:
 		conf.Err = fmt.Errorf("the service %q doesn't have any TCP load balancer", serviceQualifiedName)
 		return nil, conf.Err
 	}
 
 	logger := log.FromContext(ctx)
 
+	tlsConfig, err := conf.LoadBalancer.TLS.CreateTLSConfig()
+	if err != nil {
+		conf.Err = fmt.Errorf("invalid TLS configuration for the servers of the service %q: %v", serviceQualifiedName, err)
+		return nil, conf.Err
+	}
+
+	dialTimeout := defaultDialTimeout
+	if conf.LoadBalancer.DialTimeout > 0 {
+		dialTimeout = time.Duration(conf.LoadBalancer.DialTimeout)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   dialTimeout,
+		KeepAlive: time.Duration(conf.LoadBalancer.KeepAlive),
+	}
+
 	loadBalancer := tcp.NewRRLoadBalancer()
 
 	for name, server := range conf.LoadBalancer.Servers {
@@ -49,7 +101,7 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 			continue
 		}
 
-		handler, err := tcp.NewProxy(server.Address)
+		handler, err := tcp.NewProxy(server.Address, dialer, tlsConfig)
 		if err != nil {
 			logger.Errorf("In service %q server %q: %v", serviceQualifiedName, server.Address, err)
 			continue
@@ -60,3 +112,33 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 	}
 	return loadBalancer, nil
 }
+
+func (m *Manager) buildWeighted(ctx context.Context, serviceQualifiedName string, conf *config.TCPServiceInfo, parents []string) (tcp.Handler, error) {
+	for _, parent := range parents {
+		if parent == serviceQualifiedName {
+			conf.Err = fmt.Errorf("the weighted service %q references itself", serviceQualifiedName)
+			return nil, conf.Err
+		}
+	}
+	parents = append(parents, serviceQualifiedName)
+
+	loadBalancer := tcp.NewWRRLoadBalancer()
+
+	for _, service := range conf.Weighted.Services {
+		handler, err := m.buildTCP(ctx, service.Name, parents)
+		if err != nil {
+			conf.Err = fmt.Errorf("in the weighted service %q: %v", serviceQualifiedName, err)
+			return nil, conf.Err
+		}
+
+		weight := 1
+		if service.Weight != nil {
+			weight = *service.Weight
+		}
+
+		loadBalancer.AddWeightServer(handler, weight)
+		log.FromContext(ctx).Debugf("Adding service %q with weight %d", service.Name, weight)
+	}
+
+	return loadBalancer, nil
+}
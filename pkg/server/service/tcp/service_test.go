@@ -3,9 +3,12 @@ package tcp
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/containous/traefik/pkg/config"
 	"github.com/containous/traefik/pkg/server/internal"
+	traefiktls "github.com/containous/traefik/pkg/tls"
+	"github.com/containous/traefik/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -170,6 +173,127 @@ func TestManager_BuildTCP(t *testing.T) {
 			},
 			providerName: "provider-1",
 		},
+		{
+			desc:        "Server with TLS configuration",
+			serviceName: "serviceName",
+			configs: map[string]*config.TCPServiceInfo{
+				"provider-1@serviceName": {
+					TCPService: &config.TCPService{
+						LoadBalancer: &config.TCPLoadBalancerService{
+							Servers: []config.TCPServer{
+								{
+									Address: "192.168.0.12:5432",
+								},
+							},
+							TLS: &config.TCPServersTLS{
+								ServerName:         "db.example.com",
+								InsecureSkipVerify: true,
+							},
+							DialTimeout: types.Duration(5 * time.Second),
+							KeepAlive:   types.Duration(-1),
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "invalid TLS root CA",
+			serviceName: "serviceName",
+			configs: map[string]*config.TCPServiceInfo{
+				"provider-1@serviceName": {
+					TCPService: &config.TCPService{
+						LoadBalancer: &config.TCPLoadBalancerService{
+							Servers: []config.TCPServer{
+								{
+									Address: "192.168.0.12:5432",
+								},
+							},
+							TLS: &config.TCPServersTLS{
+								RootCAs: []traefiktls.FileOrContent{"not a certificate"},
+							},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: `invalid TLS configuration for the servers of the service "provider-1@serviceName": failed to parse root CA`,
+		},
+		{
+			desc:        "weighted service",
+			serviceName: "weighted",
+			configs: map[string]*config.TCPServiceInfo{
+				"provider-1@weighted": {
+					TCPService: &config.TCPService{
+						Weighted: &config.TCPWeightedRoundRobin{
+							Services: []config.TCPWRRService{
+								{Name: "foo", Weight: intPtr(3)},
+								{Name: "provider-2@bar"},
+							},
+						},
+					},
+				},
+				"provider-1@foo": {
+					TCPService: &config.TCPService{
+						LoadBalancer: &config.TCPLoadBalancerService{},
+					},
+				},
+				"provider-2@bar": {
+					TCPService: &config.TCPService{
+						LoadBalancer: &config.TCPLoadBalancerService{},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "weighted service with a missing service",
+			serviceName: "weighted",
+			configs: map[string]*config.TCPServiceInfo{
+				"provider-1@weighted": {
+					TCPService: &config.TCPService{
+						Weighted: &config.TCPWeightedRoundRobin{
+							Services: []config.TCPWRRService{
+								{Name: "foo"},
+							},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: `in the weighted service "provider-1@weighted": the service "provider-1@foo" does not exist`,
+		},
+		{
+			desc:        "weighted service referencing itself",
+			serviceName: "weighted",
+			configs: map[string]*config.TCPServiceInfo{
+				"provider-1@weighted": {
+					TCPService: &config.TCPService{
+						Weighted: &config.TCPWeightedRoundRobin{
+							Services: []config.TCPWRRService{
+								{Name: "weighted"},
+							},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: `in the weighted service "provider-1@weighted": the weighted service "provider-1@weighted" references itself`,
+		},
+		{
+			desc:        "service with several types",
+			serviceName: "serviceName",
+			configs: map[string]*config.TCPServiceInfo{
+				"provider-1@serviceName": {
+					TCPService: &config.TCPService{
+						LoadBalancer: &config.TCPLoadBalancerService{},
+						Weighted:     &config.TCPWeightedRoundRobin{},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: `the service "provider-1@serviceName" cannot be both a TCP load balancer and a weighted service`,
+		},
 	}
 
 	for _, test := range testCases {
@@ -179,7 +303,7 @@ func TestManager_BuildTCP(t *testing.T) {
 
 			manager := NewManager(&config.RuntimeConfiguration{
 				TCPServices: test.configs,
-			})
+			}, nil)
 
 			ctx := context.Background()
 			if len(test.providerName) > 0 {
@@ -198,3 +322,7 @@ func TestManager_BuildTCP(t *testing.T) {
 		})
 	}
 }
+
+func intPtr(value int) *int {
+	return &value
+}
@@ -0,0 +1,349 @@
+package statistics
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// window is the duration over which the live statistics of the services are computed.
+	window = time.Minute
+	// maxLatencySamples is the maximum number of request durations kept per service to compute the latency percentiles.
+	maxLatencySamples = 1024
+)
+
+// Entry holds the outcome of a request handled by a router.
+type Entry struct {
+	Time       time.Time
+	Router     string
+	Service    string
+	BackendURL string
+	Method     string
+	Host       string
+	Path       string
+	Status     int
+	Duration   time.Duration
+	// Error is the error of the proxy, if the request could not be forwarded to the backend.
+	Error string
+}
+
+// IsError checks whether the request ended in error: with a status code greater than or equal to 500, or with a proxy error.
+func (e Entry) IsError() bool {
+	return e.Status >= 500 || e.Error != ""
+}
+
+// ErrorEntry is a request which ended in error.
+type ErrorEntry struct {
+	Time       time.Time `json:"time"`
+	Router     string    `json:"router,omitempty"`
+	Service    string    `json:"service,omitempty"`
+	BackendURL string    `json:"backendURL,omitempty"`
+	Method     string    `json:"method,omitempty"`
+	Host       string    `json:"host,omitempty"`
+	Path       string    `json:"path,omitempty"`
+	Status     int       `json:"status"`
+	Message    string    `json:"message"`
+}
+
+// Latency holds the percentiles of the request durations, in seconds.
+type Latency struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+}
+
+// ServiceStats holds the live statistics of a service, computed over the last minute.
+type ServiceStats struct {
+	Requests          uint64  `json:"requests"`
+	Errors            uint64  `json:"errors"`
+	RequestsPerSecond float64 `json:"requestsPerSecond"`
+	ErrorsPerSecond   float64 `json:"errorsPerSecond"`
+	ErrorRate         float64 `json:"errorRate"`
+	Latency           Latency `json:"latency"`
+}
+
+// Recorder keeps the recent errors and the live statistics of the services, which are exposed by the API.
+type Recorder struct {
+	start time.Time
+
+	errorsLock   sync.Mutex
+	recentErrors []ErrorEntry
+	nextError    int
+
+	// servicesLock only guards the map, the statistics of each service having their own lock.
+	servicesLock sync.RWMutex
+	services     map[string]*serviceStats
+
+	// now is overridden in the tests.
+	now func() time.Time
+}
+
+// NewRecorder creates a Recorder keeping the given number of recent errors.
+func NewRecorder(recentErrors int) *Recorder {
+	if recentErrors < 0 {
+		recentErrors = 0
+	}
+
+	return &Recorder{
+		start:        time.Now(),
+		recentErrors: make([]ErrorEntry, 0, recentErrors),
+		services:     make(map[string]*serviceStats),
+		now:          time.Now,
+	}
+}
+
+// Record records the outcome of a request.
+func (r *Recorder) Record(entry Entry) {
+	if entry.Time.IsZero() {
+		entry.Time = r.now()
+	}
+
+	if entry.IsError() {
+		r.addError(entry)
+	}
+
+	if entry.Service == "" {
+		return
+	}
+
+	r.getServiceStats(entry.Service).add(entry)
+}
+
+// getServiceStats returns the statistics of the service, creating them if needed.
+func (r *Recorder) getServiceStats(service string) *serviceStats {
+	r.servicesLock.RLock()
+	stats, ok := r.services[service]
+	r.servicesLock.RUnlock()
+
+	if ok {
+		return stats
+	}
+
+	r.servicesLock.Lock()
+	defer r.servicesLock.Unlock()
+
+	stats, ok = r.services[service]
+	if !ok {
+		stats = &serviceStats{}
+		r.services[service] = stats
+	}
+	return stats
+}
+
+// PruneServices removes the statistics of the services which are not in the given list,
+// so that the services removed from the configuration are not kept forever.
+func (r *Recorder) PruneServices(services []string) {
+	keep := make(map[string]struct{}, len(services))
+	for _, service := range services {
+		keep[service] = struct{}{}
+	}
+
+	r.servicesLock.Lock()
+	defer r.servicesLock.Unlock()
+
+	for service := range r.services {
+		if _, ok := keep[service]; !ok {
+			delete(r.services, service)
+		}
+	}
+}
+
+func (r *Recorder) addError(entry Entry) {
+	r.errorsLock.Lock()
+	defer r.errorsLock.Unlock()
+
+	limit := cap(r.recentErrors)
+	if limit == 0 {
+		return
+	}
+
+	message := entry.Error
+	if message == "" {
+		message = http.StatusText(entry.Status)
+	}
+
+	errorEntry := ErrorEntry{
+		Time:       entry.Time,
+		Router:     entry.Router,
+		Service:    entry.Service,
+		BackendURL: entry.BackendURL,
+		Method:     entry.Method,
+		Host:       entry.Host,
+		Path:       entry.Path,
+		Status:     entry.Status,
+		Message:    message,
+	}
+
+	if len(r.recentErrors) < limit {
+		r.recentErrors = append(r.recentErrors, errorEntry)
+		return
+	}
+
+	r.recentErrors[r.nextError] = errorEntry
+	r.nextError = (r.nextError + 1) % limit
+}
+
+// RecentErrors returns the recent errors, the most recent first.
+func (r *Recorder) RecentErrors() []ErrorEntry {
+	r.errorsLock.Lock()
+	defer r.errorsLock.Unlock()
+
+	result := make([]ErrorEntry, 0, len(r.recentErrors))
+	for i := len(r.recentErrors) - 1; i >= 0; i-- {
+		result = append(result, r.recentErrors[(r.nextError+i)%len(r.recentErrors)])
+	}
+	return result
+}
+
+// ServiceStats returns the live statistics of the service.
+func (r *Recorder) ServiceStats(service string) ServiceStats {
+	r.servicesLock.RLock()
+	stats, ok := r.services[service]
+	r.servicesLock.RUnlock()
+
+	if !ok {
+		return ServiceStats{}
+	}
+	return stats.compute(r.now(), r.elapsed())
+}
+
+// TotalStats returns the live statistics of all the services together.
+func (r *Recorder) TotalStats() ServiceStats {
+	r.servicesLock.RLock()
+	defer r.servicesLock.RUnlock()
+
+	total := &serviceStats{}
+	for _, stats := range r.services {
+		total.merge(stats)
+	}
+	return total.compute(r.now(), r.elapsed())
+}
+
+// elapsed returns the duration over which the rates are computed, which is shorter than the window right after the start.
+func (r *Recorder) elapsed() time.Duration {
+	elapsed := r.now().Sub(r.start)
+	if elapsed > window {
+		return window
+	}
+	if elapsed < time.Second {
+		return time.Second
+	}
+	return elapsed
+}
+
+// serviceStats holds the request counts per second over the window, and the most recent request durations.
+type serviceStats struct {
+	lock        sync.Mutex
+	buckets     [window / time.Second]bucket
+	latencies   []latencySample
+	nextLatency int
+}
+
+type bucket struct {
+	second   int64
+	requests uint64
+	errors   uint64
+}
+
+type latencySample struct {
+	time     time.Time
+	duration time.Duration
+}
+
+func (s *serviceStats) add(entry Entry) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	second := entry.Time.Unix()
+	b := &s.buckets[second%int64(len(s.buckets))]
+	if b.second != second {
+		*b = bucket{second: second}
+	}
+
+	b.requests++
+	if entry.IsError() {
+		b.errors++
+	}
+
+	s.addLatency(latencySample{time: entry.Time, duration: entry.Duration})
+}
+
+func (s *serviceStats) addLatency(sample latencySample) {
+	if len(s.latencies) < maxLatencySamples {
+		s.latencies = append(s.latencies, sample)
+		return
+	}
+
+	s.latencies[s.nextLatency] = sample
+	s.nextLatency = (s.nextLatency + 1) % maxLatencySamples
+}
+
+// merge adds the requests of other to s, the durations being all kept.
+// s must not be shared, only other is locked.
+func (s *serviceStats) merge(other *serviceStats) {
+	other.lock.Lock()
+	defer other.lock.Unlock()
+
+	for i, b := range other.buckets {
+		if b.second > s.buckets[i].second {
+			s.buckets[i] = b
+		} else if b.second == s.buckets[i].second {
+			s.buckets[i].requests += b.requests
+			s.buckets[i].errors += b.errors
+		}
+	}
+	s.latencies = append(s.latencies, other.latencies...)
+}
+
+func (s *serviceStats) compute(now time.Time, elapsed time.Duration) ServiceStats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	oldest := now.Add(-window)
+
+	var result ServiceStats
+	for _, b := range s.buckets {
+		if b.second <= oldest.Unix() || b.second > now.Unix() {
+			continue
+		}
+		result.Requests += b.requests
+		result.Errors += b.errors
+	}
+
+	result.RequestsPerSecond = float64(result.Requests) / elapsed.Seconds()
+	result.ErrorsPerSecond = float64(result.Errors) / elapsed.Seconds()
+	if result.Requests > 0 {
+		result.ErrorRate = float64(result.Errors) / float64(result.Requests)
+	}
+
+	var durations []float64
+	for _, sample := range s.latencies {
+		if sample.time.After(oldest) {
+			durations = append(durations, sample.duration.Seconds())
+		}
+	}
+	sort.Float64s(durations)
+
+	result.Latency = Latency{
+		P50: percentile(durations, 50),
+		P90: percentile(durations, 90),
+		P99: percentile(durations, 99),
+	}
+
+	return result
+}
+
+// percentile returns the nearest-rank percentile of the sorted values.
+func percentile(sorted []float64, p int) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
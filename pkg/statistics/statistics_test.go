@@ -0,0 +1,184 @@
+package statistics
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_RecentErrors(t *testing.T) {
+	start := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	recorder := NewRecorder(3)
+
+	for i := 0; i < 5; i++ {
+		recorder.Record(Entry{
+			Time:    start.Add(time.Duration(i) * time.Second),
+			Router:  "foo@file",
+			Service: "bar@file",
+			Status:  http.StatusBadGateway,
+		})
+		recorder.Record(Entry{
+			Time:    start.Add(time.Duration(i) * time.Second),
+			Router:  "foo@file",
+			Service: "bar@file",
+			Status:  http.StatusNotFound,
+		})
+	}
+
+	recorder.Record(Entry{
+		Time:       start.Add(10 * time.Second),
+		Router:     "foo@file",
+		Service:    "bar@file",
+		BackendURL: "http://10.0.0.1:80",
+		Method:     http.MethodGet,
+		Host:       "foo.bar",
+		Path:       "/baz",
+		Status:     499,
+		Error:      "context canceled",
+	})
+
+	expected := []ErrorEntry{
+		{
+			Time:       start.Add(10 * time.Second),
+			Router:     "foo@file",
+			Service:    "bar@file",
+			BackendURL: "http://10.0.0.1:80",
+			Method:     http.MethodGet,
+			Host:       "foo.bar",
+			Path:       "/baz",
+			Status:     499,
+			Message:    "context canceled",
+		},
+		{Time: start.Add(4 * time.Second), Router: "foo@file", Service: "bar@file", Status: http.StatusBadGateway, Message: "Bad Gateway"},
+		{Time: start.Add(3 * time.Second), Router: "foo@file", Service: "bar@file", Status: http.StatusBadGateway, Message: "Bad Gateway"},
+	}
+
+	assert.Equal(t, expected, recorder.RecentErrors())
+}
+
+func TestRecorder_RecentErrors_disabled(t *testing.T) {
+	recorder := NewRecorder(0)
+	recorder.Record(Entry{Service: "bar@file", Status: http.StatusInternalServerError})
+
+	assert.Empty(t, recorder.RecentErrors())
+	assert.Equal(t, uint64(1), recorder.ServiceStats("bar@file").Errors)
+}
+
+func TestRecorder_ServiceStats(t *testing.T) {
+	start := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	now := start.Add(2 * time.Minute)
+
+	recorder := NewRecorder(10)
+	recorder.start = start
+	recorder.now = func() time.Time { return now }
+
+	// Outside of the window.
+	recorder.Record(Entry{Time: now.Add(-90 * time.Second), Service: "foo@file", Status: http.StatusInternalServerError, Duration: time.Hour})
+
+	for i := 1; i <= 100; i++ {
+		status := http.StatusOK
+		if i%10 == 0 {
+			status = http.StatusServiceUnavailable
+		}
+
+		recorder.Record(Entry{
+			Time:     now.Add(-time.Duration(i%30) * time.Second),
+			Service:  "foo@file",
+			Status:   status,
+			Duration: time.Duration(i) * time.Millisecond,
+		})
+	}
+
+	recorder.Record(Entry{Time: now, Service: "bar@file", Status: http.StatusOK, Duration: 500 * time.Millisecond})
+
+	stats := recorder.ServiceStats("foo@file")
+	assert.Equal(t, uint64(100), stats.Requests)
+	assert.Equal(t, uint64(10), stats.Errors)
+	assert.InDelta(t, 100.0/60, stats.RequestsPerSecond, 1e-9)
+	assert.InDelta(t, 10.0/60, stats.ErrorsPerSecond, 1e-9)
+	assert.InDelta(t, 0.1, stats.ErrorRate, 1e-9)
+	assert.InDelta(t, 0.050, stats.Latency.P50, 1e-9)
+	assert.InDelta(t, 0.090, stats.Latency.P90, 1e-9)
+	assert.InDelta(t, 0.099, stats.Latency.P99, 1e-9)
+
+	assert.Equal(t, ServiceStats{}, recorder.ServiceStats("baz@file"))
+
+	total := recorder.TotalStats()
+	assert.Equal(t, uint64(101), total.Requests)
+	assert.Equal(t, uint64(10), total.Errors)
+	assert.InDelta(t, 0.1, total.Latency.P99, 1e-9)
+}
+
+func TestRecorder_ServiceStats_afterStart(t *testing.T) {
+	start := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	recorder := NewRecorder(10)
+	recorder.start = start
+	recorder.now = func() time.Time { return start.Add(10 * time.Second) }
+
+	for i := 0; i < 20; i++ {
+		recorder.Record(Entry{Time: start.Add(5 * time.Second), Service: "foo@file", Status: http.StatusOK})
+	}
+
+	stats := recorder.ServiceStats("foo@file")
+	require.Equal(t, uint64(20), stats.Requests)
+	assert.InDelta(t, 2.0, stats.RequestsPerSecond, 1e-9)
+}
+
+func TestRecorder_PruneServices(t *testing.T) {
+	recorder := NewRecorder(10)
+	recorder.Record(Entry{Service: "foo@file", Status: http.StatusOK})
+	recorder.Record(Entry{Service: "bar@file", Status: http.StatusOK})
+
+	recorder.PruneServices([]string{"foo@file", "baz@file"})
+
+	assert.Equal(t, uint64(1), recorder.ServiceStats("foo@file").Requests)
+	assert.Equal(t, ServiceStats{}, recorder.ServiceStats("bar@file"))
+	assert.Equal(t, uint64(1), recorder.TotalStats().Requests)
+}
+
+func TestRecorder_concurrency(t *testing.T) {
+	recorder := NewRecorder(10)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			service := fmt.Sprintf("service-%d@file", i%3)
+			for j := 0; j < 100; j++ {
+				recorder.Record(Entry{Service: service, Status: http.StatusInternalServerError})
+				recorder.ServiceStats(service)
+				recorder.TotalStats()
+				recorder.RecentErrors()
+			}
+		}(i)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for j := 0; j < 100; j++ {
+			recorder.PruneServices([]string{"service-0@file"})
+		}
+	}()
+
+	wg.Wait()
+
+	assert.Len(t, recorder.RecentErrors(), 10)
+}
+
+func TestPercentile(t *testing.T) {
+	assert.Equal(t, 0.0, percentile(nil, 50))
+	assert.Equal(t, 1.0, percentile([]float64{1}, 99))
+	assert.Equal(t, 2.0, percentile([]float64{1, 2, 3, 4}, 50))
+	assert.Equal(t, 4.0, percentile([]float64{1, 2, 3, 4}, 90))
+}
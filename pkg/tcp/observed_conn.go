@@ -0,0 +1,117 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// Reasons for which a proxied connection was closed.
+const (
+	CloseReasonClientClosed  = "client_closed"
+	CloseReasonBackendClosed = "backend_closed"
+	CloseReasonDialError     = "dial_error"
+	CloseReasonCopyError     = "copy_error"
+	CloseReasonNoServer      = "no_server"
+)
+
+// ObservedConn is a connection counting the bytes read from and written to it,
+// and recording how it was proxied, for the metrics and the access logs.
+type ObservedConn struct {
+	net.Conn
+
+	bytesRead    int64
+	bytesWritten int64
+
+	lock           sync.RWMutex
+	backendAddress string
+	closeReason    string
+}
+
+// Observe returns the connection as an ObservedConn, wrapping it if it is not one yet.
+func Observe(conn net.Conn) *ObservedConn {
+	if observed, ok := conn.(*ObservedConn); ok {
+		return observed
+	}
+	return &ObservedConn{Conn: conn}
+}
+
+// Read reads from the connection and counts the bytes read.
+func (c *ObservedConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	atomic.AddInt64(&c.bytesRead, int64(n))
+	return n, err
+}
+
+// Write writes to the connection and counts the bytes written.
+func (c *ObservedConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	atomic.AddInt64(&c.bytesWritten, int64(n))
+	return n, err
+}
+
+// BytesRead returns the number of bytes read from the connection.
+func (c *ObservedConn) BytesRead() int64 {
+	return atomic.LoadInt64(&c.bytesRead)
+}
+
+// BytesWritten returns the number of bytes written to the connection.
+func (c *ObservedConn) BytesWritten() int64 {
+	return atomic.LoadInt64(&c.bytesWritten)
+}
+
+// BackendAddress returns the address of the server the connection was forwarded to.
+func (c *ObservedConn) BackendAddress() string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.backendAddress
+}
+
+// CloseReason returns the reason for which the connection was closed.
+func (c *ObservedConn) CloseReason() string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.closeReason
+}
+
+func (c *ObservedConn) setBackendAddress(address string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.backendAddress = address
+}
+
+func (c *ObservedConn) setCloseReason(reason string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	// Only the first reason is kept, as closing the connection triggers errors on the other side of the proxy.
+	if len(c.closeReason) == 0 {
+		c.closeReason = reason
+	}
+}
+
+// ServerName returns the server name sent by the client in the TLS handshake of the connection, if any.
+func ServerName(conn net.Conn) string {
+	switch c := conn.(type) {
+	case *ObservedConn:
+		return ServerName(c.Conn)
+	case *tls.Conn:
+		return c.ConnectionState().ServerName
+	case *Conn:
+		return c.ServerName
+	default:
+		return ""
+	}
+}
+
+func recordBackendAddress(conn net.Conn, address string) {
+	if observed, ok := conn.(*ObservedConn); ok {
+		observed.setBackendAddress(address)
+	}
+}
+
+func recordCloseReason(conn net.Conn, reason string) {
+	if observed, ok := conn.(*ObservedConn); ok {
+		observed.setCloseReason(reason)
+	}
+}
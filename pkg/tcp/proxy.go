@@ -1,6 +1,7 @@
 package tcp
 
 import (
+	"crypto/tls"
 	"io"
 	"net"
 
@@ -9,17 +10,34 @@ import (
 
 // Proxy forwards a TCP request to a TCP service
 type Proxy struct {
-	target *net.TCPAddr
+	target    *net.TCPAddr
+	dialer    *net.Dialer
+	tlsConfig *tls.Config
 }
 
-// NewProxy creates a new Proxy
-func NewProxy(address string) (*Proxy, error) {
+// NewProxy creates a new Proxy, dialing the service with dialer,
+// and originating TLS to the service if tlsConfig is not nil.
+func NewProxy(address string, dialer *net.Dialer, tlsConfig *tls.Config) (*Proxy, error) {
 	tcpAddr, err := net.ResolveTCPAddr("tcp", address)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Proxy{target: tcpAddr}, nil
+	if dialer == nil {
+		dialer = &net.Dialer{}
+	}
+
+	if tlsConfig != nil && len(tlsConfig.ServerName) == 0 {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig = tlsConfig.Clone()
+		tlsConfig.ServerName = host
+	}
+
+	return &Proxy{target: tcpAddr, dialer: dialer, tlsConfig: tlsConfig}, nil
 }
 
 // ServeTCP forwards the connection to a service
@@ -27,24 +45,46 @@ func (p *Proxy) ServeTCP(conn net.Conn) {
 	log.Debugf("Handling connection from %s", conn.RemoteAddr())
 	defer conn.Close()
 
-	connBackend, err := net.DialTCP("tcp", nil, p.target)
+	recordBackendAddress(conn, p.target.String())
+
+	connBackend, err := p.dialBackend()
 	if err != nil {
+		recordCloseReason(conn, CloseReasonDialError)
 		log.Errorf("Error while connection to backend: %v", err)
 		return
 	}
 	defer connBackend.Close()
 
-	errChan := make(chan error, 1)
-	go connCopy(conn, connBackend, errChan)
-	go connCopy(connBackend, conn, errChan)
+	resultChan := make(chan copyResult, 1)
+	go connCopy(conn, connBackend, CloseReasonBackendClosed, resultChan)
+	go connCopy(connBackend, conn, CloseReasonClientClosed, resultChan)
 
-	err = <-errChan
-	if err != nil {
-		log.Errorf("Error during connection: %v", err)
+	result := <-resultChan
+	if result.err != nil {
+		recordCloseReason(conn, CloseReasonCopyError)
+		log.Errorf("Error during connection: %v", result.err)
+		return
 	}
+
+	recordCloseReason(conn, result.closeReason)
+}
+
+func (p *Proxy) dialBackend() (net.Conn, error) {
+	if p.tlsConfig == nil {
+		return p.dialer.Dial("tcp", p.target.String())
+	}
+
+	return tls.DialWithDialer(p.dialer, "tcp", p.target.String(), p.tlsConfig)
+}
+
+// copyResult is the outcome of copying from one side of the proxy to the other,
+// closeReason being the side that closed the connection when no error occurred.
+type copyResult struct {
+	closeReason string
+	err         error
 }
 
-func connCopy(dst, src net.Conn, errCh chan error) {
+func connCopy(dst, src net.Conn, closeReason string, resultChan chan copyResult) {
 	_, err := io.Copy(dst, src)
-	errCh <- err
+	resultChan <- copyResult{closeReason: closeReason, err: err}
 }
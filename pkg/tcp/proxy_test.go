@@ -0,0 +1,242 @@
+package tcp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxy_ServeTCPWithTLS(t *testing.T) {
+	serverCert, serverRoots := generateTestCertificate(t, "db.example.com")
+	clientCert, clientRoots := generateTestCertificate(t, "client")
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientRoots,
+	})
+	require.NoError(t, err)
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(conn *tls.Conn) {
+				defer conn.Close()
+
+				if err := conn.Handshake(); err != nil {
+					return
+				}
+
+				_, _ = conn.Write([]byte(conn.ConnectionState().PeerCertificates[0].Subject.CommonName))
+			}(conn.(*tls.Conn))
+		}
+	}()
+
+	testCases := []struct {
+		desc      string
+		tlsConfig *tls.Config
+		expected  string
+	}{
+		{
+			desc: "valid server name and client certificate",
+			tlsConfig: &tls.Config{
+				ServerName:   "db.example.com",
+				RootCAs:      serverRoots,
+				Certificates: []tls.Certificate{clientCert},
+			},
+			expected: "client",
+		},
+		{
+			desc: "without client certificate",
+			tlsConfig: &tls.Config{
+				ServerName: "db.example.com",
+				RootCAs:    serverRoots,
+			},
+		},
+		{
+			desc: "invalid server name",
+			tlsConfig: &tls.Config{
+				ServerName:   "foo.com",
+				RootCAs:      serverRoots,
+				Certificates: []tls.Certificate{clientCert},
+			},
+		},
+		{
+			desc: "unknown authority",
+			tlsConfig: &tls.Config{
+				ServerName:   "db.example.com",
+				Certificates: []tls.Certificate{clientCert},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			proxy, err := NewProxy(listener.Addr().String(), &net.Dialer{Timeout: time.Second}, test.tlsConfig)
+			require.NoError(t, err)
+
+			clientConn, proxyConn := net.Pipe()
+			defer clientConn.Close()
+
+			go proxy.ServeTCP(proxyConn)
+
+			require.NoError(t, clientConn.SetReadDeadline(time.Now().Add(5*time.Second)))
+			data, _ := ioutil.ReadAll(clientConn)
+
+			assert.Equal(t, test.expected, string(data))
+		})
+	}
+}
+
+func TestNewProxy_defaultServerName(t *testing.T) {
+	tlsConfig := &tls.Config{}
+
+	proxy, err := NewProxy("127.0.0.1:5432", nil, tlsConfig)
+	require.NoError(t, err)
+
+	assert.Equal(t, "127.0.0.1", proxy.tlsConfig.ServerName)
+	assert.Empty(t, tlsConfig.ServerName)
+}
+
+func TestProxy_ServeTCPObserved(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(conn net.Conn) {
+				defer conn.Close()
+
+				// The client asking to quit is served a goodbye and disconnected.
+				data := make([]byte, 4)
+				if _, err := io.ReadFull(conn, data); err != nil || string(data) != "quit" {
+					_, _ = io.Copy(ioutil.Discard, conn)
+					return
+				}
+				_, _ = conn.Write([]byte("bye"))
+			}(conn)
+		}
+	}()
+
+	unreachable, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, unreachable.Close())
+
+	testCases := []struct {
+		desc                 string
+		address              string
+		message              string
+		expectedCloseReason  string
+		expectedBytesRead    int64
+		expectedBytesWritten int64
+	}{
+		{
+			desc:                 "closed by the backend",
+			address:              listener.Addr().String(),
+			message:              "quit",
+			expectedCloseReason:  CloseReasonBackendClosed,
+			expectedBytesRead:    4,
+			expectedBytesWritten: 3,
+		},
+		{
+			desc:                "closed by the client",
+			address:             listener.Addr().String(),
+			message:             "hello",
+			expectedCloseReason: CloseReasonClientClosed,
+			expectedBytesRead:   5,
+		},
+		{
+			desc:                "dial error",
+			address:             unreachable.Addr().String(),
+			expectedCloseReason: CloseReasonDialError,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			proxy, err := NewProxy(test.address, &net.Dialer{Timeout: time.Second}, nil)
+			require.NoError(t, err)
+
+			clientConn, proxyConn := net.Pipe()
+			observed := Observe(proxyConn)
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				proxy.ServeTCP(observed)
+			}()
+
+			require.NoError(t, clientConn.SetDeadline(time.Now().Add(5*time.Second)))
+			if len(test.message) > 0 {
+				_, err = clientConn.Write([]byte(test.message))
+				require.NoError(t, err)
+			}
+			if test.expectedBytesWritten == 0 {
+				require.NoError(t, clientConn.Close())
+			} else {
+				_, _ = ioutil.ReadAll(clientConn)
+			}
+
+			<-done
+
+			assert.Equal(t, test.address, observed.BackendAddress())
+			assert.Equal(t, test.expectedCloseReason, observed.CloseReason())
+			assert.Equal(t, test.expectedBytesRead, observed.BytesRead())
+			assert.Equal(t, test.expectedBytesWritten, observed.BytesWritten())
+		})
+	}
+}
+
+// generateTestCertificate generates a self-signed certificate for the name, and the pool trusting it.
+func generateTestCertificate(t *testing.T, name string) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}, pool
+}
@@ -49,21 +49,27 @@ func (r *Router) ServeTCP(conn net.Conn) {
 
 	// FIXME Optimize and test the routing table before helloServerName
 	serverName = strings.ToLower(serverName)
+	tlsConn := &Conn{
+		Peeked:     []byte(peeked),
+		Conn:       conn,
+		ServerName: serverName,
+	}
+
 	if r.routingTable != nil && serverName != "" {
 		if target, ok := r.routingTable[serverName]; ok {
-			target.ServeTCP(r.GetConn(conn, peeked))
+			target.ServeTCP(tlsConn)
 			return
 		}
 	}
 
 	// FIXME Needs tests
 	if target, ok := r.routingTable["*"]; ok {
-		target.ServeTCP(r.GetConn(conn, peeked))
+		target.ServeTCP(tlsConn)
 		return
 	}
 
 	if r.httpsForwarder != nil {
-		r.httpsForwarder.ServeTCP(r.GetConn(conn, peeked))
+		r.httpsForwarder.ServeTCP(tlsConn)
 	} else {
 		conn.Close()
 	}
@@ -154,6 +160,9 @@ type Conn struct {
 	// by Read calls. It set to nil by Read when fully consumed.
 	Peeked []byte
 
+	// ServerName is the server name sent by the client in the TLS ClientHello, if any.
+	ServerName string
+
 	// Conn is the underlying connection.
 	// It can be type asserted against *net.TCPConn or other types
 	// as needed. It should not be read from directly unless
@@ -22,7 +22,9 @@ func NewRRLoadBalancer() *RRLoadBalancer {
 // ServeTCP forwards the connection to the right service
 func (r *RRLoadBalancer) ServeTCP(conn net.Conn) {
 	if len(r.servers) == 0 {
+		recordCloseReason(conn, CloseReasonNoServer)
 		log.WithoutContext().Error("no available server")
+		conn.Close()
 		return
 	}
 
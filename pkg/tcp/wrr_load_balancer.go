@@ -0,0 +1,70 @@
+package tcp
+
+import (
+	"net"
+	"sync"
+
+	"github.com/containous/traefik/pkg/log"
+)
+
+type weightedHandler struct {
+	Handler
+	weight  int
+	current int
+}
+
+// WRRLoadBalancer is a smooth weighted round robin load balancer for TCP services.
+type WRRLoadBalancer struct {
+	handlers []*weightedHandler
+	lock     sync.Mutex
+}
+
+// NewWRRLoadBalancer creates a new WRRLoadBalancer.
+func NewWRRLoadBalancer() *WRRLoadBalancer {
+	return &WRRLoadBalancer{}
+}
+
+// ServeTCP forwards the connection to the right service.
+func (b *WRRLoadBalancer) ServeTCP(conn net.Conn) {
+	next := b.next()
+	if next == nil {
+		log.WithoutContext().Error("no available server")
+		conn.Close()
+		return
+	}
+
+	next.ServeTCP(conn)
+}
+
+// AddWeightServer appends a handler with the given weight to the existing list.
+// A handler with a weight of zero never receives any connection.
+func (b *WRRLoadBalancer) AddWeightServer(handler Handler, weight int) {
+	b.handlers = append(b.handlers, &weightedHandler{Handler: handler, weight: weight})
+}
+
+func (b *WRRLoadBalancer) next() Handler {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var total int
+	var best *weightedHandler
+	for _, handler := range b.handlers {
+		if handler.weight <= 0 {
+			continue
+		}
+
+		handler.current += handler.weight
+		total += handler.weight
+
+		if best == nil || handler.current > best.current {
+			best = handler
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+
+	best.current -= total
+	return best.Handler
+}
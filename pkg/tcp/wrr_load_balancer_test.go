@@ -0,0 +1,32 @@
+package tcp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type namedHandler struct {
+	name  string
+	calls map[string]int
+}
+
+func (h namedHandler) ServeTCP(conn net.Conn) {
+	h.calls[h.name]++
+}
+
+func TestWRRLoadBalancer(t *testing.T) {
+	calls := make(map[string]int)
+
+	balancer := NewWRRLoadBalancer()
+	balancer.AddWeightServer(namedHandler{name: "a", calls: calls}, 3)
+	balancer.AddWeightServer(namedHandler{name: "b", calls: calls}, 1)
+	balancer.AddWeightServer(namedHandler{name: "c", calls: calls}, 0)
+
+	for i := 0; i < 8; i++ {
+		balancer.ServeTCP(nil)
+	}
+
+	assert.Equal(t, map[string]int{"a": 6, "b": 2}, calls)
+}
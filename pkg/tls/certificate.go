@@ -22,6 +22,22 @@ var (
 		`VersionTLS13`: tls.VersionTLS13,
 	}
 
+	// MaxVersion Map of allowed TLS maximum versions
+	MaxVersion = map[string]uint16{
+		`VersionTLS10`: tls.VersionTLS10,
+		`VersionTLS11`: tls.VersionTLS11,
+		`VersionTLS12`: tls.VersionTLS12,
+		`VersionTLS13`: tls.VersionTLS13,
+	}
+
+	// Curves Map of TLS elliptic curves from crypto/tls
+	Curves = map[string]tls.CurveID{
+		`CurveP256`: tls.CurveP256,
+		`CurveP384`: tls.CurveP384,
+		`CurveP521`: tls.CurveP521,
+		`X25519`:    tls.X25519,
+	}
+
 	// CipherSuites Map of TLS CipherSuites from crypto/tls
 	// Available CipherSuites defined at https://golang.org/pkg/crypto/tls/#pkg-constants
 	CipherSuites = map[string]uint16{
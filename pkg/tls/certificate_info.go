@@ -0,0 +1,207 @@
+package tls
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"sort"
+	"time"
+)
+
+// CertificateInfo holds the description of a certificate served by Traefik.
+type CertificateInfo struct {
+	CommonName    string    `json:"commonName,omitempty"`
+	Domains       []string  `json:"domains,omitempty"`
+	SANs          []string  `json:"sans,omitempty"`
+	Issuer        string    `json:"issuer"`
+	SerialNumber  string    `json:"serialNumber"`
+	NotBefore     time.Time `json:"notBefore"`
+	NotAfter      time.Time `json:"notAfter"`
+	Fingerprint   string    `json:"fingerprint"`
+	Provider      string    `json:"provider,omitempty"`
+	Stores        []string  `json:"stores,omitempty"`
+	DefaultStores []string  `json:"defaultStores,omitempty"`
+}
+
+// OptionsInfo holds the description of TLS options, without their secrets.
+type OptionsInfo struct {
+	Name                   string   `json:"name"`
+	MinVersion             string   `json:"minVersion,omitempty"`
+	MaxVersion             string   `json:"maxVersion,omitempty"`
+	CipherSuites           []string `json:"cipherSuites,omitempty"`
+	CurvePreferences       []string `json:"curvePreferences,omitempty"`
+	ALPNProtocols          []string `json:"alpnProtocols,omitempty"`
+	ClientAuthType         string   `json:"clientAuthType,omitempty"`
+	SniStrict              bool     `json:"sniStrict,omitempty"`
+	SessionTicketsDisabled bool     `json:"sessionTicketsDisabled,omitempty"`
+	Error                  string   `json:"error,omitempty"`
+}
+
+// GetCertificates returns the description of the certificates of all the stores, sorted by expiration date.
+func (m *Manager) GetCertificates() []CertificateInfo {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return append([]CertificateInfo{}, m.certificates...)
+}
+
+// GetOptions returns the description of the TLS options, sorted by name.
+func (m *Manager) GetOptions() []OptionsInfo {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	configs := make(map[string]TLS, len(m.configs)+1)
+	configs["default"] = TLS{}
+	for name, config := range m.configs {
+		configs[name] = config
+	}
+
+	var results []OptionsInfo
+	for name, config := range configs {
+		info := OptionsInfo{
+			Name:                   name,
+			MinVersion:             config.MinVersion,
+			MaxVersion:             config.MaxVersion,
+			CipherSuites:           config.CipherSuites,
+			CurvePreferences:       config.CurvePreferences,
+			ALPNProtocols:          config.ALPNProtocols,
+			ClientAuthType:         config.ClientCA.ClientAuthType,
+			SniStrict:              config.SniStrict,
+			SessionTicketsDisabled: config.SessionTickets.Disabled,
+		}
+
+		if clientAuthType, err := getClientAuthType(config.ClientCA); err == nil {
+			info.ClientAuthType = clientAuthTypeName(clientAuthType)
+		}
+
+		if err := m.verifierErrs[name]; err != nil {
+			info.Error = err.Error()
+		} else if _, err := buildTLSConfig(config); err != nil {
+			info.Error = err.Error()
+		}
+
+		results = append(results, info)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	return results
+}
+
+func clientAuthTypeName(clientAuthType tls.ClientAuthType) string {
+	for name, value := range ClientAuthTypes {
+		if value == clientAuthType {
+			return name
+		}
+	}
+	return ""
+}
+
+// buildCertificatesInfo describes the certificates of the stores, including their default certificates.
+// The providers are the names of the providers of the certificates, indexed by fingerprint.
+func buildCertificatesInfo(stores map[string]*CertificateStore, providers map[string]string) []CertificateInfo {
+	infos := make(map[string]*CertificateInfo)
+
+	getInfo := func(cert *tls.Certificate) *CertificateInfo {
+		if cert == nil || len(cert.Certificate) == 0 {
+			return nil
+		}
+
+		certFingerprint := fingerprint(cert.Certificate[0])
+		if info, ok := infos[certFingerprint]; ok {
+			return info
+		}
+
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return nil
+		}
+
+		info := newCertificateInfo(leaf)
+		info.Provider = providers[certFingerprint]
+		infos[certFingerprint] = info
+		return info
+	}
+
+	for storeName, store := range stores {
+		if info := getInfo(store.DefaultCertificate); info != nil {
+			info.DefaultStores = append(info.DefaultStores, storeName)
+		}
+
+		for _, cert := range store.DynamicCerts.Get().(map[string]*tls.Certificate) {
+			if info := getInfo(cert); info != nil {
+				info.Stores = append(info.Stores, storeName)
+			}
+		}
+	}
+
+	results := make([]CertificateInfo, 0, len(infos))
+	for _, info := range infos {
+		sort.Strings(info.Stores)
+		sort.Strings(info.DefaultStores)
+		results = append(results, *info)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].NotAfter.Equal(results[j].NotAfter) {
+			return results[i].Fingerprint < results[j].Fingerprint
+		}
+		return results[i].NotAfter.Before(results[j].NotAfter)
+	})
+
+	return results
+}
+
+func newCertificateInfo(leaf *x509.Certificate) *CertificateInfo {
+	var sans []string
+	sans = append(sans, leaf.DNSNames...)
+	sans = append(sans, leaf.EmailAddresses...)
+	for _, ip := range leaf.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, uri := range leaf.URIs {
+		sans = append(sans, uri.String())
+	}
+
+	return &CertificateInfo{
+		CommonName:   leaf.Subject.CommonName,
+		Domains:      certificateDomains(leaf),
+		SANs:         sans,
+		Issuer:       leaf.Issuer.String(),
+		SerialNumber: leaf.SerialNumber.String(),
+		NotBefore:    leaf.NotBefore,
+		NotAfter:     leaf.NotAfter,
+		Fingerprint:  fingerprint(leaf.Raw),
+	}
+}
+
+// certificateFingerprint returns the fingerprint of the first certificate of the certificate file.
+func certificateFingerprint(cert *Certificate) (string, error) {
+	content, err := cert.CertFile.Read()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		var block *pem.Block
+		block, content = pem.Decode(content)
+		if block == nil {
+			return "", errors.New("no certificate found")
+		}
+
+		if block.Type == "CERTIFICATE" {
+			return fingerprint(block.Bytes), nil
+		}
+	}
+}
+
+// fingerprint returns the SHA-256 fingerprint of the DER encoded certificate.
+func fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:])
+}
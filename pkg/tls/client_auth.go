@@ -0,0 +1,452 @@
+package tls
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/pkg/log"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	// crlCheckInterval is the minimal interval between two checks of the modification of the CRL files.
+	crlCheckInterval = 10 * time.Second
+	// ocspCacheSize is the maximal number of OCSP responses cached by a verifier.
+	ocspCacheSize = 1000
+	// ocspHandshakeTimeout is the maximal duration of an OCSP request made during a handshake.
+	ocspHandshakeTimeout = 2 * time.Second
+	// ocspFailureCacheDuration is the duration during which an OCSP request which failed is not made again.
+	ocspFailureCacheDuration = 30 * time.Second
+)
+
+// ClientAuthTypes Map of the client authentication types from crypto/tls.
+var ClientAuthTypes = map[string]tls.ClientAuthType{
+	`RequireAndVerifyClientCert`: tls.RequireAndVerifyClientCert,
+	`VerifyClientCertIfGiven`:    tls.VerifyClientCertIfGiven,
+	`RequireAnyClientCert`:       tls.RequireAnyClientCert,
+}
+
+// getClientAuthType returns the client authentication type of the TLS options.
+func getClientAuthType(clientCA ClientCA) (tls.ClientAuthType, error) {
+	if len(clientCA.ClientAuthType) == 0 {
+		if len(clientCA.Files) == 0 {
+			return tls.NoClientCert, nil
+		}
+
+		if clientCA.Optional {
+			return tls.VerifyClientCertIfGiven, nil
+		}
+		return tls.RequireAndVerifyClientCert, nil
+	}
+
+	clientAuthType, ok := ClientAuthTypes[clientCA.ClientAuthType]
+	if !ok {
+		return tls.NoClientCert, fmt.Errorf("invalid ClientAuthType: %s", clientCA.ClientAuthType)
+	}
+
+	if clientAuthType != tls.RequireAnyClientCert && len(clientCA.Files) == 0 {
+		return tls.NoClientCert, fmt.Errorf("the ClientAuthType %s requires CA files", clientCA.ClientAuthType)
+	}
+
+	return clientAuthType, nil
+}
+
+// clientCertVerifier checks the client certificates against the revocation lists, the OCSP responders, and the allowed names.
+type clientCertVerifier struct {
+	optionsName  string
+	crls         *crlList
+	ocsp         *ocspStapler
+	ocspCheck    bool
+	ocspSoftFail bool
+	ocspTimeout  time.Duration
+	ocspCache    map[[sha256.Size]byte]*ocspLookup
+	ocspInflight map[[sha256.Size]byte]*ocspLookup
+	ocspLock     sync.Mutex
+	subjects     []*regexp.Regexp
+	sans         []*regexp.Regexp
+}
+
+// ocspLookup is the OCSP request of a client certificate, shared by the handshakes waiting for it,
+// and cached until the response expires, or for a short while if the request failed.
+type ocspLookup struct {
+	done     chan struct{}
+	response *ocsp.Response
+	err      error
+	expiry   time.Time
+}
+
+// newClientCertVerifier creates the verifier of the client certificates of the TLS options,
+// it returns nil if the options do not require any check in addition to the chain verification.
+func newClientCertVerifier(optionsName string, clientCA ClientCA, clientAuthType tls.ClientAuthType, stapler *ocspStapler) (*clientCertVerifier, error) {
+	if len(clientCA.CRLFiles) == 0 && !clientCA.OCSPCheck && len(clientCA.AllowedSubjects) == 0 && len(clientCA.AllowedSANs) == 0 {
+		return nil, nil
+	}
+
+	// The issuer and the OCSP responder of an unverified certificate are chosen by the client.
+	if clientAuthType == tls.RequireAnyClientCert && (len(clientCA.CRLFiles) > 0 || clientCA.OCSPCheck) {
+		return nil, errors.New("the revocation checks require the client certificates to be verified, which RequireAnyClientCert does not")
+	}
+
+	verifier := &clientCertVerifier{
+		optionsName:  optionsName,
+		ocsp:         stapler,
+		ocspCheck:    clientCA.OCSPCheck,
+		ocspSoftFail: clientCA.OCSPSoftFail,
+		ocspTimeout:  ocspHandshakeTimeout,
+		ocspCache:    make(map[[sha256.Size]byte]*ocspLookup),
+		ocspInflight: make(map[[sha256.Size]byte]*ocspLookup),
+	}
+
+	var err error
+	verifier.subjects, err = compilePatterns(clientCA.AllowedSubjects)
+	if err != nil {
+		return nil, fmt.Errorf("invalid AllowedSubjects: %v", err)
+	}
+
+	verifier.sans, err = compilePatterns(clientCA.AllowedSANs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid AllowedSANs: %v", err)
+	}
+
+	if len(clientCA.CRLFiles) > 0 {
+		verifier.crls, err = newCRLList(clientCA.CRLFiles)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return verifier, nil
+}
+
+// compilePatterns compiles the regular expressions, which must match the whole value.
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var regexps []*regexp.Regexp
+	for _, pattern := range patterns {
+		exp, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, err
+		}
+		regexps = append(regexps, exp)
+	}
+	return regexps, nil
+}
+
+// VerifyPeerCertificate checks the client certificate, and logs the reason of its rejection.
+func (v *clientCertVerifier) VerifyPeerCertificate(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	err := v.verify(rawCerts, verifiedChains)
+	if err != nil {
+		log.WithoutContext().WithField("tlsOptions", v.optionsName).Warnf("Client certificate rejected: %v", err)
+	}
+	return err
+}
+
+func (v *clientCertVerifier) verify(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return nil
+	}
+
+	// Without verification (RequireAnyClientCert), the chain is the one sent by the client, and does not end with a root.
+	var chain []*x509.Certificate
+	verified := len(verifiedChains) > 0
+	if verified {
+		chain = verifiedChains[0]
+	} else {
+		for _, rawCert := range rawCerts {
+			cert, err := x509.ParseCertificate(rawCert)
+			if err != nil {
+				return err
+			}
+			chain = append(chain, cert)
+		}
+	}
+
+	leaf := chain[0]
+	if err := v.checkNames(leaf); err != nil {
+		return err
+	}
+
+	// The revocation of an unverified chain cannot be trusted.
+	if !verified {
+		return nil
+	}
+
+	for i, cert := range chain {
+		if i == len(chain)-1 && i > 0 {
+			// The root of a verified chain is trusted.
+			break
+		}
+
+		var issuer *x509.Certificate
+		if i+1 < len(chain) {
+			issuer = chain[i+1]
+		}
+
+		if v.crls != nil && v.crls.isRevoked(cert, issuer) {
+			return fmt.Errorf("the certificate %q (serial %s) is revoked by a CRL", cert.Subject.CommonName, cert.SerialNumber)
+		}
+	}
+
+	if v.ocspCheck {
+		return v.checkOCSP(chain)
+	}
+
+	return nil
+}
+
+// checkNames checks the subject common name and the SANs of the certificate against the allowed patterns.
+func (v *clientCertVerifier) checkNames(cert *x509.Certificate) error {
+	if len(v.subjects) > 0 && !matchAny(v.subjects, cert.Subject.CommonName) {
+		return fmt.Errorf("the subject %q of the certificate is not allowed", cert.Subject.CommonName)
+	}
+
+	if len(v.sans) == 0 {
+		return nil
+	}
+
+	var sans []string
+	sans = append(sans, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+
+	for _, san := range sans {
+		if matchAny(v.sans, san) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("none of the SANs %v of the certificate %q is allowed", sans, cert.Subject.CommonName)
+}
+
+func matchAny(regexps []*regexp.Regexp, value string) bool {
+	for _, exp := range regexps {
+		if exp.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkOCSP checks the status of the leaf certificate with its OCSP responder.
+func (v *clientCertVerifier) checkOCSP(chain []*x509.Certificate) error {
+	leaf := chain[0]
+
+	responder := v.ocsp.responderURL(leaf)
+	if len(responder) == 0 || len(chain) < 2 {
+		return v.ocspFailure(leaf, errors.New("no OCSP responder or issuer"))
+	}
+
+	response, err := v.getOCSPResponse(responder, leaf, chain[1])
+	if err != nil {
+		return v.ocspFailure(leaf, err)
+	}
+
+	switch response.Status {
+	case ocsp.Good:
+		return nil
+	case ocsp.Revoked:
+		return fmt.Errorf("the certificate %q (serial %s) has been revoked on %s", leaf.Subject.CommonName, leaf.SerialNumber, response.RevokedAt)
+	default:
+		return v.ocspFailure(leaf, errors.New("unknown OCSP status"))
+	}
+}
+
+// ocspFailure returns the error of the OCSP check, or nil if the soft-fail mode is enabled.
+func (v *clientCertVerifier) ocspFailure(leaf *x509.Certificate, err error) error {
+	if v.ocspSoftFail {
+		log.WithoutContext().WithField("tlsOptions", v.optionsName).
+			Debugf("Unable to check the OCSP status of the certificate %q, accepted in soft-fail mode: %v", leaf.Subject.CommonName, err)
+		return nil
+	}
+	return fmt.Errorf("unable to check the OCSP status of the certificate %q: %v", leaf.Subject.CommonName, err)
+}
+
+// getOCSPResponse returns the OCSP response of the certificate, cached until its next update.
+// A failed request is cached for a short while, and the concurrent handshakes of a certificate share the same request.
+func (v *clientCertVerifier) getOCSPResponse(responder string, leaf, issuer *x509.Certificate) (*ocsp.Response, error) {
+	fingerprint := sha256.Sum256(leaf.Raw)
+
+	v.ocspLock.Lock()
+	if lookup, ok := v.ocspCache[fingerprint]; ok && time.Now().Before(lookup.expiry) {
+		v.ocspLock.Unlock()
+		return lookup.response, lookup.err
+	}
+
+	if lookup, ok := v.ocspInflight[fingerprint]; ok {
+		v.ocspLock.Unlock()
+		<-lookup.done
+		return lookup.response, lookup.err
+	}
+
+	lookup := &ocspLookup{done: make(chan struct{})}
+	v.ocspInflight[fingerprint] = lookup
+	v.ocspLock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), v.ocspTimeout)
+	_, lookup.response, lookup.err = v.ocsp.query(ctx, responder, leaf, issuer)
+	cancel()
+
+	if lookup.err != nil {
+		lookup.expiry = time.Now().Add(ocspFailureCacheDuration)
+	} else {
+		lookup.expiry = ocspResponseExpiry(lookup.response)
+	}
+
+	v.ocspLock.Lock()
+	if len(v.ocspCache) >= ocspCacheSize {
+		v.evictOCSPResponses()
+	}
+	v.ocspCache[fingerprint] = lookup
+	delete(v.ocspInflight, fingerprint)
+	v.ocspLock.Unlock()
+
+	close(lookup.done)
+	return lookup.response, lookup.err
+}
+
+// evictOCSPResponses removes the expired responses from the cache, or an arbitrary one if none has expired.
+// It must be called with the lock held.
+func (v *clientCertVerifier) evictOCSPResponses() {
+	now := time.Now()
+	for fingerprint, lookup := range v.ocspCache {
+		if !now.Before(lookup.expiry) {
+			delete(v.ocspCache, fingerprint)
+		}
+	}
+
+	if len(v.ocspCache) < ocspCacheSize {
+		return
+	}
+
+	for fingerprint := range v.ocspCache {
+		delete(v.ocspCache, fingerprint)
+		return
+	}
+}
+
+// ocspResponseExpiry returns the date until which the response can be used.
+func ocspResponseExpiry(response *ocsp.Response) time.Time {
+	if response.NextUpdate.IsZero() {
+		return response.ThisUpdate.Add(ocspDefaultRefreshInterval)
+	}
+	return response.NextUpdate
+}
+
+// crlList holds the certificate revocation lists, reloaded when their files are modified.
+type crlList struct {
+	files         []string
+	checkInterval time.Duration
+	lastCheck     time.Time
+	modTimes      map[string]time.Time
+	crls          map[string]*pkix.CertificateList
+	lock          sync.Mutex
+}
+
+func newCRLList(files []string) (*crlList, error) {
+	list := &crlList{
+		files:         files,
+		checkInterval: crlCheckInterval,
+		modTimes:      make(map[string]time.Time),
+		crls:          make(map[string]*pkix.CertificateList),
+	}
+
+	for _, file := range files {
+		if err := list.load(file); err != nil {
+			return nil, err
+		}
+	}
+	list.lastCheck = time.Now()
+
+	return list, nil
+}
+
+// load reads and parses the CRL file, if it has been modified since it was last loaded.
+func (l *crlList) load(file string) error {
+	info, err := os.Stat(file)
+	if err != nil {
+		return fmt.Errorf("unable to read the CRL file %s: %v", file, err)
+	}
+
+	if modTime, ok := l.modTimes[file]; ok && modTime.Equal(info.ModTime()) {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("unable to read the CRL file %s: %v", file, err)
+	}
+
+	crl, err := x509.ParseCRL(data)
+	if err != nil {
+		return fmt.Errorf("unable to parse the CRL file %s: %v", file, err)
+	}
+
+	l.crls[file] = crl
+	l.modTimes[file] = info.ModTime()
+	return nil
+}
+
+// reload reloads the modified CRL files, at most once per check interval.
+// A CRL file which cannot be reloaded keeps its previous content.
+func (l *crlList) reload() {
+	if time.Since(l.lastCheck) < l.checkInterval {
+		return
+	}
+	l.lastCheck = time.Now()
+
+	for _, file := range l.files {
+		if err := l.load(file); err != nil {
+			log.WithoutContext().Errorf("Keeping the previous revocation list: %v", err)
+		}
+	}
+}
+
+// isRevoked returns true if the certificate is listed in one of the CRLs of its issuer.
+func (l *crlList) isRevoked(cert, issuer *x509.Certificate) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.reload()
+
+	for file, crl := range l.crls {
+		crlIssuer, err := asn1.Marshal(crl.TBSCertList.Issuer)
+		if err != nil || !bytes.Equal(crlIssuer, cert.RawIssuer) {
+			continue
+		}
+
+		if issuer != nil {
+			if err := issuer.CheckCRLSignature(crl); err != nil {
+				log.WithoutContext().Errorf("Ignoring the revocation list %s: %v", file, err)
+				continue
+			}
+		}
+
+		if crl.HasExpired(time.Now()) {
+			log.WithoutContext().Warnf("The revocation list %s has expired", file)
+		}
+
+		for _, revoked := range crl.TBSCertList.RevokedCertificates {
+			if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,513 @@
+package tls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func TestClientAuth(t *testing.T) {
+	ca := newTestCA(t, "Test CA")
+	otherCA := newTestCA(t, "Other CA")
+
+	valid := ca.issue(t, 1, "client-1", "", "spiffe://example.org/client-1")
+	revoked := ca.issue(t, 2, "client-2", "", "")
+	other := ca.issue(t, 3, "other", "", "spiffe://other.org/other")
+	unknown := otherCA.issue(t, 4, "client-4", "", "")
+
+	dir, err := ioutil.TempDir("", "traefik-crl")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	crlFile := filepath.Join(dir, "ca.crl")
+	ca.writeCRL(t, crlFile, revoked)
+
+	caFile := FileOrContent(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}))
+
+	testCases := []struct {
+		desc          string
+		clientCA      ClientCA
+		clientCert    *tls.Certificate
+		expectedError bool
+	}{
+		{
+			desc:       "valid certificate",
+			clientCA:   ClientCA{Files: []FileOrContent{caFile}, CRLFiles: []string{crlFile}},
+			clientCert: valid,
+		},
+		{
+			desc:          "revoked certificate",
+			clientCA:      ClientCA{Files: []FileOrContent{caFile}, CRLFiles: []string{crlFile}},
+			clientCert:    revoked,
+			expectedError: true,
+		},
+		{
+			desc:          "unknown authority",
+			clientCA:      ClientCA{Files: []FileOrContent{caFile}},
+			clientCert:    unknown,
+			expectedError: true,
+		},
+		{
+			desc:       "unknown authority with RequireAnyClientCert",
+			clientCA:   ClientCA{Files: []FileOrContent{caFile}, ClientAuthType: "RequireAnyClientCert"},
+			clientCert: unknown,
+		},
+		{
+			desc:          "no certificate",
+			clientCA:      ClientCA{Files: []FileOrContent{caFile}},
+			expectedError: true,
+		},
+		{
+			desc:     "no certificate with VerifyClientCertIfGiven",
+			clientCA: ClientCA{Files: []FileOrContent{caFile}, ClientAuthType: "VerifyClientCertIfGiven", CRLFiles: []string{crlFile}},
+		},
+		{
+			desc:          "revoked certificate with VerifyClientCertIfGiven",
+			clientCA:      ClientCA{Files: []FileOrContent{caFile}, ClientAuthType: "VerifyClientCertIfGiven", CRLFiles: []string{crlFile}},
+			clientCert:    revoked,
+			expectedError: true,
+		},
+		{
+			desc:       "allowed subject",
+			clientCA:   ClientCA{Files: []FileOrContent{caFile}, AllowedSubjects: []string{"client-.*"}},
+			clientCert: valid,
+		},
+		{
+			desc:          "subject not allowed",
+			clientCA:      ClientCA{Files: []FileOrContent{caFile}, AllowedSubjects: []string{"client-.*"}},
+			clientCert:    other,
+			expectedError: true,
+		},
+		{
+			desc:       "allowed SAN",
+			clientCA:   ClientCA{Files: []FileOrContent{caFile}, AllowedSANs: []string{`spiffe://example\.org/.*`}},
+			clientCert: valid,
+		},
+		{
+			desc:          "SAN not allowed",
+			clientCA:      ClientCA{Files: []FileOrContent{caFile}, AllowedSANs: []string{`spiffe://example\.org/.*`}},
+			clientCert:    other,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			tlsManager := NewManager(nil)
+			tlsManager.UpdateConfigs(nil, map[string]TLS{"default": {ClientCA: test.clientCA}}, nil)
+
+			tlsConfig, err := tlsManager.Get("default", "default")
+			require.NoError(t, err)
+
+			err = handshake(tlsConfig, test.clientCert)
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClientAuth_invalidOptions(t *testing.T) {
+	ca := newTestCA(t, "Test CA")
+	caFile := FileOrContent(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}))
+
+	testCases := []struct {
+		desc     string
+		clientCA ClientCA
+	}{
+		{
+			desc:     "unknown client authentication type",
+			clientCA: ClientCA{ClientAuthType: "foo"},
+		},
+		{
+			desc:     "verification without CA files",
+			clientCA: ClientCA{ClientAuthType: "RequireAndVerifyClientCert"},
+		},
+		{
+			desc:     "missing CRL file",
+			clientCA: ClientCA{Files: []FileOrContent{caFile}, CRLFiles: []string{"/not/a/file.crl"}},
+		},
+		{
+			desc:     "revocation list without verification",
+			clientCA: ClientCA{ClientAuthType: "RequireAnyClientCert", CRLFiles: []string{"/not/a/file.crl"}},
+		},
+		{
+			desc:     "OCSP check without verification",
+			clientCA: ClientCA{Files: []FileOrContent{caFile}, ClientAuthType: "RequireAnyClientCert", OCSPCheck: true},
+		},
+		{
+			desc:     "invalid subject pattern",
+			clientCA: ClientCA{ClientAuthType: "RequireAnyClientCert", AllowedSubjects: []string{"("}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			tlsManager := NewManager(nil)
+			tlsManager.UpdateConfigs(nil, map[string]TLS{"foo": {ClientCA: test.clientCA}}, nil)
+
+			_, err := tlsManager.Get("default", "foo")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestClientAuth_OCSP(t *testing.T) {
+	ca := newTestCA(t, "Test CA")
+
+	responder := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		ocspRequest, err := ocsp.ParseRequest(body)
+		require.NoError(t, err)
+
+		status := ocsp.Good
+		if ocspRequest.SerialNumber.Int64() == 2 {
+			status = ocsp.Revoked
+		}
+
+		response, err := ocsp.CreateResponse(ca.cert, ca.cert, ocsp.Response{
+			Status:       status,
+			SerialNumber: ocspRequest.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Hour),
+			NextUpdate:   time.Now().Add(time.Hour),
+			RevokedAt:    time.Now().Add(-time.Hour),
+		}, ca.key)
+		require.NoError(t, err)
+
+		_, _ = rw.Write(response)
+	}))
+	defer responder.Close()
+
+	caFile := FileOrContent(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}))
+
+	testCases := []struct {
+		desc          string
+		clientCert    *tls.Certificate
+		softFail      bool
+		expectedError bool
+	}{
+		{
+			desc:       "good certificate",
+			clientCert: ca.issue(t, 1, "client-1", responder.URL, ""),
+		},
+		{
+			desc:          "revoked certificate",
+			clientCert:    ca.issue(t, 2, "client-2", responder.URL, ""),
+			expectedError: true,
+		},
+		{
+			desc:          "revoked certificate in soft-fail mode",
+			clientCert:    ca.issue(t, 2, "client-2", responder.URL, ""),
+			softFail:      true,
+			expectedError: true,
+		},
+		{
+			desc:          "unavailable responder",
+			clientCert:    ca.issue(t, 3, "client-3", "http://127.0.0.1:1", ""),
+			expectedError: true,
+		},
+		{
+			desc:       "unavailable responder in soft-fail mode",
+			clientCert: ca.issue(t, 3, "client-3", "http://127.0.0.1:1", ""),
+			softFail:   true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			tlsManager := NewManager(nil)
+			tlsManager.UpdateConfigs(nil, map[string]TLS{"default": {ClientCA: ClientCA{
+				Files:        []FileOrContent{caFile},
+				OCSPCheck:    true,
+				OCSPSoftFail: test.softFail,
+			}}}, nil)
+
+			tlsConfig, err := tlsManager.Get("default", "default")
+			require.NoError(t, err)
+
+			err = handshake(tlsConfig, test.clientCert)
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClientCertVerifier_getOCSPResponse(t *testing.T) {
+	ca := newTestCA(t, "Test CA")
+
+	var queries int32
+	release := make(chan struct{})
+	responder := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&queries, 1)
+		_, _ = ioutil.ReadAll(req.Body)
+
+		select {
+		case <-release:
+		case <-req.Context().Done():
+		}
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer responder.Close()
+
+	verifier := &clientCertVerifier{
+		ocsp:         newOCSPStapler(nil),
+		ocspTimeout:  time.Second,
+		ocspCache:    make(map[[sha256.Size]byte]*ocspLookup),
+		ocspInflight: make(map[[sha256.Size]byte]*ocspLookup),
+	}
+
+	leaf := ca.issue(t, 1, "client-1", responder.URL, "").Leaf
+
+	// The concurrent handshakes share the same request.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := verifier.getOCSPResponse(responder.URL, leaf, ca.cert)
+			assert.Error(t, err)
+		}()
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&queries))
+
+	// The failure is cached.
+	_, err := verifier.getOCSPResponse(responder.URL, leaf, ca.cert)
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&queries))
+}
+
+func TestClientCertVerifier_getOCSPResponse_timeout(t *testing.T) {
+	ca := newTestCA(t, "Test CA")
+
+	responder := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// The request context is canceled on the client disconnection once the body is read.
+		_, _ = ioutil.ReadAll(req.Body)
+		<-req.Context().Done()
+	}))
+	defer responder.Close()
+
+	verifier := &clientCertVerifier{
+		ocsp:         newOCSPStapler(nil),
+		ocspTimeout:  50 * time.Millisecond,
+		ocspCache:    make(map[[sha256.Size]byte]*ocspLookup),
+		ocspInflight: make(map[[sha256.Size]byte]*ocspLookup),
+	}
+
+	leaf := ca.issue(t, 1, "client-1", responder.URL, "").Leaf
+
+	start := time.Now()
+	_, err := verifier.getOCSPResponse(responder.URL, leaf, ca.cert)
+	assert.Error(t, err)
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestClientCertVerifier_evictOCSPResponses(t *testing.T) {
+	verifier := &clientCertVerifier{ocspCache: make(map[[sha256.Size]byte]*ocspLookup)}
+
+	for i := 0; i < ocspCacheSize; i++ {
+		expiry := time.Now().Add(time.Hour)
+		if i%2 == 0 {
+			expiry = time.Now().Add(-time.Hour)
+		}
+		verifier.ocspCache[sha256.Sum256([]byte{byte(i), byte(i >> 8)})] = &ocspLookup{expiry: expiry}
+	}
+
+	verifier.evictOCSPResponses()
+	assert.Len(t, verifier.ocspCache, ocspCacheSize/2)
+
+	for i := 0; i < ocspCacheSize/2; i++ {
+		verifier.ocspCache[sha256.Sum256([]byte{byte(i), byte(i >> 8), 0})] = &ocspLookup{expiry: time.Now().Add(time.Hour)}
+	}
+
+	verifier.evictOCSPResponses()
+	assert.Len(t, verifier.ocspCache, ocspCacheSize-1)
+}
+
+func TestCRLList_reload(t *testing.T) {
+	ca := newTestCA(t, "Test CA")
+	revoked := ca.issue(t, 2, "client-2", "", "")
+
+	dir, err := ioutil.TempDir("", "traefik-crl")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	crlFile := filepath.Join(dir, "ca.crl")
+	ca.writeCRL(t, crlFile)
+
+	list, err := newCRLList([]string{crlFile})
+	require.NoError(t, err)
+	list.checkInterval = 0
+
+	assert.False(t, list.isRevoked(revoked.Leaf, ca.cert))
+
+	ca.writeCRL(t, crlFile, revoked)
+	require.NoError(t, os.Chtimes(crlFile, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+
+	assert.True(t, list.isRevoked(revoked.Leaf, ca.cert))
+
+	require.NoError(t, ioutil.WriteFile(crlFile, []byte("not a CRL"), 0600))
+	require.NoError(t, os.Chtimes(crlFile, time.Now().Add(2*time.Minute), time.Now().Add(2*time.Minute)))
+
+	assert.True(t, list.isRevoked(revoked.Leaf, ca.cert), "an invalid CRL file should not replace the previous one")
+}
+
+// handshake performs a TLS handshake between a client presenting the certificate, and a server using the TLS configuration.
+// It returns the error of the server side.
+func handshake(serverConfig *tls.Config, clientCert *tls.Certificate) error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = listener.Close() }()
+
+	clientConfig := &tls.Config{InsecureSkipVerify: true}
+	if clientCert != nil {
+		// The certificate is sent even if it is not issued by one of the CAs accepted by the server.
+		clientConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return clientCert, nil
+		}
+	}
+
+	go func() {
+		client, err := tls.Dial("tcp", listener.Addr().String(), clientConfig)
+		if err != nil {
+			return
+		}
+		_, _ = io.Copy(ioutil.Discard, client)
+		_ = client.Close()
+	}()
+
+	conn, err := listener.Accept()
+	if err != nil {
+		return err
+	}
+
+	server := tls.Server(conn, serverConfig)
+	err = server.Handshake()
+	_ = server.Close()
+
+	return err
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key}
+}
+
+// issue issues a client certificate, with an optional OCSP responder and URI SAN.
+func (c *testCA) issue(t *testing.T, serial int64, name, ocspServer, uri string) *tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	if len(ocspServer) > 0 {
+		template.OCSPServer = []string{ocspServer}
+	}
+
+	if len(uri) > 0 {
+		u, err := url.Parse(uri)
+		require.NoError(t, err)
+		template.URIs = []*url.URL{u}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, c.cert, &key.PublicKey, c.key)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// writeCRL writes a CRL revoking the certificates.
+func (c *testCA) writeCRL(t *testing.T, file string, revoked ...*tls.Certificate) {
+	t.Helper()
+
+	var revokedCerts []pkix.RevokedCertificate
+	for _, cert := range revoked {
+		revokedCerts = append(revokedCerts, pkix.RevokedCertificate{
+			SerialNumber:   cert.Leaf.SerialNumber,
+			RevocationTime: time.Now().Add(-time.Minute),
+		})
+	}
+
+	crl, err := c.cert.CreateCRL(rand.Reader, c.key, revokedCerts, time.Now().Add(-time.Minute), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: crl}), 0600))
+}
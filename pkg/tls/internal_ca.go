@@ -0,0 +1,373 @@
+package tls
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/types"
+	"golang.org/x/time/rate"
+)
+
+// internalCABackdate is the delay the issued certificates are valid before their issuance, to tolerate clock skews.
+const internalCABackdate = 5 * time.Minute
+
+// InternalCAConfig configures the certificates issued on demand by an internal certificate authority.
+type InternalCAConfig struct {
+	CertFile    FileOrContent        `description:"Certificate of the internal certificate authority." export:"true"`
+	KeyFile     FileOrContent        `description:"Private key of the internal certificate authority."`
+	Domains     []string             `description:"Domains allowed to be issued a certificate, '*.' matches all the subdomains." export:"true"`
+	Duration    types.Duration       `description:"Validity duration of the issued certificates." export:"true"`
+	RenewBefore types.Duration       `description:"Delay before the expiration of the issued certificates to renew them." export:"true"`
+	RateLimit   *InternalCARateLimit `description:"Issuance rate limit." export:"true"`
+	Storage     string               `description:"Directory where the issued certificates are stored. If empty, they are only kept in memory." export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *InternalCAConfig) SetDefaults() {
+	c.Duration = types.Duration(24 * time.Hour)
+	c.RenewBefore = types.Duration(8 * time.Hour)
+	c.RateLimit = &InternalCARateLimit{Average: 10, Burst: 10}
+}
+
+// InternalCARateLimit limits the number of certificates issued by the internal certificate authority.
+type InternalCARateLimit struct {
+	Average int64 `description:"Maximum number of certificates issued per minute." export:"true"`
+	Burst   int64 `description:"Maximum number of certificates issued at once." export:"true"`
+}
+
+// InternalCA issues certificates signed by a certificate authority on the first TLS handshake for a domain.
+type InternalCA struct {
+	config  InternalCAConfig
+	cert    *x509.Certificate
+	key     crypto.Signer
+	limiter *rate.Limiter
+
+	// lock only guards the maps, the certificates being loaded and issued without holding it.
+	lock     sync.Mutex
+	certs    map[string]*tls.Certificate
+	inflight map[string]*issuance
+}
+
+// issuance is the loading or the issuance of the certificate of a domain,
+// shared by the handshakes waiting for it.
+type issuance struct {
+	done chan struct{}
+	cert *tls.Certificate
+	err  error
+}
+
+// NewInternalCA creates an internal certificate authority from its configuration.
+func NewInternalCA(config *InternalCAConfig) (*InternalCA, error) {
+	if config == nil {
+		return nil, errors.New("no configuration")
+	}
+
+	certContent, err := config.CertFile.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read certificate: %v", err)
+	}
+
+	keyContent, err := config.KeyFile.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read private key: %v", err)
+	}
+
+	keyPair, err := tls.X509KeyPair(certContent, keyContent)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key pair: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate: %v", err)
+	}
+
+	if !cert.IsCA {
+		return nil, fmt.Errorf("certificate %s is not a certificate authority", cert.Subject)
+	}
+
+	key, ok := keyPair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported private key")
+	}
+
+	if config.Duration <= 0 {
+		return nil, fmt.Errorf("invalid duration: %s", time.Duration(config.Duration))
+	}
+
+	if config.RenewBefore < 0 || config.RenewBefore >= config.Duration {
+		return nil, fmt.Errorf("renew before duration %s must be positive and shorter than the duration %s",
+			time.Duration(config.RenewBefore), time.Duration(config.Duration))
+	}
+
+	limiter := rate.NewLimiter(rate.Inf, 0)
+	if config.RateLimit != nil {
+		if config.RateLimit.Average <= 0 || config.RateLimit.Burst <= 0 {
+			return nil, errors.New("the rate limit average and burst must be positive")
+		}
+		limiter = rate.NewLimiter(rate.Limit(float64(config.RateLimit.Average)/time.Minute.Seconds()), int(config.RateLimit.Burst))
+	}
+
+	if len(config.Storage) > 0 {
+		if err := os.MkdirAll(config.Storage, 0700); err != nil {
+			return nil, fmt.Errorf("unable to create storage directory: %v", err)
+		}
+	}
+
+	return &InternalCA{
+		config:   *config,
+		cert:     cert,
+		key:      key,
+		limiter:  limiter,
+		certs:    make(map[string]*tls.Certificate),
+		inflight: make(map[string]*issuance),
+	}, nil
+}
+
+// GetCertificate returns the certificate issued for the domain, issuing or renewing it if needed.
+// It returns nil if the domain is not allowed.
+func (c *InternalCA) GetCertificate(domain string) (*tls.Certificate, error) {
+	if !c.allowed(domain) {
+		return nil, nil
+	}
+
+	c.lock.Lock()
+	cert, ok := c.certs[domain]
+	if ok && time.Now().Before(c.renewalDate(cert.Leaf)) {
+		c.lock.Unlock()
+		return cert, nil
+	}
+
+	if call, ok := c.inflight[domain]; ok {
+		c.lock.Unlock()
+		<-call.done
+		return call.cert, call.err
+	}
+
+	call := &issuance{done: make(chan struct{})}
+	c.inflight[domain] = call
+	c.lock.Unlock()
+
+	call.cert, call.err = c.obtain(domain, cert)
+
+	c.lock.Lock()
+	if call.cert != nil {
+		c.certs[domain] = call.cert
+	}
+	delete(c.inflight, domain)
+	c.lock.Unlock()
+
+	close(call.done)
+	return call.cert, call.err
+}
+
+// obtain loads the certificate of the domain from the storage if it is not cached, and issues it if it must be renewed.
+func (c *InternalCA) obtain(domain string, cert *tls.Certificate) (*tls.Certificate, error) {
+	if cert == nil {
+		var err error
+		cert, err = c.load(domain)
+		if err != nil {
+			log.WithoutContext().Debugf("Unable to load the certificate of %s issued by the internal CA: %v", domain, err)
+		}
+	}
+
+	now := time.Now()
+	if cert != nil && now.Before(c.renewalDate(cert.Leaf)) {
+		return cert, nil
+	}
+
+	if !c.limiter.Allow() {
+		// The certificate to renew is served until it expires.
+		if cert != nil && now.Before(cert.Leaf.NotAfter) {
+			return cert, nil
+		}
+		return nil, fmt.Errorf("issuance rate limit reached for %s", domain)
+	}
+
+	issued, err := c.issue(domain, now)
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithoutContext().Debugf("Certificate issued by the internal CA for %s, valid until %s", domain, issued.Leaf.NotAfter)
+
+	return issued, nil
+}
+
+// renewalDate returns the date the certificate must be renewed,
+// which is its expiration date if its validity was shortened by the expiration of the certificate authority.
+func (c *InternalCA) renewalDate(leaf *x509.Certificate) time.Time {
+	renewalDate := leaf.NotBefore.Add(internalCABackdate + time.Duration(c.config.Duration) - time.Duration(c.config.RenewBefore))
+	if renewalDate.After(leaf.NotAfter) {
+		return leaf.NotAfter
+	}
+	return renewalDate
+}
+
+// allowed returns whether the domain can be issued a certificate.
+func (c *InternalCA) allowed(domain string) bool {
+	if !isValidDomain(domain) {
+		return false
+	}
+
+	for _, allowed := range c.config.Domains {
+		allowed = types.CanonicalDomain(allowed)
+		if allowed == domain {
+			return true
+		}
+
+		if strings.HasPrefix(allowed, "*.") && strings.HasSuffix(domain, allowed[1:]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *InternalCA) issue(domain string, now time.Time) (*tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	notAfter := now.Add(time.Duration(c.config.Duration))
+	if notAfter.After(c.cert.NotAfter) {
+		notAfter = c.cert.NotAfter
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: domain},
+		NotBefore:             now.Add(-internalCABackdate),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{domain},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, c.cert, &key.PublicKey, c.key)
+	if err != nil {
+		return nil, err
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw})...)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	if len(c.config.Storage) > 0 {
+		if err := c.store(domain, certPEM, keyPEM); err != nil {
+			log.WithoutContext().Errorf("Unable to store the certificate of %s issued by the internal CA: %v", domain, err)
+		}
+	}
+
+	return c.parse(certPEM, keyPEM)
+}
+
+// load reads the certificate of the domain from the storage, if it was issued by the current certificate authority.
+func (c *InternalCA) load(domain string) (*tls.Certificate, error) {
+	if len(c.config.Storage) == 0 {
+		return nil, nil
+	}
+
+	certPath, keyPath := c.paths(domain)
+
+	certPEM, err := ioutil.ReadFile(certPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keyPEM, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := c.parse(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cert.Leaf.CheckSignatureFrom(c.cert); err != nil {
+		return nil, fmt.Errorf("not issued by the internal CA: %v", err)
+	}
+
+	return cert, nil
+}
+
+func (c *InternalCA) store(domain string, certPEM, keyPEM []byte) error {
+	certPath, keyPath := c.paths(domain)
+
+	if err := ioutil.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(certPath, certPEM, 0600)
+}
+
+func (c *InternalCA) paths(domain string) (string, string) {
+	return filepath.Join(c.config.Storage, domain+".crt"), filepath.Join(c.config.Storage, domain+".key")
+}
+
+func (c *InternalCA) parse(certPEM, keyPEM []byte) (*tls.Certificate, error) {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &cert, nil
+}
+
+// isValidDomain returns whether the domain is a host name, which can safely be used as a file name.
+func isValidDomain(domain string) bool {
+	if len(domain) == 0 || len(domain) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(domain, ".") {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return false
+			}
+		}
+	}
+
+	return true
+}
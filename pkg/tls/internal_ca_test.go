@@ -0,0 +1,269 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containous/traefik/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInternalCA_GetCertificate(t *testing.T) {
+	ca := newTestCA(t, "Internal CA")
+
+	testCases := []struct {
+		desc          string
+		domain        string
+		expectedIssue bool
+	}{
+		{
+			desc:          "allowed domain",
+			domain:        "internal.example.com",
+			expectedIssue: true,
+		},
+		{
+			desc:          "allowed subdomain",
+			domain:        "foo.bar.dev.example.com",
+			expectedIssue: true,
+		},
+		{
+			desc:   "wildcard parent domain",
+			domain: "dev.example.com",
+		},
+		{
+			desc:   "not allowed domain",
+			domain: "example.com",
+		},
+		{
+			desc:   "invalid domain",
+			domain: "../dev.example.com",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			internalCA, err := NewInternalCA(newTestInternalCAConfig(t, ca, ""))
+			require.NoError(t, err)
+
+			cert, err := internalCA.GetCertificate(test.domain)
+			require.NoError(t, err)
+
+			if !test.expectedIssue {
+				assert.Nil(t, cert)
+				return
+			}
+
+			require.NotNil(t, cert)
+			assert.Equal(t, []string{test.domain}, cert.Leaf.DNSNames)
+			assert.WithinDuration(t, time.Now().Add(time.Hour), cert.Leaf.NotAfter, time.Minute)
+
+			roots := x509.NewCertPool()
+			roots.AddCert(ca.cert)
+			_, err = cert.Leaf.Verify(x509.VerifyOptions{DNSName: test.domain, Roots: roots})
+			require.NoError(t, err)
+
+			cached, err := internalCA.GetCertificate(test.domain)
+			require.NoError(t, err)
+			assert.Equal(t, cert, cached)
+		})
+	}
+}
+
+func TestInternalCA_RateLimit(t *testing.T) {
+	config := newTestInternalCAConfig(t, newTestCA(t, "Internal CA"), "")
+	config.RateLimit = &InternalCARateLimit{Average: 1, Burst: 1}
+
+	internalCA, err := NewInternalCA(config)
+	require.NoError(t, err)
+
+	cert, err := internalCA.GetCertificate("foo.dev.example.com")
+	require.NoError(t, err)
+	require.NotNil(t, cert)
+
+	_, err = internalCA.GetCertificate("bar.dev.example.com")
+	assert.Error(t, err)
+
+	// The issued certificates do not count against the rate limit.
+	cached, err := internalCA.GetCertificate("foo.dev.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, cert, cached)
+}
+
+func TestInternalCA_concurrentIssuance(t *testing.T) {
+	config := newTestInternalCAConfig(t, newTestCA(t, "Internal CA"), "")
+	config.RateLimit = &InternalCARateLimit{Average: 1, Burst: 1}
+
+	internalCA, err := NewInternalCA(config)
+	require.NoError(t, err)
+
+	// The concurrent handshakes share a single issuance, which counts once against the rate limit.
+	certs := make(chan *tls.Certificate, 10)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			cert, errCert := internalCA.GetCertificate("foo.dev.example.com")
+			assert.NoError(t, errCert)
+			certs <- cert
+		}()
+	}
+	wg.Wait()
+	close(certs)
+
+	first := <-certs
+	require.NotNil(t, first)
+	for cert := range certs {
+		assert.Equal(t, first, cert)
+	}
+}
+
+func TestInternalCA_Renewal(t *testing.T) {
+	internalCA, err := NewInternalCA(newTestInternalCAConfig(t, newTestCA(t, "Internal CA"), ""))
+	require.NoError(t, err)
+
+	old, err := internalCA.issue("foo.dev.example.com", time.Now().Add(-50*time.Minute))
+	require.NoError(t, err)
+	internalCA.certs["foo.dev.example.com"] = old
+
+	cert, err := internalCA.GetCertificate("foo.dev.example.com")
+	require.NoError(t, err)
+	require.NotNil(t, cert)
+
+	assert.NotEqual(t, old.Leaf.SerialNumber, cert.Leaf.SerialNumber)
+	assert.True(t, cert.Leaf.NotAfter.After(old.Leaf.NotAfter))
+}
+
+func TestInternalCA_Storage(t *testing.T) {
+	ca := newTestCA(t, "Internal CA")
+
+	dir, err := ioutil.TempDir("", "traefik-internal-ca")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	internalCA, err := NewInternalCA(newTestInternalCAConfig(t, ca, dir))
+	require.NoError(t, err)
+
+	cert, err := internalCA.GetCertificate("foo.dev.example.com")
+	require.NoError(t, err)
+	require.NotNil(t, cert)
+
+	// The stored certificate is used after a restart.
+	restarted, err := NewInternalCA(newTestInternalCAConfig(t, ca, dir))
+	require.NoError(t, err)
+
+	stored, err := restarted.GetCertificate("foo.dev.example.com")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, cert.Leaf.SerialNumber, stored.Leaf.SerialNumber)
+
+	// The certificates stored by another certificate authority are replaced.
+	other, err := NewInternalCA(newTestInternalCAConfig(t, newTestCA(t, "Other CA"), dir))
+	require.NoError(t, err)
+
+	replaced, err := other.GetCertificate("foo.dev.example.com")
+	require.NoError(t, err)
+	require.NotNil(t, replaced)
+	assert.NotEqual(t, cert.Leaf.SerialNumber, replaced.Leaf.SerialNumber)
+}
+
+func TestNewInternalCA(t *testing.T) {
+	ca := newTestCA(t, "Internal CA")
+	leaf := ca.issue(t, 2, "leaf", "", "")
+
+	testCases := []struct {
+		desc   string
+		update func(config *InternalCAConfig)
+	}{
+		{
+			desc: "not a certificate authority",
+			update: func(config *InternalCAConfig) {
+				config.CertFile = FileOrContent(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Certificate[0]}))
+			},
+		},
+		{
+			desc: "renew before longer than the duration",
+			update: func(config *InternalCAConfig) {
+				config.RenewBefore = types.Duration(2 * time.Hour)
+			},
+		},
+		{
+			desc: "invalid rate limit",
+			update: func(config *InternalCAConfig) {
+				config.RateLimit = &InternalCARateLimit{}
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := newTestInternalCAConfig(t, ca, "")
+			test.update(config)
+
+			_, err := NewInternalCA(config)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestManager_GetInternalCA(t *testing.T) {
+	config := newTestInternalCAConfig(t, newTestCA(t, "Internal CA"), "")
+	config.Domains = append(config.Domains, "example.com")
+
+	internalCA, err := NewInternalCA(config)
+	require.NoError(t, err)
+
+	tlsManager := NewManager(nil)
+	tlsManager.UpdateConfigs(nil, nil, []*Configuration{
+		{Certificate: &Certificate{CertFile: localhostCert, KeyFile: localhostKey}},
+	})
+	tlsManager.SetInternalCA(internalCA)
+
+	tlsConfig, err := tlsManager.Get("default", "default")
+	require.NoError(t, err)
+
+	cert, err := tlsConfig.GetCertificate(&tls.ClientHelloInfo{ServerName: "foo.dev.example.com"})
+	require.NoError(t, err)
+	require.NotNil(t, cert.Leaf)
+	assert.Equal(t, []string{"foo.dev.example.com"}, cert.Leaf.DNSNames)
+
+	// The configured certificates have precedence over the internal CA.
+	cert, err = tlsConfig.GetCertificate(&tls.ClientHelloInfo{ServerName: "example.com"})
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Acme Co"}, leaf.Subject.Organization)
+}
+
+func newTestInternalCAConfig(t *testing.T, ca *testCA, storage string) *InternalCAConfig {
+	t.Helper()
+
+	keyDER, err := x509.MarshalECPrivateKey(ca.key)
+	require.NoError(t, err)
+
+	config := &InternalCAConfig{}
+	config.SetDefaults()
+	config.CertFile = FileOrContent(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}))
+	config.KeyFile = FileOrContent(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	config.Domains = []string{"internal.example.com", "*.dev.example.com"}
+	config.Duration = types.Duration(time.Hour)
+	config.RenewBefore = types.Duration(20 * time.Minute)
+	config.Storage = storage
+
+	return config
+}
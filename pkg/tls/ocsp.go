@@ -0,0 +1,420 @@
+package tls
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/safe"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	// ocspCheckInterval is the interval between two checks of the OCSP responses to refresh.
+	ocspCheckInterval = time.Minute
+	// ocspRetryInterval is the delay before fetching again an OCSP response which could not be fetched.
+	ocspRetryInterval = 5 * time.Minute
+	// ocspDefaultRefreshInterval is the delay before refreshing an OCSP response which has no NextUpdate.
+	ocspDefaultRefreshInterval = time.Hour
+	// ocspMaxResponseSize is the maximal size of the OCSP responses.
+	ocspMaxResponseSize = 1024 * 1024
+)
+
+// OCSPConfig configures how the OCSP responses stapled to the certificates are fetched.
+type OCSPConfig struct {
+	ResponderOverrides map[string]string `description:"Map of the OCSP responders URLs to replace, by the URL to use instead." export:"true"`
+}
+
+// OCSPStapleInfo holds the status of the OCSP response stapled to a certificate.
+type OCSPStapleInfo struct {
+	Domains    []string   `json:"domains,omitempty"`
+	Responder  string     `json:"responder"`
+	Status     string     `json:"status"`
+	Stapled    bool       `json:"stapled"`
+	Error      string     `json:"error,omitempty"`
+	FetchedAt  *time.Time `json:"fetchedAt,omitempty"`
+	ThisUpdate *time.Time `json:"thisUpdate,omitempty"`
+	NextUpdate *time.Time `json:"nextUpdate,omitempty"`
+	Age        string     `json:"age,omitempty"`
+}
+
+// ocspStaple holds the OCSP response of a certificate. It is never modified once stored, a refresh replaces it.
+type ocspStaple struct {
+	leaf        *x509.Certificate
+	issuer      *x509.Certificate
+	responder   string
+	raw         []byte
+	response    *ocsp.Response
+	fetchedAt   time.Time
+	nextRefresh time.Time
+	err         error
+}
+
+// stapled returns the OCSP response to staple, nil if the certificate is not known to be valid.
+func (s *ocspStaple) stapled() []byte {
+	if s.response == nil || s.response.Status != ocsp.Good {
+		return nil
+	}
+
+	if !s.response.NextUpdate.IsZero() && time.Now().After(s.response.NextUpdate) {
+		return nil
+	}
+
+	return s.raw
+}
+
+func (s *ocspStaple) info() OCSPStapleInfo {
+	info := OCSPStapleInfo{
+		Domains:   certificateDomains(s.leaf),
+		Responder: s.responder,
+		Status:    "pending",
+		Stapled:   s.stapled() != nil,
+	}
+
+	if s.err != nil {
+		info.Error = s.err.Error()
+	}
+
+	if !s.fetchedAt.IsZero() {
+		fetchedAt := s.fetchedAt
+		info.FetchedAt = &fetchedAt
+	}
+
+	if s.response == nil {
+		if s.err != nil {
+			info.Status = "error"
+		}
+		return info
+	}
+
+	switch s.response.Status {
+	case ocsp.Good:
+		info.Status = "good"
+	case ocsp.Revoked:
+		info.Status = "revoked"
+	default:
+		info.Status = "unknown"
+	}
+
+	thisUpdate := s.response.ThisUpdate
+	info.ThisUpdate = &thisUpdate
+	info.Age = time.Since(thisUpdate).Round(time.Second).String()
+
+	if !s.response.NextUpdate.IsZero() {
+		nextUpdate := s.response.NextUpdate
+		info.NextUpdate = &nextUpdate
+	}
+
+	return info
+}
+
+// ocspStapler fetches, caches and refreshes the OCSP responses of the certificates, identified by the fingerprint of their leaf.
+type ocspStapler struct {
+	client             *http.Client
+	responderOverrides map[string]string
+	staples            map[[sha256.Size]byte]*ocspStaple
+	lock               sync.RWMutex
+	wake               chan struct{}
+	stop               chan struct{}
+	startOnce          sync.Once
+	stopOnce           sync.Once
+}
+
+func newOCSPStapler(config *OCSPConfig) *ocspStapler {
+	stapler := &ocspStapler{
+		client:  &http.Client{Timeout: 10 * time.Second},
+		staples: make(map[[sha256.Size]byte]*ocspStaple),
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+	}
+
+	if config != nil {
+		stapler.responderOverrides = config.ResponderOverrides
+	}
+
+	return stapler
+}
+
+// update sets the certificates to staple, keeping the OCSP responses already fetched.
+func (s *ocspStapler) update(certificates []*tls.Certificate) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	staples := make(map[[sha256.Size]byte]*ocspStaple)
+	for _, cert := range certificates {
+		if cert == nil || len(cert.Certificate) == 0 {
+			continue
+		}
+
+		fingerprint := sha256.Sum256(cert.Certificate[0])
+		if _, ok := staples[fingerprint]; ok {
+			continue
+		}
+
+		if staple, ok := s.staples[fingerprint]; ok {
+			staples[fingerprint] = staple
+			continue
+		}
+
+		staple, err := s.newStaple(cert)
+		if err != nil {
+			log.WithoutContext().Debugf("OCSP stapling disabled for a certificate: %v", err)
+			continue
+		}
+		if staple != nil {
+			staples[fingerprint] = staple
+		}
+	}
+
+	s.staples = staples
+
+	if len(s.staples) == 0 {
+		return
+	}
+
+	s.startOnce.Do(func() {
+		safe.Go(s.refreshLoop)
+	})
+
+	// Wakes the refresh loop up to fetch the responses of the new certificates, unless a wake up is already pending.
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// refreshLoop refreshes the OCSP responses at every check interval, or when it is woken up, until the stapler is stopped.
+func (s *ocspStapler) refreshLoop() {
+	ticker := time.NewTicker(ocspCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.wake:
+		case <-s.stop:
+			return
+		}
+
+		s.refresh()
+	}
+}
+
+// close stops the refresh loop.
+func (s *ocspStapler) close() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+}
+
+// newStaple returns the staple of the certificate to fetch, nil if the certificate has no OCSP responder.
+func (s *ocspStapler) newStaple(cert *tls.Certificate) (*ocspStaple, error) {
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(leaf.OCSPServer) == 0 {
+		return nil, nil
+	}
+
+	if len(cert.Certificate) < 2 {
+		return nil, fmt.Errorf("no issuer certificate in the chain of %v", certificateDomains(leaf))
+	}
+
+	issuer, err := x509.ParseCertificate(cert.Certificate[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return &ocspStaple{leaf: leaf, issuer: issuer, responder: s.responderURL(leaf)}, nil
+}
+
+// responderURL returns the URL of the OCSP responder of the certificate, taking the overrides into account.
+func (s *ocspStapler) responderURL(leaf *x509.Certificate) string {
+	if len(leaf.OCSPServer) == 0 {
+		return ""
+	}
+
+	responder := leaf.OCSPServer[0]
+	if override, ok := s.responderOverrides[responder]; ok {
+		return override
+	}
+	return responder
+}
+
+// refresh fetches the OCSP responses which are missing or about to expire.
+func (s *ocspStapler) refresh() {
+	s.lock.RLock()
+	toRefresh := make(map[[sha256.Size]byte]*ocspStaple)
+	for fingerprint, staple := range s.staples {
+		if !time.Now().Before(staple.nextRefresh) {
+			toRefresh[fingerprint] = staple
+		}
+	}
+	s.lock.RUnlock()
+
+	for fingerprint, staple := range toRefresh {
+		refreshed := s.fetch(staple)
+
+		s.lock.Lock()
+		// The certificate may have been removed or refreshed meanwhile.
+		if s.staples[fingerprint] == staple {
+			s.staples[fingerprint] = refreshed
+		}
+		s.lock.Unlock()
+	}
+}
+
+// fetch returns a new staple holding the response of the OCSP responder.
+// If the response cannot be fetched, the previous one is kept while it is valid.
+func (s *ocspStapler) fetch(staple *ocspStaple) *ocspStaple {
+	logger := log.WithoutContext().WithField("ocspResponder", staple.responder)
+
+	refreshed := *staple
+	refreshed.fetchedAt = time.Now()
+
+	raw, response, err := s.query(context.Background(), staple.responder, staple.leaf, staple.issuer)
+	if err != nil {
+		logger.Errorf("Unable to fetch the OCSP response for %v: %v", certificateDomains(staple.leaf), err)
+		refreshed.err = err
+		refreshed.nextRefresh = refreshed.fetchedAt.Add(ocspRetryInterval)
+		return &refreshed
+	}
+
+	if response.Status == ocsp.Revoked {
+		logger.Errorf("The certificate for %v has been revoked on %s", certificateDomains(staple.leaf), response.RevokedAt)
+	}
+
+	refreshed.raw = raw
+	refreshed.response = response
+	refreshed.err = nil
+	refreshed.nextRefresh = nextOCSPRefresh(response, refreshed.fetchedAt)
+
+	logger.Debugf("OCSP response fetched for %v, next refresh on %s", certificateDomains(staple.leaf), refreshed.nextRefresh)
+	return &refreshed
+}
+
+// query requests the status of the leaf certificate to the OCSP responder.
+func (s *ocspStapler) query(ctx context.Context, responder string, leaf, issuer *x509.Certificate) ([]byte, *ocsp.Response, error) {
+	request, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, responder, bytes.NewReader(request))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/ocsp-request")
+
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	raw, err := ioutil.ReadAll(io.LimitReader(resp.Body, ocspMaxResponseSize))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response, err := ocsp.ParseResponseForCert(raw, leaf, issuer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if response.Status == ocsp.Good && !response.NextUpdate.IsZero() && time.Now().After(response.NextUpdate) {
+		return nil, nil, errors.New("expired OCSP response")
+	}
+
+	return raw, response, nil
+}
+
+// nextOCSPRefresh returns the date to refresh the response, halfway through its validity period.
+func nextOCSPRefresh(response *ocsp.Response, fetchedAt time.Time) time.Time {
+	if response.NextUpdate.IsZero() {
+		return fetchedAt.Add(ocspDefaultRefreshInterval)
+	}
+
+	next := response.ThisUpdate.Add(response.NextUpdate.Sub(response.ThisUpdate) / 2)
+	if next.Before(fetchedAt.Add(ocspCheckInterval)) {
+		return fetchedAt.Add(ocspCheckInterval)
+	}
+	return next
+}
+
+// staple returns a copy of the certificate with its OCSP response stapled, or the certificate itself if there is no response to staple.
+func (s *ocspStapler) staple(cert *tls.Certificate) *tls.Certificate {
+	if cert == nil || len(cert.Certificate) == 0 {
+		return cert
+	}
+
+	s.lock.RLock()
+	staple, ok := s.staples[sha256.Sum256(cert.Certificate[0])]
+	s.lock.RUnlock()
+
+	if !ok {
+		return cert
+	}
+
+	raw := staple.stapled()
+	if raw == nil {
+		return cert
+	}
+
+	stapled := *cert
+	stapled.OCSPStaple = raw
+	return &stapled
+}
+
+// infos returns the status of the OCSP responses, sorted by domains.
+func (s *ocspStapler) infos() []OCSPStapleInfo {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	infos := make([]OCSPStapleInfo, 0, len(s.staples))
+	for _, staple := range s.staples {
+		infos = append(infos, staple.info())
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return fmt.Sprint(infos[i].Domains) < fmt.Sprint(infos[j].Domains)
+	})
+
+	return infos
+}
+
+func certificateDomains(cert *x509.Certificate) []string {
+	var domains []string
+	if len(cert.Subject.CommonName) > 0 {
+		domains = append(domains, cert.Subject.CommonName)
+	}
+
+	for _, domain := range cert.DNSNames {
+		if domain != cert.Subject.CommonName {
+			domains = append(domains, domain)
+		}
+	}
+
+	for _, ip := range cert.IPAddresses {
+		domains = append(domains, ip.String())
+	}
+
+	return domains
+}
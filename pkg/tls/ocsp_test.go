@@ -0,0 +1,258 @@
+package tls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+func TestOCSPStapling(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		responderStatus int
+		ocspStatus      int
+		expectedStatus  string
+		expectedStapled bool
+	}{
+		{
+			desc:            "good certificate",
+			responderStatus: http.StatusOK,
+			ocspStatus:      ocsp.Good,
+			expectedStatus:  "good",
+			expectedStapled: true,
+		},
+		{
+			desc:            "revoked certificate",
+			responderStatus: http.StatusOK,
+			ocspStatus:      ocsp.Revoked,
+			expectedStatus:  "revoked",
+		},
+		{
+			desc:            "unavailable responder",
+			responderStatus: http.StatusServiceUnavailable,
+			expectedStatus:  "error",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			issuer, issuerKey := generateOCSPTestCertificate(t, nil, nil, "")
+			issuerCert, err := x509.ParseCertificate(issuer)
+			require.NoError(t, err)
+
+			leaf, leafKey := generateOCSPTestCertificate(t, issuerCert, issuerKey, "http://ocsp.example.com")
+
+			responder := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if test.responderStatus != http.StatusOK {
+					rw.WriteHeader(test.responderStatus)
+					return
+				}
+
+				body, err := ioutil.ReadAll(req.Body)
+				require.NoError(t, err)
+
+				ocspRequest, err := ocsp.ParseRequest(body)
+				require.NoError(t, err)
+
+				response, err := ocsp.CreateResponse(issuerCert, issuerCert, ocsp.Response{
+					Status:       test.ocspStatus,
+					SerialNumber: ocspRequest.SerialNumber,
+					ThisUpdate:   time.Now().Add(-time.Hour),
+					NextUpdate:   time.Now().Add(time.Hour),
+					RevokedAt:    time.Now().Add(-time.Hour),
+				}, issuerKey)
+				require.NoError(t, err)
+
+				_, _ = rw.Write(response)
+			}))
+			defer responder.Close()
+
+			keyDER, err := x509.MarshalECPrivateKey(leafKey)
+			require.NoError(t, err)
+
+			chain := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf}), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: issuer})...)
+
+			tlsManager := NewManager(&OCSPConfig{ResponderOverrides: map[string]string{"http://ocsp.example.com": responder.URL}})
+			tlsManager.UpdateConfigs(nil, nil, []*Configuration{{
+				Certificate: &Certificate{
+					CertFile: FileOrContent(chain),
+					KeyFile:  FileOrContent(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+				},
+			}})
+
+			var staple OCSPStapleInfo
+			deadline := time.Now().Add(5 * time.Second)
+			for time.Now().Before(deadline) {
+				staples := tlsManager.GetOCSPStaples()
+				require.Len(t, staples, 1)
+
+				staple = staples[0]
+				if staple.Status != "pending" {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			assert.Equal(t, test.expectedStatus, staple.Status)
+			assert.Equal(t, test.expectedStapled, staple.Stapled)
+			assert.Equal(t, responder.URL, staple.Responder)
+			assert.Equal(t, []string{"foo.com"}, staple.Domains)
+
+			tlsConfig, err := tlsManager.Get("default", "default")
+			require.NoError(t, err)
+
+			cert, err := tlsConfig.GetCertificate(&tls.ClientHelloInfo{ServerName: "foo.com"})
+			require.NoError(t, err)
+			require.NotNil(t, cert)
+
+			if !test.expectedStapled {
+				assert.Nil(t, cert.OCSPStaple)
+				return
+			}
+
+			leafCert, err := x509.ParseCertificate(leaf)
+			require.NoError(t, err)
+
+			response, err := ocsp.ParseResponseForCert(cert.OCSPStaple, leafCert, issuerCert)
+			require.NoError(t, err)
+			assert.Equal(t, ocsp.Good, response.Status)
+		})
+	}
+}
+
+func TestNextOCSPRefresh(t *testing.T) {
+	fetchedAt := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		response *ocsp.Response
+		expected time.Time
+	}{
+		{
+			desc:     "no next update",
+			response: &ocsp.Response{ThisUpdate: fetchedAt},
+			expected: fetchedAt.Add(ocspDefaultRefreshInterval),
+		},
+		{
+			desc:     "halfway through the validity period",
+			response: &ocsp.Response{ThisUpdate: fetchedAt.Add(-time.Hour), NextUpdate: fetchedAt.Add(47 * time.Hour)},
+			expected: fetchedAt.Add(23 * time.Hour),
+		},
+		{
+			desc:     "response about to expire",
+			response: &ocsp.Response{ThisUpdate: fetchedAt.Add(-48 * time.Hour), NextUpdate: fetchedAt.Add(time.Second)},
+			expected: fetchedAt.Add(ocspCheckInterval),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, nextOCSPRefresh(test.response, fetchedAt))
+		})
+	}
+}
+
+func TestOCSPStapling_noResponder(t *testing.T) {
+	tlsManager := NewManager(nil)
+	tlsManager.UpdateConfigs(nil, nil, []*Configuration{{Certificate: &Certificate{CertFile: localhostCert, KeyFile: localhostKey}}})
+
+	assert.Empty(t, tlsManager.GetOCSPStaples())
+}
+
+func TestOCSPStapler_singleRefreshLoop(t *testing.T) {
+	issuer, issuerKey := generateOCSPTestCertificate(t, nil, nil, "")
+	issuerCert, err := x509.ParseCertificate(issuer)
+	require.NoError(t, err)
+
+	leaf, _ := generateOCSPTestCertificate(t, issuerCert, issuerKey, "http://ocsp.example.com")
+
+	var queries, inFlight, maxInFlight int32
+	responder := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&queries, 1)
+
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+
+		time.Sleep(50 * time.Millisecond)
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer responder.Close()
+
+	stapler := newOCSPStapler(&OCSPConfig{ResponderOverrides: map[string]string{"http://ocsp.example.com": responder.URL}})
+	defer stapler.close()
+
+	for i := 0; i < 10; i++ {
+		stapler.update([]*tls.Certificate{{Certificate: [][]byte{leaf, issuer}}})
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) && stapler.infos()[0].Status == "pending" {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Equal(t, "error", stapler.infos()[0].Status)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&maxInFlight))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&queries))
+}
+
+// generateOCSPTestCertificate generates a CA certificate if the issuer is nil, otherwise a certificate for foo.com checked with the OCSP responder.
+func generateOCSPTestCertificate(t *testing.T, issuer *x509.Certificate, issuerKey *ecdsa.PrivateKey, ocspServer string) ([]byte, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+
+	parent, signer := template, key
+	if issuer != nil {
+		template.Subject = pkix.Name{CommonName: "foo.com"}
+		template.DNSNames = []string{"foo.com"}
+		template.IsCA = false
+		template.KeyUsage = x509.KeyUsageDigitalSignature
+		template.OCSPServer = []string{ocspServer}
+		parent, signer = issuer, issuerKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	require.NoError(t, err)
+
+	return der, key
+}
@@ -4,17 +4,35 @@ const certificateHeader = "-----BEGIN CERTIFICATE-----\n"
 
 // ClientCA defines traefik CA files for a entryPoint
 // and it indicates if they are mandatory or have just to be analyzed if provided.
+// The client certificates can also be checked against revocation lists, OCSP responders, and allowed names.
 type ClientCA struct {
-	Files    []FileOrContent
-	Optional bool
+	Files           []FileOrContent
+	Optional        bool
+	ClientAuthType  string
+	CRLFiles        []string
+	OCSPCheck       bool
+	OCSPSoftFail    bool
+	AllowedSubjects []string
+	AllowedSANs     []string
+}
+
+// SessionTickets configures the TLS session resumption with session tickets.
+// The first key encrypts the new tickets, the other ones only decrypt the tickets issued before a key rotation.
+type SessionTickets struct {
+	Disabled bool `export:"true"`
+	Keys     []FileOrContent
 }
 
 // TLS configures TLS for an entry point
 type TLS struct {
-	MinVersion   string `export:"true"`
-	CipherSuites []string
-	ClientCA     ClientCA
-	SniStrict    bool `export:"true"`
+	MinVersion       string `export:"true"`
+	MaxVersion       string `export:"true"`
+	CipherSuites     []string
+	CurvePreferences []string
+	ALPNProtocols    []string
+	ClientCA         ClientCA
+	SniStrict        bool `export:"true"`
+	SessionTickets   SessionTickets
 }
 
 // Store holds the options for a given Store
@@ -26,4 +44,6 @@ type Store struct {
 type Configuration struct {
 	Stores      []string
 	Certificate *Certificate
+	// Provider is the name of the provider of the certificate, set when the configurations are merged.
+	Provider string `json:"-" toml:"-" label:"-"`
 }
@@ -3,7 +3,11 @@ package tls
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 	"sync"
 
 	"github.com/containous/traefik/pkg/log"
@@ -20,12 +24,30 @@ type Manager struct {
 	configs       map[string]TLS
 	certs         []*Configuration
 	TLSAlpnGetter func(string) (*tls.Certificate, error)
+	ocsp          *ocspStapler
+	verifiers     map[string]*clientCertVerifier
+	verifierErrs  map[string]error
+	certificates  []CertificateInfo
+	internalCA    *InternalCA
 	lock          sync.RWMutex
 }
 
 // NewManager creates a new Manager
-func NewManager() *Manager {
-	return &Manager{}
+func NewManager(ocspConfig *OCSPConfig) *Manager {
+	return &Manager{ocsp: newOCSPStapler(ocspConfig)}
+}
+
+// Close stops the refresh of the OCSP responses.
+func (m *Manager) Close() {
+	m.ocsp.close()
+}
+
+// SetInternalCA sets the certificate authority issuing the certificates of the domains without a certificate.
+func (m *Manager) SetInternalCA(internalCA *InternalCA) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.internalCA = internalCA
 }
 
 // UpdateConfigs updates the TLS* configuration options
@@ -33,6 +55,8 @@ func (m *Manager) UpdateConfigs(stores map[string]Store, configs map[string]TLS,
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
+	m.updateClientCertVerifiers(configs)
+
 	m.configs = configs
 	m.storesConfig = stores
 	m.certs = certs
@@ -47,6 +71,7 @@ func (m *Manager) UpdateConfigs(stores map[string]Store, configs map[string]TLS,
 	}
 
 	storesCertificates := make(map[string]map[string]*tls.Certificate)
+	providers := make(map[string]string)
 	for _, conf := range certs {
 		if len(conf.Stores) == 0 {
 			if log.GetLevel() >= logrus.DebugLevel {
@@ -60,11 +85,64 @@ func (m *Manager) UpdateConfigs(stores map[string]Store, configs map[string]TLS,
 				log.Errorf("Unable to append certificate %s to store %s: %v", conf.Certificate.GetTruncatedCertificateName(), store, err)
 			}
 		}
+
+		if certFingerprint, err := certificateFingerprint(conf.Certificate); err == nil {
+			providers[certFingerprint] = conf.Provider
+		}
 	}
 
 	for storeName, certs := range storesCertificates {
 		m.getStore(storeName).DynamicCerts.Set(certs)
 	}
+
+	// The default store is always used by the routers.
+	m.getStore("default")
+
+	var certificates []*tls.Certificate
+	for _, store := range m.stores {
+		certificates = append(certificates, store.DefaultCertificate)
+		for _, cert := range store.DynamicCerts.Get().(map[string]*tls.Certificate) {
+			certificates = append(certificates, cert)
+		}
+	}
+	m.ocsp.update(certificates)
+
+	m.certificates = buildCertificatesInfo(m.stores, providers)
+}
+
+// updateClientCertVerifiers creates the verifiers of the client certificates of the TLS options,
+// keeping the verifiers of the unmodified options.
+func (m *Manager) updateClientCertVerifiers(configs map[string]TLS) {
+	verifiers := make(map[string]*clientCertVerifier)
+	m.verifierErrs = make(map[string]error)
+
+	for name, config := range configs {
+		if previous, ok := m.configs[name]; ok && m.verifiers[name] != nil && reflect.DeepEqual(previous.ClientCA, config.ClientCA) {
+			verifiers[name] = m.verifiers[name]
+			continue
+		}
+
+		// An invalid client authentication must not fall back to a configuration without client authentication.
+		clientAuthType, err := getClientAuthType(config.ClientCA)
+		if err != nil {
+			log.WithoutContext().Errorf("Invalid client authentication for the TLS options %s: %v", name, err)
+			m.verifierErrs[name] = err
+			continue
+		}
+
+		verifier, err := newClientCertVerifier(name, config.ClientCA, clientAuthType, m.ocsp)
+		if err != nil {
+			log.WithoutContext().Errorf("Invalid client certificate checks for the TLS options %s: %v", name, err)
+			m.verifierErrs[name] = err
+			continue
+		}
+
+		if verifier != nil {
+			verifiers[name] = verifier
+		}
+	}
+
+	m.verifiers = verifiers
 }
 
 // Get gets the TLS configuration to use for a given store / configuration
@@ -79,16 +157,31 @@ func (m *Manager) Get(storeName string, configName string) (*tls.Config, error)
 
 	store := m.getStore(storeName)
 
-	tlsConfig, err := buildTLSConfig(config)
+	err := m.verifierErrs[configName]
+	var tlsConfig *tls.Config
+	if err == nil {
+		tlsConfig, err = buildTLSConfig(config)
+	}
 	if err != nil {
-		log.Error(err)
+		// The default options are used by every entry point, they must not prevent the routes from being served.
+		if configName != "default" {
+			return nil, fmt.Errorf("invalid TLS options %s: %v", configName, err)
+		}
+
+		log.WithoutContext().Errorf("Invalid default TLS options, falling back to the Go defaults: %v", err)
 		tlsConfig = &tls.Config{}
 	}
 
+	if verifier, ok := m.verifiers[configName]; ok {
+		tlsConfig.VerifyPeerCertificate = verifier.VerifyPeerCertificate
+	}
+
+	internalCA := m.internalCA
+
 	tlsConfig.GetCertificate = func(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
 		domainToCheck := types.CanonicalDomain(clientHello.ServerName)
 
-		if m.TLSAlpnGetter != nil {
+		if m.TLSAlpnGetter != nil && isTLSALPNChallenge(clientHello) {
 			cert, err := m.TLSAlpnGetter(domainToCheck)
 			if err != nil {
 				return nil, err
@@ -101,7 +194,18 @@ func (m *Manager) Get(storeName string, configName string) (*tls.Config, error)
 
 		bestCertificate := store.GetBestCertificate(clientHello)
 		if bestCertificate != nil {
-			return bestCertificate, nil
+			return m.ocsp.staple(bestCertificate), nil
+		}
+
+		if internalCA != nil && len(domainToCheck) > 0 {
+			cert, err := internalCA.GetCertificate(domainToCheck)
+			if err != nil {
+				log.WithoutContext().Errorf("Unable to get a certificate from the internal CA for %q: %v", domainToCheck, err)
+			}
+
+			if cert != nil {
+				return cert, nil
+			}
 		}
 
 		if m.configs[configName].SniStrict {
@@ -109,11 +213,16 @@ func (m *Manager) Get(storeName string, configName string) (*tls.Config, error)
 		}
 
 		log.WithoutContext().Debugf("Serving default certificate for request: %q", domainToCheck)
-		return store.DefaultCertificate, nil
+		return m.ocsp.staple(store.DefaultCertificate), nil
 	}
 	return tlsConfig, nil
 }
 
+// GetOCSPStaples returns the status of the OCSP responses stapled to the certificates.
+func (m *Manager) GetOCSPStaples() []OCSPStapleInfo {
+	return m.ocsp.infos()
+}
+
 func (m *Manager) getStore(storeName string) *CertificateStore {
 	_, ok := m.stores[storeName]
 	if !ok {
@@ -151,12 +260,41 @@ func buildCertificateStore(tlsStore Store) (*CertificateStore, error) {
 	return certificateStore, nil
 }
 
+// isTLSALPNChallenge returns whether the handshake is a TLS-ALPN-01 challenge validation,
+// which only offers the acme-tls/1 protocol.
+func isTLSALPNChallenge(clientHello *tls.ClientHelloInfo) bool {
+	for _, proto := range clientHello.SupportedProtos {
+		if proto == tlsalpn01.ACMETLS1Protocol {
+			return true
+		}
+	}
+	return false
+}
+
 // creates a TLS config that allows terminating HTTPS for multiple domains using SNI
 func buildTLSConfig(tlsOption TLS) (*tls.Config, error) {
 	conf := &tls.Config{}
 
-	// ensure http2 enabled
+	// ensure http2 enabled by default, and the TLS-ALPN challenge always available
 	conf.NextProtos = []string{"h2", "http/1.1", tlsalpn01.ACMETLS1Protocol}
+	if len(tlsOption.ALPNProtocols) > 0 {
+		conf.NextProtos = make([]string, 0, len(tlsOption.ALPNProtocols)+1)
+		for _, protocol := range tlsOption.ALPNProtocols {
+			if len(protocol) == 0 {
+				return nil, errors.New("invalid empty ALPN protocol")
+			}
+			if protocol != tlsalpn01.ACMETLS1Protocol {
+				conf.NextProtos = append(conf.NextProtos, protocol)
+			}
+		}
+		conf.NextProtos = append(conf.NextProtos, tlsalpn01.ACMETLS1Protocol)
+	}
+
+	clientAuthType, err := getClientAuthType(tlsOption.ClientCA)
+	if err != nil {
+		return nil, err
+	}
+	conf.ClientAuth = clientAuthType
 
 	if len(tlsOption.ClientCA.Files) > 0 {
 		pool := x509.NewCertPool()
@@ -171,19 +309,30 @@ func buildTLSConfig(tlsOption TLS) (*tls.Config, error) {
 			}
 		}
 		conf.ClientCAs = pool
-		if tlsOption.ClientCA.Optional {
-			conf.ClientAuth = tls.VerifyClientCertIfGiven
-		} else {
-			conf.ClientAuth = tls.RequireAndVerifyClientCert
-		}
 	}
 
 	// Set the minimum TLS version if set in the config TOML
-	if minConst, exists := MinVersion[tlsOption.MinVersion]; exists {
+	if len(tlsOption.MinVersion) > 0 {
+		minConst, exists := MinVersion[tlsOption.MinVersion]
+		if !exists {
+			return nil, fmt.Errorf("invalid MinVersion: %s", tlsOption.MinVersion)
+		}
 		conf.PreferServerCipherSuites = true
 		conf.MinVersion = minConst
 	}
 
+	// Set the maximum TLS version if set in the config TOML
+	if len(tlsOption.MaxVersion) > 0 {
+		maxConst, exists := MaxVersion[tlsOption.MaxVersion]
+		if !exists {
+			return nil, fmt.Errorf("invalid MaxVersion: %s", tlsOption.MaxVersion)
+		}
+		if conf.MinVersion > maxConst {
+			return nil, fmt.Errorf("the MinVersion %s is greater than the MaxVersion %s", tlsOption.MinVersion, tlsOption.MaxVersion)
+		}
+		conf.MaxVersion = maxConst
+	}
+
 	// Set the list of CipherSuites if set in the config TOML
 	if tlsOption.CipherSuites != nil {
 		// if our list of CipherSuites is defined in the entryPoint config, we can re-initialize the suites list as empty
@@ -198,9 +347,53 @@ func buildTLSConfig(tlsOption TLS) (*tls.Config, error) {
 		}
 	}
 
+	// Set the list of CurvePreferences if set in the config TOML
+	for _, curve := range tlsOption.CurvePreferences {
+		curveConst, exists := Curves[curve]
+		if !exists {
+			return nil, fmt.Errorf("invalid CurvePreferences: %s", curve)
+		}
+		conf.CurvePreferences = append(conf.CurvePreferences, curveConst)
+	}
+
+	conf.SessionTicketsDisabled = tlsOption.SessionTickets.Disabled
+	if !conf.SessionTicketsDisabled && len(tlsOption.SessionTickets.Keys) > 0 {
+		keys, err := buildSessionTicketKeys(tlsOption.SessionTickets.Keys)
+		if err != nil {
+			return nil, err
+		}
+		conf.SetSessionTicketKeys(keys)
+	}
+
 	return conf, nil
 }
 
+// buildSessionTicketKeys reads the session ticket keys, each one being 32 bytes encoded in base64.
+// Keys read from files are kept across restarts, and can be shared by several instances.
+func buildSessionTicketKeys(keyFiles []FileOrContent) ([][32]byte, error) {
+	var keys [][32]byte
+	for i, keyFile := range keyFiles {
+		data, err := keyFile.Read()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the session ticket key %d: %v", i, err)
+		}
+
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid session ticket key %d: %v", i, err)
+		}
+
+		var key [32]byte
+		if len(raw) != len(key) {
+			return nil, fmt.Errorf("invalid session ticket key %d: %d bytes instead of %d", i, len(raw), len(key))
+		}
+		copy(key[:], raw)
+
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
 func buildDefaultCertificate(defaultCertificate *Certificate) (*tls.Certificate, error) {
 	certFile, err := defaultCertificate.CertFile.Read()
 	if err != nil {
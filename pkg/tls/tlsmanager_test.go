@@ -2,7 +2,11 @@ package tls
 
 import (
 	"crypto/tls"
+	"encoding/base64"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // LocalhostCert is a PEM-encoded TLS cert with SAN IPs
@@ -54,7 +58,7 @@ func TestTLSInStore(t *testing.T) {
 			},
 		}
 
-	tlsManager := NewManager()
+	tlsManager := NewManager(nil)
 	tlsManager.UpdateConfigs(nil, nil, dynamicConfigs)
 
 	certs := tlsManager.GetStore("default").DynamicCerts.Get().(map[string]*tls.Certificate)
@@ -62,3 +66,204 @@ func TestTLSInStore(t *testing.T) {
 		t.Fatal("got error: default store must have TLS certificates.")
 	}
 }
+
+func TestManager_Get(t *testing.T) {
+	tlsConfigs := map[string]TLS{
+		"foo":     {MinVersion: "VersionTLS12"},
+		"bar":     {MinVersion: "VersionTLS11"},
+		"baz":     {MinVersion: "VersionTLS14"},
+		"default": {MinVersion: "VersionTLS14"},
+	}
+
+	testCases := []struct {
+		desc               string
+		tlsOptionsName     string
+		expectedMinVersion uint16
+		expectedError      bool
+	}{
+		{
+			desc:               "Get a tls config from a valid name",
+			tlsOptionsName:     "foo",
+			expectedMinVersion: tls.VersionTLS12,
+		},
+		{
+			desc:               "Get another tls config from a valid name",
+			tlsOptionsName:     "bar",
+			expectedMinVersion: tls.VersionTLS11,
+		},
+		{
+			desc:           "Get a tls config from an invalid name",
+			tlsOptionsName: "unknown",
+			expectedError:  true,
+		},
+		{
+			desc:           "Get a tls config from an invalid configuration",
+			tlsOptionsName: "baz",
+			expectedError:  true,
+		},
+		{
+			desc:           "Get the default tls config from an invalid configuration",
+			tlsOptionsName: "default",
+		},
+	}
+
+	tlsManager := NewManager(nil)
+	tlsManager.UpdateConfigs(nil, tlsConfigs, nil)
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config, err := tlsManager.Get("default", test.tlsOptionsName)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedMinVersion, config.MinVersion)
+		})
+	}
+}
+
+func TestManager_Get_tlsALPNChallenge(t *testing.T) {
+	challengeCert := &tls.Certificate{}
+
+	var lookups []string
+	tlsManager := NewManager(nil)
+	tlsManager.TLSAlpnGetter = func(domain string) (*tls.Certificate, error) {
+		lookups = append(lookups, domain)
+		return challengeCert, nil
+	}
+	tlsManager.UpdateConfigs(nil, nil, nil)
+
+	config, err := tlsManager.Get("default", "default")
+	require.NoError(t, err)
+
+	// The challenges are only looked up for the TLS-ALPN-01 validations.
+	cert, err := config.GetCertificate(&tls.ClientHelloInfo{ServerName: "foo.com", SupportedProtos: []string{"h2", "http/1.1"}})
+	require.NoError(t, err)
+	assert.True(t, cert != challengeCert)
+	assert.Empty(t, lookups)
+
+	cert, err = config.GetCertificate(&tls.ClientHelloInfo{ServerName: "foo.com", SupportedProtos: []string{"acme-tls/1"}})
+	require.NoError(t, err)
+	assert.True(t, cert == challengeCert)
+	assert.Equal(t, []string{"foo.com"}, lookups)
+}
+
+func TestBuildTLSConfig(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(make([]byte, 32))
+
+	testCases := []struct {
+		desc          string
+		tlsOption     TLS
+		expected      func(*testing.T, *tls.Config)
+		expectedError bool
+	}{
+		{
+			desc: "default options",
+			expected: func(t *testing.T, config *tls.Config) {
+				assert.Equal(t, []string{"h2", "http/1.1", "acme-tls/1"}, config.NextProtos)
+				assert.Zero(t, config.MinVersion)
+				assert.Zero(t, config.MaxVersion)
+				assert.False(t, config.SessionTicketsDisabled)
+			},
+		},
+		{
+			desc:      "TLS 1.3 only",
+			tlsOption: TLS{MinVersion: "VersionTLS13", MaxVersion: "VersionTLS13"},
+			expected: func(t *testing.T, config *tls.Config) {
+				assert.Equal(t, uint16(tls.VersionTLS13), config.MinVersion)
+				assert.Equal(t, uint16(tls.VersionTLS13), config.MaxVersion)
+			},
+		},
+		{
+			desc:          "invalid MinVersion",
+			tlsOption:     TLS{MinVersion: "VersionTLS14"},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid MaxVersion",
+			tlsOption:     TLS{MaxVersion: "foo"},
+			expectedError: true,
+		},
+		{
+			desc:          "MinVersion greater than MaxVersion",
+			tlsOption:     TLS{MinVersion: "VersionTLS13", MaxVersion: "VersionTLS12"},
+			expectedError: true,
+		},
+		{
+			desc:      "curve preferences",
+			tlsOption: TLS{CurvePreferences: []string{"X25519", "CurveP256"}},
+			expected: func(t *testing.T, config *tls.Config) {
+				assert.Equal(t, []tls.CurveID{tls.X25519, tls.CurveP256}, config.CurvePreferences)
+			},
+		},
+		{
+			desc:          "invalid curve",
+			tlsOption:     TLS{CurvePreferences: []string{"CurveP128"}},
+			expectedError: true,
+		},
+		{
+			desc:      "ALPN protocols without h2",
+			tlsOption: TLS{ALPNProtocols: []string{"http/1.1"}},
+			expected: func(t *testing.T, config *tls.Config) {
+				assert.Equal(t, []string{"http/1.1", "acme-tls/1"}, config.NextProtos)
+			},
+		},
+		{
+			desc:      "ALPN protocols with the TLS-ALPN challenge protocol",
+			tlsOption: TLS{ALPNProtocols: []string{"acme-tls/1", "h2"}},
+			expected: func(t *testing.T, config *tls.Config) {
+				assert.Equal(t, []string{"h2", "acme-tls/1"}, config.NextProtos)
+			},
+		},
+		{
+			desc:          "empty ALPN protocol",
+			tlsOption:     TLS{ALPNProtocols: []string{""}},
+			expectedError: true,
+		},
+		{
+			desc:      "session tickets disabled",
+			tlsOption: TLS{SessionTickets: SessionTickets{Disabled: true}},
+			expected: func(t *testing.T, config *tls.Config) {
+				assert.True(t, config.SessionTicketsDisabled)
+			},
+		},
+		{
+			desc:      "session ticket keys",
+			tlsOption: TLS{SessionTickets: SessionTickets{Keys: []FileOrContent{FileOrContent(key), FileOrContent(key + "\n")}}},
+			expected: func(t *testing.T, config *tls.Config) {
+				assert.False(t, config.SessionTicketsDisabled)
+			},
+		},
+		{
+			desc:          "session ticket key not encoded in base64",
+			tlsOption:     TLS{SessionTickets: SessionTickets{Keys: []FileOrContent{"not a key"}}},
+			expectedError: true,
+		},
+		{
+			desc:          "session ticket key too short",
+			tlsOption:     TLS{SessionTickets: SessionTickets{Keys: []FileOrContent{FileOrContent(base64.StdEncoding.EncodeToString([]byte("foo")))}}},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config, err := buildTLSConfig(test.tlsOption)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			test.expected(t, config)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package opentelemetry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/otlp"
+	"github.com/containous/traefik/pkg/safe"
+)
+
+const (
+	maxQueueSize  = 2048
+	maxBatchSize  = 512
+	flushInterval = 5 * time.Second
+)
+
+type tracesClient interface {
+	ExportTraces(ctx context.Context, payload []byte) error
+	Close() error
+}
+
+// batchExporter exports the spans to the collector in batches,
+// every flushInterval or as soon as maxBatchSize spans are queued.
+type batchExporter struct {
+	client   tracesClient
+	resource []otlp.KeyValue
+
+	spans     chan otlp.Span
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+func newBatchExporter(client tracesClient, resource []otlp.KeyValue) *batchExporter {
+	e := &batchExporter{
+		client:   client,
+		resource: resource,
+		spans:    make(chan otlp.Span, maxQueueSize),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+
+	safe.Go(e.loop)
+
+	return e
+}
+
+// Export queues the span, which is dropped if the queue is full so that the requests are never slowed down by the export.
+func (e *batchExporter) Export(span otlp.Span) {
+	select {
+	case e.spans <- span:
+	default:
+		log.WithoutContext().Debugf("OpenTelemetry spans queue is full: dropping span %q", span.Name)
+	}
+}
+
+// Close exports the queued spans and closes the connection to the collector.
+func (e *batchExporter) Close() error {
+	e.closeOnce.Do(func() {
+		close(e.done)
+		<-e.stopped
+	})
+	return e.client.Close()
+}
+
+func (e *batchExporter) loop() {
+	defer close(e.stopped)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]otlp.Span, 0, maxBatchSize)
+
+	for {
+		select {
+		case span := <-e.spans:
+			batch = append(batch, span)
+			if len(batch) >= maxBatchSize {
+				batch = e.flush(batch)
+			}
+		case <-ticker.C:
+			batch = e.flush(batch)
+		case <-e.done:
+			for {
+				select {
+				case span := <-e.spans:
+					batch = append(batch, span)
+				default:
+					e.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *batchExporter) flush(batch []otlp.Span) []otlp.Span {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	if err := e.client.ExportTraces(context.Background(), otlp.EncodeTraces(e.resource, batch)); err != nil {
+		log.WithoutContext().Errorf("Unable to export %d spans: %v", len(batch), err)
+	}
+
+	return batch[:0]
+}
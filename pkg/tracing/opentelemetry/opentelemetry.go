@@ -0,0 +1,51 @@
+package opentelemetry
+
+import (
+	"io"
+
+	"github.com/containous/traefik/pkg/log"
+	"github.com/containous/traefik/pkg/otlp"
+	"github.com/opentracing/opentracing-go"
+)
+
+// Name sets the name of this tracer.
+const Name = "opentelemetry"
+
+// Config provides configuration settings for an OpenTelemetry tracer.
+type Config struct {
+	Address  string            `description:"Set the host:port of the OpenTelemetry collector the spans are exported to." export:"false"`
+	GRPC     bool              `description:"Export the spans with OTLP/gRPC instead of OTLP/HTTP." export:"true"`
+	Insecure bool              `description:"Connect to the collector without TLS." export:"true"`
+	Headers  map[string]string `description:"Headers sent with the export requests." export:"false"`
+}
+
+// SetDefaults sets the default values.
+func (c *Config) SetDefaults() {
+	c.Address = "localhost:4318"
+	c.GRPC = false
+	// The collectors listen without TLS by default.
+	c.Insecure = true
+}
+
+// Setup sets up the tracer
+func (c *Config) Setup(serviceName string) (opentracing.Tracer, io.Closer, error) {
+	client, err := otlp.NewClient(otlp.Config{
+		Address:  c.Address,
+		GRPC:     c.GRPC,
+		Insecure: c.Insecure,
+		Headers:  c.Headers,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	exporter := newBatchExporter(client, otlp.Resource(serviceName))
+	tracer := NewTracer(exporter)
+
+	// Without this, child spans are getting the NOOP tracer
+	opentracing.SetGlobalTracer(tracer)
+
+	log.WithoutContext().Debug("OpenTelemetry tracer configured")
+
+	return tracer, exporter, nil
+}
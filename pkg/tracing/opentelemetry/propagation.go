@@ -0,0 +1,191 @@
+package opentelemetry
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// Headers of the W3C trace context and baggage specifications.
+const (
+	traceParentHeader = "traceparent"
+	traceStateHeader  = "tracestate"
+	baggageHeader     = "baggage"
+)
+
+const (
+	traceParentVersion = "00"
+	sampledFlag        = 0x01
+)
+
+// Inject writes the span context in the W3C traceparent, tracestate and baggage headers.
+func (t *Tracer) Inject(sm opentracing.SpanContext, format interface{}, carrier interface{}) error {
+	sc, ok := sm.(spanContext)
+	if !ok {
+		return opentracing.ErrInvalidSpanContext
+	}
+
+	if format != opentracing.HTTPHeaders && format != opentracing.TextMap {
+		return opentracing.ErrUnsupportedFormat
+	}
+
+	writer, ok := carrier.(opentracing.TextMapWriter)
+	if !ok {
+		return opentracing.ErrInvalidCarrier
+	}
+
+	writer.Set(traceParentHeader, formatTraceParent(sc))
+
+	if len(sc.traceState) > 0 {
+		writer.Set(traceStateHeader, sc.traceState)
+	}
+
+	if len(sc.baggage) > 0 {
+		writer.Set(baggageHeader, formatBaggage(sc.baggage))
+	}
+
+	return nil
+}
+
+// Extract reads the span context from the W3C traceparent, tracestate and baggage headers.
+func (t *Tracer) Extract(format interface{}, carrier interface{}) (opentracing.SpanContext, error) {
+	if format != opentracing.HTTPHeaders && format != opentracing.TextMap {
+		return nil, opentracing.ErrUnsupportedFormat
+	}
+
+	reader, ok := carrier.(opentracing.TextMapReader)
+	if !ok {
+		return nil, opentracing.ErrInvalidCarrier
+	}
+
+	var traceParent string
+	var traceStates, baggages []string
+
+	err := reader.ForeachKey(func(key, value string) error {
+		switch strings.ToLower(key) {
+		case traceParentHeader:
+			traceParent = value
+		case traceStateHeader:
+			traceStates = append(traceStates, value)
+		case baggageHeader:
+			baggages = append(baggages, value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(traceParent) == 0 {
+		return nil, opentracing.ErrSpanContextNotFound
+	}
+
+	sc, err := parseTraceParent(traceParent)
+	if err != nil {
+		return nil, opentracing.ErrSpanContextCorrupted
+	}
+
+	sc.traceState = strings.Join(traceStates, ",")
+	sc.baggage = parseBaggage(strings.Join(baggages, ","))
+
+	return sc, nil
+}
+
+func formatTraceParent(sc spanContext) string {
+	var flags byte
+	if sc.sampled {
+		flags |= sampledFlag
+	}
+	return fmt.Sprintf("%s-%x-%x-%02x", traceParentVersion, sc.traceID, sc.spanID, flags)
+}
+
+func parseTraceParent(value string) (spanContext, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return spanContext{}, errors.New("invalid traceparent")
+	}
+
+	version := parts[0]
+	if len(version) != 2 || version == "ff" || !isLowerHex(version) {
+		return spanContext{}, fmt.Errorf("invalid traceparent version %q", version)
+	}
+
+	// The future versions may add fields, but the version 00 has exactly 4 of them.
+	if version == traceParentVersion && len(parts) != 4 {
+		return spanContext{}, errors.New("invalid traceparent")
+	}
+
+	var sc spanContext
+
+	if len(parts[1]) != 2*len(sc.traceID) || !isLowerHex(parts[1]) {
+		return spanContext{}, errors.New("invalid trace ID")
+	}
+	_, _ = hex.Decode(sc.traceID[:], []byte(parts[1]))
+
+	if len(parts[2]) != 2*len(sc.spanID) || !isLowerHex(parts[2]) {
+		return spanContext{}, errors.New("invalid parent ID")
+	}
+	_, _ = hex.Decode(sc.spanID[:], []byte(parts[2]))
+
+	if sc.traceID == [16]byte{} || sc.spanID == [8]byte{} {
+		return spanContext{}, errors.New("invalid traceparent: all zeros ID")
+	}
+
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil || len(flags) != 1 || !isLowerHex(parts[3]) {
+		return spanContext{}, errors.New("invalid trace flags")
+	}
+	sc.sampled = flags[0]&sampledFlag == sampledFlag
+
+	return sc, nil
+}
+
+func isLowerHex(value string) bool {
+	for _, c := range value {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func formatBaggage(baggage map[string]string) string {
+	items := make([]string, 0, len(baggage))
+	for key, value := range baggage {
+		items = append(items, key+"="+url.PathEscape(value))
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
+
+// parseBaggage parses the baggage items, their properties being ignored.
+func parseBaggage(value string) map[string]string {
+	if len(value) == 0 {
+		return nil
+	}
+
+	baggage := make(map[string]string)
+	for _, item := range strings.Split(value, ",") {
+		member := strings.SplitN(strings.SplitN(item, ";", 2)[0], "=", 2)
+		if len(member) != 2 {
+			continue
+		}
+
+		key := strings.TrimSpace(member[0])
+		itemValue, err := url.PathUnescape(strings.TrimSpace(member[1]))
+		if len(key) == 0 || err != nil {
+			continue
+		}
+		baggage[key] = itemValue
+	}
+
+	if len(baggage) == 0 {
+		return nil
+	}
+	return baggage
+}
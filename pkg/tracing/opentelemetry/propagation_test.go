@@ -0,0 +1,143 @@
+package opentelemetry
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/containous/traefik/pkg/tracing"
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		traceParent     string
+		expectedSampled bool
+		expectedErr     bool
+	}{
+		{
+			desc:            "sampled",
+			traceParent:     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedSampled: true,
+		},
+		{
+			desc:        "not sampled",
+			traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+		},
+		{
+			desc:            "future version with additional fields",
+			traceParent:     "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-foo",
+			expectedSampled: true,
+		},
+		{
+			desc:        "additional fields in version 00",
+			traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-foo",
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid version",
+			traceParent: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedErr: true,
+		},
+		{
+			desc:        "uppercase trace ID",
+			traceParent: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+			expectedErr: true,
+		},
+		{
+			desc:        "all zeros trace ID",
+			traceParent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			expectedErr: true,
+		},
+		{
+			desc:        "all zeros parent ID",
+			traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+			expectedErr: true,
+		},
+		{
+			desc:        "short parent ID",
+			traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902-01",
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid flags",
+			traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-x1",
+			expectedErr: true,
+		},
+		{
+			desc:        "missing fields",
+			traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			sc, err := parseTraceParent(test.traceParent)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedSampled, sc.sampled)
+			assert.Equal(t, [16]byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}, sc.traceID)
+			assert.Equal(t, [8]byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}, sc.spanID)
+		})
+	}
+}
+
+func TestTracer_Extract(t *testing.T) {
+	tracer := NewTracer(&collectingExporter{})
+
+	header := http.Header{}
+	header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	header.Add("Tracestate", "foo=bar")
+	header.Add("Tracestate", "baz=qux")
+	header.Set("Baggage", "user=j%20doe;prop=1, tenant=acme")
+
+	sm, err := tracer.Extract(opentracing.HTTPHeaders, tracing.HTTPHeadersCarrier(header))
+	require.NoError(t, err)
+
+	sc := sm.(spanContext)
+	assert.False(t, sc.sampled)
+	assert.Equal(t, "foo=bar,baz=qux", sc.traceState)
+	assert.Equal(t, map[string]string{"user": "j doe", "tenant": "acme"}, sc.baggage)
+
+	_, err = tracer.Extract(opentracing.HTTPHeaders, tracing.HTTPHeadersCarrier(http.Header{}))
+	assert.Equal(t, opentracing.ErrSpanContextNotFound, err)
+
+	header.Set("Traceparent", "00-foo")
+	_, err = tracer.Extract(opentracing.HTTPHeaders, tracing.HTTPHeadersCarrier(header))
+	assert.Equal(t, opentracing.ErrSpanContextCorrupted, err)
+
+	_, err = tracer.Extract(opentracing.Binary, tracing.HTTPHeadersCarrier(header))
+	assert.Equal(t, opentracing.ErrUnsupportedFormat, err)
+}
+
+func TestTracer_InjectExtract(t *testing.T) {
+	tracer := NewTracer(&collectingExporter{})
+
+	span := tracer.StartSpan("foo")
+	span.SetBaggageItem("tenant", "acme corp")
+
+	header := http.Header{}
+	require.NoError(t, tracer.Inject(span.Context(), opentracing.HTTPHeaders, tracing.HTTPHeadersCarrier(header)))
+
+	sc := span.Context().(spanContext)
+	assert.Equal(t, formatTraceParent(sc), header.Get("Traceparent"))
+	assert.Regexp(t, `^00-[0-9a-f]{32}-[0-9a-f]{16}-01$`, header.Get("Traceparent"))
+	assert.Equal(t, "tenant=acme%20corp", header.Get("Baggage"))
+
+	extracted, err := tracer.Extract(opentracing.HTTPHeaders, tracing.HTTPHeadersCarrier(header))
+	require.NoError(t, err)
+	assert.Equal(t, sc, extracted)
+
+	err = tracer.Inject(opentracing.NoopTracer{}.StartSpan("bar").Context(), opentracing.HTTPHeaders, tracing.HTTPHeadersCarrier(header))
+	assert.Equal(t, opentracing.ErrInvalidSpanContext, err)
+}
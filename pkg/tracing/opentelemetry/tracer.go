@@ -0,0 +1,326 @@
+package opentelemetry
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/containous/traefik/pkg/otlp"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	otlog "github.com/opentracing/opentracing-go/log"
+)
+
+// Exporter exports the finished spans.
+type Exporter interface {
+	Export(span otlp.Span)
+}
+
+// Tracer is an OpenTracing tracer exporting its spans in the OpenTelemetry format,
+// and propagating the W3C trace context.
+type Tracer struct {
+	exporter Exporter
+}
+
+// NewTracer creates a tracer exporting the sampled spans to exporter.
+func NewTracer(exporter Exporter) *Tracer {
+	return &Tracer{exporter: exporter}
+}
+
+// StartSpan starts a span, child of the first span context of the references created by an OpenTelemetry tracer.
+func (t *Tracer) StartSpan(operationName string, opts ...opentracing.StartSpanOption) opentracing.Span {
+	options := opentracing.StartSpanOptions{}
+	for _, opt := range opts {
+		opt.Apply(&options)
+	}
+
+	start := options.StartTime
+	if start.IsZero() {
+		start = time.Now()
+	}
+
+	s := &span{
+		tracer:        t,
+		operationName: operationName,
+		start:         start,
+		tags:          make(map[string]interface{}),
+	}
+
+	if parent, ok := parentContext(options.References); ok {
+		s.parentSpanID = parent.spanID
+		s.context = spanContext{
+			traceID:    parent.traceID,
+			spanID:     newSpanID(),
+			sampled:    parent.sampled,
+			traceState: parent.traceState,
+			baggage:    parent.baggage,
+		}
+	} else {
+		s.context = spanContext{
+			traceID: newTraceID(),
+			spanID:  newSpanID(),
+			sampled: true,
+		}
+	}
+
+	for key, value := range options.Tags {
+		s.setTag(key, value)
+	}
+
+	return s
+}
+
+// parentContext returns the span context of the first ChildOf reference, or of the first FollowsFrom one.
+func parentContext(references []opentracing.SpanReference) (spanContext, bool) {
+	var followsFrom *spanContext
+
+	for _, reference := range references {
+		sc, ok := reference.ReferencedContext.(spanContext)
+		if !ok {
+			continue
+		}
+
+		if reference.Type == opentracing.ChildOfRef {
+			return sc, true
+		}
+
+		if followsFrom == nil {
+			followsFrom = &sc
+		}
+	}
+
+	if followsFrom != nil {
+		return *followsFrom, true
+	}
+	return spanContext{}, false
+}
+
+func newTraceID() [16]byte {
+	var id [16]byte
+	for id == [16]byte{} {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+func newSpanID() [8]byte {
+	var id [8]byte
+	for id == [8]byte{} {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+// spanContext is the W3C trace context of a span, with its baggage.
+type spanContext struct {
+	traceID    [16]byte
+	spanID     [8]byte
+	sampled    bool
+	traceState string
+	baggage    map[string]string
+}
+
+// ForeachBaggageItem calls handler for each baggage item, until it returns false.
+func (c spanContext) ForeachBaggageItem(handler func(k, v string) bool) {
+	for k, v := range c.baggage {
+		if !handler(k, v) {
+			return
+		}
+	}
+}
+
+type span struct {
+	tracer *Tracer
+
+	lock          sync.Mutex
+	context       spanContext
+	parentSpanID  [8]byte
+	operationName string
+	start         time.Time
+	tags          map[string]interface{}
+	logs          []opentracing.LogRecord
+	finished      bool
+}
+
+func (s *span) Finish() {
+	s.FinishWithOptions(opentracing.FinishOptions{})
+}
+
+func (s *span) FinishWithOptions(opts opentracing.FinishOptions) {
+	end := opts.FinishTime
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.finished {
+		return
+	}
+	s.finished = true
+
+	s.logs = append(s.logs, opts.LogRecords...)
+	for _, data := range opts.BulkLogData {
+		s.logs = append(s.logs, data.ToLogRecord())
+	}
+
+	if s.context.sampled {
+		s.tracer.exporter.Export(s.toOTLP(end))
+	}
+}
+
+func (s *span) Context() opentracing.SpanContext {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.context
+}
+
+func (s *span) SetOperationName(operationName string) opentracing.Span {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.operationName = operationName
+	return s
+}
+
+func (s *span) SetTag(key string, value interface{}) opentracing.Span {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.setTag(key, value)
+	return s
+}
+
+// setTag sets the tag, the sampling priority one overriding the sampling decision of the span and of its future children.
+func (s *span) setTag(key string, value interface{}) {
+	if key != string(ext.SamplingPriority) {
+		s.tags[key] = value
+		return
+	}
+
+	priority, err := strconv.Atoi(fmt.Sprint(value))
+	if err != nil {
+		return
+	}
+	s.context.sampled = priority > 0
+}
+
+func (s *span) LogFields(fields ...otlog.Field) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.logs = append(s.logs, opentracing.LogRecord{Timestamp: time.Now(), Fields: fields})
+}
+
+func (s *span) LogKV(alternatingKeyValues ...interface{}) {
+	fields, err := otlog.InterleavedKVToFields(alternatingKeyValues...)
+	if err != nil {
+		s.LogFields(otlog.Error(err), otlog.String("function", "LogKV"))
+		return
+	}
+	s.LogFields(fields...)
+}
+
+func (s *span) SetBaggageItem(restrictedKey, value string) opentracing.Span {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// The baggage is shared with the child spans, so it is copied on write.
+	baggage := make(map[string]string, len(s.context.baggage)+1)
+	for k, v := range s.context.baggage {
+		baggage[k] = v
+	}
+	baggage[restrictedKey] = value
+	s.context.baggage = baggage
+
+	return s
+}
+
+func (s *span) BaggageItem(restrictedKey string) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.context.baggage[restrictedKey]
+}
+
+func (s *span) Tracer() opentracing.Tracer {
+	return s.tracer
+}
+
+func (s *span) LogEvent(event string) {
+	s.Log(opentracing.LogData{Event: event})
+}
+
+func (s *span) LogEventWithPayload(event string, payload interface{}) {
+	s.Log(opentracing.LogData{Event: event, Payload: payload})
+}
+
+func (s *span) Log(data opentracing.LogData) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.logs = append(s.logs, data.ToLogRecord())
+}
+
+// toOTLP converts the span, the span.kind and error tags being converted to the kind and status of the span.
+func (s *span) toOTLP(end time.Time) otlp.Span {
+	result := otlp.Span{
+		TraceID:      s.context.traceID,
+		SpanID:       s.context.spanID,
+		ParentSpanID: s.parentSpanID,
+		TraceState:   s.context.traceState,
+		Name:         s.operationName,
+		Kind:         otlp.SpanKindInternal,
+		Start:        s.start,
+		End:          end,
+	}
+
+	keys := make([]string, 0, len(s.tags))
+	for key := range s.tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := s.tags[key]
+
+		switch key {
+		case string(ext.SpanKind):
+			result.Kind = spanKind(value)
+		case string(ext.Error):
+			if isError, ok := value.(bool); ok && isError {
+				result.StatusCode = otlp.StatusCodeError
+			}
+		default:
+			result.Attributes = append(result.Attributes, otlp.KeyValue{Key: key, Value: value})
+		}
+	}
+
+	for _, record := range s.logs {
+		event := otlp.Event{Time: record.Timestamp, Name: "log"}
+		for _, field := range record.Fields {
+			if field.Key() == "event" {
+				event.Name = fmt.Sprint(field.Value())
+				continue
+			}
+			event.Attributes = append(event.Attributes, otlp.KeyValue{Key: field.Key(), Value: field.Value()})
+		}
+		result.Events = append(result.Events, event)
+	}
+
+	return result
+}
+
+func spanKind(value interface{}) otlp.SpanKind {
+	switch fmt.Sprint(value) {
+	case string(ext.SpanKindRPCServerEnum):
+		return otlp.SpanKindServer
+	case string(ext.SpanKindRPCClientEnum):
+		return otlp.SpanKindClient
+	case string(ext.SpanKindProducerEnum):
+		return otlp.SpanKindProducer
+	case string(ext.SpanKindConsumerEnum):
+		return otlp.SpanKindConsumer
+	default:
+		return otlp.SpanKindInternal
+	}
+}
@@ -0,0 +1,135 @@
+package opentelemetry
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/containous/traefik/pkg/otlp"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type collectingExporter struct {
+	lock  sync.Mutex
+	spans []otlp.Span
+}
+
+func (e *collectingExporter) Export(span otlp.Span) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.spans = append(e.spans, span)
+}
+
+func TestTracer_StartSpan(t *testing.T) {
+	exporter := &collectingExporter{}
+	tracer := NewTracer(exporter)
+
+	parent := tracer.StartSpan("parent")
+	parent.SetBaggageItem("tenant", "acme")
+
+	child := tracer.StartSpan("child", opentracing.ChildOf(parent.Context()), ext.SpanKindRPCClient)
+	child.SetTag("http.status_code", 500)
+	ext.Error.Set(child, true)
+	child.LogKV("event", "retry", "attempt", 2)
+	child.Finish()
+	child.Finish()
+
+	parent.Finish()
+
+	require.Len(t, exporter.spans, 2)
+
+	childSpan, parentSpan := exporter.spans[0], exporter.spans[1]
+
+	assert.Equal(t, "parent", parentSpan.Name)
+	assert.Equal(t, [8]byte{}, parentSpan.ParentSpanID)
+	assert.Equal(t, otlp.SpanKindInternal, parentSpan.Kind)
+
+	assert.Equal(t, "child", childSpan.Name)
+	assert.Equal(t, parentSpan.TraceID, childSpan.TraceID)
+	assert.Equal(t, parentSpan.SpanID, childSpan.ParentSpanID)
+	assert.NotEqual(t, parentSpan.SpanID, childSpan.SpanID)
+	assert.Equal(t, otlp.SpanKindClient, childSpan.Kind)
+	assert.Equal(t, otlp.StatusCodeError, childSpan.StatusCode)
+	assert.Equal(t, []otlp.KeyValue{{Key: "http.status_code", Value: 500}}, childSpan.Attributes)
+	require.Len(t, childSpan.Events, 1)
+	assert.Equal(t, "retry", childSpan.Events[0].Name)
+	assert.Equal(t, []otlp.KeyValue{{Key: "attempt", Value: 2}}, childSpan.Events[0].Attributes)
+	assert.False(t, childSpan.End.Before(childSpan.Start))
+
+	assert.Equal(t, "acme", child.BaggageItem("tenant"))
+}
+
+func TestTracer_StartSpan_notSampled(t *testing.T) {
+	exporter := &collectingExporter{}
+	tracer := NewTracer(exporter)
+
+	parent, err := parseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	require.NoError(t, err)
+
+	span := tracer.StartSpan("foo", opentracing.ChildOf(parent))
+	span.Finish()
+
+	assert.Empty(t, exporter.spans)
+	assert.Equal(t, parent.traceID, span.Context().(spanContext).traceID)
+}
+
+func TestTracer_StartSpan_samplingPriority(t *testing.T) {
+	exporter := &collectingExporter{}
+	tracer := NewTracer(exporter)
+
+	parent := tracer.StartSpan("parent")
+	ext.SamplingPriority.Set(parent, 0)
+
+	child := tracer.StartSpan("child", opentracing.ChildOf(parent.Context()))
+	child.Finish()
+	parent.Finish()
+
+	assert.Empty(t, exporter.spans)
+
+	span := tracer.StartSpan("foo", opentracing.ChildOf(parent.Context()), opentracing.Tag{Key: string(ext.SamplingPriority), Value: 1})
+	span.Finish()
+
+	require.Len(t, exporter.spans, 1)
+	assert.Empty(t, exporter.spans[0].Attributes)
+}
+
+func TestConfig_Setup(t *testing.T) {
+	payloads := make(chan []byte, 1)
+
+	collector := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/v1/traces", req.URL.Path)
+		assert.Equal(t, "bar", req.Header.Get("X-Foo"))
+
+		payload, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		payloads <- payload
+	}))
+	defer collector.Close()
+
+	config := &Config{}
+	config.SetDefaults()
+	config.Address = strings.TrimPrefix(collector.URL, "http://")
+	config.Insecure = true
+	config.Headers = map[string]string{"X-Foo": "bar"}
+
+	tracer, closer, err := config.Setup("traefik")
+	require.NoError(t, err)
+
+	span := tracer.StartSpan("my-operation")
+	span.Finish()
+
+	// Closing the tracer exports the queued spans.
+	require.NoError(t, closer.Close())
+
+	payload := <-payloads
+	assert.True(t, bytes.Contains(payload, []byte("my-operation")))
+	traceID := span.Context().(spanContext).traceID
+	assert.True(t, bytes.Contains(payload, traceID[:]))
+}
@@ -0,0 +1,359 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Trace context propagation formats.
+const (
+	PropagationTraceContext = "tracecontext"
+	PropagationB3           = "b3"
+	PropagationB3Multi      = "b3multi"
+	PropagationJaeger       = "jaeger"
+)
+
+const (
+	traceParentHeader     = "Traceparent"
+	baggageHeader         = "Baggage"
+	b3Header              = "B3"
+	b3TraceIDHeader       = "X-B3-Traceid"
+	b3SpanIDHeader        = "X-B3-Spanid"
+	b3ParentSpanIDHeader  = "X-B3-Parentspanid"
+	b3SampledHeader       = "X-B3-Sampled"
+	b3FlagsHeader         = "X-B3-Flags"
+	jaegerHeader          = "Uber-Trace-Id"
+	jaegerBaggagePrefix   = "Uberctx-"
+	datadogTraceIDHeader  = "X-Datadog-Trace-Id"
+	datadogParentIDHeader = "X-Datadog-Parent-Id"
+	datadogPriorityHeader = "X-Datadog-Sampling-Priority"
+)
+
+// traceContext is the trace context of a span, independent of the tracking backend.
+type traceContext struct {
+	// traceID is the trace ID as 32 lowercase hex characters.
+	traceID string
+	// spanID is the span ID as 16 lowercase hex characters.
+	spanID  string
+	sampled bool
+	baggage map[string]string
+}
+
+// propagator reads and writes the trace context from and to the headers, in a given format.
+type propagator interface {
+	extract(header http.Header) (traceContext, bool)
+	inject(tc traceContext, header http.Header)
+}
+
+var propagators = map[string]propagator{
+	PropagationTraceContext: traceContextPropagator{},
+	PropagationB3:           b3Propagator{},
+	PropagationB3Multi:      b3MultiPropagator{},
+	PropagationJaeger:       jaegerPropagator{},
+}
+
+// nativePropagators are the formats the tracking backends are known to use natively,
+// in which the trace contexts are translated from and to.
+var nativePropagators = []propagator{
+	traceContextPropagator{},
+	b3MultiPropagator{},
+	b3Propagator{},
+	jaegerPropagator{},
+	datadogPropagator{},
+}
+
+func newPropagators(formats []string) ([]propagator, error) {
+	var result []propagator
+	for _, format := range formats {
+		p, ok := propagators[strings.ToLower(format)]
+		if !ok {
+			return nil, fmt.Errorf("unknown propagation format %q", format)
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+func extractFirst(propagators []propagator, header http.Header) (traceContext, bool) {
+	for _, p := range propagators {
+		if tc, ok := p.extract(header); ok {
+			return tc, true
+		}
+	}
+	return traceContext{}, false
+}
+
+type traceContextPropagator struct{}
+
+func (traceContextPropagator) extract(header http.Header) (traceContext, bool) {
+	parts := strings.Split(strings.TrimSpace(header.Get(traceParentHeader)), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || parts[0] == "00" && len(parts) != 4 {
+		return traceContext{}, false
+	}
+
+	tc := traceContext{traceID: parts[1], spanID: parts[2]}
+	if !isHexID(tc.traceID, 32) || !isHexID(tc.spanID, 16) || len(parts[3]) != 2 {
+		return traceContext{}, false
+	}
+
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil {
+		return traceContext{}, false
+	}
+	tc.sampled = flags&1 == 1
+
+	tc.baggage = parseBaggage(header.Get(baggageHeader))
+
+	return tc, true
+}
+
+func (traceContextPropagator) inject(tc traceContext, header http.Header) {
+	flags := "00"
+	if tc.sampled {
+		flags = "01"
+	}
+	header.Set(traceParentHeader, "00-"+tc.traceID+"-"+tc.spanID+"-"+flags)
+
+	if len(tc.baggage) > 0 {
+		var members []string
+		for key, value := range tc.baggage {
+			members = append(members, url.PathEscape(key)+"="+url.PathEscape(value))
+		}
+		sort.Strings(members)
+		header.Set(baggageHeader, strings.Join(members, ","))
+	}
+}
+
+func parseBaggage(value string) map[string]string {
+	baggage := make(map[string]string)
+	for _, member := range strings.Split(value, ",") {
+		// The properties of the member are ignored.
+		keyValue := strings.SplitN(strings.SplitN(member, ";", 2)[0], "=", 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+
+		key, err := url.PathUnescape(strings.TrimSpace(keyValue[0]))
+		if err != nil || key == "" {
+			continue
+		}
+
+		val, err := url.PathUnescape(strings.TrimSpace(keyValue[1]))
+		if err != nil {
+			continue
+		}
+		baggage[key] = val
+	}
+
+	if len(baggage) == 0 {
+		return nil
+	}
+	return baggage
+}
+
+type b3Propagator struct{}
+
+func (b3Propagator) extract(header http.Header) (traceContext, bool) {
+	// A B3 header holding only the sampling decision, without IDs, carries no trace context.
+	parts := strings.Split(strings.TrimSpace(header.Get(b3Header)), "-")
+	if len(parts) < 2 {
+		return traceContext{}, false
+	}
+
+	tc := traceContext{traceID: padTraceID(parts[0]), spanID: parts[1], sampled: true}
+	if !isHexID(tc.traceID, 32) || !isHexID(tc.spanID, 16) {
+		return traceContext{}, false
+	}
+
+	if len(parts) > 2 {
+		tc.sampled = parts[2] == "1" || parts[2] == "d"
+	}
+
+	return tc, true
+}
+
+func (b3Propagator) inject(tc traceContext, header http.Header) {
+	sampled := "0"
+	if tc.sampled {
+		sampled = "1"
+	}
+	header.Set(b3Header, tc.traceID+"-"+tc.spanID+"-"+sampled)
+}
+
+type b3MultiPropagator struct{}
+
+func (b3MultiPropagator) extract(header http.Header) (traceContext, bool) {
+	tc := traceContext{
+		traceID: padTraceID(header.Get(b3TraceIDHeader)),
+		spanID:  header.Get(b3SpanIDHeader),
+		sampled: true,
+	}
+	if !isHexID(tc.traceID, 32) || !isHexID(tc.spanID, 16) {
+		return traceContext{}, false
+	}
+
+	if sampled := header.Get(b3SampledHeader); sampled != "" {
+		tc.sampled = sampled == "1" || strings.EqualFold(sampled, "true")
+	}
+	if header.Get(b3FlagsHeader) == "1" {
+		tc.sampled = true
+	}
+
+	return tc, true
+}
+
+func (b3MultiPropagator) inject(tc traceContext, header http.Header) {
+	header.Set(b3TraceIDHeader, tc.traceID)
+	header.Set(b3SpanIDHeader, tc.spanID)
+	header.Del(b3ParentSpanIDHeader)
+
+	sampled := "0"
+	if tc.sampled {
+		sampled = "1"
+	}
+	header.Set(b3SampledHeader, sampled)
+}
+
+type jaegerPropagator struct{}
+
+func (jaegerPropagator) extract(header http.Header) (traceContext, bool) {
+	// The value can be URL encoded.
+	value, err := url.QueryUnescape(header.Get(jaegerHeader))
+	if err != nil {
+		return traceContext{}, false
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) != 4 || len(parts[0]) > 32 || len(parts[1]) > 16 {
+		return traceContext{}, false
+	}
+
+	tc := traceContext{
+		traceID: padTraceID(parts[0]),
+		spanID:  strings.Repeat("0", 16-len(parts[1])) + parts[1],
+	}
+	if !isHexID(tc.traceID, 32) || !isHexID(tc.spanID, 16) {
+		return traceContext{}, false
+	}
+
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil {
+		return traceContext{}, false
+	}
+	tc.sampled = flags&1 == 1
+
+	for key, values := range header {
+		if !strings.HasPrefix(key, jaegerBaggagePrefix) || len(values) == 0 {
+			continue
+		}
+
+		if tc.baggage == nil {
+			tc.baggage = make(map[string]string)
+		}
+
+		val, err := url.QueryUnescape(values[0])
+		if err != nil {
+			val = values[0]
+		}
+		tc.baggage[strings.ToLower(strings.TrimPrefix(key, jaegerBaggagePrefix))] = val
+	}
+
+	return tc, true
+}
+
+func (jaegerPropagator) inject(tc traceContext, header http.Header) {
+	flags := "0"
+	if tc.sampled {
+		flags = "1"
+	}
+
+	// The 64 bit trace IDs are written as such, for the tracers not supporting the 128 bit ones.
+	traceID := strings.TrimPrefix(tc.traceID, "0000000000000000")
+	header.Set(jaegerHeader, traceID+":"+tc.spanID+":0:"+flags)
+
+	for key, value := range tc.baggage {
+		header.Set(jaegerBaggagePrefix+key, url.QueryEscape(value))
+	}
+}
+
+// datadogPropagator only translates the trace contexts from and to the DataDog tracer,
+// which uses decimal 64 bit IDs.
+type datadogPropagator struct{}
+
+func (datadogPropagator) extract(header http.Header) (traceContext, bool) {
+	traceID, err := strconv.ParseUint(header.Get(datadogTraceIDHeader), 10, 64)
+	if err != nil || traceID == 0 {
+		return traceContext{}, false
+	}
+
+	spanID, err := strconv.ParseUint(header.Get(datadogParentIDHeader), 10, 64)
+	if err != nil || spanID == 0 {
+		return traceContext{}, false
+	}
+
+	tc := traceContext{
+		traceID: fmt.Sprintf("%032x", traceID),
+		spanID:  fmt.Sprintf("%016x", spanID),
+		sampled: true,
+	}
+
+	if priority, err := strconv.Atoi(header.Get(datadogPriorityHeader)); err == nil {
+		tc.sampled = priority > 0
+	}
+
+	return tc, true
+}
+
+func (datadogPropagator) inject(tc traceContext, header http.Header) {
+	// DataDog only knows the lower 64 bits of the trace ID.
+	traceID, err := strconv.ParseUint(tc.traceID[16:], 16, 64)
+	if err != nil {
+		return
+	}
+
+	spanID, err := strconv.ParseUint(tc.spanID, 16, 64)
+	if err != nil {
+		return
+	}
+
+	header.Set(datadogTraceIDHeader, strconv.FormatUint(traceID, 10))
+	header.Set(datadogParentIDHeader, strconv.FormatUint(spanID, 10))
+
+	priority := "0"
+	if tc.sampled {
+		priority = "1"
+	}
+	header.Set(datadogPriorityHeader, priority)
+}
+
+// padTraceID left pads the 64 bit trace IDs to 128 bits.
+func padTraceID(traceID string) string {
+	if len(traceID) > 0 && len(traceID) < 32 {
+		return strings.Repeat("0", 32-len(traceID)) + traceID
+	}
+	return traceID
+}
+
+// isHexID checks that id is made of size lowercase hex characters, not all zeros.
+func isHexID(id string, size int) bool {
+	if len(id) != size {
+		return false
+	}
+
+	allZeros := true
+	for _, c := range id {
+		switch {
+		case c == '0':
+		case c >= '1' && c <= '9', c >= 'a' && c <= 'f':
+			allZeros = false
+		default:
+			return false
+		}
+	}
+
+	return !allZeros
+}
@@ -0,0 +1,250 @@
+package tracing
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropagators_extract(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		propagator propagator
+		header     map[string]string
+		expected   *traceContext
+	}{
+		{
+			desc:       "tracecontext",
+			propagator: traceContextPropagator{},
+			header: map[string]string{
+				"Traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+				"Baggage":     "user=j%20doe;prop=1, tenant=acme",
+			},
+			expected: &traceContext{
+				traceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+				spanID:  "00f067aa0ba902b7",
+				sampled: true,
+				baggage: map[string]string{"user": "j doe", "tenant": "acme"},
+			},
+		},
+		{
+			desc:       "tracecontext not sampled",
+			propagator: traceContextPropagator{},
+			header:     map[string]string{"Traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"},
+			expected: &traceContext{
+				traceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+				spanID:  "00f067aa0ba902b7",
+			},
+		},
+		{
+			desc:       "tracecontext with all zeros trace ID",
+			propagator: traceContextPropagator{},
+			header:     map[string]string{"Traceparent": "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		},
+		{
+			desc:       "tracecontext with invalid version",
+			propagator: traceContextPropagator{},
+			header:     map[string]string{"Traceparent": "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		},
+		{
+			desc:       "b3 single header",
+			propagator: b3Propagator{},
+			header:     map[string]string{"B3": "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90"},
+			expected: &traceContext{
+				traceID: "80f198ee56343ba864fe8b2a57d3eff7",
+				spanID:  "e457b5a2e4d86bd1",
+				sampled: true,
+			},
+		},
+		{
+			desc:       "b3 single header with 64 bit trace ID and deferred sampling",
+			propagator: b3Propagator{},
+			header:     map[string]string{"B3": "64fe8b2a57d3eff7-e457b5a2e4d86bd1"},
+			expected: &traceContext{
+				traceID: "000000000000000064fe8b2a57d3eff7",
+				spanID:  "e457b5a2e4d86bd1",
+				sampled: true,
+			},
+		},
+		{
+			desc:       "b3 single header with sampling decision only",
+			propagator: b3Propagator{},
+			header:     map[string]string{"B3": "0"},
+		},
+		{
+			desc:       "b3 multiple headers",
+			propagator: b3MultiPropagator{},
+			header: map[string]string{
+				"X-B3-TraceId": "80f198ee56343ba864fe8b2a57d3eff7",
+				"X-B3-SpanId":  "e457b5a2e4d86bd1",
+				"X-B3-Sampled": "0",
+			},
+			expected: &traceContext{
+				traceID: "80f198ee56343ba864fe8b2a57d3eff7",
+				spanID:  "e457b5a2e4d86bd1",
+			},
+		},
+		{
+			desc:       "b3 multiple headers with debug flag",
+			propagator: b3MultiPropagator{},
+			header: map[string]string{
+				"X-B3-TraceId": "64fe8b2a57d3eff7",
+				"X-B3-SpanId":  "e457b5a2e4d86bd1",
+				"X-B3-Sampled": "0",
+				"X-B3-Flags":   "1",
+			},
+			expected: &traceContext{
+				traceID: "000000000000000064fe8b2a57d3eff7",
+				spanID:  "e457b5a2e4d86bd1",
+				sampled: true,
+			},
+		},
+		{
+			desc:       "b3 multiple headers without span ID",
+			propagator: b3MultiPropagator{},
+			header:     map[string]string{"X-B3-TraceId": "80f198ee56343ba864fe8b2a57d3eff7"},
+		},
+		{
+			desc:       "jaeger",
+			propagator: jaegerPropagator{},
+			header: map[string]string{
+				"Uber-Trace-Id":  "64fe8b2a57d3eff7:a2e4d86bd1:0:1",
+				"Uberctx-Tenant": "acme%20corp",
+			},
+			expected: &traceContext{
+				traceID: "000000000000000064fe8b2a57d3eff7",
+				spanID:  "000000a2e4d86bd1",
+				sampled: true,
+				baggage: map[string]string{"tenant": "acme corp"},
+			},
+		},
+		{
+			desc:       "jaeger URL encoded",
+			propagator: jaegerPropagator{},
+			header:     map[string]string{"Uber-Trace-Id": "80f198ee56343ba864fe8b2a57d3eff7%3Ae457b5a2e4d86bd1%3A0%3A0"},
+			expected: &traceContext{
+				traceID: "80f198ee56343ba864fe8b2a57d3eff7",
+				spanID:  "e457b5a2e4d86bd1",
+			},
+		},
+		{
+			desc:       "jaeger with missing fields",
+			propagator: jaegerPropagator{},
+			header:     map[string]string{"Uber-Trace-Id": "64fe8b2a57d3eff7:a2e4d86bd1"},
+		},
+		{
+			desc:       "datadog",
+			propagator: datadogPropagator{},
+			header: map[string]string{
+				"X-Datadog-Trace-Id":          "7277407061855694839",
+				"X-Datadog-Parent-Id":         "16453819474850114513",
+				"X-Datadog-Sampling-Priority": "0",
+			},
+			expected: &traceContext{
+				traceID: "000000000000000064fe8b2a57d3eff7",
+				spanID:  "e457b5a2e4d86bd1",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			header := http.Header{}
+			for name, value := range test.header {
+				header.Set(name, value)
+			}
+
+			tc, ok := test.propagator.extract(header)
+			if test.expected == nil {
+				assert.False(t, ok)
+				return
+			}
+
+			require.True(t, ok)
+			assert.Equal(t, *test.expected, tc)
+		})
+	}
+}
+
+func TestPropagators_roundTrip(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		propagator propagator
+		tc         traceContext
+	}{
+		{
+			desc:       "tracecontext",
+			propagator: traceContextPropagator{},
+			tc: traceContext{
+				traceID: "80f198ee56343ba864fe8b2a57d3eff7",
+				spanID:  "e457b5a2e4d86bd1",
+				sampled: true,
+				baggage: map[string]string{"tenant": "acme corp", "user": "foo"},
+			},
+		},
+		{
+			desc:       "b3",
+			propagator: b3Propagator{},
+			tc: traceContext{
+				traceID: "80f198ee56343ba864fe8b2a57d3eff7",
+				spanID:  "e457b5a2e4d86bd1",
+			},
+		},
+		{
+			desc:       "b3multi",
+			propagator: b3MultiPropagator{},
+			tc: traceContext{
+				traceID: "80f198ee56343ba864fe8b2a57d3eff7",
+				spanID:  "e457b5a2e4d86bd1",
+				sampled: true,
+			},
+		},
+		{
+			desc:       "jaeger with 64 bit trace ID",
+			propagator: jaegerPropagator{},
+			tc: traceContext{
+				traceID: "000000000000000064fe8b2a57d3eff7",
+				spanID:  "e457b5a2e4d86bd1",
+				sampled: true,
+				baggage: map[string]string{"tenant": "acme corp"},
+			},
+		},
+		{
+			desc:       "datadog",
+			propagator: datadogPropagator{},
+			tc: traceContext{
+				traceID: "000000000000000064fe8b2a57d3eff7",
+				spanID:  "e457b5a2e4d86bd1",
+				sampled: true,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			header := http.Header{}
+			test.propagator.inject(test.tc, header)
+
+			tc, ok := test.propagator.extract(header)
+			require.True(t, ok)
+			assert.Equal(t, test.tc, tc)
+		})
+	}
+}
+
+func TestNewPropagators(t *testing.T) {
+	result, err := newPropagators([]string{"B3", "tracecontext"})
+	require.NoError(t, err)
+	assert.Equal(t, []propagator{b3Propagator{}, traceContextPropagator{}}, result)
+
+	_, err = newPropagators([]string{"foo"})
+	assert.Error(t, err)
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
+	"strings"
 
 	"github.com/containous/traefik/pkg/log"
 	"github.com/opentracing/opentracing-go"
@@ -18,6 +20,7 @@ const (
 	// SpanKindNoneEnum Span kind enum none.
 	SpanKindNoneEnum ext.SpanKindEnum = "none"
 	tracingKey       contextKey       = iota
+	samplingKey
 )
 
 // WithTracing Adds Tracing into the context.
@@ -43,22 +46,64 @@ type TrackingBackend interface {
 	Setup(componentName string) (opentracing.Tracer, io.Closer, error)
 }
 
+// Options holds the settings of the tracing that do not depend on the tracking backend.
+type Options struct {
+	Propagation   []string
+	HeaderTags    map[string]string
+	Sampling      *Sampling
+	ExcludedPaths []string
+}
+
+// Sampling holds the sampling decisions taken for the requests starting a trace,
+// on top of the ones of the tracking backend.
+type Sampling struct {
+	Rate    float64            `description:"Ratio of the requests starting a trace that are sampled, from 0 to 1." export:"true"`
+	Routers map[string]float64 `description:"Sampling ratios overriding the default one for the requests handled by the given routers." export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (s *Sampling) SetDefaults() {
+	s.Rate = 1.0
+}
+
 // Tracing middleware.
 type Tracing struct {
 	ServiceName   string `description:"Set the name for this service" export:"true"`
 	SpanNameLimit int    `description:"Set the maximum character limit for Span names (default 0 = no limit)" export:"true"`
 
-	tracer opentracing.Tracer
-	closer io.Closer
+	tracer        opentracing.Tracer
+	closer        io.Closer
+	propagators   []propagator
+	headerTags    map[string]string
+	sampling      *Sampling
+	excludedPaths []string
 }
 
 // NewTracing Creates a Tracing.
-func NewTracing(serviceName string, spanNameLimit int, trackingBackend TrackingBackend) (*Tracing, error) {
+func NewTracing(serviceName string, spanNameLimit int, trackingBackend TrackingBackend, options *Options) (*Tracing, error) {
 	tracing := &Tracing{
 		ServiceName:   serviceName,
 		SpanNameLimit: spanNameLimit,
 	}
 
+	if options != nil {
+		var err error
+		tracing.propagators, err = newPropagators(options.Propagation)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, path := range options.ExcludedPaths {
+			if !strings.HasPrefix(path, "/") {
+				return nil, fmt.Errorf("invalid excluded path %q: the path must start with /", path)
+			}
+		}
+
+		tracing.headerTags = options.HeaderTags
+		tracing.sampling = options.Sampling
+		tracing.excludedPaths = options.ExcludedPaths
+	}
+
 	var err error
 	tracing.tracer, tracing.closer, err = trackingBackend.Setup(serviceName)
 	if err != nil {
@@ -79,14 +124,121 @@ func (t *Tracing) StartSpanf(r *http.Request, spanKind ext.SpanKindEnum, opPrefi
 	return StartSpan(r, operationName, spanKind, opts...)
 }
 
-// Inject delegates to opentracing.Tracer.
+// Inject delegates to opentracing.Tracer,
+// or writes the headers in the configured propagation formats instead of the native one of the tracer.
 func (t *Tracing) Inject(sm opentracing.SpanContext, format interface{}, carrier interface{}) error {
-	return t.tracer.Inject(sm, format, carrier)
+	header, ok := carrier.(HTTPHeadersCarrier)
+	if len(t.propagators) == 0 || !ok {
+		return t.tracer.Inject(sm, format, carrier)
+	}
+
+	native := http.Header{}
+	if err := t.tracer.Inject(sm, format, HTTPHeadersCarrier(native)); err != nil {
+		return err
+	}
+
+	tc, found := extractFirst(nativePropagators, native)
+	if !found {
+		log.WithoutContext().Debug("Unable to translate the trace context of the tracer: falling back on its native propagation format")
+		return t.tracer.Inject(sm, format, carrier)
+	}
+
+	for _, p := range t.propagators {
+		p.inject(tc, http.Header(header))
+	}
+	return nil
 }
 
-// Extract delegates to opentracing.Tracer.
+// Extract delegates to opentracing.Tracer,
+// or reads the headers in the configured propagation formats instead of the native one of the tracer.
 func (t *Tracing) Extract(format interface{}, carrier interface{}) (opentracing.SpanContext, error) {
-	return t.tracer.Extract(format, carrier)
+	header, ok := carrier.(HTTPHeadersCarrier)
+	if len(t.propagators) == 0 || !ok {
+		return t.tracer.Extract(format, carrier)
+	}
+
+	tc, found := extractFirst(t.propagators, http.Header(header))
+	if !found {
+		return nil, opentracing.ErrSpanContextNotFound
+	}
+
+	// The trace context is written in all the formats known to be used by the tracers,
+	// for the tracer to read it from its native one.
+	native := http.Header{}
+	for _, p := range nativePropagators {
+		p.inject(tc, native)
+	}
+	return t.tracer.Extract(format, HTTPHeadersCarrier(native))
+}
+
+// IsExcluded checks whether the request must not be traced.
+// An excluded path excludes its sub-paths too, except the root path which only excludes itself.
+func (t *Tracing) IsExcluded(r *http.Request) bool {
+	for _, path := range t.excludedPaths {
+		if r.URL.Path == path {
+			return true
+		}
+
+		if path != "/" && strings.HasPrefix(r.URL.Path, strings.TrimSuffix(path, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// LogRequestHeaders sets the span tags from the configured request headers.
+func (t *Tracing) LogRequestHeaders(span opentracing.Span, r *http.Request) {
+	for tag, header := range t.headerTags {
+		if value := r.Header.Get(header); value != "" {
+			span.SetTag(tag, value)
+		}
+	}
+}
+
+// Sample takes the sampling decision of the request starting a trace,
+// overriding the one of the tracer when the request is not sampled.
+func (t *Tracing) Sample(span opentracing.Span, r *http.Request) *http.Request {
+	if t.sampling == nil {
+		return r
+	}
+
+	draw := rand.Float64()
+	if draw >= t.sampling.Rate {
+		ext.SamplingPriority.Set(span, 0)
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), samplingKey, draw))
+}
+
+// SampleRouter takes the sampling decision again for the request starting a trace,
+// with the sampling ratio of the router handling it if it overrides the default one.
+func (t *Tracing) SampleRouter(r *http.Request, routerName string) {
+	if t.sampling == nil {
+		return
+	}
+
+	rate, ok := t.sampling.Routers[routerName]
+	if !ok {
+		return
+	}
+
+	draw, ok := r.Context().Value(samplingKey).(float64)
+	if !ok {
+		return
+	}
+
+	sampled := draw < rate
+	if sampled == (draw < t.sampling.Rate) {
+		return
+	}
+
+	if span := GetSpan(r); span != nil {
+		if sampled {
+			ext.SamplingPriority.Set(span, 1)
+		} else {
+			ext.SamplingPriority.Set(span, 0)
+		}
+	}
 }
 
 // IsEnabled determines if tracing was successfully activated.
@@ -131,16 +283,22 @@ func GetSpan(r *http.Request) opentracing.Span {
 	return opentracing.SpanFromContext(r.Context())
 }
 
-// InjectRequestHeaders used to inject OpenTracing headers into the request.
+// InjectRequestHeaders used to inject OpenTracing headers into the request,
+// in the propagation formats of the Tracing in the request context if any.
 func InjectRequestHeaders(r *http.Request) {
-	if span := GetSpan(r); span != nil {
-		err := opentracing.GlobalTracer().Inject(
-			span.Context(),
-			opentracing.HTTPHeaders,
-			HTTPHeadersCarrier(r.Header))
-		if err != nil {
-			log.FromContext(r.Context()).Error(err)
-		}
+	span := GetSpan(r)
+	if span == nil {
+		return
+	}
+
+	var err error
+	if tr, errTracing := FromContext(r.Context()); errTracing == nil {
+		err = tr.Inject(span.Context(), opentracing.HTTPHeaders, HTTPHeadersCarrier(r.Header))
+	} else {
+		err = opentracing.GlobalTracer().Inject(span.Context(), opentracing.HTTPHeaders, HTTPHeadersCarrier(r.Header))
+	}
+	if err != nil {
+		log.FromContext(r.Context()).Error(err)
 	}
 }
 
@@ -0,0 +1,209 @@
+package tracing
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// b3Tracer is a tracer natively propagating its span contexts with the B3 multiple headers.
+type b3Tracer struct {
+	opentracing.NoopTracer
+}
+
+func (b3Tracer) Setup(componentName string) (opentracing.Tracer, io.Closer, error) {
+	return b3Tracer{}, nil, nil
+}
+
+func (b3Tracer) Inject(sm opentracing.SpanContext, format interface{}, carrier interface{}) error {
+	sc, ok := sm.(b3SpanContext)
+	if !ok {
+		return opentracing.ErrInvalidSpanContext
+	}
+	b3MultiPropagator{}.inject(traceContext(sc), http.Header(carrier.(HTTPHeadersCarrier)))
+	return nil
+}
+
+func (b3Tracer) Extract(format interface{}, carrier interface{}) (opentracing.SpanContext, error) {
+	tc, ok := b3MultiPropagator{}.extract(http.Header(carrier.(HTTPHeadersCarrier)))
+	if !ok {
+		return nil, opentracing.ErrSpanContextNotFound
+	}
+	return b3SpanContext(tc), nil
+}
+
+type b3SpanContext traceContext
+
+func (b3SpanContext) ForeachBaggageItem(handler func(k, v string) bool) {}
+
+type taggedSpan struct {
+	opentracing.Span
+	tags map[string]interface{}
+}
+
+func (s *taggedSpan) SetTag(key string, value interface{}) opentracing.Span {
+	s.tags[key] = value
+	return s
+}
+
+func TestTracing_Extract(t *testing.T) {
+	tr, err := NewTracing("traefik", 0, b3Tracer{}, &Options{Propagation: []string{"jaeger", "tracecontext"}})
+	require.NoError(t, err)
+
+	header := http.Header{}
+	header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+
+	sc, err := tr.Extract(opentracing.HTTPHeaders, HTTPHeadersCarrier(header))
+	require.NoError(t, err)
+	assert.Equal(t, b3SpanContext{traceID: "4bf92f3577b34da6a3ce929d0e0e4736", spanID: "00f067aa0ba902b7"}, sc)
+
+	// The native format of the tracer is not read when the propagation formats are configured.
+	header = http.Header{}
+	header.Set("X-B3-TraceId", "4bf92f3577b34da6a3ce929d0e0e4736")
+	header.Set("X-B3-SpanId", "00f067aa0ba902b7")
+
+	_, err = tr.Extract(opentracing.HTTPHeaders, HTTPHeadersCarrier(header))
+	assert.Equal(t, opentracing.ErrSpanContextNotFound, err)
+}
+
+func TestTracing_Inject(t *testing.T) {
+	tr, err := NewTracing("traefik", 0, b3Tracer{}, &Options{Propagation: []string{"b3", "jaeger"}})
+	require.NoError(t, err)
+
+	header := http.Header{}
+	sc := b3SpanContext{traceID: "4bf92f3577b34da6a3ce929d0e0e4736", spanID: "00f067aa0ba902b7", sampled: true}
+	require.NoError(t, tr.Inject(sc, opentracing.HTTPHeaders, HTTPHeadersCarrier(header)))
+
+	assert.Equal(t, http.Header{
+		"B3":            {"4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1"},
+		"Uber-Trace-Id": {"4bf92f3577b34da6a3ce929d0e0e4736:00f067aa0ba902b7:0:1"},
+	}, header)
+}
+
+func TestTracing_Inject_nativePropagation(t *testing.T) {
+	tr, err := NewTracing("traefik", 0, b3Tracer{}, nil)
+	require.NoError(t, err)
+
+	header := http.Header{}
+	sc := b3SpanContext{traceID: "4bf92f3577b34da6a3ce929d0e0e4736", spanID: "00f067aa0ba902b7", sampled: true}
+	require.NoError(t, tr.Inject(sc, opentracing.HTTPHeaders, HTTPHeadersCarrier(header)))
+
+	assert.Equal(t, http.Header{
+		"X-B3-Traceid": {"4bf92f3577b34da6a3ce929d0e0e4736"},
+		"X-B3-Spanid":  {"00f067aa0ba902b7"},
+		"X-B3-Sampled": {"1"},
+	}, header)
+}
+
+func TestNewTracing_unknownPropagation(t *testing.T) {
+	_, err := NewTracing("traefik", 0, b3Tracer{}, &Options{Propagation: []string{"foo"}})
+	assert.Error(t, err)
+}
+
+func TestNewTracing_invalidExcludedPath(t *testing.T) {
+	_, err := NewTracing("traefik", 0, b3Tracer{}, &Options{ExcludedPaths: []string{"ping"}})
+	assert.Error(t, err)
+
+	_, err = NewTracing("traefik", 0, b3Tracer{}, &Options{ExcludedPaths: []string{""}})
+	assert.Error(t, err)
+}
+
+func TestTracing_IsExcluded(t *testing.T) {
+	tr, err := NewTracing("traefik", 0, b3Tracer{}, &Options{ExcludedPaths: []string{"/ping", "/metrics/", "/"}})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		path     string
+		expected bool
+	}{
+		{path: "/", expected: true},
+		{path: "/ping", expected: true},
+		{path: "/ping/", expected: true},
+		{path: "/pingpong"},
+		{path: "/metrics", expected: false},
+		{path: "/metrics/foo", expected: true},
+		{path: "/foo"},
+		{path: "/foo/bar"},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.path, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "http://foo.bar"+test.path, nil)
+			assert.Equal(t, test.expected, tr.IsExcluded(req))
+		})
+	}
+}
+
+func TestTracing_SampleRouter(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		sampling         *Sampling
+		router           string
+		expectedPriority map[string]interface{}
+	}{
+		{
+			desc:             "no sampling",
+			router:           "foo@file",
+			expectedPriority: map[string]interface{}{},
+		},
+		{
+			desc:             "all requests sampled",
+			sampling:         &Sampling{Rate: 1},
+			router:           "foo@file",
+			expectedPriority: map[string]interface{}{},
+		},
+		{
+			desc:             "no requests sampled",
+			sampling:         &Sampling{Rate: 0},
+			router:           "foo@file",
+			expectedPriority: map[string]interface{}{string(ext.SamplingPriority): uint16(0)},
+		},
+		{
+			desc:             "router override sampling all the requests",
+			sampling:         &Sampling{Rate: 0, Routers: map[string]float64{"foo@file": 1}},
+			router:           "foo@file",
+			expectedPriority: map[string]interface{}{string(ext.SamplingPriority): uint16(1)},
+		},
+		{
+			desc:             "router override sampling no requests",
+			sampling:         &Sampling{Rate: 1, Routers: map[string]float64{"foo@file": 0}},
+			router:           "foo@file",
+			expectedPriority: map[string]interface{}{string(ext.SamplingPriority): uint16(0)},
+		},
+		{
+			desc:             "override of another router",
+			sampling:         &Sampling{Rate: 0, Routers: map[string]float64{"bar@file": 1}},
+			router:           "foo@file",
+			expectedPriority: map[string]interface{}{string(ext.SamplingPriority): uint16(0)},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			tr, err := NewTracing("traefik", 0, b3Tracer{}, &Options{Sampling: test.sampling})
+			require.NoError(t, err)
+
+			span := &taggedSpan{Span: opentracing.NoopTracer{}.StartSpan("foo"), tags: make(map[string]interface{})}
+
+			req := httptest.NewRequest(http.MethodGet, "http://foo.bar", nil)
+			req = req.WithContext(opentracing.ContextWithSpan(req.Context(), span))
+
+			req = tr.Sample(span, req)
+			tr.SampleRouter(req, test.router)
+
+			assert.Equal(t, test.expectedPriority, span.tags)
+		})
+	}
+}
@@ -5,10 +5,12 @@ import (
 	"strings"
 )
 
+// +k8s:deepcopy-gen=true
+
 // Domain holds a domain name with SANs.
 type Domain struct {
-	Main string   `description:"Default subject name."`
-	SANs []string `description:"Subject alternative names."`
+	Main string   `description:"Default subject name." json:"main,omitempty" toml:"main,omitempty"`
+	SANs []string `description:"Subject alternative names." json:"sans,omitempty" toml:"sans,omitempty"`
 }
 
 // ToStrArray convert a domain into an array of strings.
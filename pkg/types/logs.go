@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 const (
 	// AccessLogKeep is the keep string value
 	AccessLogKeep = "keep"
@@ -32,11 +34,16 @@ func (l *TraefikLog) SetDefaults() {
 
 // AccessLog holds the configuration settings for the access logger (middlewares/accesslog).
 type AccessLog struct {
-	FilePath      string            `json:"file,omitempty" description:"Access log file path. Stdout is used when omitted or empty." export:"true"`
-	Format        string            `json:"format,omitempty" description:"Access log format: json | common" export:"true"`
-	Filters       *AccessLogFilters `json:"filters,omitempty" description:"Access log filters, used to keep only specific access logs." export:"true"`
-	Fields        *AccessLogFields  `json:"fields,omitempty" description:"AccessLogFields." export:"true"`
-	BufferingSize int64             `json:"bufferingSize,omitempty" description:"Number of access log lines to process in a buffered way." export:"true"`
+	FilePath      string             `json:"file,omitempty" description:"Access log file path. Stdout is used when omitted or empty." export:"true"`
+	Format        string             `json:"format,omitempty" description:"Access log format: json | common | combined | logfmt | template" export:"true"`
+	Template      string             `json:"template,omitempty" description:"Template of the access log lines, used by the template format." export:"true"`
+	Filters       *AccessLogFilters  `json:"filters,omitempty" description:"Access log filters, used to keep only specific access logs." export:"true"`
+	Fields        *AccessLogFields   `json:"fields,omitempty" description:"AccessLogFields." export:"true"`
+	BufferingSize int64              `json:"bufferingSize,omitempty" description:"Number of access log lines to process in a buffered way." export:"true"`
+	TCP           bool               `json:"tcp,omitempty" description:"Write an access log line for each TCP connection handled by a TCP router." export:"true"`
+	Rotation      *AccessLogRotation `json:"rotation,omitempty" description:"Rotate the access log file by size or age." export:"true" label:"allowEmpty"`
+	Syslog        *AccessLogSyslog   `json:"syslog,omitempty" description:"Write the access logs to syslog (RFC 5424) instead of a file." export:"true" label:"allowEmpty"`
+	Remote        *AccessLogRemote   `json:"remote,omitempty" description:"Write the access logs to a remote TCP or UDP log collector instead of a file." export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -48,11 +55,55 @@ func (l *AccessLog) SetDefaults() {
 	l.Fields.SetDefaults()
 }
 
+// AccessLogRotation holds the rotation configuration of the access log file.
+type AccessLogRotation struct {
+	MaxSize    int      `json:"maxSize,omitempty" description:"Maximum size in megabytes of the access log file before it gets rotated." export:"true"`
+	MaxAge     Duration `json:"maxAge,omitempty" description:"Maximum age of the access log file before it gets rotated." export:"true"`
+	MaxBackups int      `json:"maxBackups,omitempty" description:"Maximum number of rotated access log files to keep, all of them being kept when zero." export:"true"`
+	Compress   bool     `json:"compress,omitempty" description:"Compress the rotated access log files with gzip." export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (r *AccessLogRotation) SetDefaults() {
+	r.MaxSize = 100
+}
+
+// AccessLogSyslog holds the syslog configuration of the access logs.
+type AccessLogSyslog struct {
+	Network  string `json:"network,omitempty" description:"Network of the syslog server: udp | tcp | unix | unixgram. The local syslog daemon is used when omitted or empty." export:"true"`
+	Address  string `json:"address,omitempty" description:"Address of the syslog server." export:"true"`
+	Facility string `json:"facility,omitempty" description:"Syslog facility of the messages: kern | user | mail | daemon | auth | syslog | lpr | news | uucp | cron | authpriv | ftp | local0 to local7." export:"true"`
+	Tag      string `json:"tag,omitempty" description:"Application name of the messages." export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (s *AccessLogSyslog) SetDefaults() {
+	s.Facility = "local0"
+	s.Tag = "traefik"
+}
+
+// AccessLogRemote holds the configuration of a remote log collector receiving the access logs, one line per message.
+type AccessLogRemote struct {
+	Network   string   `json:"network,omitempty" description:"Network of the log collector: tcp | udp" export:"true"`
+	Address   string   `json:"address,omitempty" description:"Address of the log collector." export:"true"`
+	QueueSize int      `json:"queueSize,omitempty" description:"Number of access log lines waiting to be sent before the next ones are dropped." export:"true"`
+	Timeout   Duration `json:"timeout,omitempty" description:"Timeout to connect and write to the log collector." export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (r *AccessLogRemote) SetDefaults() {
+	r.Network = "tcp"
+	r.QueueSize = 1000
+	r.Timeout = Duration(5 * time.Second)
+}
+
 // AccessLogFilters holds filters configuration
 type AccessLogFilters struct {
 	StatusCodes   []string `json:"statusCodes,omitempty" description:"Keep access logs with status codes in the specified range." export:"true"`
 	RetryAttempts bool     `json:"retryAttempts,omitempty" description:"Keep access logs when at least one retry happened." export:"true"`
 	MinDuration   Duration `json:"duration,omitempty" description:"Keep access logs when request took longer than the specified duration." export:"true"`
+	SampleRate    float64  `json:"sampleRate,omitempty" description:"Fraction of the successful requests (status code lower than 400) logged, between 0 and 1, the errors being always logged. All the requests are logged when omitted or zero." export:"true"`
+	OptIn         bool     `json:"optIn,omitempty" description:"Only log the requests of the routers enabling the access logs in their options." export:"true"`
 }
 
 // FieldHeaders holds configuration for access log headers
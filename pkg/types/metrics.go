@@ -6,17 +6,27 @@ import (
 
 // Metrics provides options to expose and send Traefik metrics to different third party monitoring systems
 type Metrics struct {
-	Prometheus *Prometheus `description:"Prometheus metrics exporter type." export:"true" label:"allowEmpty"`
-	Datadog    *Datadog    `description:"DataDog metrics exporter type." export:"true" label:"allowEmpty"`
-	StatsD     *Statsd     `description:"StatsD metrics exporter type." export:"true" label:"allowEmpty"`
-	InfluxDB   *InfluxDB   `description:"InfluxDB metrics exporter type." label:"allowEmpty"`
+	Prometheus    *Prometheus    `description:"Prometheus metrics exporter type." export:"true" label:"allowEmpty"`
+	Datadog       *Datadog       `description:"DataDog metrics exporter type." export:"true" label:"allowEmpty"`
+	StatsD        *Statsd        `description:"StatsD metrics exporter type." export:"true" label:"allowEmpty"`
+	InfluxDB      *InfluxDB      `description:"InfluxDB metrics exporter type." label:"allowEmpty"`
+	OpenTelemetry *OpenTelemetry `description:"OpenTelemetry metrics exporter type." export:"true" label:"allowEmpty"`
 }
 
 // Prometheus can contain specific configuration used by the Prometheus Metrics exporter
 type Prometheus struct {
-	Buckets     []float64 `description:"Buckets for latency metrics." export:"true"`
-	EntryPoint  string    `description:"EntryPoint." export:"true"`
-	Middlewares []string  `description:"Middlewares." export:"true"`
+	Buckets              []float64                         `description:"Buckets for latency metrics." export:"true"`
+	EntryPoint           string                            `description:"EntryPoint." export:"true"`
+	Middlewares          []string                          `description:"Middlewares." export:"true"`
+	DisableMethodLabel   bool                              `description:"Remove the method label from the request metrics." export:"true"`
+	DisableProtocolLabel bool                              `description:"Remove the protocol label from the request metrics." export:"true"`
+	HeaderLabels         map[string]*PrometheusHeaderLabel `description:"Labels added to the router metrics from the request headers, keyed by label name." export:"true"`
+}
+
+// PrometheusHeaderLabel holds the configuration of a label whose value comes from a request header.
+type PrometheusHeaderLabel struct {
+	Header string   `description:"Name of the request header." export:"true"`
+	Values []string `description:"Allowed values of the header, the other values being reported as 'other'." export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -68,6 +78,25 @@ func (i *InfluxDB) SetDefaults() {
 	i.PushInterval = Duration(10 * time.Second)
 }
 
+// OpenTelemetry contains the collector address and metrics pushing interval configuration
+type OpenTelemetry struct {
+	Address      string            `description:"OpenTelemetry collector address (host:port)."`
+	GRPC         bool              `description:"Push the metrics with OTLP/gRPC instead of OTLP/HTTP." export:"true"`
+	Insecure     bool              `description:"Connect to the collector without TLS." export:"true"`
+	Headers      map[string]string `description:"Headers sent with the push requests."`
+	PushInterval Duration          `description:"OpenTelemetry push interval." export:"true"`
+	Buckets      []float64         `description:"Buckets for latency metrics." export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (o *OpenTelemetry) SetDefaults() {
+	o.Address = "localhost:4318"
+	// The collectors listen without TLS by default.
+	o.Insecure = true
+	o.PushInterval = Duration(10 * time.Second)
+	o.Buckets = []float64{0.1, 0.3, 1.2, 5}
+}
+
 // Statistics provides options for monitoring request and response stats
 type Statistics struct {
 	RecentErrors int `description:"Number of recent errors logged." export:"true"`